@@ -1,15 +1,18 @@
 package federation
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/stellar/go/address"
 	proto "github.com/stellar/go/protocols/federation"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
 )
 
 // LookupByAddress performs a federated lookup following to the stellar
@@ -80,29 +83,76 @@ func (c *Client) LookupByAccountID(aid string) (*proto.IDResponse, error) {
 }
 
 // ForwardRequest performs a federated lookup following to the stellar
-// federation protocol using the "forward" type request.
-func (c *Client) ForwardRequest(domain string, fields url.Values) (*proto.NameResponse, error) {
+// federation protocol using the "forward" type request. The account and memo
+// of the response are returned in typed form, an error is returned if they are
+// invalid.
+func (c *Client) ForwardRequest(domain string, fields url.Values) (*ForwardResponse, error) {
 	fserv, err := c.getFederationServer(domain)
 	if err != nil {
 		return nil, errors.Wrap(err, "lookup federation server failed")
 	}
 
-	fields.Add("type", "forward")
-	url := c.url(fserv, fields)
+	qstr := url.Values{}
+	for name, values := range fields {
+		qstr[name] = values
+	}
+	qstr.Set("type", "forward")
+	url := c.url(fserv, qstr)
 
-	var resp proto.NameResponse
-	err = c.getJSON(url, &resp)
+	var resp ForwardResponse
+	err = c.getJSON(url, &resp.NameResponse)
 	if err != nil {
 		return nil, errors.Wrap(err, "get federation failed")
 	}
 
-	if resp.MemoType != "" && resp.Memo.String() == "" {
-		return nil, errors.New("Invalid federation response (memo)")
+	err = resp.Account.SetAddress(resp.AccountID)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid federation response (account_id)")
+	}
+
+	resp.TypedMemo, err = typedMemo(resp.MemoType, resp.Memo.Value)
+	if err != nil {
+		return nil, errors.Wrap(err, "Invalid federation response (memo)")
 	}
 
 	return &resp, nil
 }
 
+// typedMemo converts the memo of a federation response to `xdr.Memo`.
+func typedMemo(memoType, memo string) (xdr.Memo, error) {
+	switch memoType {
+	case "":
+		if memo != "" {
+			return xdr.Memo{}, errors.New("memo_type missing")
+		}
+		return xdr.NewMemo(xdr.MemoTypeMemoNone, nil)
+	case "id":
+		id, err := strconv.ParseUint(memo, 10, 64)
+		if err != nil {
+			return xdr.Memo{}, errors.Wrap(err, "id memo must be an unsigned 64-bit integer")
+		}
+		return xdr.NewMemo(xdr.MemoTypeMemoId, xdr.Uint64(id))
+	case "text":
+		if memo == "" {
+			return xdr.Memo{}, errors.New("text memo is empty")
+		}
+		if len(memo) > 28 {
+			return xdr.Memo{}, errors.New("text memo must be up to 28 bytes long")
+		}
+		return xdr.NewMemo(xdr.MemoTypeMemoText, memo)
+	case "hash":
+		raw, err := base64.StdEncoding.DecodeString(memo)
+		if err != nil || len(raw) != 32 {
+			return xdr.Memo{}, errors.New("hash memo must be 32 bytes and base64 encoded")
+		}
+		var hash xdr.Hash
+		copy(hash[:], raw)
+		return xdr.NewMemo(xdr.MemoTypeMemoHash, hash)
+	default:
+		return xdr.Memo{}, errors.Errorf("unsupported memo_type: %s", memoType)
+	}
+}
+
 func (c *Client) getFederationServer(domain string) (string, error) {
 	stoml, err := c.StellarTOML.GetStellarToml(domain)
 	if err != nil {
@@ -10,6 +10,7 @@ import (
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/clients/stellartoml"
 	"github.com/stellar/go/support/http/httptest"
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -160,6 +161,43 @@ func TestForwardRequest(t *testing.T) {
 		assert.Equal(t, "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C", resp.AccountID)
 		assert.Equal(t, "id", resp.MemoType)
 		assert.Equal(t, "123", resp.Memo.String())
+		assert.Equal(t, "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C", resp.Account.Address())
+		assert.Equal(t, xdr.MemoTypeMemoId, resp.TypedMemo.Type)
+		assert.Equal(t, xdr.Uint64(123), *resp.TypedMemo.Id)
+	}
+	// the fields of the caller are left untouched
+	assert.Equal(t, "", fields.Get("type"))
+
+	// invalid memo
+	hmock.On("GET", "https://stellar.org/federation").
+		ReturnJSON(http.StatusOK, map[string]string{
+			"account_id": "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C",
+			"memo_type":  "id",
+			"memo":       "not a number",
+		})
+	_, err = c.ForwardRequest("stellar.org", fields)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Invalid federation response (memo)")
+	}
+
+	// invalid account
+	hmock.On("GET", "https://stellar.org/federation").
+		ReturnJSON(http.StatusOK, map[string]string{
+			"account_id": "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766",
+		})
+	_, err = c.ForwardRequest("stellar.org", fields)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Invalid federation response (account_id)")
+	}
+
+	// no memo
+	hmock.On("GET", "https://stellar.org/federation").
+		ReturnJSON(http.StatusOK, map[string]string{
+			"account_id": "GASTNVNLHVR3NFO3QACMHCJT3JUSIV4NBXDHDO4VTPDTNN65W3B2766C",
+		})
+	resp, err = c.ForwardRequest("stellar.org", fields)
+	if assert.NoError(t, err) {
+		assert.Equal(t, xdr.MemoTypeMemoNone, resp.TypedMemo.Type)
 	}
 }
 
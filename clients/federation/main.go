@@ -7,6 +7,7 @@ import (
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/clients/stellartoml"
 	proto "github.com/stellar/go/protocols/federation"
+	"github.com/stellar/go/xdr"
 )
 
 // FederationResponseMaxSize is the maximum size of response from a federation server
@@ -38,7 +39,17 @@ type Client struct {
 type ClientInterface interface {
 	LookupByAddress(addy string) (*proto.NameResponse, error)
 	LookupByAccountID(aid string) (*proto.IDResponse, error)
-	ForwardRequest(domain string, fields url.Values) (*proto.NameResponse, error)
+	ForwardRequest(domain string, fields url.Values) (*ForwardResponse, error)
+}
+
+// ForwardResponse represents the result of a "forward" federation request.
+// Account and TypedMemo hold the account_id and memo of the response in typed
+// form: TypedMemo is of type `xdr.MemoTypeMemoNone` when the federation server
+// returned no memo.
+type ForwardResponse struct {
+	proto.NameResponse
+	Account   xdr.AccountId
+	TypedMemo xdr.Memo
 }
 
 // Horizon represents a horizon client that can be consulted for data when
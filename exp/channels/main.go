@@ -0,0 +1,327 @@
+/*
+Package channels submits transactions for a single account through a pool of channel accounts.
+
+Transactions of an account must be submitted one after the other, because each of them uses the
+next sequence number of the account. A channel account is an account whose only purpose is to be
+the source of transactions: the operations of a transaction still have the main account as
+their source, and the transaction is signed by both the channel and the main account. As each
+channel has its own sequence number, a pool of N channels can have N transactions of the main
+account in flight at the same time.
+
+	pool := channels.NewPool(client, network.TestNetworkPassphrase, mainKeypair)
+	_, err := pool.Create(10, "2")
+	...
+	resp, err := pool.Submit(&txnbuild.Payment{Destination: ..., Amount: "10", Asset: txnbuild.NativeAsset{}})
+	...
+	err = pool.Close()
+
+Pool is safe for use by multiple goroutines.
+*/
+package channels
+
+import (
+	"sync"
+
+	horizonclient "github.com/stellar/go/exp/clients/horizon"
+	"github.com/stellar/go/exp/txnbuild"
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+)
+
+// DefaultTimeout is the number of seconds a transaction submitted by a Pool is valid for, if
+// Pool.Timeout is not set.
+const DefaultTimeout = int64(300)
+
+// ErrPoolClosed is returned when submitting to a Pool that has been closed.
+var ErrPoolClosed = errors.New("Pool is closed")
+
+// ErrNoChannels is returned when submitting to a Pool that has no channels.
+var ErrNoChannels = errors.New("Pool has no channels")
+
+// Pool is a pool of channel accounts, used to submit the transactions of a main account
+// concurrently. Create it with NewPool.
+type Pool struct {
+	// BaseFee is the fee per operation of the transactions, see txnbuild.Transaction.
+	BaseFee uint32
+	// Timeout is the number of seconds a transaction is valid for, DefaultTimeout if not set.
+	Timeout int64
+	// BadSequenceRetries is how many times a transaction that failed with tx_bad_seq is
+	// submitted again, once the sequence number of its channel has been reloaded.
+	BadSequenceRetries int
+
+	client  horizonclient.ClientInterface
+	network string
+	main    *keypair.Full
+	account *txnbuild.ManagedAccount
+
+	mutex    sync.Mutex
+	channels []*channel
+	free     chan *channel
+	closed   bool
+}
+
+// channel is a channel account of a Pool.
+type channel struct {
+	keypair *keypair.Full
+	account *txnbuild.ManagedAccount
+}
+
+// NewPool is a factory method that creates an empty Pool for the main account main, on the
+// network with the given passphrase. Channels are added with Add or Create.
+func NewPool(client horizonclient.ClientInterface, networkPassphrase string, main *keypair.Full) *Pool {
+	return &Pool{
+		BadSequenceRetries: 1,
+		client:             client,
+		network:            networkPassphrase,
+		main:               main,
+		account:            txnbuild.NewManagedAccount(main.Address(), client),
+		free:               make(chan *channel, txnbuild.MaxOperations),
+	}
+}
+
+// Len returns the number of channels in the pool.
+func (p *Pool) Len() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return len(p.channels)
+}
+
+// Add adds existing channel accounts to the pool. Their sequence numbers are loaded from horizon
+// when they are first used.
+func (p *Pool) Add(keypairs ...*keypair.Full) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.closed {
+		return ErrPoolClosed
+	}
+	if len(p.channels)+len(keypairs) > cap(p.free) {
+		return errors.Errorf("Pool cannot have more than %d channels", cap(p.free))
+	}
+
+	for _, kp := range keypairs {
+		c := &channel{keypair: kp, account: txnbuild.NewManagedAccount(kp.Address(), p.client)}
+		p.channels = append(p.channels, c)
+		p.free <- c
+	}
+	return nil
+}
+
+// Create creates n new channel accounts funded by the main account, each with a starting balance
+// of startingBalance lumens, and adds them to the pool. It returns the keypairs of the new
+// channels, so that they can be added to a pool again later.
+func (p *Pool) Create(n int, startingBalance string) ([]*keypair.Full, error) {
+	if n <= 0 || n > txnbuild.MaxOperations {
+		return nil, errors.Errorf("Can only create between 1 and %d channels at once", txnbuild.MaxOperations)
+	}
+
+	keypairs := make([]*keypair.Full, n)
+	ops := make([]txnbuild.Operation, n)
+	for i := range keypairs {
+		kp, err := keypair.Random()
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to generate channel keypair")
+		}
+		keypairs[i] = kp
+		ops[i] = &txnbuild.CreateAccount{Destination: kp.Address(), Amount: startingBalance}
+	}
+
+	_, err := p.submit(p.account, ops, p.main)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to create channel accounts")
+	}
+
+	return keypairs, p.Add(keypairs...)
+}
+
+// Submit submits a transaction made of ops on behalf of the main account, using the first
+// channel that is free as its source. Operations without a source account get the main account
+// as their source. ops are not modified: the source account is set on copies. Submit blocks
+// until a channel is free.
+//
+// If the transaction fails with tx_bad_seq, the sequence number of the channel is reloaded and
+// the transaction built and submitted again, up to BadSequenceRetries times. Note that a
+// transaction whose submission timed out may still have been applied: if the caller submits the
+// same ops again, the tx_bad_seq caused by the applied transaction is retried with a new sequence
+// number and the operations are applied twice. Set BadSequenceRetries to 0 when that is not
+// acceptable.
+func (p *Pool) Submit(ops ...txnbuild.Operation) (hProtocol.TransactionSuccess, error) {
+	sourced := make([]txnbuild.Operation, len(ops))
+	for i, op := range ops {
+		var err error
+		sourced[i], err = withSourceAccount(op, p.account)
+		if err != nil {
+			return hProtocol.TransactionSuccess{}, err
+		}
+	}
+
+	c, err := p.acquire()
+	if err != nil {
+		return hProtocol.TransactionSuccess{}, err
+	}
+	defer p.release(c)
+
+	return p.submit(c.account, sourced, c.keypair, p.main)
+}
+
+// Close merges every channel back into the main account, after waiting for the transactions in
+// flight. No transactions can be submitted to the pool once it is closed. Channels that cannot
+// be merged are left in the pool, and Close may be called again to retry.
+func (p *Pool) Close() error {
+	p.mutex.Lock()
+	p.closed = true
+	n := len(p.channels)
+	p.mutex.Unlock()
+
+	// Take every channel, so that nothing is submitted while they are merged
+	taken := make([]*channel, 0, n)
+	for i := 0; i < n; i++ {
+		taken = append(taken, <-p.free)
+	}
+
+	var firstErr error
+	for _, c := range taken {
+		op := &txnbuild.AccountMerge{Destination: p.main.Address()}
+		_, err := p.submit(c.account, []txnbuild.Operation{op}, c.keypair)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = errors.Wrapf(err, "Failed to merge channel %s", c.keypair.Address())
+			}
+			p.free <- c
+			continue
+		}
+		p.remove(c)
+	}
+	return firstErr
+}
+
+func (p *Pool) acquire() (*channel, error) {
+	p.mutex.Lock()
+	closed, empty := p.closed, len(p.channels) == 0
+	p.mutex.Unlock()
+
+	if closed {
+		return nil, ErrPoolClosed
+	}
+	if empty {
+		return nil, ErrNoChannels
+	}
+
+	c := <-p.free
+	// The pool may have been closed while waiting, in which case Close needs the channel back
+	p.mutex.Lock()
+	closed = p.closed
+	p.mutex.Unlock()
+	if closed {
+		p.release(c)
+		return nil, ErrPoolClosed
+	}
+	return c, nil
+}
+
+func (p *Pool) release(c *channel) {
+	p.free <- c
+}
+
+func (p *Pool) remove(c *channel) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for i := range p.channels {
+		if p.channels[i] == c {
+			p.channels = append(p.channels[:i], p.channels[i+1:]...)
+			return
+		}
+	}
+}
+
+// submit builds a transaction of ops with source as its source account, signs it with signers
+// and submits it, retrying on tx_bad_seq.
+func (p *Pool) submit(source *txnbuild.ManagedAccount, ops []txnbuild.Operation, signers ...*keypair.Full) (hProtocol.TransactionSuccess, error) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	for attempt := 0; ; attempt++ {
+		tx := txnbuild.Transaction{
+			SourceAccount: source,
+			Operations:    ops,
+			BaseFee:       p.BaseFee,
+			Network:       p.network,
+			Timebounds:    txnbuild.NewTimeout(timeout),
+		}
+		err := tx.Build()
+		if err != nil {
+			return hProtocol.TransactionSuccess{}, errors.Wrap(err, "Failed to build transaction")
+		}
+		err = tx.Sign(signers...)
+		if err != nil {
+			return hProtocol.TransactionSuccess{}, errors.Wrap(err, "Failed to sign transaction")
+		}
+		txeBase64, err := tx.Base64()
+		if err != nil {
+			return hProtocol.TransactionSuccess{}, errors.Wrap(err, "Failed to encode transaction")
+		}
+
+		resp, err := p.client.SubmitTransaction(txeBase64)
+		if err == nil {
+			return resp, nil
+		}
+		if !source.CheckError(err) || attempt >= p.BadSequenceRetries {
+			return resp, err
+		}
+	}
+}
+
+// withSourceAccount returns a copy of op with its source account set to account, unless it
+// already has one. op itself is left unchanged.
+func withSourceAccount(op txnbuild.Operation, account txnbuild.Account) (txnbuild.Operation, error) {
+	var copied txnbuild.Operation
+	var source *txnbuild.Account
+	switch o := op.(type) {
+	case *txnbuild.CreateAccount:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.Payment:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.PathPayment:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.ManageOffer:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.CreatePassiveOffer:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.SetOptions:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.ChangeTrust:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.AllowTrust:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.AccountMerge:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.Inflation:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.ManageData:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	case *txnbuild.BumpSequence:
+		c := *o
+		copied, source = &c, &c.SourceAccount
+	default:
+		return nil, errors.Errorf("Unsupported operation %T", op)
+	}
+
+	if *source == nil {
+		*source = account
+	}
+	return copied, nil
+}
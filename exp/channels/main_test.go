@@ -0,0 +1,220 @@
+package channels
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	horizonclient "github.com/stellar/go/exp/clients/horizon"
+	"github.com/stellar/go/exp/txnbuild"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeypair(seed string) *keypair.Full {
+	kp, _ := keypair.Parse(seed)
+	return kp.(*keypair.Full)
+}
+
+var (
+	mainKeypair = newKeypair("SBPQUZ6G4FZNWFHKUWC5BEYWF6R52E3SEP7R3GWYSM2XTKGF5LNTWW4R")
+	channel1    = newKeypair("SBMSVD4KKELKGZXHBUQTIROWUAPQASDX7KEJITARP4VMZ6KLUHOGPTYW")
+	channel2    = newKeypair("SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY")
+)
+
+func badSequenceError() *horizonclient.Error {
+	return &horizonclient.Error{
+		Problem: problem.P{
+			Title: "Transaction Failed",
+			Extras: map[string]interface{}{
+				"result_codes": map[string]interface{}{"transaction": "tx_bad_seq"},
+			},
+		},
+	}
+}
+
+// recordSubmissions makes client accept every transaction, and returns the transactions it got.
+func recordSubmissions(client *horizonclient.MockClient) func() []txnbuild.Transaction {
+	var mutex sync.Mutex
+	var submitted []string
+	client.On("SubmitTransaction", mock.Anything).
+		Return(hProtocol.TransactionSuccess{}, nil).
+		Run(func(args mock.Arguments) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			submitted = append(submitted, args.String(0))
+		})
+
+	return func() []txnbuild.Transaction {
+		mutex.Lock()
+		defer mutex.Unlock()
+		var txs []txnbuild.Transaction
+		for _, txeB64 := range submitted {
+			tx, err := txnbuild.TransactionFromXDR(txeB64)
+			if err != nil {
+				panic(err)
+			}
+			txs = append(txs, tx)
+		}
+		return txs
+	}
+}
+
+func TestCreate(t *testing.T) {
+	client := &horizonclient.MockClient{}
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: mainKeypair.Address()}).
+		Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+	submitted := recordSubmissions(client)
+
+	pool := NewPool(client, network.TestNetworkPassphrase, mainKeypair)
+	keypairs, err := pool.Create(3, "2.5")
+	require.NoError(t, err)
+	assert.Len(t, keypairs, 3)
+	assert.Equal(t, 3, pool.Len())
+
+	txs := submitted()
+	require.Len(t, txs, 1)
+	assert.Equal(t, mainKeypair.Address(), txs[0].SourceAccount.GetAccountID())
+	require.Len(t, txs[0].Operations, 3)
+	for i, op := range txs[0].Operations {
+		assert.Equal(t, &txnbuild.CreateAccount{Destination: keypairs[i].Address(), Amount: "2.5000000"}, op)
+	}
+
+	_, err = pool.Create(0, "2.5")
+	assert.EqualError(t, err, "Can only create between 1 and 100 channels at once")
+	client.AssertExpectations(t)
+}
+
+func TestSubmitConcurrently(t *testing.T) {
+	client := &horizonclient.MockClient{}
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: channel1.Address()}).
+		Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: channel2.Address()}).
+		Return(hProtocol.Account{Sequence: "200"}, nil).Once()
+	submitted := recordSubmissions(client)
+
+	pool := NewPool(client, network.TestNetworkPassphrase, mainKeypair)
+	require.NoError(t, pool.Add(channel1, channel2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pool.Submit(&txnbuild.Payment{
+				Destination: channel1.Address(),
+				Amount:      "10",
+				Asset:       txnbuild.NativeAsset{},
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	txs := submitted()
+	require.Len(t, txs, 10)
+	seen := map[string]bool{}
+	for _, tx := range txs {
+		source := tx.SourceAccount.(*txnbuild.SimpleAccount)
+		assert.Contains(t, []string{channel1.Address(), channel2.Address()}, source.AccountID)
+		key := fmt.Sprintf("%s/%d", source.AccountID, source.Sequence)
+		assert.False(t, seen[key], "sequence number used twice")
+		seen[key] = true
+
+		// The operations are done by the main account, which signs along with the channel
+		assert.Equal(t, mainKeypair.Address(), tx.Operations[0].(*txnbuild.Payment).SourceAccount.GetAccountID())
+		assert.Len(t, tx.Signatures(), 2)
+	}
+	client.AssertExpectations(t)
+}
+
+func TestSubmitKeepsOperations(t *testing.T) {
+	client := &horizonclient.MockClient{}
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: channel1.Address()}).
+		Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+	submitted := recordSubmissions(client)
+
+	pool := NewPool(client, network.TestNetworkPassphrase, mainKeypair)
+	require.NoError(t, pool.Add(channel1))
+
+	op := &txnbuild.Payment{Destination: channel2.Address(), Amount: "10", Asset: txnbuild.NativeAsset{}}
+	_, err := pool.Submit(op)
+	require.NoError(t, err)
+	assert.Nil(t, op.SourceAccount)
+
+	txs := submitted()
+	require.Len(t, txs, 1)
+	assert.Equal(t, mainKeypair.Address(), txs[0].Operations[0].(*txnbuild.Payment).SourceAccount.GetAccountID())
+	client.AssertExpectations(t)
+}
+
+func TestSubmitBadSequence(t *testing.T) {
+	client := &horizonclient.MockClient{}
+	request := horizonclient.AccountRequest{AccountID: channel1.Address()}
+	client.On("AccountDetail", request).Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+	client.On("AccountDetail", request).Return(hProtocol.Account{Sequence: "150"}, nil).Once()
+	client.On("SubmitTransaction", mock.Anything).
+		Return(hProtocol.TransactionSuccess{}, badSequenceError()).Once()
+	submitted := recordSubmissions(client)
+
+	pool := NewPool(client, network.TestNetworkPassphrase, mainKeypair)
+	require.NoError(t, pool.Add(channel1))
+	_, err := pool.Submit(&txnbuild.Inflation{})
+	require.NoError(t, err)
+
+	txs := submitted()
+	require.Len(t, txs, 1)
+	assert.Equal(t, int64(150), txs[0].SourceAccount.(*txnbuild.SimpleAccount).Sequence)
+	client.AssertExpectations(t)
+
+	// Without retries the error is returned
+	client = &horizonclient.MockClient{}
+	client.On("AccountDetail", request).Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+	client.On("SubmitTransaction", mock.Anything).
+		Return(hProtocol.TransactionSuccess{}, badSequenceError()).Once()
+
+	pool = NewPool(client, network.TestNetworkPassphrase, mainKeypair)
+	pool.BadSequenceRetries = 0
+	require.NoError(t, pool.Add(channel1))
+	_, err = pool.Submit(&txnbuild.Inflation{})
+	assert.Equal(t, badSequenceError(), err)
+	client.AssertExpectations(t)
+}
+
+func TestClose(t *testing.T) {
+	client := &horizonclient.MockClient{}
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: channel1.Address()}).
+		Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: channel2.Address()}).
+		Return(hProtocol.Account{Sequence: "200"}, nil).Once()
+	submitted := recordSubmissions(client)
+
+	pool := NewPool(client, network.TestNetworkPassphrase, mainKeypair)
+	require.NoError(t, pool.Add(channel1, channel2))
+	require.NoError(t, pool.Close())
+	assert.Equal(t, 0, pool.Len())
+
+	txs := submitted()
+	require.Len(t, txs, 2)
+	for _, tx := range txs {
+		require.Len(t, tx.Operations, 1)
+		assert.Equal(t, mainKeypair.Address(), tx.Operations[0].(*txnbuild.AccountMerge).Destination)
+		assert.Len(t, tx.Signatures(), 1)
+	}
+
+	_, err := pool.Submit(&txnbuild.Inflation{})
+	assert.Equal(t, ErrPoolClosed, err)
+	assert.Equal(t, ErrPoolClosed, pool.Add(channel1))
+	client.AssertExpectations(t)
+}
+
+func TestSubmitNoChannels(t *testing.T) {
+	pool := NewPool(&horizonclient.MockClient{}, network.TestNetworkPassphrase, mainKeypair)
+	_, err := pool.Submit(&txnbuild.Inflation{})
+	assert.Equal(t, ErrNoChannels, err)
+}
@@ -19,45 +19,83 @@ import (
 	"github.com/stellar/go/support/errors"
 )
 
-func (c *Client) sendRequest(hr HorizonRequest, a interface{}) (err error) {
+func (c *Client) sendRequest(ctx context.Context, hr HorizonRequest, a interface{}) (err error) {
 	endpoint, err := hr.BuildURL()
 	if err != nil {
 		return
 	}
 
 	c.HorizonURL = c.getHorizonURL()
-	var req *http.Request
+	method := "GET"
 	// check if it is a submitRequest
 	_, ok := hr.(submitRequest)
 	if ok {
-		req, err = http.NewRequest("POST", c.HorizonURL+endpoint, nil)
-		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
-	} else {
-		req, err = http.NewRequest("GET", c.HorizonURL+endpoint, nil)
+		method = "POST"
 	}
 
-	if err != nil {
-		return errors.Wrap(err, "Error creating HTTP request")
+	return c.sendHTTPRequest(ctx, method, c.HorizonURL+endpoint, a)
+}
+
+// sendGetRequest follows a link returned by horizon, such as the next page of a page.
+func (c *Client) sendGetRequest(ctx context.Context, link string, a interface{}) error {
+	if link == "" {
+		return errors.New("No link to follow")
 	}
-	c.setClientAppHeaders(req)
+	return c.sendHTTPRequest(ctx, "GET", link, a)
+}
 
+// sendHTTPRequest sends a request to requestURL with ctx and decodes the response into a,
+// retrying it as configured by c.MaxRetries.
+func (c *Client) sendHTTPRequest(ctx context.Context, method, requestURL string, a interface{}) (err error) {
 	if c.horizonTimeOut == 0 {
 		c.horizonTimeOut = HorizonTimeOut
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*c.horizonTimeOut)
+
+	for attempt := uint(0); ; attempt++ {
+		var resp *http.Response
+		resp, err = c.sendAttempt(ctx, method, requestURL, a)
+		if err == nil || attempt >= c.MaxRetries {
+			return
+		}
+		// Only retry network errors and responses horizon may answer differently next time
+		if resp != nil && !retryable(resp.StatusCode) {
+			return
+		}
+
+		if sleepErr := sleep(ctx, c.retryDelay(attempt, resp)); sleepErr != nil {
+			return
+		}
+	}
+}
+
+// sendAttempt sends a single request and decodes its response into a. The response is
+// returned unless the request failed altogether, to decide whether to retry.
+func (c *Client) sendAttempt(ctx context.Context, method, url string, a interface{}) (*http.Response, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error creating HTTP request")
+	}
+	if method == "POST" {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded; param=value")
+	}
+	c.setClientAppHeaders(req)
+
+	ctx, cancel := context.WithTimeout(ctx, time.Second*c.horizonTimeOut)
+	defer cancel()
 
 	resp, err := c.HTTP.Do(req.WithContext(ctx))
 	if err != nil {
-		cancel()
-		return
+		return nil, err
 	}
 
-	err = decodeResponse(resp, &a)
-	cancel()
-	return
+	return resp, decodeResponse(resp, &a)
 }
 
-// stream handles connections to endpoints that support streaming on an horizon server
+// stream handles connections to endpoints that support streaming on an horizon server.
+// When the connection is closed by the server, the stream reconnects and resumes from
+// the last event received. Failures to connect, non-2xx responses and dropped connections
+// are retried up to c.MaxRetries consecutive times, after which their error is returned.
+// Errors of the handler end the stream at once.
 func (c *Client) stream(
 	ctx context.Context,
 	streamURL string,
@@ -73,6 +111,7 @@ func (c *Client) stream(
 		query.Set("cursor", "now")
 	}
 
+	failures := uint(0)
 	for {
 		// updates the url with new cursor
 		su.RawQuery = query.Encode()
@@ -85,105 +124,151 @@ func (c *Client) stream(
 		c.setClientAppHeaders(req)
 
 		// We can use c.HTTP here because we set Timeout per request not on the client. See sendRequest()
-		resp, err := c.HTTP.Do(req)
+		resp, err := c.HTTP.Do(req.WithContext(ctx))
 		if err != nil {
-			return errors.Wrap(err, "Error sending HTTP request")
+			err = errors.Wrap(err, "Error sending HTTP request")
+		} else if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
+			// Expected statusCode are 200-299
+			resp.Body.Close()
+			err = fmt.Errorf("Got bad HTTP status code %d", resp.StatusCode)
+			if !retryable(resp.StatusCode) {
+				return err
+			}
+		} else {
+			received := 0
+			err = readEvents(ctx, resp.Body, func(event sse.Event) error {
+				// Update cursor with event ID
+				if event.Id != "" {
+					query.Set("cursor", event.Id)
+				}
+				received++
+				return handleEvent(event, handler)
+			})
+			resp.Body.Close()
+			if received > 0 {
+				failures = 0
+			}
+			if _, dropped := err.(droppedError); !dropped && err != nil {
+				return err
+			}
 		}
 
-		// Expected statusCode are 200-299
-		if !(resp.StatusCode >= 200 && resp.StatusCode < 300) {
-			return fmt.Errorf("Got bad HTTP status code %d", resp.StatusCode)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err == nil {
+			// The server closed the stream, resume it at once
+			continue
+		}
+		if failures >= c.MaxRetries {
+			if dropped, ok := err.(droppedError); ok {
+				return dropped.error
+			}
+			return err
 		}
-		defer resp.Body.Close()
+		if sleep(ctx, c.retryDelay(failures, resp)) != nil {
+			return nil
+		}
+		failures++
+	}
+}
 
-		reader := bufio.NewReader(resp.Body)
+// droppedError is returned by readEvents if the connection failed while reading events.
+type droppedError struct {
+	error
+}
+
+// readEvents reads the events of a stream one by one and passes them to onEvent, until the
+// stream ends, ctx is done (both returning nil) or an error occurs.
+func readEvents(ctx context.Context, body io.Reader, onEvent func(sse.Event) error) error {
+	reader := bufio.NewReader(body)
 
-		// Read events one by one. Break this loop when there is no more data to be
-		// read from resp.Body (io.EOF).
-	Events:
+	// Read events one by one. Return when there is no more data to be
+	// read from body (io.EOF).
+	for {
+		// Read until empty line = event delimiter. The perfect solution would be to read
+		// as many bytes as possible and forward them to sse.Decode. However this
+		// requires much more complicated code.
+		// We could also write our own `sse` package that works fine with streams directly
+		// (github.com/manucorporat/sse is just using io/ioutils.ReadAll).
+		var buffer bytes.Buffer
+		nonEmptylinesRead := 0
 		for {
-			// Read until empty line = event delimiter. The perfect solution would be to read
-			// as many bytes as possible and forward them to sse.Decode. However this
-			// requires much more complicated code.
-			// We could also write our own `sse` package that works fine with streams directly
-			// (github.com/manucorporat/sse is just using io/ioutils.ReadAll).
-			var buffer bytes.Buffer
-			nonEmptylinesRead := 0
-			for {
-				// Check if ctx is not cancelled
-				select {
-				case <-ctx.Done():
-					return nil
-				default:
-					// Continue
-				}
+			// Check if ctx is not cancelled
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				// Continue
+			}
 
-				line, err := reader.ReadString('\n')
-				if err != nil {
-					if err == io.EOF || err == io.ErrUnexpectedEOF {
-						// We catch EOF errors to handle two possible situations:
-						// - The last line before closing the stream was not empty. This should never
-						//   happen in Horizon as it always sends an empty line after each event.
-						// - The stream was closed by the server/proxy because the connection was idle.
-						//
-						// In the former case, that (again) should never happen in Horizon, we need to
-						// check if there are any events we need to decode. We do this in the `if`
-						// statement below just in case if Horizon behaviour changes in a future.
-						//
-						// From spec:
-						// > Once the end of the file is reached, the user agent must dispatch the
-						// > event one final time, as defined below.
-						if nonEmptylinesRead == 0 {
-							break Events
-						}
-					} else {
-						return errors.Wrap(err, "Error reading line")
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				if err == io.EOF || err == io.ErrUnexpectedEOF {
+					// We catch EOF errors to handle two possible situations:
+					// - The last line before closing the stream was not empty. This should never
+					//   happen in Horizon as it always sends an empty line after each event.
+					// - The stream was closed by the server/proxy because the connection was idle.
+					//
+					// In the former case, that (again) should never happen in Horizon, we need to
+					// check if there are any events we need to decode. We do this in the `if`
+					// statement below just in case if Horizon behaviour changes in a future.
+					//
+					// From spec:
+					// > Once the end of the file is reached, the user agent must dispatch the
+					// > event one final time, as defined below.
+					if nonEmptylinesRead == 0 {
+						return nil
 					}
+				} else {
+					if ctx.Err() != nil {
+						return nil
+					}
+					return droppedError{errors.Wrap(err, "Error reading line")}
 				}
-				buffer.WriteString(line)
-
-				if strings.TrimRight(line, "\n\r") == "" {
-					break
-				}
-
-				nonEmptylinesRead++
 			}
+			buffer.WriteString(line)
 
-			events, err := sse.Decode(strings.NewReader(buffer.String()))
-			if err != nil {
-				return errors.Wrap(err, "Error decoding event")
+			if strings.TrimRight(line, "\n\r") == "" {
+				break
 			}
 
-			// Right now len(events) should always be 1. This loop will be helpful after writing
-			// new SSE decoder that can handle io.Reader without using ioutils.ReadAll().
-			for _, event := range events {
-				if event.Event != "message" {
-					continue
-				}
+			nonEmptylinesRead++
+		}
 
-				// Update cursor with event ID
-				if event.Id != "" {
-					query.Set("cursor", event.Id)
-				}
+		events, err := sse.Decode(strings.NewReader(buffer.String()))
+		if err != nil {
+			return errors.Wrap(err, "Error decoding event")
+		}
 
-				switch data := event.Data.(type) {
-				case string:
-					err = handler([]byte(data))
-					err = errors.Wrap(err, "Handler error")
-				case []byte:
-					err = handler(data)
-					err = errors.Wrap(err, "Handler error")
-				default:
-					err = errors.New("Invalid event.Data type")
-				}
-				if err != nil {
-					return err
-				}
+		// Right now len(events) should always be 1. This loop will be helpful after writing
+		// new SSE decoder that can handle io.Reader without using ioutils.ReadAll().
+		for _, event := range events {
+			if event.Event != "message" {
+				continue
+			}
+			if err = onEvent(event); err != nil {
+				return err
 			}
 		}
 	}
 }
 
+// handleEvent passes the data of a stream event to handler.
+func handleEvent(event sse.Event, handler func(data []byte) error) (err error) {
+	switch data := event.Data.(type) {
+	case string:
+		err = handler([]byte(data))
+		err = errors.Wrap(err, "Handler error")
+	case []byte:
+		err = handler(data)
+		err = errors.Wrap(err, "Handler error")
+	default:
+		err = errors.New("Invalid event.Data type")
+	}
+	return
+}
+
 func (c *Client) setClientAppHeaders(req *http.Request) {
 	req.Header.Set("X-Client-Name", "go-stellar-sdk")
 	req.Header.Set("X-Client-Version", app.Version())
@@ -209,7 +294,12 @@ func (c *Client) GetHorizonTimeOut() time.Duration {
 
 // AccountDetail returns information for a single account.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/accounts-single.html
-func (c *Client) AccountDetail(request AccountRequest) (account hProtocol.Account, err error) {
+func (c *Client) AccountDetail(request AccountRequest) (hProtocol.Account, error) {
+	return c.AccountDetailCtx(c.context(), request)
+}
+
+// AccountDetailCtx is AccountDetail made with ctx, so that the request stops when ctx is done.
+func (c *Client) AccountDetailCtx(ctx context.Context, request AccountRequest) (account hProtocol.Account, err error) {
 	if request.AccountID == "" {
 		err = errors.New("No account ID provided")
 	}
@@ -218,13 +308,18 @@ func (c *Client) AccountDetail(request AccountRequest) (account hProtocol.Accoun
 		return
 	}
 
-	err = c.sendRequest(request, &account)
+	err = c.sendRequest(ctx, request, &account)
 	return
 }
 
 // AccountData returns a single data associated with a given account
 // See https://www.stellar.org/developers/horizon/reference/endpoints/data-for-account.html
-func (c *Client) AccountData(request AccountRequest) (accountData hProtocol.AccountData, err error) {
+func (c *Client) AccountData(request AccountRequest) (hProtocol.AccountData, error) {
+	return c.AccountDataCtx(c.context(), request)
+}
+
+// AccountDataCtx is AccountData made with ctx, so that the request stops when ctx is done.
+func (c *Client) AccountDataCtx(ctx context.Context, request AccountRequest) (accountData hProtocol.AccountData, err error) {
 	if request.AccountID == "" || request.DataKey == "" {
 		err = errors.New("Too few parameters")
 	}
@@ -233,21 +328,31 @@ func (c *Client) AccountData(request AccountRequest) (accountData hProtocol.Acco
 		return
 	}
 
-	err = c.sendRequest(request, &accountData)
+	err = c.sendRequest(ctx, request, &accountData)
 	return
 }
 
 // Effects returns effects(https://www.stellar.org/developers/horizon/reference/resources/effect.html)
 // It can be used to return effects for an account, a ledger, an operation, a transaction and all effects on the network.
-func (c *Client) Effects(request EffectRequest) (effects hProtocol.EffectsPage, err error) {
-	err = c.sendRequest(request, &effects)
+func (c *Client) Effects(request EffectRequest) (hProtocol.EffectsPage, error) {
+	return c.EffectsCtx(c.context(), request)
+}
+
+// EffectsCtx is Effects made with ctx, so that the request stops when ctx is done.
+func (c *Client) EffectsCtx(ctx context.Context, request EffectRequest) (effects hProtocol.EffectsPage, err error) {
+	err = c.sendRequest(ctx, request, &effects)
 	return
 }
 
 // Assets returns asset information.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/assets-all.html
-func (c *Client) Assets(request AssetRequest) (assets hProtocol.AssetsPage, err error) {
-	err = c.sendRequest(request, &assets)
+func (c *Client) Assets(request AssetRequest) (hProtocol.AssetsPage, error) {
+	return c.AssetsCtx(c.context(), request)
+}
+
+// AssetsCtx is Assets made with ctx, so that the request stops when ctx is done.
+func (c *Client) AssetsCtx(ctx context.Context, request AssetRequest) (assets hProtocol.AssetsPage, err error) {
+	err = c.sendRequest(ctx, request, &assets)
 	return
 }
 
@@ -260,14 +365,24 @@ func (c *Client) Stream(ctx context.Context, request StreamRequest, handler func
 
 // Ledgers returns information about all ledgers.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/ledgers-all.html
-func (c *Client) Ledgers(request LedgerRequest) (ledgers hProtocol.LedgersPage, err error) {
-	err = c.sendRequest(request, &ledgers)
+func (c *Client) Ledgers(request LedgerRequest) (hProtocol.LedgersPage, error) {
+	return c.LedgersCtx(c.context(), request)
+}
+
+// LedgersCtx is Ledgers made with ctx, so that the request stops when ctx is done.
+func (c *Client) LedgersCtx(ctx context.Context, request LedgerRequest) (ledgers hProtocol.LedgersPage, err error) {
+	err = c.sendRequest(ctx, request, &ledgers)
 	return
 }
 
 // LedgerDetail returns information about a particular ledger for a given sequence number
 // See https://www.stellar.org/developers/horizon/reference/endpoints/ledgers-single.html
-func (c *Client) LedgerDetail(sequence uint32) (ledger hProtocol.Ledger, err error) {
+func (c *Client) LedgerDetail(sequence uint32) (hProtocol.Ledger, error) {
+	return c.LedgerDetailCtx(c.context(), sequence)
+}
+
+// LedgerDetailCtx is LedgerDetail made with ctx, so that the request stops when ctx is done.
+func (c *Client) LedgerDetailCtx(ctx context.Context, sequence uint32) (ledger hProtocol.Ledger, err error) {
 	if sequence <= 0 {
 		err = errors.New("Invalid sequence number provided")
 	}
@@ -278,43 +393,68 @@ func (c *Client) LedgerDetail(sequence uint32) (ledger hProtocol.Ledger, err err
 
 	request := LedgerRequest{forSequence: sequence}
 
-	err = c.sendRequest(request, &ledger)
+	err = c.sendRequest(ctx, request, &ledger)
 	return
 }
 
 // Metrics returns monitoring information about a horizon server
 // See https://www.stellar.org/developers/horizon/reference/endpoints/metrics.html
-func (c *Client) Metrics() (metrics hProtocol.Metrics, err error) {
+func (c *Client) Metrics() (hProtocol.Metrics, error) {
+	return c.MetricsCtx(c.context())
+}
+
+// MetricsCtx is Metrics made with ctx, so that the request stops when ctx is done.
+func (c *Client) MetricsCtx(ctx context.Context) (metrics hProtocol.Metrics, err error) {
 	request := metricsRequest{endpoint: "metrics"}
-	err = c.sendRequest(request, &metrics)
+	err = c.sendRequest(ctx, request, &metrics)
 	return
 }
 
 // FeeStats returns information about fees in the last 5 ledgers.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/fee-stats.html
-func (c *Client) FeeStats() (feestats hProtocol.FeeStats, err error) {
+func (c *Client) FeeStats() (hProtocol.FeeStats, error) {
+	return c.FeeStatsCtx(c.context())
+}
+
+// FeeStatsCtx is FeeStats made with ctx, so that the request stops when ctx is done.
+func (c *Client) FeeStatsCtx(ctx context.Context) (feestats hProtocol.FeeStats, err error) {
 	request := feeStatsRequest{endpoint: "fee_stats"}
-	err = c.sendRequest(request, &feestats)
+	err = c.sendRequest(ctx, request, &feestats)
 	return
 }
 
 // Offers returns information about offers made on the SDEX.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/offers-for-account.html
-func (c *Client) Offers(request OfferRequest) (offers hProtocol.OffersPage, err error) {
-	err = c.sendRequest(request, &offers)
+func (c *Client) Offers(request OfferRequest) (hProtocol.OffersPage, error) {
+	return c.OffersCtx(c.context(), request)
+}
+
+// OffersCtx is Offers made with ctx, so that the request stops when ctx is done.
+func (c *Client) OffersCtx(ctx context.Context, request OfferRequest) (offers hProtocol.OffersPage, err error) {
+	err = c.sendRequest(ctx, request, &offers)
 	return
 }
 
 // Operations returns stellar operations (https://www.stellar.org/developers/horizon/reference/resources/operation.html)
 // It can be used to return operations for an account, a ledger, a transaction and all operations on the network.
-func (c *Client) Operations(request OperationRequest) (ops operations.OperationsPage, err error) {
-	err = c.sendRequest(request.SetOperationsEndpoint(), &ops)
+func (c *Client) Operations(request OperationRequest) (operations.OperationsPage, error) {
+	return c.OperationsCtx(c.context(), request)
+}
+
+// OperationsCtx is Operations made with ctx, so that the request stops when ctx is done.
+func (c *Client) OperationsCtx(ctx context.Context, request OperationRequest) (ops operations.OperationsPage, err error) {
+	err = c.sendRequest(ctx, request.SetOperationsEndpoint(), &ops)
 	return
 }
 
 // OperationDetail returns a single stellar operations (https://www.stellar.org/developers/horizon/reference/resources/operation.html)
 // for a given operation id
-func (c *Client) OperationDetail(id string) (ops operations.Operation, err error) {
+func (c *Client) OperationDetail(id string) (operations.Operation, error) {
+	return c.OperationDetailCtx(c.context(), id)
+}
+
+// OperationDetailCtx is OperationDetail made with ctx, so that the request stops when ctx is done.
+func (c *Client) OperationDetailCtx(ctx context.Context, id string) (ops operations.Operation, err error) {
 	if id == "" {
 		return ops, errors.New("Invalid operation id provided")
 	}
@@ -323,7 +463,7 @@ func (c *Client) OperationDetail(id string) (ops operations.Operation, err error
 
 	var record interface{}
 
-	err = c.sendRequest(request, &record)
+	err = c.sendRequest(ctx, request, &record)
 	if err != nil {
 		return ops, errors.Wrap(err, "Sending request to horizon")
 	}
@@ -343,56 +483,91 @@ func (c *Client) OperationDetail(id string) (ops operations.Operation, err error
 
 // SubmitTransaction submits a transaction to the network. err can be either error object or horizon.Error object.
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-create.html
-func (c *Client) SubmitTransaction(transactionXdr string) (txSuccess hProtocol.TransactionSuccess,
+func (c *Client) SubmitTransaction(transactionXdr string) (hProtocol.TransactionSuccess, error) {
+	return c.SubmitTransactionCtx(c.context(), transactionXdr)
+}
+
+// SubmitTransactionCtx is SubmitTransaction made with ctx, so that the request stops when ctx is done.
+func (c *Client) SubmitTransactionCtx(ctx context.Context, transactionXdr string) (txSuccess hProtocol.TransactionSuccess,
 	err error) {
 	request := submitRequest{endpoint: "transactions", transactionXdr: transactionXdr}
-	err = c.sendRequest(request, &txSuccess)
+	err = c.sendRequest(ctx, request, &txSuccess)
 	return
 
 }
 
 // Transactions returns stellar transactions (https://www.stellar.org/developers/horizon/reference/resources/transaction.html)
 // It can be used to return transactions for an account, a ledger,and all transactions on the network.
-func (c *Client) Transactions(request TransactionRequest) (txs hProtocol.TransactionsPage, err error) {
-	err = c.sendRequest(request, &txs)
+func (c *Client) Transactions(request TransactionRequest) (hProtocol.TransactionsPage, error) {
+	return c.TransactionsCtx(c.context(), request)
+}
+
+// TransactionsCtx is Transactions made with ctx, so that the request stops when ctx is done.
+func (c *Client) TransactionsCtx(ctx context.Context, request TransactionRequest) (txs hProtocol.TransactionsPage, err error) {
+	err = c.sendRequest(ctx, request, &txs)
 	return
 }
 
 // TransactionDetail returns information about a particular transaction for a given transaction hash
 // See https://www.stellar.org/developers/horizon/reference/endpoints/transactions-single.html
-func (c *Client) TransactionDetail(txHash string) (tx hProtocol.Transaction, err error) {
+func (c *Client) TransactionDetail(txHash string) (hProtocol.Transaction, error) {
+	return c.TransactionDetailCtx(c.context(), txHash)
+}
+
+// TransactionDetailCtx is TransactionDetail made with ctx, so that the request stops when ctx is done.
+func (c *Client) TransactionDetailCtx(ctx context.Context, txHash string) (tx hProtocol.Transaction, err error) {
 	if txHash == "" {
 		return tx, errors.New("No transaction hash provided")
 	}
 
 	request := TransactionRequest{forTransactionHash: txHash}
-	err = c.sendRequest(request, &tx)
+	err = c.sendRequest(ctx, request, &tx)
 	return
 }
 
 // OrderBook returns the orderbook for an asset pair (https://www.stellar.org/developers/horizon/reference/resources/orderbook.html)
-func (c *Client) OrderBook(request OrderBookRequest) (obs hProtocol.OrderBookSummary, err error) {
-	err = c.sendRequest(request, &obs)
+func (c *Client) OrderBook(request OrderBookRequest) (hProtocol.OrderBookSummary, error) {
+	return c.OrderBookCtx(c.context(), request)
+}
+
+// OrderBookCtx is OrderBook made with ctx, so that the request stops when ctx is done.
+func (c *Client) OrderBookCtx(ctx context.Context, request OrderBookRequest) (obs hProtocol.OrderBookSummary, err error) {
+	err = c.sendRequest(ctx, request, &obs)
 	return
 }
 
 // Paths returns the available paths to make a payment. See https://www.stellar.org/developers/horizon/reference/endpoints/path-finding.html
-func (c *Client) Paths(request PathsRequest) (paths hProtocol.PathsPage, err error) {
-	err = c.sendRequest(request, &paths)
+func (c *Client) Paths(request PathsRequest) (hProtocol.PathsPage, error) {
+	return c.PathsCtx(c.context(), request)
+}
+
+// PathsCtx is Paths made with ctx, so that the request stops when ctx is done.
+func (c *Client) PathsCtx(ctx context.Context, request PathsRequest) (paths hProtocol.PathsPage, err error) {
+	err = c.sendRequest(ctx, request, &paths)
 	return
 }
 
 // Payments returns stellar account_merge, create_account, path payment and payment operations.
 // It can be used to return payments for an account, a ledger, a transaction and all payments on the network.
-func (c *Client) Payments(request OperationRequest) (ops operations.OperationsPage, err error) {
-	err = c.sendRequest(request.SetPaymentsEndpoint(), &ops)
+func (c *Client) Payments(request OperationRequest) (operations.OperationsPage, error) {
+	return c.PaymentsCtx(c.context(), request)
+}
+
+// PaymentsCtx is Payments made with ctx, so that the request stops when ctx is done.
+func (c *Client) PaymentsCtx(ctx context.Context, request OperationRequest) (ops operations.OperationsPage, err error) {
+	err = c.sendRequest(ctx, request.SetPaymentsEndpoint(), &ops)
 	return
 }
 
 // Trades returns stellar trades (https://www.stellar.org/developers/horizon/reference/resources/trade.html)
 // It can be used to return trades for an account, an offer and all trades on the network.
-func (c *Client) Trades(request TradeRequest) (tds hProtocol.TradesPage, err error) {
-	err = c.sendRequest(request, &tds)
+func (c *Client) Trades(request TradeRequest) (hProtocol.TradesPage, error) {
+	return c.TradesCtx(c.context(), request)
+}
+
+// TradesCtx is Trades made with ctx, so that the request stops when ctx is done.
+func (c *Client) TradesCtx(ctx context.Context, request TradeRequest) (tds hProtocol.TradesPage, err error) {
+	err = c.sendRequest(ctx, request, &tds)
 	return
 }
 
@@ -405,8 +580,13 @@ func (c *Client) StreamTrades(ctx context.Context, request TradeRequest, handler
 }
 
 // TradeAggregations returns stellar trade aggregations (https://www.stellar.org/developers/horizon/reference/resources/trade_aggregation.html)
-func (c *Client) TradeAggregations(request TradeAggregationRequest) (tds hProtocol.TradeAggregationsPage, err error) {
-	err = c.sendRequest(request, &tds)
+func (c *Client) TradeAggregations(request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error) {
+	return c.TradeAggregationsCtx(c.context(), request)
+}
+
+// TradeAggregationsCtx is TradeAggregations made with ctx, so that the request stops when ctx is done.
+func (c *Client) TradeAggregationsCtx(ctx context.Context, request TradeAggregationRequest) (tds hProtocol.TradeAggregationsPage, err error) {
+	err = c.sendRequest(ctx, request, &tds)
 	return
 }
 
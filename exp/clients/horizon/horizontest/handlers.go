@@ -0,0 +1,224 @@
+package horizontest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// defaultLimit is the number of records of a page when no limit is given.
+const defaultLimit = 10
+
+func (s *Server) router() http.Handler {
+	mux := chi.NewRouter()
+	mux.Get("/accounts/{id}", s.getAccount)
+	mux.Post("/transactions", s.postTransaction)
+	mux.Get("/transactions/{hash}", s.getTransaction)
+	mux.Get("/transactions", s.collection(&s.transactions))
+	mux.Get("/accounts/{id}/transactions", s.collection(&s.transactions))
+	mux.Get("/payments", s.collection(&s.payments))
+	mux.Get("/accounts/{id}/payments", s.collection(&s.payments))
+	return mux
+}
+
+func (s *Server) getAccount(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	resource, ok := s.accountResource(chi.URLParam(r, "id"))
+	s.mutex.Unlock()
+
+	if !ok {
+		problem.Render(r.Context(), w, problem.NotFound)
+		return
+	}
+	hal.Render(w, resource)
+}
+
+func (s *Server) postTransaction(w http.ResponseWriter, r *http.Request) {
+	// The horizon client sends the envelope in the query string, others in the body
+	if err := r.ParseForm(); err != nil {
+		problem.Render(r.Context(), w, problem.BadRequest)
+		return
+	}
+
+	switch resp := s.submit(r.Form.Get("tx")).(type) {
+	case problem.P:
+		problem.Render(r.Context(), w, resp)
+	default:
+		hal.Render(w, resp)
+	}
+}
+
+func (s *Server) getTransaction(w http.ResponseWriter, r *http.Request) {
+	hash := chi.URLParam(r, "hash")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, tx := range s.transactions {
+		if resource := tx.resource.(hProtocol.Transaction); resource.Hash == hash {
+			hal.Render(w, resource)
+			return
+		}
+	}
+	problem.Render(r.Context(), w, problem.NotFound)
+}
+
+// collection returns the handler of a collection, which is either rendered as a page or
+// streamed.
+func (s *Server) collection(records *[]record) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		accountID := chi.URLParam(r, "id")
+		includeFailed := query.Get("include_failed") == "true"
+
+		limit := defaultLimit
+		if query.Get("limit") != "" {
+			l, err := strconv.Atoi(query.Get("limit"))
+			if err != nil || l <= 0 || l > 200 {
+				problem.Render(r.Context(), w, problem.BadRequest)
+				return
+			}
+			limit = l
+		}
+
+		order := query.Get("order")
+		if order == "" {
+			order = "asc"
+		}
+		if order != "asc" && order != "desc" {
+			problem.Render(r.Context(), w, problem.BadRequest)
+			return
+		}
+
+		cursor, ok := s.parseCursor(query.Get("cursor"), order)
+		if !ok {
+			problem.Render(r.Context(), w, problem.BadRequest)
+			return
+		}
+
+		if r.Header.Get("Accept") == "text/event-stream" {
+			s.stream(w, r, records, accountID, includeFailed, cursor)
+			return
+		}
+
+		s.mutex.Lock()
+		var page hal.Page
+		page.FullURL = r.URL
+		page.Order = order
+		page.Limit = uint64(limit)
+		page.Cursor = query.Get("cursor")
+		for _, rec := range selectRecords(*records, accountID, includeFailed, cursor, order, limit) {
+			page.Add(rec.resource.(hal.Pageable))
+		}
+		s.mutex.Unlock()
+
+		page.FullURL.Scheme = "http"
+		page.FullURL.Host = r.Host
+		page.PopulateLinks()
+		hal.Render(w, page)
+	}
+}
+
+// parseCursor parses the cursor parameter of a collection. "now" is the paging token of the
+// last record, and an empty cursor is before the first record (after the last for desc).
+func (s *Server) parseCursor(value, order string) (int64, bool) {
+	switch value {
+	case "now":
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		return int64(s.ledger.sequence+1) << 32, true
+	case "":
+		if order == "desc" {
+			return int64(^uint64(0) >> 1), true
+		}
+		return 0, true
+	default:
+		cursor, err := strconv.ParseInt(value, 10, 64)
+		return cursor, err == nil && cursor >= 0
+	}
+}
+
+// selectRecords returns up to limit records of accountID (or any account if empty), after
+// cursor in the given order. Records of failed transactions are only returned when
+// includeFailed is true. It must be called with the mutex held.
+func selectRecords(records []record, accountID string, includeFailed bool, cursor int64, order string, limit int) []record {
+	var selected []record
+	add := func(rec record) bool {
+		if accountID != "" && !involves(rec, accountID) || rec.failed && !includeFailed {
+			return true
+		}
+		selected = append(selected, rec)
+		return len(selected) < limit
+	}
+
+	if order == "desc" {
+		for i := len(records) - 1; i >= 0; i-- {
+			if records[i].pagingToken < cursor && !add(records[i]) {
+				break
+			}
+		}
+		return selected
+	}
+
+	for _, rec := range records {
+		if rec.pagingToken > cursor && !add(rec) {
+			break
+		}
+	}
+	return selected
+}
+
+func involves(rec record, accountID string) bool {
+	for _, a := range rec.accounts {
+		if a == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+// stream sends the records after cursor as server-sent events, then the new ones as ledgers
+// close, until the client goes away or the server is closed.
+func (s *Server) stream(w http.ResponseWriter, r *http.Request, records *[]record, accountID string, includeFailed bool, cursor int64) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		problem.Render(r.Context(), w, problem.ServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, "retry: 1000\nevent: open\ndata: \"hello\"\n\n")
+	flusher.Flush()
+
+	for {
+		s.mutex.Lock()
+		selected := selectRecords(*records, accountID, includeFailed, cursor, "asc", len(*records)+1)
+		changed := s.changed
+		s.mutex.Unlock()
+
+		for _, rec := range selected {
+			data, err := json.Marshal(rec.resource)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %d\ndata: %s\n\n", rec.pagingToken, data)
+			cursor = rec.pagingToken
+		}
+		flusher.Flush()
+
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		case <-s.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package horizontest
+
+import (
+	"math"
+	"time"
+
+	"github.com/stellar/go/keypair"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	// baseFee is the minimum fee per operation, in stroops.
+	baseFee = 100
+	// minBalance is the minimum starting balance of an account: two base reserves of 0.5 XLM.
+	minBalance = 10000000
+)
+
+var nativeAsset = base.Asset{Type: "native"}
+
+// account is an account of the in-memory ledger.
+type account struct {
+	id       string
+	sequence int64
+	balances map[base.Asset]*balance
+}
+
+// balance is the balance of an account in an asset. The native balance has no limit.
+type balance struct {
+	amount xdr.Int64
+	limit  xdr.Int64
+}
+
+func newAccount(id string, sequence int64, nativeBalance xdr.Int64) *account {
+	return &account{
+		id:       id,
+		sequence: sequence,
+		balances: map[base.Asset]*balance{
+			nativeAsset: {amount: nativeBalance, limit: math.MaxInt64},
+		},
+	}
+}
+
+// ledger is the state of the accounts as of a ledger. It only knows about balances and
+// sequence numbers: there are no offers, signers other than the master key, flags or reserves
+// other than the minimum starting balance.
+type ledger struct {
+	sequence int32
+	accounts map[string]*account
+}
+
+func (l *ledger) clone() *ledger {
+	clone := &ledger{sequence: l.sequence, accounts: make(map[string]*account, len(l.accounts))}
+	for id, a := range l.accounts {
+		ac := &account{id: a.id, sequence: a.sequence, balances: make(map[base.Asset]*balance, len(a.balances))}
+		for asset, b := range a.balances {
+			bc := *b
+			ac.balances[asset] = &bc
+		}
+		clone.accounts[id] = ac
+	}
+	return clone
+}
+
+// apply applies a transaction to the next ledger, the fee and sequence number of the
+// transaction being taken even if one of its operations fails. The ledger is only changed by
+// the operations if they all succeed. The result codes are those horizon reports.
+func (l *ledger) apply(env *xdr.TransactionEnvelope, hash [32]byte, now time.Time) (xdr.TransactionResult, hProtocol.TransactionResultCodes) {
+	var result xdr.TransactionResult
+	var codes hProtocol.TransactionResultCodes
+	txResult := func(code xdr.TransactionResultCode) (xdr.TransactionResult, hProtocol.TransactionResultCodes) {
+		result.Result.Code = code
+		codes.TransactionCode = txCodes[code]
+		return result, codes
+	}
+
+	tx := env.Tx
+	source, ok := l.accounts[tx.SourceAccount.Address()]
+	switch {
+	case !ok:
+		return txResult(xdr.TransactionResultCodeTxNoAccount)
+	case len(tx.Operations) == 0:
+		return txResult(xdr.TransactionResultCodeTxMissingOperation)
+	case int64(tx.Fee) < int64(baseFee*len(tx.Operations)):
+		return txResult(xdr.TransactionResultCodeTxInsufficientFee)
+	case tx.TimeBounds != nil && now.Unix() < int64(tx.TimeBounds.MinTime):
+		return txResult(xdr.TransactionResultCodeTxTooEarly)
+	case tx.TimeBounds != nil && tx.TimeBounds.MaxTime != 0 && now.Unix() > int64(tx.TimeBounds.MaxTime):
+		return txResult(xdr.TransactionResultCodeTxTooLate)
+	case int64(tx.SeqNum) != source.sequence+1:
+		return txResult(xdr.TransactionResultCodeTxBadSeq)
+	case !signedBy(env, hash, source.id):
+		return txResult(xdr.TransactionResultCodeTxBadAuth)
+	case source.balances[nativeAsset].amount < xdr.Int64(tx.Fee):
+		return txResult(xdr.TransactionResultCodeTxInsufficientBalance)
+	}
+
+	source.balances[nativeAsset].amount -= xdr.Int64(tx.Fee)
+	source.sequence++
+	result.FeeCharged = xdr.Int64(tx.Fee)
+
+	next := l.clone()
+	next.sequence++
+	failed := false
+	opResults := make([]xdr.OperationResult, len(tx.Operations))
+	for i, op := range tx.Operations {
+		sourceID := source.id
+		if op.SourceAccount != nil {
+			sourceID = op.SourceAccount.Address()
+		}
+
+		var code string
+		opSource, ok := next.accounts[sourceID]
+		switch {
+		case !ok:
+			opResults[i], code = xdr.OperationResult{Code: xdr.OperationResultCodeOpNoAccount}, "op_no_source_account"
+		case !signedBy(env, hash, sourceID):
+			opResults[i], code = xdr.OperationResult{Code: xdr.OperationResultCodeOpBadAuth}, "op_bad_auth"
+		default:
+			opResults[i], code = next.applyOperation(opSource, op.Body)
+		}
+		codes.OperationCodes = append(codes.OperationCodes, code)
+		failed = failed || code != opSuccess
+	}
+	result.Result.Results = &opResults
+
+	if failed {
+		return txResult(xdr.TransactionResultCodeTxFailed)
+	}
+	l.accounts = next.accounts
+	return txResult(xdr.TransactionResultCodeTxSuccess)
+}
+
+func (l *ledger) applyOperation(source *account, body xdr.OperationBody) (xdr.OperationResult, string) {
+	switch body.Type {
+	case xdr.OperationTypeCreateAccount:
+		return l.createAccount(source, body.MustCreateAccountOp())
+	case xdr.OperationTypePayment:
+		return l.payment(source, body.MustPaymentOp())
+	case xdr.OperationTypeChangeTrust:
+		return l.changeTrust(source, body.MustChangeTrustOp())
+	default:
+		return xdr.OperationResult{Code: xdr.OperationResultCodeOpNotSupported}, "op_not_supported"
+	}
+}
+
+func (l *ledger) createAccount(source *account, op xdr.CreateAccountOp) (xdr.OperationResult, string) {
+	result := func(code xdr.CreateAccountResultCode, s string) (xdr.OperationResult, string) {
+		return innerResult(xdr.OperationResultTr{
+			Type:                xdr.OperationTypeCreateAccount,
+			CreateAccountResult: &xdr.CreateAccountResult{Code: code},
+		}), s
+	}
+
+	destination := op.Destination.Address()
+	switch {
+	case op.StartingBalance <= 0:
+		return result(xdr.CreateAccountResultCodeCreateAccountMalformed, "op_malformed")
+	case l.accounts[destination] != nil:
+		return result(xdr.CreateAccountResultCodeCreateAccountAlreadyExist, "op_already_exists")
+	case op.StartingBalance < minBalance:
+		return result(xdr.CreateAccountResultCodeCreateAccountLowReserve, "op_low_reserve")
+	case source.balances[nativeAsset].amount < op.StartingBalance:
+		return result(xdr.CreateAccountResultCodeCreateAccountUnderfunded, "op_underfunded")
+	}
+
+	source.balances[nativeAsset].amount -= op.StartingBalance
+	l.accounts[destination] = newAccount(destination, int64(l.sequence)<<32, op.StartingBalance)
+	return result(xdr.CreateAccountResultCodeCreateAccountSuccess, opSuccess)
+}
+
+func (l *ledger) payment(source *account, op xdr.PaymentOp) (xdr.OperationResult, string) {
+	result := func(code xdr.PaymentResultCode, s string) (xdr.OperationResult, string) {
+		return innerResult(xdr.OperationResultTr{
+			Type:          xdr.OperationTypePayment,
+			PaymentResult: &xdr.PaymentResult{Code: code},
+		}), s
+	}
+
+	asset := toBaseAsset(op.Asset)
+	destination, ok := l.accounts[op.Destination.Address()]
+	switch {
+	case op.Amount <= 0:
+		return result(xdr.PaymentResultCodePaymentMalformed, "op_malformed")
+	case !ok:
+		return result(xdr.PaymentResultCodePaymentNoDestination, "op_no_destination")
+	case asset != nativeAsset && l.accounts[asset.Issuer] == nil:
+		return result(xdr.PaymentResultCodePaymentNoIssuer, "op_no_issuer")
+	}
+
+	// Issuers send and receive their own assets without a trust line
+	from, to := source.balances[asset], destination.balances[asset]
+	if asset.Issuer != source.id {
+		if from == nil {
+			return result(xdr.PaymentResultCodePaymentSrcNoTrust, "op_src_no_trust")
+		}
+		if from.amount < op.Amount {
+			return result(xdr.PaymentResultCodePaymentUnderfunded, "op_underfunded")
+		}
+	}
+	if asset.Issuer != destination.id {
+		if to == nil {
+			return result(xdr.PaymentResultCodePaymentNoTrust, "op_no_trust")
+		}
+		if to.limit-to.amount < op.Amount {
+			return result(xdr.PaymentResultCodePaymentLineFull, "op_line_full")
+		}
+	}
+
+	if asset.Issuer != source.id {
+		from.amount -= op.Amount
+	}
+	if asset.Issuer != destination.id {
+		to.amount += op.Amount
+	}
+	return result(xdr.PaymentResultCodePaymentSuccess, opSuccess)
+}
+
+func (l *ledger) changeTrust(source *account, op xdr.ChangeTrustOp) (xdr.OperationResult, string) {
+	result := func(code xdr.ChangeTrustResultCode, s string) (xdr.OperationResult, string) {
+		return innerResult(xdr.OperationResultTr{
+			Type:              xdr.OperationTypeChangeTrust,
+			ChangeTrustResult: &xdr.ChangeTrustResult{Code: code},
+		}), s
+	}
+
+	asset := toBaseAsset(op.Line)
+	line := source.balances[asset]
+	switch {
+	case asset == nativeAsset || op.Limit < 0:
+		return result(xdr.ChangeTrustResultCodeChangeTrustMalformed, "op_malformed")
+	case asset.Issuer == source.id:
+		return result(xdr.ChangeTrustResultCodeChangeTrustSelfNotAllowed, "op_self_not_allowed")
+	case l.accounts[asset.Issuer] == nil:
+		return result(xdr.ChangeTrustResultCodeChangeTrustNoIssuer, "op_no_issuer")
+	case line != nil && line.amount > op.Limit:
+		return result(xdr.ChangeTrustResultCodeChangeTrustInvalidLimit, "op_invalid_limit")
+	}
+
+	switch {
+	case op.Limit == 0:
+		delete(source.balances, asset)
+	case line == nil:
+		source.balances[asset] = &balance{limit: op.Limit}
+	default:
+		line.limit = op.Limit
+	}
+	return result(xdr.ChangeTrustResultCodeChangeTrustSuccess, opSuccess)
+}
+
+const opSuccess = "op_success"
+
+var txCodes = map[xdr.TransactionResultCode]string{
+	xdr.TransactionResultCodeTxSuccess:             "tx_success",
+	xdr.TransactionResultCodeTxFailed:              "tx_failed",
+	xdr.TransactionResultCodeTxTooEarly:            "tx_too_early",
+	xdr.TransactionResultCodeTxTooLate:             "tx_too_late",
+	xdr.TransactionResultCodeTxMissingOperation:    "tx_missing_operation",
+	xdr.TransactionResultCodeTxBadSeq:              "tx_bad_seq",
+	xdr.TransactionResultCodeTxBadAuth:             "tx_bad_auth",
+	xdr.TransactionResultCodeTxInsufficientBalance: "tx_insufficient_balance",
+	xdr.TransactionResultCodeTxNoAccount:           "tx_no_source_account",
+	xdr.TransactionResultCodeTxInsufficientFee:     "tx_insufficient_fee",
+}
+
+func innerResult(tr xdr.OperationResultTr) xdr.OperationResult {
+	return xdr.OperationResult{Code: xdr.OperationResultCodeOpInner, Tr: &tr}
+}
+
+// signedBy returns true if the envelope holds a valid signature of address' master key.
+func signedBy(env *xdr.TransactionEnvelope, hash [32]byte, address string) bool {
+	kp, err := keypair.Parse(address)
+	if err != nil {
+		return false
+	}
+
+	hint := kp.Hint()
+	for _, sig := range env.Signatures {
+		if sig.Hint == xdr.SignatureHint(hint) && kp.Verify(hash[:], sig.Signature) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func toBaseAsset(asset xdr.Asset) base.Asset {
+	var result base.Asset
+	asset.MustExtract(&result.Type, &result.Code, &result.Issuer)
+	return result
+}
@@ -0,0 +1,346 @@
+/*
+Package horizontest provides an in-process fake horizon server, so that services talking to
+horizon over HTTP can be tested end to end without a network.
+
+The server keeps an in-memory ledger of accounts, their native and credit balances and their
+sequence numbers. Submitted transactions are checked (source account, sequence number, fee,
+time bounds and master key signatures) and their create account, payment and change trust
+operations applied; other operations fail with op_not_supported. Each transaction that makes it
+into the ledger, failed or not, closes a ledger. Like horizon's, the collections list failed
+transactions and their operations only with include_failed=true.
+
+It serves:
+
+	GET  /accounts/{id}
+	POST /transactions
+	GET  /transactions/{hash}
+	GET  /transactions, /accounts/{id}/transactions
+	GET  /payments, /accounts/{id}/payments
+
+The collections support the cursor, order and limit parameters, and are streamed as server-sent
+events when requested with "Accept: text/event-stream".
+
+	server := horizontest.NewServer(network.TestNetworkPassphrase)
+	defer server.Close()
+	server.CreateAccount(kp.Address(), "1000")
+	client := server.Client()
+*/
+package horizontest
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	stdtest "net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/amount"
+	horizonclient "github.com/stellar/go/exp/clients/horizon"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+)
+
+// Server is a fake horizon server. Create it with NewServer, and close it with Close.
+type Server struct {
+	*stdtest.Server
+	NetworkPassphrase string
+
+	mutex        sync.Mutex
+	ledger       *ledger
+	transactions []record
+	payments     []record
+	// changed is closed, and replaced, when a ledger closes
+	changed chan struct{}
+	done    chan struct{}
+}
+
+// record is a resource of a collection.
+type record struct {
+	pagingToken int64
+	// accounts are the accounts involved, for the collections of an account
+	accounts []string
+	// failed is true for failed transactions and their operations
+	failed   bool
+	resource interface{}
+}
+
+// NewServer starts a fake horizon server for the network with the given passphrase. Its
+// ledger has no accounts: add some with CreateAccount.
+func NewServer(networkPassphrase string) *Server {
+	s := &Server{
+		NetworkPassphrase: networkPassphrase,
+		ledger:            &ledger{sequence: 1, accounts: map[string]*account{}},
+		changed:           make(chan struct{}),
+		done:              make(chan struct{}),
+	}
+	s.Server = stdtest.NewServer(s.router())
+	return s
+}
+
+// Close ends the streams in progress and shuts the server down.
+func (s *Server) Close() {
+	close(s.done)
+	s.Server.Close()
+}
+
+// Client returns a horizon client for the server.
+func (s *Server) Client() *horizonclient.Client {
+	return &horizonclient.Client{HorizonURL: s.URL, HTTP: s.Server.Client()}
+}
+
+// CreateAccount adds an account holding nativeBalance lumens to the ledger, without a
+// transaction. Use it to set up the accounts a test starts with.
+func (s *Server) CreateAccount(address, nativeBalance string) error {
+	balance, err := amount.Parse(nativeBalance)
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse balance")
+	}
+	var accountID xdr.AccountId
+	if err = accountID.SetAddress(address); err != nil {
+		return errors.Wrap(err, "Failed to parse address")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.ledger.accounts[address] != nil {
+		return errors.Errorf("Account %s already exists", address)
+	}
+	s.ledger.accounts[address] = newAccount(address, int64(s.ledger.sequence)<<32, balance)
+	return nil
+}
+
+// LedgerSequence returns the sequence of the last closed ledger.
+func (s *Server) LedgerSequence() int32 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.ledger.sequence
+}
+
+// accountResource returns the horizon resource of an account, with the mutex held.
+func (s *Server) accountResource(id string) (hProtocol.Account, bool) {
+	a, ok := s.ledger.accounts[id]
+	if !ok {
+		return hProtocol.Account{}, false
+	}
+
+	resource := hProtocol.Account{
+		HistoryAccount: hProtocol.HistoryAccount{ID: a.id, PT: a.id, AccountID: a.id},
+		Sequence:       strconv.FormatInt(a.sequence, 10),
+		Signers:        []hProtocol.Signer{{Weight: 1, Key: a.id, Type: "ed25519_public_key"}},
+		Data:           map[string]string{},
+	}
+	resource.SubentryCount = int32(len(a.balances) - 1)
+
+	// Like horizon, list the trust lines first and the native balance last. Trust lines are
+	// sorted by code, then issuer, so the order doesn't depend on map iteration.
+	var assets []base.Asset
+	for asset := range a.balances {
+		if asset != nativeAsset {
+			assets = append(assets, asset)
+		}
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		if assets[i].Code != assets[j].Code {
+			return assets[i].Code < assets[j].Code
+		}
+		return assets[i].Issuer < assets[j].Issuer
+	})
+	for _, asset := range assets {
+		b := a.balances[asset]
+		resource.Balances = append(resource.Balances, hProtocol.Balance{
+			Balance:            amount.String(b.amount),
+			Limit:              amount.String(b.limit),
+			BuyingLiabilities:  "0.0000000",
+			SellingLiabilities: "0.0000000",
+			Asset:              asset,
+		})
+	}
+	resource.Balances = append(resource.Balances, hProtocol.Balance{
+		Balance:            amount.String(a.balances[nativeAsset].amount),
+		BuyingLiabilities:  "0.0000000",
+		SellingLiabilities: "0.0000000",
+		Asset:              nativeAsset,
+	})
+	return resource, true
+}
+
+// submit applies a transaction envelope to the ledger and returns the response to render:
+// a hProtocol.TransactionSuccess or a problem.P.
+func (s *Server) submit(envelopeXDR string) interface{} {
+	var env xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXDR, &env); err != nil {
+		return problem.P{
+			Type:   "transaction_malformed",
+			Title:  "Transaction Malformed",
+			Status: 400,
+			Detail: "Horizon could not decode the transaction envelope in this request.",
+			Extras: map[string]interface{}{"envelope_xdr": envelopeXDR},
+		}
+	}
+	hash, err := network.HashTransaction(&env.Tx, s.NetworkPassphrase)
+	if err != nil {
+		return problem.ServerError
+	}
+	hexHash := hex.EncodeToString(hash[:])
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := time.Now().UTC()
+	result, codes := s.ledger.apply(&env, hash, now)
+	resultXDR, err := xdr.MarshalBase64(result)
+	if err != nil {
+		return problem.ServerError
+	}
+
+	failed := problem.P{
+		Type:   "transaction_failed",
+		Title:  "Transaction Failed",
+		Status: 400,
+		Detail: "The transaction failed when submitted to the stellar network.",
+		Extras: map[string]interface{}{
+			"envelope_xdr": envelopeXDR,
+			"result_xdr":   resultXDR,
+			"result_codes": codes,
+		},
+	}
+	code := result.Result.Code
+	if code != xdr.TransactionResultCodeTxSuccess && code != xdr.TransactionResultCodeTxFailed {
+		// the transaction didn't make it into the ledger
+		return failed
+	}
+
+	s.ledger.sequence++
+	meta := xdr.TransactionMeta{
+		V:  1,
+		V1: &xdr.TransactionMetaV1{Operations: make([]xdr.OperationMeta, len(env.Tx.Operations))},
+	}
+	metaXDR, err := xdr.MarshalBase64(meta)
+	if err != nil {
+		return problem.ServerError
+	}
+
+	successful := code == xdr.TransactionResultCodeTxSuccess
+	s.record(&env, hexHash, resultXDR, metaXDR, now, successful)
+	close(s.changed)
+	s.changed = make(chan struct{})
+	if !successful {
+		return failed
+	}
+
+	var resp hProtocol.TransactionSuccess
+	resp.Links.Transaction.Href = fmt.Sprintf("%s/transactions/%s", s.URL, hexHash)
+	resp.Hash = hexHash
+	resp.Ledger = s.ledger.sequence
+	resp.Env = envelopeXDR
+	resp.Result = resultXDR
+	resp.Meta = metaXDR
+	return resp
+}
+
+// record adds the resources of a transaction that made it into the ledger to the collections,
+// with the mutex held. Paging tokens are built like horizon's: the ledger sequence, the index of the
+// transaction in the ledger (always the first) and the index of the operation.
+func (s *Server) record(env *xdr.TransactionEnvelope, hash, resultXDR, metaXDR string, closedAt time.Time, successful bool) {
+	tx := env.Tx
+	source := tx.SourceAccount.Address()
+	txToken := int64(s.ledger.sequence)<<32 | 1<<12
+
+	signatures := make([]string, len(env.Signatures))
+	for i, sig := range env.Signatures {
+		signatures[i] = base64.StdEncoding.EncodeToString(sig.Signature)
+	}
+
+	txResource := hProtocol.Transaction{
+		ID:              hash,
+		PT:              strconv.FormatInt(txToken, 10),
+		Successful:      successful,
+		Hash:            hash,
+		Ledger:          s.ledger.sequence,
+		LedgerCloseTime: closedAt,
+		Account:         source,
+		AccountSequence: strconv.FormatInt(int64(tx.SeqNum), 10),
+		FeePaid:         int32(tx.Fee),
+		OperationCount:  int32(len(tx.Operations)),
+		ResultXdr:       resultXDR,
+		ResultMetaXdr:   metaXDR,
+		MemoType:        memoType(tx.Memo),
+		Signatures:      signatures,
+	}
+	txResource.EnvelopeXdr, _ = xdr.MarshalBase64(env)
+	txResource.Links.Self.Href = fmt.Sprintf("%s/transactions/%s", s.URL, hash)
+	accounts := []string{source}
+
+	for i, op := range tx.Operations {
+		opSource := source
+		if op.SourceAccount != nil {
+			opSource = op.SourceAccount.Address()
+		}
+		accounts = append(accounts, opSource)
+
+		pagingToken := txToken + int64(i) + 1
+		opBase := operations.Base{
+			ID:                    strconv.FormatInt(pagingToken, 10),
+			PT:                    strconv.FormatInt(pagingToken, 10),
+			TransactionSuccessful: successful,
+			SourceAccount:         opSource,
+			Type:                  operations.TypeNames[op.Body.Type],
+			TypeI:                 int32(op.Body.Type),
+			LedgerCloseTime:       closedAt,
+			TransactionHash:       hash,
+		}
+
+		var payment record
+		switch op.Body.Type {
+		case xdr.OperationTypeCreateAccount:
+			body := op.Body.MustCreateAccountOp()
+			destination := body.Destination.Address()
+			payment = record{pagingToken, []string{opSource, destination}, !successful, operations.CreateAccount{
+				Base:            opBase,
+				StartingBalance: amount.String(body.StartingBalance),
+				Funder:          opSource,
+				Account:         destination,
+			}}
+			accounts = append(accounts, destination)
+		case xdr.OperationTypePayment:
+			body := op.Body.MustPaymentOp()
+			destination := body.Destination.Address()
+			payment = record{pagingToken, []string{opSource, destination}, !successful, operations.Payment{
+				Base:   opBase,
+				Asset:  toBaseAsset(body.Asset),
+				From:   opSource,
+				To:     destination,
+				Amount: amount.String(body.Amount),
+			}}
+			accounts = append(accounts, destination)
+		default:
+			continue
+		}
+		s.payments = append(s.payments, payment)
+	}
+
+	s.transactions = append(s.transactions, record{txToken, accounts, !successful, txResource})
+}
+
+func memoType(memo xdr.Memo) string {
+	switch memo.Type {
+	case xdr.MemoTypeMemoText:
+		return "text"
+	case xdr.MemoTypeMemoId:
+		return "id"
+	case xdr.MemoTypeMemoHash:
+		return "hash"
+	case xdr.MemoTypeMemoReturn:
+		return "return"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,292 @@
+package horizontest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	horizonclient "github.com/stellar/go/exp/clients/horizon"
+	"github.com/stellar/go/exp/txnbuild"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newKeypair(seed string) *keypair.Full {
+	kp, _ := keypair.Parse(seed)
+	return kp.(*keypair.Full)
+}
+
+var (
+	kp0 = newKeypair("SBPQUZ6G4FZNWFHKUWC5BEYWF6R52E3SEP7R3GWYSM2XTKGF5LNTWW4R")
+	kp1 = newKeypair("SBMSVD4KKELKGZXHBUQTIROWUAPQASDX7KEJITARP4VMZ6KLUHOGPTYW")
+	kp2 = newKeypair("SBZVMB74Z76QZ3ZOY7UTDFYKMEGKW5XFJEB6PFKBF4UYSSWHG4EDH7PY")
+)
+
+// submit builds a transaction of ops from source, signs it with signers and submits it.
+func submit(t *testing.T, client *horizonclient.Client, source *keypair.Full, ops []txnbuild.Operation, signers ...*keypair.Full) error {
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: source.Address()})
+	require.NoError(t, err)
+
+	tx := txnbuild.Transaction{
+		SourceAccount: &account,
+		Operations:    ops,
+		Network:       network.TestNetworkPassphrase,
+		Timebounds:    txnbuild.NewTimeout(300),
+	}
+	require.NoError(t, tx.Build())
+	if len(signers) == 0 {
+		signers = []*keypair.Full{source}
+	}
+	require.NoError(t, tx.Sign(signers...))
+	txe, err := tx.Base64()
+	require.NoError(t, err)
+
+	_, err = client.SubmitTransaction(txe)
+	return err
+}
+
+func resultCodes(t *testing.T, err error) (string, []string) {
+	herr, ok := err.(*horizonclient.Error)
+	require.True(t, ok, "expected a horizon error, got %v", err)
+	result, rerr := herr.TransactionResult()
+	require.NoError(t, rerr)
+
+	var opCodes []string
+	for _, op := range result.Operations {
+		opCodes = append(opCodes, op.Code)
+	}
+	return result.Code, opCodes
+}
+
+func TestAccounts(t *testing.T) {
+	server := NewServer(network.TestNetworkPassphrase)
+	defer server.Close()
+	client := server.Client()
+
+	require.NoError(t, server.CreateAccount(kp0.Address(), "1000"))
+	assert.Error(t, server.CreateAccount(kp0.Address(), "1000"))
+	assert.Error(t, server.CreateAccount("GABC", "1000"))
+
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: kp0.Address()})
+	require.NoError(t, err)
+	assert.Equal(t, kp0.Address(), account.AccountID)
+	assert.Equal(t, "4294967296", account.Sequence)
+	balance, err := account.GetNativeBalance()
+	require.NoError(t, err)
+	assert.Equal(t, "1000.0000000", balance)
+
+	_, err = client.AccountDetail(horizonclient.AccountRequest{AccountID: kp1.Address()})
+	if herr, ok := err.(*horizonclient.Error); assert.True(t, ok) {
+		assert.Equal(t, 404, herr.Problem.Status)
+	}
+}
+
+func TestAccountBalancesOrder(t *testing.T) {
+	server := NewServer(network.TestNetworkPassphrase)
+	defer server.Close()
+	client := server.Client()
+	require.NoError(t, server.CreateAccount(kp0.Address(), "1000"))
+
+	kp1Account := &txnbuild.SimpleAccount{AccountID: kp1.Address()}
+	require.NoError(t, submit(t, client, kp0, []txnbuild.Operation{
+		&txnbuild.CreateAccount{Destination: kp1.Address(), Amount: "100"},
+		&txnbuild.CreateAccount{Destination: kp2.Address(), Amount: "100"},
+		&txnbuild.ChangeTrust{Line: txnbuild.CreditAsset{Code: "USD", Issuer: kp2.Address()}, Limit: "10", SourceAccount: kp1Account},
+		&txnbuild.ChangeTrust{Line: txnbuild.CreditAsset{Code: "USD", Issuer: kp0.Address()}, Limit: "10", SourceAccount: kp1Account},
+		&txnbuild.ChangeTrust{Line: txnbuild.CreditAsset{Code: "EUR", Issuer: kp2.Address()}, Limit: "10", SourceAccount: kp1Account},
+	}, kp0, kp1))
+
+	// USD trust lines are sorted by issuer
+	first, second := kp0.Address(), kp2.Address()
+	if second < first {
+		first, second = second, first
+	}
+	expected := []string{"EUR:" + kp2.Address(), "USD:" + first, "USD:" + second, "native:"}
+	for i := 0; i < 5; i++ {
+		account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: kp1.Address()})
+		require.NoError(t, err)
+
+		var balances []string
+		for _, b := range account.Balances {
+			if b.Type == "native" {
+				balances = append(balances, "native:")
+			} else {
+				balances = append(balances, b.Code+":"+b.Issuer)
+			}
+		}
+		assert.Equal(t, expected, balances)
+	}
+}
+
+func TestPayments(t *testing.T) {
+	server := NewServer(network.TestNetworkPassphrase)
+	defer server.Close()
+	client := server.Client()
+	require.NoError(t, server.CreateAccount(kp0.Address(), "1000"))
+
+	// kp0 creates kp1 and kp2, kp1 trusts kp0's USD, which kp0 sends to it
+	usd := txnbuild.CreditAsset{Code: "USD", Issuer: kp0.Address()}
+	err := submit(t, client, kp0, []txnbuild.Operation{
+		&txnbuild.CreateAccount{Destination: kp1.Address(), Amount: "100"},
+		&txnbuild.CreateAccount{Destination: kp2.Address(), Amount: "100"},
+		&txnbuild.ChangeTrust{Line: usd, Limit: "500", SourceAccount: &txnbuild.SimpleAccount{AccountID: kp1.Address()}},
+		&txnbuild.Payment{Destination: kp1.Address(), Amount: "50", Asset: usd},
+	}, kp0, kp1)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), server.LedgerSequence())
+
+	account, err := client.AccountDetail(horizonclient.AccountRequest{AccountID: kp1.Address()})
+	require.NoError(t, err)
+	assert.Equal(t, "8589934592", account.Sequence)
+	assert.Equal(t, "50.0000000", account.GetCreditBalance("USD", kp0.Address()))
+	account, err = client.AccountDetail(horizonclient.AccountRequest{AccountID: kp0.Address()})
+	require.NoError(t, err)
+	balance, _ := account.GetNativeBalance()
+	assert.Equal(t, "799.9999600", balance)
+
+	// kp2 doesn't trust USD: the transaction fails as a whole, but its fee is charged
+	err = submit(t, client, kp1, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: kp2.Address(), Amount: "10", Asset: txnbuild.NativeAsset{}},
+		&txnbuild.Payment{Destination: kp2.Address(), Amount: "10", Asset: usd},
+	})
+	code, opCodes := resultCodes(t, err)
+	assert.Equal(t, "tx_failed", code)
+	assert.Equal(t, []string{"op_success", "op_no_trust"}, opCodes)
+	account, err = client.AccountDetail(horizonclient.AccountRequest{AccountID: kp1.Address()})
+	require.NoError(t, err)
+	balance, _ = account.GetNativeBalance()
+	assert.Equal(t, "99.9999800", balance)
+	assert.Equal(t, "8589934593", account.Sequence)
+
+	err = submit(t, client, kp1, []txnbuild.Operation{
+		&txnbuild.Payment{Destination: kp2.Address(), Amount: "1000", Asset: txnbuild.NativeAsset{}},
+		&txnbuild.Payment{Destination: kp0.Address(), Amount: "50", Asset: usd},
+	})
+	_, opCodes = resultCodes(t, err)
+	assert.Equal(t, []string{"op_underfunded", "op_success"}, opCodes)
+
+	page, err := client.Payments(horizonclient.OperationRequest{ForAccount: kp1.Address()})
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 2)
+	assert.IsType(t, operations.CreateAccount{}, page.Embedded.Records[0])
+	payment := page.Embedded.Records[1].(operations.Payment)
+	assert.Equal(t, "50.0000000", payment.Amount)
+	assert.Equal(t, "USD", payment.Code)
+
+	page, err = client.NextOperationsPage(page)
+	require.NoError(t, err)
+	assert.Empty(t, page.Embedded.Records)
+
+	// failed transactions are listed only when requested, like their payments
+	txs, err := client.Transactions(horizonclient.TransactionRequest{ForAccount: kp1.Address()})
+	require.NoError(t, err)
+	require.Len(t, txs.Embedded.Records, 1)
+	assert.True(t, txs.Embedded.Records[0].Successful)
+
+	txs, err = client.Transactions(horizonclient.TransactionRequest{ForAccount: kp1.Address(), IncludeFailed: true})
+	require.NoError(t, err)
+	require.Len(t, txs.Embedded.Records, 3)
+	for _, tx := range txs.Embedded.Records[1:] {
+		assert.False(t, tx.Successful)
+		assert.Equal(t, kp1.Address(), tx.Account)
+	}
+
+	page, err = client.Payments(horizonclient.OperationRequest{ForAccount: kp1.Address(), IncludeFailed: true})
+	require.NoError(t, err)
+	assert.Len(t, page.Embedded.Records, 6)
+}
+
+func TestTransactionChecks(t *testing.T) {
+	server := NewServer(network.TestNetworkPassphrase)
+	defer server.Close()
+	client := server.Client()
+	require.NoError(t, server.CreateAccount(kp0.Address(), "1000"))
+
+	ops := []txnbuild.Operation{&txnbuild.Payment{Destination: kp0.Address(), Amount: "1", Asset: txnbuild.NativeAsset{}}}
+
+	// wrong signer
+	code, _ := resultCodes(t, submit(t, client, kp0, ops, kp1))
+	assert.Equal(t, "tx_bad_auth", code)
+
+	// unknown source account
+	account := txnbuild.SimpleAccount{AccountID: kp1.Address()}
+	tx := txnbuild.Transaction{SourceAccount: &account, Operations: ops, Network: network.TestNetworkPassphrase}
+	require.NoError(t, tx.Build())
+	require.NoError(t, tx.Sign(kp1))
+	txe, err := tx.Base64()
+	require.NoError(t, err)
+	_, err = client.SubmitTransaction(txe)
+	code, _ = resultCodes(t, err)
+	assert.Equal(t, "tx_no_source_account", code)
+
+	// bad sequence number
+	account = txnbuild.SimpleAccount{AccountID: kp0.Address(), Sequence: 10}
+	tx = txnbuild.Transaction{SourceAccount: &account, Operations: ops, Network: network.TestNetworkPassphrase}
+	require.NoError(t, tx.Build())
+	require.NoError(t, tx.Sign(kp0))
+	txe, err = tx.Base64()
+	require.NoError(t, err)
+	_, err = client.SubmitTransaction(txe)
+	code, _ = resultCodes(t, err)
+	assert.Equal(t, "tx_bad_seq", code)
+
+	// none of them consumed a sequence number
+	assert.NoError(t, submit(t, client, kp0, ops))
+	assert.Equal(t, int32(2), server.LedgerSequence())
+
+	_, err = client.SubmitTransaction("kaboom")
+	if herr, ok := err.(*horizonclient.Error); assert.True(t, ok) {
+		assert.Equal(t, "https://stellar.org/horizon-errors/transaction_malformed", herr.Problem.Type)
+	}
+}
+
+func TestStreamPayments(t *testing.T) {
+	server := NewServer(network.TestNetworkPassphrase)
+	defer server.Close()
+	client := server.Client()
+	require.NoError(t, server.CreateAccount(kp0.Address(), "1000"))
+	require.NoError(t, submit(t, client, kp0, []txnbuild.Operation{
+		&txnbuild.CreateAccount{Destination: kp1.Address(), Amount: "100"},
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Start after the create account, so payments submitted before the stream is connected
+	// are streamed too
+	page, err := client.Payments(horizonclient.OperationRequest{ForAccount: kp1.Address()})
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 1)
+	cursor := page.Embedded.Records[0].PagingToken()
+
+	received := make(chan operations.Operation)
+	streamErr := make(chan error)
+	go func() {
+		// The stream has its own client, the client isn't safe for concurrent use
+		request := horizonclient.OperationRequest{ForAccount: kp1.Address(), Cursor: cursor}
+		streamErr <- server.Client().StreamPayments(ctx, request, func(op operations.Operation) {
+			received <- op
+		})
+	}()
+
+	for _, amount := range []string{"1", "2"} {
+		require.NoError(t, submit(t, client, kp0, []txnbuild.Operation{
+			&txnbuild.Payment{Destination: kp1.Address(), Amount: amount, Asset: txnbuild.NativeAsset{}},
+		}))
+	}
+
+	for _, amount := range []string{"1.0000000", "2.0000000"} {
+		select {
+		case op := <-received:
+			assert.Equal(t, amount, op.(operations.Payment).Amount)
+		case <-ctx.Done():
+			t.Fatal("payment not streamed")
+		}
+	}
+
+	cancel()
+	assert.NoError(t, <-streamErr)
+}
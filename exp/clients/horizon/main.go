@@ -98,37 +98,63 @@ type HTTP interface {
 	PostForm(url string, data url.Values) (resp *http.Response, err error)
 }
 
-// Client struct contains data for creating an horizon client that connects to the stellar network
+// Client struct contains data for creating an horizon client that connects to the stellar network.
+// MaxRetries is the number of times a request is retried after a network error or a rate limited
+// (429) or server error (5xx) response, waiting RetryBackoff (doubled on each retry) in between.
+// Streams count consecutive failures to connect or stay connected against MaxRetries, and resume
+// from the last event received. MaxRetries is 0 by default, which disables retries.
 type Client struct {
 	HorizonURL     string
 	HTTP           HTTP
 	horizonTimeOut time.Duration
 	AppName        string
 	AppVersion     string
+	MaxRetries     uint
+	RetryBackoff   time.Duration
+	ctx            context.Context
 }
 
 // ClientInterface contains methods implemented by the horizon client
 type ClientInterface interface {
 	AccountDetail(request AccountRequest) (hProtocol.Account, error)
+	AccountDetailCtx(ctx context.Context, request AccountRequest) (hProtocol.Account, error)
 	AccountData(request AccountRequest) (hProtocol.AccountData, error)
+	AccountDataCtx(ctx context.Context, request AccountRequest) (hProtocol.AccountData, error)
 	Effects(request EffectRequest) (hProtocol.EffectsPage, error)
+	EffectsCtx(ctx context.Context, request EffectRequest) (hProtocol.EffectsPage, error)
 	Assets(request AssetRequest) (hProtocol.AssetsPage, error)
+	AssetsCtx(ctx context.Context, request AssetRequest) (hProtocol.AssetsPage, error)
 	Ledgers(request LedgerRequest) (hProtocol.LedgersPage, error)
+	LedgersCtx(ctx context.Context, request LedgerRequest) (hProtocol.LedgersPage, error)
 	LedgerDetail(sequence uint32) (hProtocol.Ledger, error)
+	LedgerDetailCtx(ctx context.Context, sequence uint32) (hProtocol.Ledger, error)
 	Metrics() (hProtocol.Metrics, error)
+	MetricsCtx(ctx context.Context) (hProtocol.Metrics, error)
 	Stream(ctx context.Context, request StreamRequest, handler func(interface{})) error
 	FeeStats() (hProtocol.FeeStats, error)
+	FeeStatsCtx(ctx context.Context) (hProtocol.FeeStats, error)
 	Offers(request OfferRequest) (hProtocol.OffersPage, error)
+	OffersCtx(ctx context.Context, request OfferRequest) (hProtocol.OffersPage, error)
 	Operations(request OperationRequest) (operations.OperationsPage, error)
+	OperationsCtx(ctx context.Context, request OperationRequest) (operations.OperationsPage, error)
 	OperationDetail(id string) (operations.Operation, error)
+	OperationDetailCtx(ctx context.Context, id string) (operations.Operation, error)
 	SubmitTransaction(transactionXdr string) (hProtocol.TransactionSuccess, error)
+	SubmitTransactionCtx(ctx context.Context, transactionXdr string) (hProtocol.TransactionSuccess, error)
 	Transactions(request TransactionRequest) (hProtocol.TransactionsPage, error)
+	TransactionsCtx(ctx context.Context, request TransactionRequest) (hProtocol.TransactionsPage, error)
 	TransactionDetail(txHash string) (hProtocol.Transaction, error)
+	TransactionDetailCtx(ctx context.Context, txHash string) (hProtocol.Transaction, error)
 	OrderBook(request OrderBookRequest) (hProtocol.OrderBookSummary, error)
+	OrderBookCtx(ctx context.Context, request OrderBookRequest) (hProtocol.OrderBookSummary, error)
 	Paths(request PathsRequest) (hProtocol.PathsPage, error)
+	PathsCtx(ctx context.Context, request PathsRequest) (hProtocol.PathsPage, error)
 	Payments(request OperationRequest) (operations.OperationsPage, error)
+	PaymentsCtx(ctx context.Context, request OperationRequest) (operations.OperationsPage, error)
 	TradeAggregations(request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error)
+	TradeAggregationsCtx(ctx context.Context, request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error)
 	Trades(request TradeRequest) (hProtocol.TradesPage, error)
+	TradesCtx(ctx context.Context, request TradeRequest) (hProtocol.TradesPage, error)
 	StreamTransactions(ctx context.Context, request TransactionRequest, handler TransactionHandler) error
 	StreamTrades(ctx context.Context, request TradeRequest, handler TradeHandler) error
 	StreamEffects(ctx context.Context, request EffectRequest, handler EffectHandler) error
@@ -137,6 +163,39 @@ type ClientInterface interface {
 	StreamOffers(ctx context.Context, request OfferRequest, handler OfferHandler) error
 	StreamLedgers(ctx context.Context, request LedgerRequest, handler LedgerHandler) error
 	StreamOrderBooks(ctx context.Context, request OrderBookRequest, handler OrderBookHandler) error
+	NextEffectsPage(page hProtocol.EffectsPage) (hProtocol.EffectsPage, error)
+	NextEffectsPageCtx(ctx context.Context, page hProtocol.EffectsPage) (hProtocol.EffectsPage, error)
+	PrevEffectsPage(page hProtocol.EffectsPage) (hProtocol.EffectsPage, error)
+	PrevEffectsPageCtx(ctx context.Context, page hProtocol.EffectsPage) (hProtocol.EffectsPage, error)
+	NextAssetsPage(page hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	NextAssetsPageCtx(ctx context.Context, page hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	PrevAssetsPage(page hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	PrevAssetsPageCtx(ctx context.Context, page hProtocol.AssetsPage) (hProtocol.AssetsPage, error)
+	NextLedgersPage(page hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	NextLedgersPageCtx(ctx context.Context, page hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	PrevLedgersPage(page hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	PrevLedgersPageCtx(ctx context.Context, page hProtocol.LedgersPage) (hProtocol.LedgersPage, error)
+	NextOffersPage(page hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	NextOffersPageCtx(ctx context.Context, page hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	PrevOffersPage(page hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	PrevOffersPageCtx(ctx context.Context, page hProtocol.OffersPage) (hProtocol.OffersPage, error)
+	NextOperationsPage(page operations.OperationsPage) (operations.OperationsPage, error)
+	NextOperationsPageCtx(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error)
+	PrevOperationsPage(page operations.OperationsPage) (operations.OperationsPage, error)
+	PrevOperationsPageCtx(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error)
+	NextTransactionsPage(page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	NextTransactionsPageCtx(ctx context.Context, page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	PrevTransactionsPage(page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	PrevTransactionsPageCtx(ctx context.Context, page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error)
+	NextTradesPage(page hProtocol.TradesPage) (hProtocol.TradesPage, error)
+	NextTradesPageCtx(ctx context.Context, page hProtocol.TradesPage) (hProtocol.TradesPage, error)
+	PrevTradesPage(page hProtocol.TradesPage) (hProtocol.TradesPage, error)
+	PrevTradesPageCtx(ctx context.Context, page hProtocol.TradesPage) (hProtocol.TradesPage, error)
+	NextTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	NextTradeAggregationsPageCtx(ctx context.Context, page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	PrevTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	PrevTradeAggregationsPageCtx(ctx context.Context, page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error)
+	Iterate(ctx context.Context, request HorizonRequest, handler RecordHandler) error
 }
 
 // DefaultTestNetClient is a default client to connect to test network
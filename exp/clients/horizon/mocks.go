@@ -19,24 +19,48 @@ func (m *MockClient) AccountDetail(request AccountRequest) (hProtocol.Account, e
 	return a.Get(0).(hProtocol.Account), a.Error(1)
 }
 
+// AccountDetailCtx is a mocking method
+func (m *MockClient) AccountDetailCtx(ctx context.Context, request AccountRequest) (hProtocol.Account, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.Account), a.Error(1)
+}
+
 // AccountData is a mocking method
 func (m *MockClient) AccountData(request AccountRequest) (hProtocol.AccountData, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.AccountData), a.Error(1)
 }
 
+// AccountDataCtx is a mocking method
+func (m *MockClient) AccountDataCtx(ctx context.Context, request AccountRequest) (hProtocol.AccountData, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.AccountData), a.Error(1)
+}
+
 // Effects is a mocking method
 func (m *MockClient) Effects(request EffectRequest) (hProtocol.EffectsPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.EffectsPage), a.Error(1)
 }
 
+// EffectsCtx is a mocking method
+func (m *MockClient) EffectsCtx(ctx context.Context, request EffectRequest) (hProtocol.EffectsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.EffectsPage), a.Error(1)
+}
+
 // Assets is a mocking method
 func (m *MockClient) Assets(request AssetRequest) (hProtocol.AssetsPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
 }
 
+// AssetsCtx is a mocking method
+func (m *MockClient) AssetsCtx(ctx context.Context, request AssetRequest) (hProtocol.AssetsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
 // Stream is a mocking method
 func (m *MockClient) Stream(ctx context.Context,
 	request StreamRequest,
@@ -52,90 +76,180 @@ func (m *MockClient) Ledgers(request LedgerRequest) (hProtocol.LedgersPage, erro
 	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
 }
 
+// LedgersCtx is a mocking method
+func (m *MockClient) LedgersCtx(ctx context.Context, request LedgerRequest) (hProtocol.LedgersPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
 // LedgerDetail is a mocking method
 func (m *MockClient) LedgerDetail(sequence uint32) (hProtocol.Ledger, error) {
 	a := m.Called(sequence)
 	return a.Get(0).(hProtocol.Ledger), a.Error(1)
 }
 
+// LedgerDetailCtx is a mocking method
+func (m *MockClient) LedgerDetailCtx(ctx context.Context, sequence uint32) (hProtocol.Ledger, error) {
+	a := m.Called(ctx, sequence)
+	return a.Get(0).(hProtocol.Ledger), a.Error(1)
+}
+
 // Metrics is a mocking method
 func (m *MockClient) Metrics() (hProtocol.Metrics, error) {
 	a := m.Called()
 	return a.Get(0).(hProtocol.Metrics), a.Error(1)
 }
 
+// MetricsCtx is a mocking method
+func (m *MockClient) MetricsCtx(ctx context.Context) (hProtocol.Metrics, error) {
+	a := m.Called(ctx)
+	return a.Get(0).(hProtocol.Metrics), a.Error(1)
+}
+
 // FeeStats is a mocking method
 func (m *MockClient) FeeStats() (hProtocol.FeeStats, error) {
 	a := m.Called()
 	return a.Get(0).(hProtocol.FeeStats), a.Error(1)
 }
 
+// FeeStatsCtx is a mocking method
+func (m *MockClient) FeeStatsCtx(ctx context.Context) (hProtocol.FeeStats, error) {
+	a := m.Called(ctx)
+	return a.Get(0).(hProtocol.FeeStats), a.Error(1)
+}
+
 // Offers is a mocking method
 func (m *MockClient) Offers(request OfferRequest) (hProtocol.OffersPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
 }
 
+// OffersCtx is a mocking method
+func (m *MockClient) OffersCtx(ctx context.Context, request OfferRequest) (hProtocol.OffersPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
 // Operations is a mocking method
 func (m *MockClient) Operations(request OperationRequest) (operations.OperationsPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(operations.OperationsPage), a.Error(1)
 }
 
+// OperationsCtx is a mocking method
+func (m *MockClient) OperationsCtx(ctx context.Context, request OperationRequest) (operations.OperationsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
 // OperationDetail is a mocking method
 func (m *MockClient) OperationDetail(id string) (operations.Operation, error) {
 	a := m.Called(id)
 	return a.Get(0).(operations.Operation), a.Error(1)
 }
 
+// OperationDetailCtx is a mocking method
+func (m *MockClient) OperationDetailCtx(ctx context.Context, id string) (operations.Operation, error) {
+	a := m.Called(ctx, id)
+	return a.Get(0).(operations.Operation), a.Error(1)
+}
+
 // SubmitTransaction is a mocking method
 func (m *MockClient) SubmitTransaction(transactionXdr string) (hProtocol.TransactionSuccess, error) {
 	a := m.Called(transactionXdr)
 	return a.Get(0).(hProtocol.TransactionSuccess), a.Error(1)
 }
 
+// SubmitTransactionCtx is a mocking method
+func (m *MockClient) SubmitTransactionCtx(ctx context.Context, transactionXdr string) (hProtocol.TransactionSuccess, error) {
+	a := m.Called(ctx, transactionXdr)
+	return a.Get(0).(hProtocol.TransactionSuccess), a.Error(1)
+}
+
 // Transactions is a mocking method
 func (m *MockClient) Transactions(request TransactionRequest) (hProtocol.TransactionsPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
 }
 
+// TransactionsCtx is a mocking method
+func (m *MockClient) TransactionsCtx(ctx context.Context, request TransactionRequest) (hProtocol.TransactionsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
 // TransactionDetail is a mocking method
 func (m *MockClient) TransactionDetail(txHash string) (hProtocol.Transaction, error) {
 	a := m.Called(txHash)
 	return a.Get(0).(hProtocol.Transaction), a.Error(1)
 }
 
+// TransactionDetailCtx is a mocking method
+func (m *MockClient) TransactionDetailCtx(ctx context.Context, txHash string) (hProtocol.Transaction, error) {
+	a := m.Called(ctx, txHash)
+	return a.Get(0).(hProtocol.Transaction), a.Error(1)
+}
+
 // OrderBook is a mocking method
 func (m *MockClient) OrderBook(request OrderBookRequest) (hProtocol.OrderBookSummary, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.OrderBookSummary), a.Error(1)
 }
 
+// OrderBookCtx is a mocking method
+func (m *MockClient) OrderBookCtx(ctx context.Context, request OrderBookRequest) (hProtocol.OrderBookSummary, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.OrderBookSummary), a.Error(1)
+}
+
 // Paths is a mocking method
 func (m *MockClient) Paths(request PathsRequest) (hProtocol.PathsPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
 }
 
+// PathsCtx is a mocking method
+func (m *MockClient) PathsCtx(ctx context.Context, request PathsRequest) (hProtocol.PathsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.PathsPage), a.Error(1)
+}
+
 // Payments is a mocking method
 func (m *MockClient) Payments(request OperationRequest) (operations.OperationsPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(operations.OperationsPage), a.Error(1)
 }
 
+// PaymentsCtx is a mocking method
+func (m *MockClient) PaymentsCtx(ctx context.Context, request OperationRequest) (operations.OperationsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
 // TradeAggregations is a mocking method
 func (m *MockClient) TradeAggregations(request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
 }
 
+// TradeAggregationsCtx is a mocking method
+func (m *MockClient) TradeAggregationsCtx(ctx context.Context, request TradeAggregationRequest) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
 // Trades is a mocking method
 func (m *MockClient) Trades(request TradeRequest) (hProtocol.TradesPage, error) {
 	a := m.Called(request)
 	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
 }
 
+// TradesCtx is a mocking method
+func (m *MockClient) TradesCtx(ctx context.Context, request TradeRequest) (hProtocol.TradesPage, error) {
+	a := m.Called(ctx, request)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
 // StreamTransactions is a mocking method
 func (m *MockClient) StreamTransactions(ctx context.Context, request TransactionRequest, handler TransactionHandler) error {
 	return m.Called(ctx, request, handler).Error(0)
@@ -176,5 +290,202 @@ func (m *MockClient) StreamOrderBooks(ctx context.Context, request OrderBookRequ
 	return m.Called(ctx, request, handler).Error(0)
 }
 
+// NextEffectsPage is a mocking method
+func (m *MockClient) NextEffectsPage(page hProtocol.EffectsPage) (hProtocol.EffectsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.EffectsPage), a.Error(1)
+}
+
+// NextEffectsPageCtx is a mocking method
+func (m *MockClient) NextEffectsPageCtx(ctx context.Context, page hProtocol.EffectsPage) (hProtocol.EffectsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.EffectsPage), a.Error(1)
+}
+
+// PrevEffectsPage is a mocking method
+func (m *MockClient) PrevEffectsPage(page hProtocol.EffectsPage) (hProtocol.EffectsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.EffectsPage), a.Error(1)
+}
+
+// PrevEffectsPageCtx is a mocking method
+func (m *MockClient) PrevEffectsPageCtx(ctx context.Context, page hProtocol.EffectsPage) (hProtocol.EffectsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.EffectsPage), a.Error(1)
+}
+
+// NextAssetsPage is a mocking method
+func (m *MockClient) NextAssetsPage(page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
+// NextAssetsPageCtx is a mocking method
+func (m *MockClient) NextAssetsPageCtx(ctx context.Context, page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
+// PrevAssetsPage is a mocking method
+func (m *MockClient) PrevAssetsPage(page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
+// PrevAssetsPageCtx is a mocking method
+func (m *MockClient) PrevAssetsPageCtx(ctx context.Context, page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.AssetsPage), a.Error(1)
+}
+
+// NextLedgersPage is a mocking method
+func (m *MockClient) NextLedgersPage(page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
+// NextLedgersPageCtx is a mocking method
+func (m *MockClient) NextLedgersPageCtx(ctx context.Context, page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
+// PrevLedgersPage is a mocking method
+func (m *MockClient) PrevLedgersPage(page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
+// PrevLedgersPageCtx is a mocking method
+func (m *MockClient) PrevLedgersPageCtx(ctx context.Context, page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.LedgersPage), a.Error(1)
+}
+
+// NextOffersPage is a mocking method
+func (m *MockClient) NextOffersPage(page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
+// NextOffersPageCtx is a mocking method
+func (m *MockClient) NextOffersPageCtx(ctx context.Context, page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
+// PrevOffersPage is a mocking method
+func (m *MockClient) PrevOffersPage(page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
+// PrevOffersPageCtx is a mocking method
+func (m *MockClient) PrevOffersPageCtx(ctx context.Context, page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.OffersPage), a.Error(1)
+}
+
+// NextOperationsPage is a mocking method
+func (m *MockClient) NextOperationsPage(page operations.OperationsPage) (operations.OperationsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// NextOperationsPageCtx is a mocking method
+func (m *MockClient) NextOperationsPageCtx(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// PrevOperationsPage is a mocking method
+func (m *MockClient) PrevOperationsPage(page operations.OperationsPage) (operations.OperationsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// PrevOperationsPageCtx is a mocking method
+func (m *MockClient) PrevOperationsPageCtx(ctx context.Context, page operations.OperationsPage) (operations.OperationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(operations.OperationsPage), a.Error(1)
+}
+
+// NextTransactionsPage is a mocking method
+func (m *MockClient) NextTransactionsPage(page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
+// NextTransactionsPageCtx is a mocking method
+func (m *MockClient) NextTransactionsPageCtx(ctx context.Context, page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
+// PrevTransactionsPage is a mocking method
+func (m *MockClient) PrevTransactionsPage(page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
+// PrevTransactionsPageCtx is a mocking method
+func (m *MockClient) PrevTransactionsPageCtx(ctx context.Context, page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TransactionsPage), a.Error(1)
+}
+
+// NextTradesPage is a mocking method
+func (m *MockClient) NextTradesPage(page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
+// NextTradesPageCtx is a mocking method
+func (m *MockClient) NextTradesPageCtx(ctx context.Context, page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
+// PrevTradesPage is a mocking method
+func (m *MockClient) PrevTradesPage(page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
+// PrevTradesPageCtx is a mocking method
+func (m *MockClient) PrevTradesPageCtx(ctx context.Context, page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradesPage), a.Error(1)
+}
+
+// NextTradeAggregationsPage is a mocking method
+func (m *MockClient) NextTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
+// NextTradeAggregationsPageCtx is a mocking method
+func (m *MockClient) NextTradeAggregationsPageCtx(ctx context.Context, page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
+// PrevTradeAggregationsPage is a mocking method
+func (m *MockClient) PrevTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(page)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
+// PrevTradeAggregationsPageCtx is a mocking method
+func (m *MockClient) PrevTradeAggregationsPageCtx(ctx context.Context, page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	a := m.Called(ctx, page)
+	return a.Get(0).(hProtocol.TradeAggregationsPage), a.Error(1)
+}
+
+// Iterate is a mocking method
+func (m *MockClient) Iterate(ctx context.Context, request HorizonRequest, handler RecordHandler) error {
+	return m.Called(ctx, request, handler).Error(0)
+}
+
 // ensure that the MockClient implements ClientInterface
 var _ ClientInterface = &MockClient{}
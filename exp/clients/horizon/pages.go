@@ -0,0 +1,332 @@
+package horizonclient
+
+import (
+	"context"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/support/errors"
+)
+
+// ErrStopIteration can be returned by a RecordHandler to stop Iterate without an error.
+var ErrStopIteration = errors.New("stop iteration")
+
+// RecordHandler is a function that is called by Iterate for every record. Records have the type
+// of the records of the page returned for the request, for instance hProtocol.Transaction for a
+// TransactionRequest.
+type RecordHandler func(record interface{}) error
+
+// NextEffectsPage returns the next page of effects, following the next link of page.
+func (c *Client) NextEffectsPage(page hProtocol.EffectsPage) (hProtocol.EffectsPage, error) {
+	return c.NextEffectsPageCtx(c.context(), page)
+}
+
+// NextEffectsPageCtx is NextEffectsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextEffectsPageCtx(ctx context.Context, page hProtocol.EffectsPage) (next hProtocol.EffectsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevEffectsPage returns the previous page of effects, following the prev link of page.
+func (c *Client) PrevEffectsPage(page hProtocol.EffectsPage) (hProtocol.EffectsPage, error) {
+	return c.PrevEffectsPageCtx(c.context(), page)
+}
+
+// PrevEffectsPageCtx is PrevEffectsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevEffectsPageCtx(ctx context.Context, page hProtocol.EffectsPage) (prev hProtocol.EffectsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// NextAssetsPage returns the next page of assets, following the next link of page.
+func (c *Client) NextAssetsPage(page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	return c.NextAssetsPageCtx(c.context(), page)
+}
+
+// NextAssetsPageCtx is NextAssetsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextAssetsPageCtx(ctx context.Context, page hProtocol.AssetsPage) (next hProtocol.AssetsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevAssetsPage returns the previous page of assets, following the prev link of page.
+func (c *Client) PrevAssetsPage(page hProtocol.AssetsPage) (hProtocol.AssetsPage, error) {
+	return c.PrevAssetsPageCtx(c.context(), page)
+}
+
+// PrevAssetsPageCtx is PrevAssetsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevAssetsPageCtx(ctx context.Context, page hProtocol.AssetsPage) (prev hProtocol.AssetsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// NextLedgersPage returns the next page of ledgers, following the next link of page.
+func (c *Client) NextLedgersPage(page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	return c.NextLedgersPageCtx(c.context(), page)
+}
+
+// NextLedgersPageCtx is NextLedgersPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextLedgersPageCtx(ctx context.Context, page hProtocol.LedgersPage) (next hProtocol.LedgersPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevLedgersPage returns the previous page of ledgers, following the prev link of page.
+func (c *Client) PrevLedgersPage(page hProtocol.LedgersPage) (hProtocol.LedgersPage, error) {
+	return c.PrevLedgersPageCtx(c.context(), page)
+}
+
+// PrevLedgersPageCtx is PrevLedgersPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevLedgersPageCtx(ctx context.Context, page hProtocol.LedgersPage) (prev hProtocol.LedgersPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// NextOffersPage returns the next page of offers, following the next link of page.
+func (c *Client) NextOffersPage(page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	return c.NextOffersPageCtx(c.context(), page)
+}
+
+// NextOffersPageCtx is NextOffersPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextOffersPageCtx(ctx context.Context, page hProtocol.OffersPage) (next hProtocol.OffersPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevOffersPage returns the previous page of offers, following the prev link of page.
+func (c *Client) PrevOffersPage(page hProtocol.OffersPage) (hProtocol.OffersPage, error) {
+	return c.PrevOffersPageCtx(c.context(), page)
+}
+
+// PrevOffersPageCtx is PrevOffersPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevOffersPageCtx(ctx context.Context, page hProtocol.OffersPage) (prev hProtocol.OffersPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// NextOperationsPage returns the next page of operations (or payments), following the next link of page.
+func (c *Client) NextOperationsPage(page operations.OperationsPage) (operations.OperationsPage, error) {
+	return c.NextOperationsPageCtx(c.context(), page)
+}
+
+// NextOperationsPageCtx is NextOperationsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextOperationsPageCtx(ctx context.Context, page operations.OperationsPage) (next operations.OperationsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevOperationsPage returns the previous page of operations (or payments), following the prev link of page.
+func (c *Client) PrevOperationsPage(page operations.OperationsPage) (operations.OperationsPage, error) {
+	return c.PrevOperationsPageCtx(c.context(), page)
+}
+
+// PrevOperationsPageCtx is PrevOperationsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevOperationsPageCtx(ctx context.Context, page operations.OperationsPage) (prev operations.OperationsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// NextTransactionsPage returns the next page of transactions, following the next link of page.
+func (c *Client) NextTransactionsPage(page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	return c.NextTransactionsPageCtx(c.context(), page)
+}
+
+// NextTransactionsPageCtx is NextTransactionsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextTransactionsPageCtx(ctx context.Context, page hProtocol.TransactionsPage) (next hProtocol.TransactionsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevTransactionsPage returns the previous page of transactions, following the prev link of page.
+func (c *Client) PrevTransactionsPage(page hProtocol.TransactionsPage) (hProtocol.TransactionsPage, error) {
+	return c.PrevTransactionsPageCtx(c.context(), page)
+}
+
+// PrevTransactionsPageCtx is PrevTransactionsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevTransactionsPageCtx(ctx context.Context, page hProtocol.TransactionsPage) (prev hProtocol.TransactionsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// NextTradesPage returns the next page of trades, following the next link of page.
+func (c *Client) NextTradesPage(page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	return c.NextTradesPageCtx(c.context(), page)
+}
+
+// NextTradesPageCtx is NextTradesPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextTradesPageCtx(ctx context.Context, page hProtocol.TradesPage) (next hProtocol.TradesPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevTradesPage returns the previous page of trades, following the prev link of page.
+func (c *Client) PrevTradesPage(page hProtocol.TradesPage) (hProtocol.TradesPage, error) {
+	return c.PrevTradesPageCtx(c.context(), page)
+}
+
+// PrevTradesPageCtx is PrevTradesPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevTradesPageCtx(ctx context.Context, page hProtocol.TradesPage) (prev hProtocol.TradesPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// NextTradeAggregationsPage returns the next page of trade aggregations, following the next link of page.
+func (c *Client) NextTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	return c.NextTradeAggregationsPageCtx(c.context(), page)
+}
+
+// NextTradeAggregationsPageCtx is NextTradeAggregationsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) NextTradeAggregationsPageCtx(ctx context.Context, page hProtocol.TradeAggregationsPage) (next hProtocol.TradeAggregationsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Next.Href, &next)
+	return
+}
+
+// PrevTradeAggregationsPage returns the previous page of trade aggregations, following the prev link of page.
+func (c *Client) PrevTradeAggregationsPage(page hProtocol.TradeAggregationsPage) (hProtocol.TradeAggregationsPage, error) {
+	return c.PrevTradeAggregationsPageCtx(c.context(), page)
+}
+
+// PrevTradeAggregationsPageCtx is PrevTradeAggregationsPage made with ctx, so that the request stops when ctx is done.
+func (c *Client) PrevTradeAggregationsPageCtx(ctx context.Context, page hProtocol.TradeAggregationsPage) (prev hProtocol.TradeAggregationsPage, err error) {
+	err = c.sendGetRequest(ctx, page.Links.Prev.Href, &prev)
+	return
+}
+
+// pageFetcher gets the page at a URL, returning its records and the link to the next page.
+type pageFetcher func(link string) (records []interface{}, next string, err error)
+
+// Iterate calls handler with every record of request, following the next links of the pages
+// until an empty page is returned, the handler returns an error or ctx is done. The supported
+// requests are those of the endpoints returning pages: EffectRequest, AssetRequest, LedgerRequest,
+// OfferRequest, OperationRequest (for operations, or payments after SetPaymentsEndpoint),
+// TransactionRequest, TradeRequest and TradeAggregationRequest. If the handler returns
+// ErrStopIteration, Iterate stops and returns nil; other errors are returned as they are.
+func (c *Client) Iterate(ctx context.Context, request HorizonRequest, handler RecordHandler) error {
+	// Client.Payments and the like pass operation requests by pointer
+	if r, ok := request.(*OperationRequest); ok {
+		request = *r
+	}
+
+	var fetch pageFetcher
+	switch r := request.(type) {
+	case EffectRequest:
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page hProtocol.EffectsPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	case AssetRequest:
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page hProtocol.AssetsPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	case LedgerRequest:
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page hProtocol.LedgersPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	case OfferRequest:
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page hProtocol.OffersPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	case OperationRequest:
+		if r.endpoint == "" {
+			request = r.SetOperationsEndpoint()
+		}
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page operations.OperationsPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	case TransactionRequest:
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page hProtocol.TransactionsPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	case TradeRequest:
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page hProtocol.TradesPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	case TradeAggregationRequest:
+		fetch = func(link string) ([]interface{}, string, error) {
+			var page hProtocol.TradeAggregationsPage
+			err := c.sendGetRequest(ctx, link, &page)
+			records := make([]interface{}, len(page.Embedded.Records))
+			for i, record := range page.Embedded.Records {
+				records[i] = record
+			}
+			return records, page.Links.Next.Href, err
+		}
+	default:
+		return errors.Errorf("Iterating over %T is not supported", request)
+	}
+
+	endpoint, err := request.BuildURL()
+	if err != nil {
+		return errors.Wrap(err, "Unable to build endpoint")
+	}
+
+	link := c.getHorizonURL() + endpoint
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		records, next, err := fetch(link)
+		if err != nil {
+			return err
+		}
+		if len(records) == 0 {
+			return nil
+		}
+
+		for _, record := range records {
+			if err = handler(record); err != nil {
+				if err == ErrStopIteration {
+					return nil
+				}
+				return err
+			}
+		}
+
+		if next == "" || next == link {
+			return nil
+		}
+		link = next
+	}
+}
@@ -0,0 +1,161 @@
+package horizonclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/protocols/horizon/operations"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// txPage returns a page of transactions with the given hashes, linking to the pages at the
+// cursors before and after it.
+func txPage(self, prev, next string, hashes ...string) string {
+	records := ""
+	for i, hash := range hashes {
+		if i > 0 {
+			records += ","
+		}
+		records += fmt.Sprintf(`{"hash": "%s", "paging_token": "%s"}`, hash, hash)
+	}
+	return fmt.Sprintf(`{
+  "_links": {
+    "self": {"href": "https://localhost/transactions?cursor=%s&limit=2&order=asc"},
+    "next": {"href": "https://localhost/transactions?cursor=%s&limit=2&order=asc"},
+    "prev": {"href": "https://localhost/transactions?cursor=%s&limit=2&order=desc"}
+  },
+  "_embedded": {"records": [%s]}
+}`, self, next, prev, records)
+}
+
+func TestNextPrevPage(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	hmock.On("GET", "https://localhost/transactions?limit=2&order=asc").
+		ReturnString(200, txPage("", "1", "2", "1", "2"))
+	hmock.On("GET", "https://localhost/transactions?cursor=2&limit=2&order=asc").
+		ReturnString(200, txPage("2", "3", "4", "3", "4"))
+	hmock.On("GET", "https://localhost/transactions?cursor=3&limit=2&order=desc").
+		ReturnString(200, txPage("3", "2", "1", "2", "1"))
+
+	page, err := client.Transactions(TransactionRequest{Limit: 2, Order: OrderAsc})
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 2)
+
+	page, err = client.NextTransactionsPage(page)
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 2)
+	assert.Equal(t, "3", page.Embedded.Records[0].Hash)
+
+	page, err = client.PrevTransactionsPage(page)
+	require.NoError(t, err)
+	require.Len(t, page.Embedded.Records, 2)
+	assert.Equal(t, "2", page.Embedded.Records[0].Hash)
+
+	// pages without links can't be followed
+	_, err = client.NextTransactionsPage(hProtocol.TransactionsPage{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "No link to follow")
+	}
+}
+
+func TestIterate(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	// mocked responses can only be read once
+	mockPages := func() {
+		hmock.On("GET", "https://localhost/transactions?limit=2&order=asc").
+			ReturnString(200, txPage("", "1", "2", "1", "2"))
+		hmock.On("GET", "https://localhost/transactions?cursor=2&limit=2&order=asc").
+			ReturnString(200, txPage("2", "3", "3", "3"))
+		hmock.On("GET", "https://localhost/transactions?cursor=3&limit=2&order=asc").
+			ReturnString(200, txPage("3", "3", "3"))
+	}
+
+	mockPages()
+	request := TransactionRequest{Limit: 2, Order: OrderAsc}
+	var hashes []string
+	err := client.Iterate(context.Background(), request, func(record interface{}) error {
+		hashes = append(hashes, record.(hProtocol.Transaction).Hash)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, hashes)
+
+	// stopping early
+	mockPages()
+	hashes = nil
+	err = client.Iterate(context.Background(), request, func(record interface{}) error {
+		hashes = append(hashes, record.(hProtocol.Transaction).Hash)
+		if len(hashes) == 2 {
+			return ErrStopIteration
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, hashes)
+
+	// handler errors are returned
+	mockPages()
+	handlerErr := errors.New("handler failed")
+	err = client.Iterate(context.Background(), request, func(record interface{}) error {
+		return handlerErr
+	})
+	assert.Equal(t, handlerErr, err)
+
+	// and so are cancelled contexts
+	mockPages()
+	ctx, cancel := context.WithCancel(context.Background())
+	err = client.Iterate(ctx, request, func(record interface{}) error {
+		cancel()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	err = client.Iterate(context.Background(), OrderBookRequest{}, func(record interface{}) error {
+		return nil
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "not supported")
+	}
+}
+
+func TestIteratePayments(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL: "https://localhost/",
+		HTTP:       hmock,
+	}
+
+	hmock.On("GET", "https://localhost/payments?cursor=now&limit=1").ReturnString(200, `{
+  "_links": {"next": {"href": "https://localhost/payments?cursor=1&limit=1"}},
+  "_embedded": {"records": [{"id": "1", "paging_token": "1", "type": "payment", "type_i": 1, "amount": "10.0000000"}]}
+}`)
+	hmock.On("GET", "https://localhost/payments?cursor=1&limit=1").ReturnString(200, `{
+  "_links": {"next": {"href": "https://localhost/payments?cursor=1&limit=1"}},
+  "_embedded": {"records": []}
+}`)
+
+	request := OperationRequest{Cursor: "now", Limit: 1}
+	var payments []operations.Payment
+	err := client.Iterate(context.Background(), request.SetPaymentsEndpoint(), func(record interface{}) error {
+		payments = append(payments, record.(operations.Payment))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, payments, 1)
+	assert.Equal(t, "10.0000000", payments[0].Amount)
+}
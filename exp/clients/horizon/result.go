@@ -0,0 +1,223 @@
+package horizonclient
+
+import (
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionResult is the decoded result of a submitted transaction. It is returned by
+// Error.TransactionResult for a failed submission, and by DecodeTransactionSuccess for a
+// successful one.
+type TransactionResult struct {
+	// Code is the result code of the transaction as reported by horizon, for instance
+	// "tx_success", "tx_failed" or "tx_bad_seq".
+	Code string
+	// FeeCharged is the fee charged for the transaction, in stroops.
+	FeeCharged int64
+	// Operations holds the results of the operations, in order. Transactions that failed before
+	// their operations were applied (anything but "tx_failed") have none.
+	Operations []OperationResult
+	// XDR is the decoded result_xdr of the transaction.
+	XDR xdr.TransactionResult
+	// Meta is the decoded result_meta_xdr of a successful transaction, nil otherwise.
+	Meta *xdr.TransactionMeta
+}
+
+// OperationResult is the result of one operation of a submitted transaction.
+type OperationResult struct {
+	// Index is the position of the operation in the transaction.
+	Index int
+	// Type is the type of the operation. It is taken from the transaction envelope if horizon
+	// returned it, otherwise from XDR, which only holds it for operations that were applied.
+	Type xdr.OperationType
+	// Code is the result code of the operation as reported by horizon, for instance
+	// "op_success" or "op_underfunded".
+	Code string
+	// XDR is the decoded result of the operation.
+	XDR xdr.OperationResult
+}
+
+// Failed returns true if the operation did not succeed.
+func (r OperationResult) Failed() bool {
+	return r.Code != "op_success"
+}
+
+// OffersClaimed returns the offers that were (partially) taken by a successful manage offer,
+// create passive offer or path payment operation.
+func (r OperationResult) OffersClaimed() []xdr.ClaimOfferAtom {
+	tr, ok := r.XDR.GetTr()
+	if !ok {
+		return nil
+	}
+
+	switch tr.Type {
+	case xdr.OperationTypeManageOffer, xdr.OperationTypeCreatePassiveOffer:
+		if success, ok := manageOfferSuccess(tr); ok {
+			return success.OffersClaimed
+		}
+	case xdr.OperationTypePathPayment:
+		if success, ok := tr.MustPathPaymentResult().GetSuccess(); ok {
+			return success.Offers
+		}
+	}
+	return nil
+}
+
+// Offer returns the offer that was created or updated by a successful manage offer or create
+// passive offer operation. ok is false if the operation left no offer in the order book,
+// because it was deleted or fully taken.
+func (r OperationResult) Offer() (offer xdr.OfferEntry, ok bool) {
+	tr, ok := r.XDR.GetTr()
+	if !ok {
+		return offer, false
+	}
+
+	success, ok := manageOfferSuccess(tr)
+	if !ok {
+		return offer, false
+	}
+	return success.Offer.GetOffer()
+}
+
+// CreatedOfferID returns the ID of the offer created by a successful manage offer or create
+// passive offer operation, if it created one.
+func (r OperationResult) CreatedOfferID() (int64, bool) {
+	offer, ok := r.Offer()
+	if !ok {
+		return 0, false
+	}
+
+	success, _ := manageOfferSuccess(r.XDR.MustTr())
+	if success.Offer.Effect != xdr.ManageOfferEffectManageOfferCreated {
+		return 0, false
+	}
+	return int64(offer.OfferId), true
+}
+
+func manageOfferSuccess(tr xdr.OperationResultTr) (xdr.ManageOfferSuccessResult, bool) {
+	var result xdr.ManageOfferResult
+	switch tr.Type {
+	case xdr.OperationTypeManageOffer:
+		result = tr.MustManageOfferResult()
+	case xdr.OperationTypeCreatePassiveOffer:
+		result = tr.MustCreatePassiveOfferResult()
+	default:
+		return xdr.ManageOfferSuccessResult{}, false
+	}
+	return result.GetSuccess()
+}
+
+// FailedOperations returns the results of the operations that did not succeed.
+func (r TransactionResult) FailedOperations() []OperationResult {
+	var failed []OperationResult
+	for _, op := range r.Operations {
+		if op.Failed() {
+			failed = append(failed, op)
+		}
+	}
+	return failed
+}
+
+// CreatedOfferIDs returns the IDs of the offers created by the operations of the
+// transaction, in order.
+func (r TransactionResult) CreatedOfferIDs() []int64 {
+	var ids []int64
+	for _, op := range r.Operations {
+		if id, ok := op.CreatedOfferID(); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// OffersClaimed returns the offers taken by the operations of the transaction, in order.
+func (r TransactionResult) OffersClaimed() []xdr.ClaimOfferAtom {
+	var claimed []xdr.ClaimOfferAtom
+	for _, op := range r.Operations {
+		claimed = append(claimed, op.OffersClaimed()...)
+	}
+	return claimed
+}
+
+// TransactionResult decodes the result of the failed transaction submission that triggered
+// this error. The result codes are those reported by horizon in the extra fields.
+func (herr *Error) TransactionResult() (TransactionResult, error) {
+	resultXDR, err := herr.ResultString()
+	if err != nil {
+		return TransactionResult{}, err
+	}
+
+	codes, err := herr.ResultCodes()
+	if err != nil && err != ErrResultCodesNotPopulated {
+		return TransactionResult{}, err
+	}
+
+	var envelope *xdr.TransactionEnvelope
+	if _, err = herr.EnvelopeXDR(); err == nil {
+		envelope, err = herr.Envelope()
+		if err != nil {
+			return TransactionResult{}, err
+		}
+	}
+
+	return decodeTransactionResult(resultXDR, codes, envelope)
+}
+
+// DecodeTransactionSuccess decodes the result and the meta of a successful transaction
+// submission, as returned by Client.SubmitTransaction.
+func DecodeTransactionSuccess(resp hProtocol.TransactionSuccess) (TransactionResult, error) {
+	var envelope *xdr.TransactionEnvelope
+	if resp.Env != "" {
+		envelope = &xdr.TransactionEnvelope{}
+		if err := xdr.SafeUnmarshalBase64(resp.Env, envelope); err != nil {
+			return TransactionResult{}, errors.Wrap(err, "xdr decode of envelope failed")
+		}
+	}
+
+	codes := &hProtocol.TransactionResultCodes{TransactionCode: "tx_success"}
+	result, err := decodeTransactionResult(resp.Result, codes, envelope)
+	if err != nil {
+		return TransactionResult{}, err
+	}
+	for i := range result.Operations {
+		result.Operations[i].Code = "op_success"
+	}
+
+	if resp.Meta != "" {
+		var meta xdr.TransactionMeta
+		if err = xdr.SafeUnmarshalBase64(resp.Meta, &meta); err != nil {
+			return TransactionResult{}, errors.Wrap(err, "xdr decode of meta failed")
+		}
+		result.Meta = &meta
+	}
+	return result, nil
+}
+
+// decodeTransactionResult decodes the base 64 result XDR of a transaction. codes and envelope
+// are optional.
+func decodeTransactionResult(resultXDR string, codes *hProtocol.TransactionResultCodes, envelope *xdr.TransactionEnvelope) (TransactionResult, error) {
+	var result TransactionResult
+	if err := xdr.SafeUnmarshalBase64(resultXDR, &result.XDR); err != nil {
+		return TransactionResult{}, errors.Wrap(err, "xdr decode of result failed")
+	}
+	result.FeeCharged = int64(result.XDR.FeeCharged)
+	if codes != nil {
+		result.Code = codes.TransactionCode
+	}
+
+	opResults, _ := result.XDR.Result.GetResults()
+	for i, opResult := range opResults {
+		op := OperationResult{Index: i, XDR: opResult}
+		if envelope != nil && i < len(envelope.Tx.Operations) {
+			op.Type = envelope.Tx.Operations[i].Body.Type
+		} else if tr, ok := opResult.GetTr(); ok {
+			op.Type = tr.Type
+		}
+		if codes != nil && i < len(codes.OperationCodes) {
+			op.Code = codes.OperationCodes[i]
+		}
+		result.Operations = append(result.Operations, op)
+	}
+	return result, nil
+}
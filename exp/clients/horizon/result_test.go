@@ -0,0 +1,126 @@
+package horizonclient
+
+import (
+	"testing"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError_TransactionResult(t *testing.T) {
+	var herr Error
+	herr.Problem.Type = "transaction_failed"
+	herr.Problem.Extras = map[string]interface{}{
+		"result_xdr": "AAAAAAAAAMj/////AAAAAgAAAAAAAAAA/////wAAAAAAAAAAAAAAAAAAAAA=",
+		"result_codes": map[string]interface{}{
+			"transaction": "tx_failed",
+			"operations":  []string{"op_underfunded", "op_success"},
+		},
+	}
+
+	result, err := herr.TransactionResult()
+	require.NoError(t, err)
+	assert.Equal(t, "tx_failed", result.Code)
+	assert.Equal(t, int64(200), result.FeeCharged)
+	assert.Nil(t, result.Meta)
+	require.Len(t, result.Operations, 2)
+
+	assert.Equal(t, 0, result.Operations[0].Index)
+	assert.Equal(t, xdr.OperationTypeCreateAccount, result.Operations[0].Type)
+	assert.Equal(t, "op_underfunded", result.Operations[0].Code)
+	assert.True(t, result.Operations[0].Failed())
+	assert.Equal(t, 1, result.Operations[1].Index)
+	assert.False(t, result.Operations[1].Failed())
+
+	failed := result.FailedOperations()
+	require.Len(t, failed, 1)
+	assert.Equal(t, 0, failed[0].Index)
+
+	// the operation types come from the envelope when there is one
+	herr.Problem.Extras["envelope_xdr"] = `AAAAADSMMRmQGDH6EJzkgi/7PoKhphMHyNGQgDp2tlS/dhGXAAAAZAAT3TUAAAAwAAAAAAAAAAAAAAABAAAAAAAAAAMAAAABSU5SAAAAAAA0jDEZkBgx+hCc5IIv+z6CoaYTB8jRkIA6drZUv3YRlwAAAAFVU0QAAAAAADSMMRmQGDH6EJzkgi/7PoKhphMHyNGQgDp2tlS/dhGXAAAAAAX14QAAAAAKAAAAAQAAAAAAAAAAAAAAAAAAAAG/dhGXAAAAQLuStfImg0OeeGAQmvLkJSZ1MPSkCzCYNbGqX5oYNuuOqZ5SmWhEsC7uOD9ha4V7KengiwNlc0oMNqBVo22S7gk=`
+	result, err = herr.TransactionResult()
+	require.NoError(t, err)
+	assert.Equal(t, xdr.OperationTypeManageOffer, result.Operations[0].Type)
+
+	// sad path: missing result_xdr extra
+	herr.Problem.Extras = map[string]interface{}{}
+	_, err = herr.TransactionResult()
+	assert.Equal(t, ErrResultNotPopulated, err)
+
+	// sad path: undecodable result_xdr extra
+	herr.Problem.Extras = map[string]interface{}{"result_xdr": "kaboom"}
+	_, err = herr.TransactionResult()
+	assert.Error(t, err)
+}
+
+func TestDecodeTransactionSuccess(t *testing.T) {
+	var seller xdr.AccountId
+	require.NoError(t, seller.SetAddress("GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"))
+	claimed := xdr.ClaimOfferAtom{SellerId: seller, OfferId: 7, AmountSold: 100, AmountBought: 50}
+	offer := xdr.OfferEntry{SellerId: seller, OfferId: 42, Amount: 900}
+	opResults := []xdr.OperationResult{
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type: xdr.OperationTypeManageOffer,
+				ManageOfferResult: &xdr.ManageOfferResult{
+					Code: xdr.ManageOfferResultCodeManageOfferSuccess,
+					Success: &xdr.ManageOfferSuccessResult{
+						OffersClaimed: []xdr.ClaimOfferAtom{claimed},
+						Offer: xdr.ManageOfferSuccessResultOffer{
+							Effect: xdr.ManageOfferEffectManageOfferCreated,
+							Offer:  &offer,
+						},
+					},
+				},
+			},
+		},
+		{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type:            xdr.OperationTypeInflation,
+				InflationResult: &xdr.InflationResult{Code: xdr.InflationResultCodeInflationSuccess, Payouts: &[]xdr.InflationPayout{}},
+			},
+		},
+	}
+	resultXDR, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code:    xdr.TransactionResultCodeTxSuccess,
+			Results: &opResults,
+		},
+	})
+	require.NoError(t, err)
+	metaXDR, err := xdr.MarshalBase64(xdr.TransactionMeta{
+		V:  1,
+		V1: &xdr.TransactionMetaV1{Operations: []xdr.OperationMeta{{}, {}}},
+	})
+	require.NoError(t, err)
+
+	result, err := DecodeTransactionSuccess(hProtocol.TransactionSuccess{Result: resultXDR, Meta: metaXDR})
+	require.NoError(t, err)
+	assert.Equal(t, "tx_success", result.Code)
+	assert.Equal(t, int64(200), result.FeeCharged)
+	assert.Empty(t, result.FailedOperations())
+	require.Len(t, result.Operations, 2)
+	assert.Equal(t, xdr.OperationTypeManageOffer, result.Operations[0].Type)
+	assert.Equal(t, xdr.OperationTypeInflation, result.Operations[1].Type)
+	assert.Equal(t, "op_success", result.Operations[1].Code)
+
+	assert.Equal(t, []xdr.ClaimOfferAtom{claimed}, result.OffersClaimed())
+	assert.Equal(t, []int64{42}, result.CreatedOfferIDs())
+	gotOffer, ok := result.Operations[0].Offer()
+	assert.True(t, ok)
+	assert.Equal(t, offer, gotOffer)
+	_, ok = result.Operations[1].CreatedOfferID()
+	assert.False(t, ok)
+
+	require.NotNil(t, result.Meta)
+	assert.Len(t, result.Meta.MustV1().Operations, 2)
+
+	// sad path: undecodable meta
+	_, err = DecodeTransactionSuccess(hProtocol.TransactionSuccess{Result: resultXDR, Meta: "kaboom"})
+	assert.Error(t, err)
+}
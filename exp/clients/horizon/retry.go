@@ -0,0 +1,96 @@
+package horizonclient
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// DefaultRetryBackoff is the delay before the first retry of a request, if Client.RetryBackoff
+	// is not set.
+	DefaultRetryBackoff = time.Second
+
+	// MaxRetryBackoff is the longest delay between two retries, unless horizon asks for a longer one.
+	MaxRetryBackoff = time.Minute
+)
+
+// retryable returns true if a request that got a response with the given status code may
+// succeed if it is sent again: it was rate limited or the server failed.
+func retryable(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// retryDelay returns how long to wait before retry number attempt (starting at 0). It honours
+// the Retry-After and X-RateLimit-* headers of a rate limited response; otherwise the delay
+// starts at RetryBackoff and doubles with every attempt.
+func (c *Client) retryDelay(attempt uint, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseSeconds(resp.Header.Get("Retry-After")); ok {
+			return delay
+		}
+		// Horizon sends the number of seconds until the rate limit is reset
+		if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			if delay, ok := parseSeconds(resp.Header.Get("X-RateLimit-Reset")); ok {
+				return delay
+			}
+		}
+	}
+
+	delay := c.RetryBackoff
+	if delay <= 0 {
+		delay = DefaultRetryBackoff
+	}
+	for i := uint(0); i < attempt && delay < MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > MaxRetryBackoff {
+		delay = MaxRetryBackoff
+	}
+	return delay
+}
+
+func parseSeconds(value string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// sleep waits for d, or until ctx is done, in which case it returns the context's error.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// context returns the context requests of the client are made with.
+func (c *Client) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// WithContext returns a copy of the client whose requests are made with ctx by default, so that
+// they can be cancelled or given a deadline. To cancel a single request, use the Ctx variant of
+// its method instead:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	account, err := client.AccountDetailCtx(ctx, request)
+//
+// Each request is still subject to the horizon timeout of the client (see SetHorizonTimeOut),
+// and waiting before a retry stops when ctx is done.
+func (c *Client) WithContext(ctx context.Context) *Client {
+	cc := *c
+	cc.ctx = ctx
+	return &cc
+}
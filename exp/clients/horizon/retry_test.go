@@ -0,0 +1,254 @@
+package horizonclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceResponder returns the given responses in order, repeating the last one.
+func sequenceResponder(calls *int, responses ...func() (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+	return func(*http.Request) (*http.Response, error) {
+		i := *calls
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		*calls++
+		return responses[i]()
+	}
+}
+
+func respond(status int, body string, header http.Header) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		resp := &http.Response{
+			StatusCode: status,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}
+		for k, v := range header {
+			resp.Header[k] = v
+		}
+		return resp, nil
+	}
+}
+
+func fail(msg string) func() (*http.Response, error) {
+	return func() (*http.Response, error) {
+		return nil, errors.New(msg)
+	}
+}
+
+func TestRetryServerErrors(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:   "https://localhost/",
+		HTTP:         hmock,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	}
+	url := "https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"
+	request := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+
+	// server errors, rate limits and network errors are retried
+	calls := 0
+	hmock.On("GET", url).Return(sequenceResponder(&calls,
+		respond(503, `{"status": 503, "title": "Service Unavailable"}`, nil),
+		respond(429, `{"status": 429, "title": "Rate Limit Exceeded"}`, http.Header{"Retry-After": {"0"}}),
+		fail("connection reset"),
+		respond(200, accountResponse, nil),
+	))
+	account, err := client.AccountDetail(request)
+	require.NoError(t, err)
+	assert.Equal(t, "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU", account.AccountID)
+	assert.Equal(t, 4, calls)
+
+	// until MaxRetries is reached
+	calls = 0
+	hmock.On("GET", url).Return(sequenceResponder(&calls,
+		respond(500, `{"status": 500, "title": "Internal Server Error"}`, nil),
+	))
+	_, err = client.AccountDetail(request)
+	if assert.Error(t, err) {
+		herr, ok := err.(*Error)
+		require.True(t, ok)
+		assert.Equal(t, "Internal Server Error", herr.Problem.Title)
+	}
+	assert.Equal(t, 4, calls)
+
+	// client errors are not retried
+	calls = 0
+	hmock.On("GET", url).Return(sequenceResponder(&calls,
+		respond(404, `{"status": 404, "title": "Resource Missing"}`, nil),
+	))
+	_, err = client.AccountDetail(request)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// and nothing is retried by default
+	client.MaxRetries = 0
+	calls = 0
+	hmock.On("GET", url).Return(sequenceResponder(&calls,
+		respond(503, `{"status": 503, "title": "Service Unavailable"}`, nil),
+	))
+	_, err = client.AccountDetail(request)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRetryDelay(t *testing.T) {
+	client := &Client{RetryBackoff: time.Second}
+
+	assert.Equal(t, time.Second, client.retryDelay(0, nil))
+	assert.Equal(t, 4*time.Second, client.retryDelay(2, nil))
+	assert.Equal(t, MaxRetryBackoff, client.retryDelay(20, nil))
+
+	resp := &http.Response{StatusCode: 429, Header: http.Header{}}
+	resp.Header.Set("X-RateLimit-Remaining", "0")
+	resp.Header.Set("X-RateLimit-Reset", "360")
+	assert.Equal(t, 360*time.Second, client.retryDelay(0, resp))
+
+	resp.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, client.retryDelay(0, resp))
+
+	client = &Client{}
+	assert.Equal(t, DefaultRetryBackoff, client.retryDelay(0, nil))
+}
+
+func TestWithContext(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:   "https://localhost/",
+		HTTP:         hmock,
+		MaxRetries:   10,
+		RetryBackoff: time.Hour,
+	}
+	request := AccountRequest{AccountID: "GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU"}
+	calls := 0
+	hmock.On(
+		"GET",
+		"https://localhost/accounts/GCLWGQPMKXQSPF776IU33AH4PZNOOWNAWGGKVTBQMIC5IMKUNP3E6NVU",
+	).Return(sequenceResponder(&calls,
+		respond(503, `{"status": 503, "title": "Service Unavailable"}`, nil),
+	))
+
+	// waiting for the next retry stops with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.WithContext(ctx).AccountDetail(request)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Nil(t, client.ctx, "the original client is unchanged")
+}
+
+func TestRequestCtx(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:   "https://localhost/",
+		HTTP:         hmock,
+		MaxRetries:   10,
+		RetryBackoff: time.Hour,
+	}
+	calls := 0
+	hmock.On(
+		"GET",
+		"https://localhost/ledgers/2",
+	).Return(sequenceResponder(&calls,
+		respond(503, `{"status": 503, "title": "Service Unavailable"}`, nil),
+	))
+
+	// the context of a single request stops waiting for its retries
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := client.LedgerDetailCtx(ctx, 2)
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	// a cancelled context isn't sent at all
+	calls = 0
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	_, err = client.NextLedgersPageCtx(ctx, hProtocol.LedgersPage{Links: hal.Links{Next: hal.Link{Href: "https://localhost/ledgers/2"}}})
+	assert.Error(t, err)
+}
+
+// brokenReader returns an error once its data has been read, like a dropped connection.
+type brokenReader struct {
+	io.Reader
+}
+
+func (r brokenReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		return n, errors.New("connection reset by peer")
+	}
+	return n, err
+}
+
+func TestStreamResume(t *testing.T) {
+	hmock := httptest.NewClient()
+	client := &Client{
+		HorizonURL:   "https://localhost/",
+		HTTP:         hmock,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	}
+
+	event := "id: 2406637679673344\n" + ledgerStreamResponse + "\n"
+	hmock.On("GET", "https://localhost/ledgers?cursor=1").Return(
+		func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: 200,
+				Body:       ioutil.NopCloser(brokenReader{strings.NewReader(event)}),
+			}, nil
+		},
+	)
+	calls := 0
+	hmock.On("GET", "https://localhost/ledgers?cursor=2406637679673344").Return(sequenceResponder(&calls,
+		respond(503, "", nil),
+		fail("connection refused"),
+		respond(200, event, nil),
+	))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var ledgers []hProtocol.Ledger
+	err := client.StreamLedgers(ctx, LedgerRequest{Cursor: "1"}, func(ledger hProtocol.Ledger) {
+		ledgers = append(ledgers, ledger)
+		if len(ledgers) == 2 {
+			cancel()
+		}
+	})
+	require.NoError(t, err)
+	assert.Len(t, ledgers, 2)
+	assert.Equal(t, 3, calls)
+
+	// consecutive failures end the stream
+	calls = 0
+	hmock.On("GET", "https://localhost/ledgers?cursor=now").Return(sequenceResponder(&calls,
+		respond(500, "", nil),
+	))
+	err = client.StreamLedgers(context.Background(), LedgerRequest{}, func(ledger hProtocol.Ledger) {})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "Got bad HTTP status code 500")
+	}
+	assert.Equal(t, 4, calls)
+
+	// and so do client errors, at once
+	calls = 0
+	hmock.On("GET", "https://localhost/ledgers?cursor=now").Return(sequenceResponder(&calls,
+		respond(404, "", nil),
+	))
+	err = client.StreamLedgers(context.Background(), LedgerRequest{}, func(ledger hProtocol.Ledger) {})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -305,10 +305,6 @@ func (c *ScraperConfig) retrieveAssets(limit int) (assets []hProtocol.AssetStat,
 	c.Logger.Infoln("Fetching assets from Horizon")
 
 	for assetsPage.Links.Next.Href != assetsPage.Links.Self.Href {
-		assetsPage, err = c.Client.Assets(r)
-		if err != nil {
-			return
-		}
 		assets = append(assets, assetsPage.Embedded.Records...)
 
 		if limit != 0 { // for performance reasons, only perform these additional checks when limit != 0
@@ -320,14 +316,11 @@ func (c *ScraperConfig) retrieveAssets(limit int) (assets []hProtocol.AssetStat,
 			}
 		}
 
-		nextURL := assetsPage.Links.Next.Href
-		n, err := nextCursor(nextURL)
+		c.Logger.Debugln("Fetching next page:", assetsPage.Links.Next.Href)
+		assetsPage, err = c.Client.NextAssetsPage(assetsPage)
 		if err != nil {
-			return assets, err
+			return
 		}
-		c.Logger.Debugln("Cursor currently at:", n)
-
-		r = horizonclient.AssetRequest{Limit: 200, Cursor: n}
 	}
 
 	c.Logger.Infof("Fetched: %d assets\n", len(assets))
@@ -56,15 +56,8 @@ func (c *ScraperConfig) retrieveTrades(since time.Time, limit int) (trades []hPr
 			}
 		}
 
-		// Finding next page's params:
-		nextURL := tradesPage.Links.Next.Href
-		n, err := nextCursor(nextURL)
-		if err != nil {
-			return trades, err
-		}
-		c.Logger.Debugln("Cursor currently at:", n)
-		r.Cursor = n
-		tradesPage, err = c.Client.Trades(r)
+		c.Logger.Debugln("Fetching next page:", tradesPage.Links.Next.Href)
+		tradesPage, err = c.Client.NextTradesPage(tradesPage)
 		if err != nil {
 			return trades, err
 		}
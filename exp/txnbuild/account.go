@@ -0,0 +1,34 @@
+package txnbuild
+
+import (
+	"github.com/stellar/go/xdr"
+)
+
+// SimpleAccount is a minimal implementation of an Account, holding just an account ID and
+// its current sequence number.
+type SimpleAccount struct {
+	AccountID string
+	Sequence  int64
+}
+
+// NewSimpleAccount is a factory method that creates a SimpleAccount from "accountID" and "sequence".
+func NewSimpleAccount(accountID string, sequence int64) SimpleAccount {
+	return SimpleAccount{accountID, sequence}
+}
+
+// GetAccountID returns the Account ID.
+func (sa *SimpleAccount) GetAccountID() string {
+	return sa.AccountID
+}
+
+// GetSequenceNumber returns the current sequence number of the account.
+func (sa *SimpleAccount) GetSequenceNumber() (xdr.SequenceNumber, error) {
+	return xdr.SequenceNumber(sa.Sequence), nil
+}
+
+// IncrementSequenceNumber increments the internal record of the account's sequence
+// number by 1, and returns the new value.
+func (sa *SimpleAccount) IncrementSequenceNumber() (xdr.SequenceNumber, error) {
+	sa.Sequence++
+	return xdr.SequenceNumber(sa.Sequence), nil
+}
@@ -8,7 +8,8 @@ import (
 // AccountMerge represents the Stellar merge account operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type AccountMerge struct {
-	Destination string
+	Destination   string
+	SourceAccount Account
 }
 
 // BuildXDR for AccountMerge returns a fully configured XDR Operation.
@@ -22,6 +23,31 @@ func (am *AccountMerge) BuildXDR() (xdr.Operation, error) {
 
 	opType := xdr.OperationTypeAccountMerge
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, am.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for AccountMerge initialises the txnbuild struct from the corresponding xdr Operation.
+func (am *AccountMerge) FromXDR(xdrOp xdr.Operation) error {
+	destination, ok := xdrOp.Body.GetDestination()
+	if !ok {
+		return errors.New("Error parsing account_merge operation from xdr")
+	}
+
+	am.Destination = destination.Address()
+	am.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
+
+// Validate for AccountMerge checks the fields of the operation before it is built.
+func (am *AccountMerge) Validate() error {
+	if err := validateAccountID("Destination", am.Destination); err != nil {
+		return err
+	}
+	return validateSourceAccount(am.SourceAccount)
 }
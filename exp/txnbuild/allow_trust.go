@@ -1,6 +1,8 @@
 package txnbuild
 
 import (
+	"strings"
+
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -8,9 +10,10 @@ import (
 // AllowTrust represents the Stellar allow trust operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type AllowTrust struct {
-	Trustor   string
-	Type      Asset
-	Authorize bool
+	Trustor       string
+	Type          Asset
+	Authorize     bool
+	SourceAccount Account
 }
 
 // BuildXDR for AllowTrust returns a fully configured XDR Operation.
@@ -44,6 +47,62 @@ func (at *AllowTrust) BuildXDR() (xdr.Operation, error) {
 
 	opType := xdr.OperationTypeAllowTrust
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, at.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for AllowTrust initialises the txnbuild struct from the corresponding xdr Operation.
+// An allow trust operation only holds the code of the asset, whose issuer is the source
+// account of the operation. The issuer is set from the operation's source account if it has
+// one; otherwise it is left for the caller (for instance TransactionFromXDR) to fill in.
+func (at *AllowTrust) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetAllowTrustOp()
+	if !ok {
+		return errors.New("Error parsing allow_trust operation from xdr")
+	}
+
+	var code string
+	switch result.Asset.Type {
+	case xdr.AssetTypeAssetTypeCreditAlphanum4:
+		code4 := result.Asset.MustAssetCode4()
+		code = strings.TrimRight(string(code4[:]), "\x00")
+	case xdr.AssetTypeAssetTypeCreditAlphanum12:
+		code12 := result.Asset.MustAssetCode12()
+		code = strings.TrimRight(string(code12[:]), "\x00")
+	default:
+		return errors.New("Invalid asset type for allow_trust operation")
+	}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	var issuer string
+	if xdrOp.SourceAccount != nil {
+		issuer = xdrOp.SourceAccount.Address()
+	}
+
+	at.Trustor = result.Trustor.Address()
+	at.Type = CreditAsset{Code: code, Issuer: issuer}
+	at.Authorize = result.Authorize
+	at.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
+
+// Validate for AllowTrust checks the fields of the operation before it is built.
+func (at *AllowTrust) Validate() error {
+	if err := validateAccountID("Trustor", at.Trustor); err != nil {
+		return err
+	}
+	if at.Type == nil {
+		return newValidationError("Type", "Asset is missing")
+	}
+	if at.Type.IsNative() {
+		return newValidationError("Type", "Trustline doesn't exist for a native (XLM) asset")
+	}
+	if _, err := at.Type.GetType(); err != nil {
+		return newValidationError("Type", "Asset code '%s' must be between 1 and 12 characters", at.Type.GetCode())
+	}
+	return validateSourceAccount(at.SourceAccount)
 }
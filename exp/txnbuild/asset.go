@@ -96,3 +96,18 @@ func (ca CreditAsset) ToXDR() (xdr.Asset, error) {
 
 	return xdrAsset, nil
 }
+
+// assetFromXDR returns the Asset represented by an XDR asset.
+func assetFromXDR(xdrAsset xdr.Asset) (Asset, error) {
+	var assetType xdr.AssetType
+	var code, issuer string
+	err := xdrAsset.Extract(&assetType, &code, &issuer)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to extract asset")
+	}
+
+	if assetType == xdr.AssetTypeAssetTypeNative {
+		return NativeAsset{}, nil
+	}
+	return CreditAsset{Code: code, Issuer: issuer}, nil
+}
@@ -9,8 +9,9 @@ import (
 // ChangeTrust represents the Stellar change trust operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type ChangeTrust struct {
-	Line  Asset
-	Limit string
+	Line          Asset
+	Limit         string
+	SourceAccount Account
 }
 
 // RemoveTrustlineOp returns a ChangeTrust operation to remove the trustline of the described asset,
@@ -43,6 +44,44 @@ func (ct *ChangeTrust) BuildXDR() (xdr.Operation, error) {
 		Limit: xdrLimit,
 	}
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, ct.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for ChangeTrust initialises the txnbuild struct from the corresponding xdr Operation.
+func (ct *ChangeTrust) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetChangeTrustOp()
+	if !ok {
+		return errors.New("Error parsing change_trust operation from xdr")
+	}
+
+	line, err := assetFromXDR(result.Line)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing trustline asset")
+	}
+
+	ct.Line = line
+	ct.Limit = amount.String(result.Limit)
+	ct.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+// Validate for ChangeTrust checks the fields of the operation before it is built.
+func (ct *ChangeTrust) Validate() error {
+	if ct.Line != nil && ct.Line.IsNative() {
+		return newValidationError("Line", "Trustline cannot be extended to a native (XLM) asset")
+	}
+	if err := validateAsset("Line", ct.Line); err != nil {
+		return err
+	}
+	// A limit of zero removes the trustline
+	if err := validateAmount("Limit", ct.Limit, true); err != nil {
+		return err
+	}
+	return validateSourceAccount(ct.SourceAccount)
 }
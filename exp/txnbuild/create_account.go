@@ -9,8 +9,9 @@ import (
 // CreateAccount represents the Stellar create account operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type CreateAccount struct {
-	Destination string
-	Amount      string
+	Destination   string
+	Amount        string
+	SourceAccount Account
 }
 
 // BuildXDR for CreateAccount returns a fully configured XDR Operation.
@@ -29,6 +30,35 @@ func (ca *CreateAccount) BuildXDR() (xdr.Operation, error) {
 
 	opType := xdr.OperationTypeCreateAccount
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, ca.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for CreateAccount initialises the txnbuild struct from the corresponding xdr Operation.
+func (ca *CreateAccount) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetCreateAccountOp()
+	if !ok {
+		return errors.New("Error parsing create_account operation from xdr")
+	}
+
+	ca.Destination = result.Destination.Address()
+	ca.Amount = amount.String(result.StartingBalance)
+	ca.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+// Validate for CreateAccount checks the fields of the operation before it is built.
+func (ca *CreateAccount) Validate() error {
+	if err := validateAccountID("Destination", ca.Destination); err != nil {
+		return err
+	}
+	if err := validateAmount("Amount", ca.Amount, false); err != nil {
+		return err
+	}
+	return validateSourceAccount(ca.SourceAccount)
 }
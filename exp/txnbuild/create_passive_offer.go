@@ -2,7 +2,6 @@ package txnbuild
 
 import (
 	"github.com/stellar/go/amount"
-	"github.com/stellar/go/price"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -10,10 +9,14 @@ import (
 // CreatePassiveOffer represents the Stellar create passive offer operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type CreatePassiveOffer struct {
-	Selling Asset
-	Buying  Asset
-	Amount  string
-	Price   string // TODO: Extend to include number, and n/d fraction. See package 'amount'
+	Selling       Asset
+	Buying        Asset
+	Amount        string
+	Price         string // TODO: Extend to include number, and n/d fraction. See package 'amount'
+	SourceAccount Account
+
+	// xdrPrice is the exact price decoded by FromXDR, see parsePrice.
+	xdrPrice *xdr.Price
 }
 
 // BuildXDR for CreatePassiveOffer returns a fully configured XDR Operation.
@@ -33,7 +36,7 @@ func (cpo *CreatePassiveOffer) BuildXDR() (xdr.Operation, error) {
 		return xdr.Operation{}, errors.Wrap(err, "Failed to parse 'Amount'")
 	}
 
-	xdrPrice, err := price.Parse(cpo.Price)
+	xdrPrice, err := parsePrice(cpo.Price, cpo.xdrPrice)
 	if err != nil {
 		return xdr.Operation{}, errors.Wrap(err, "Failed to parse 'Price'")
 	}
@@ -47,6 +50,53 @@ func (cpo *CreatePassiveOffer) BuildXDR() (xdr.Operation, error) {
 
 	opType := xdr.OperationTypeCreatePassiveOffer
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, cpo.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for CreatePassiveOffer initialises the txnbuild struct from the corresponding xdr Operation.
+func (cpo *CreatePassiveOffer) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetCreatePassiveOfferOp()
+	if !ok {
+		return errors.New("Error parsing create_passive_offer operation from xdr")
+	}
+
+	selling, err := assetFromXDR(result.Selling)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing 'Selling' field")
+	}
+	buying, err := assetFromXDR(result.Buying)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing 'Buying' field")
+	}
+
+	cpo.Selling = selling
+	cpo.Buying = buying
+	cpo.Amount = amount.String(result.Amount)
+	cpo.Price = result.Price.String()
+	cpo.xdrPrice = &result.Price
+	cpo.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+// Validate for CreatePassiveOffer checks the fields of the operation before it is built.
+func (cpo *CreatePassiveOffer) Validate() error {
+	if err := validateAsset("Selling", cpo.Selling); err != nil {
+		return err
+	}
+	if err := validateAsset("Buying", cpo.Buying); err != nil {
+		return err
+	}
+	if err := validateAmount("Amount", cpo.Amount, false); err != nil {
+		return err
+	}
+	if err := validatePrice("Price", cpo.Price, cpo.xdrPrice); err != nil {
+		return err
+	}
+	return validateSourceAccount(cpo.SourceAccount)
 }
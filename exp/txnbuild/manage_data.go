@@ -8,8 +8,9 @@ import (
 // ManageData represents the Stellar manage data operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type ManageData struct {
-	Name  string
-	Value []byte
+	Name          string
+	Value         []byte
+	SourceAccount Account
 }
 
 // BuildXDR for ManageData returns a fully configured XDR Operation.
@@ -26,6 +27,39 @@ func (md *ManageData) BuildXDR() (xdr.Operation, error) {
 
 	opType := xdr.OperationTypeManageData
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, md.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for ManageData initialises the txnbuild struct from the corresponding xdr Operation.
+func (md *ManageData) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetManageDataOp()
+	if !ok {
+		return errors.New("Error parsing manage_data operation from xdr")
+	}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	md.Name = string(result.DataName)
+	md.Value = nil
+	if result.DataValue != nil {
+		// A non-nil Value, even if empty, sets the data entry.
+		md.Value = append([]byte{}, *result.DataValue...)
+	}
+	md.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
+
+// Validate for ManageData checks the fields of the operation before it is built.
+func (md *ManageData) Validate() error {
+	if len(md.Name) == 0 || len(md.Name) > 64 {
+		return newValidationError("Name", "Data name '%s' must be between 1 and 64 bytes long", md.Name)
+	}
+	if len(md.Value) > 64 {
+		return newValidationError("Value", "Data value is %d bytes long, the maximum is 64", len(md.Value))
+	}
+	return validateSourceAccount(md.SourceAccount)
 }
@@ -2,7 +2,6 @@ package txnbuild
 
 import (
 	"github.com/stellar/go/amount"
-	"github.com/stellar/go/price"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -49,11 +48,15 @@ func DeleteOfferOp(offerID uint64) ManageOffer {
 // ManageOffer represents the Stellar manage offer operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type ManageOffer struct {
-	Selling Asset
-	Buying  Asset
-	Amount  string
-	Price   string // TODO: Extend to include number, and n/d fraction. See package 'amount'
-	OfferID uint64
+	Selling       Asset
+	Buying        Asset
+	Amount        string
+	Price         string // TODO: Extend to include number, and n/d fraction. See package 'amount'
+	OfferID       uint64
+	SourceAccount Account
+
+	// xdrPrice is the exact price decoded by FromXDR, see parsePrice.
+	xdrPrice *xdr.Price
 }
 
 // BuildXDR for ManageOffer returns a fully configured XDR Operation.
@@ -73,7 +76,7 @@ func (mo *ManageOffer) BuildXDR() (xdr.Operation, error) {
 		return xdr.Operation{}, errors.Wrap(err, "Failed to parse 'Amount'")
 	}
 
-	xdrPrice, err := price.Parse(mo.Price)
+	xdrPrice, err := parsePrice(mo.Price, mo.xdrPrice)
 	if err != nil {
 		return xdr.Operation{}, errors.Wrap(err, "Failed to parse 'Price'")
 	}
@@ -87,6 +90,55 @@ func (mo *ManageOffer) BuildXDR() (xdr.Operation, error) {
 		OfferId: xdr.Uint64(mo.OfferID),
 	}
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, mo.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for ManageOffer initialises the txnbuild struct from the corresponding xdr Operation.
+func (mo *ManageOffer) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetManageOfferOp()
+	if !ok {
+		return errors.New("Error parsing manage_offer operation from xdr")
+	}
+
+	selling, err := assetFromXDR(result.Selling)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing 'Selling' field")
+	}
+	buying, err := assetFromXDR(result.Buying)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing 'Buying' field")
+	}
+
+	mo.Selling = selling
+	mo.Buying = buying
+	mo.Amount = amount.String(result.Amount)
+	mo.Price = result.Price.String()
+	mo.xdrPrice = &result.Price
+	mo.OfferID = uint64(result.OfferId)
+	mo.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+// Validate for ManageOffer checks the fields of the operation before it is built.
+func (mo *ManageOffer) Validate() error {
+	if err := validateAsset("Selling", mo.Selling); err != nil {
+		return err
+	}
+	if err := validateAsset("Buying", mo.Buying); err != nil {
+		return err
+	}
+	// An amount of zero deletes the offer
+	if err := validateAmount("Amount", mo.Amount, true); err != nil {
+		return err
+	}
+	if err := validatePrice("Price", mo.Price, mo.xdrPrice); err != nil {
+		return err
+	}
+	return validateSourceAccount(mo.SourceAccount)
 }
@@ -0,0 +1,116 @@
+package txnbuild
+
+import (
+	"sync"
+
+	horizonclient "github.com/stellar/go/exp/clients/horizon"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+// ManagedAccount is an Account whose sequence number is kept in sync with horizon, so that
+// transactions for the same source account can be built concurrently. It is safe for use by
+// multiple goroutines: every call to IncrementSequenceNumber (as made by Transaction.Build)
+// gets a distinct sequence number.
+//
+// The sequence number is loaded from horizon when it is first needed, and again after a
+// transaction is rejected with tx_bad_seq, for instance because a transaction was built but
+// never submitted. Submit transactions with SubmitTransaction, or pass the errors of other
+// submissions to CheckError, for this to happen.
+type ManagedAccount struct {
+	accountID string
+	client    horizonclient.ClientInterface
+
+	mutex    sync.Mutex
+	sequence xdr.SequenceNumber
+	loaded   bool
+}
+
+// NewManagedAccount is a factory method that creates a ManagedAccount for accountID, whose
+// sequence number is loaded through client.
+func NewManagedAccount(accountID string, client horizonclient.ClientInterface) *ManagedAccount {
+	return &ManagedAccount{accountID: accountID, client: client}
+}
+
+// GetAccountID returns the Account ID.
+func (ma *ManagedAccount) GetAccountID() string {
+	return ma.accountID
+}
+
+// IncrementSequenceNumber returns the next sequence number of the account, loading the
+// current one from horizon if needed.
+func (ma *ManagedAccount) IncrementSequenceNumber() (xdr.SequenceNumber, error) {
+	ma.mutex.Lock()
+	defer ma.mutex.Unlock()
+
+	if !ma.loaded {
+		if err := ma.load(); err != nil {
+			return 0, err
+		}
+	}
+	ma.sequence++
+	return ma.sequence, nil
+}
+
+// Reload loads the current sequence number of the account from horizon at once.
+func (ma *ManagedAccount) Reload() error {
+	ma.mutex.Lock()
+	defer ma.mutex.Unlock()
+	return ma.load()
+}
+
+// load must be called with the mutex held.
+func (ma *ManagedAccount) load() error {
+	account, err := ma.client.AccountDetail(horizonclient.AccountRequest{AccountID: ma.accountID})
+	if err != nil {
+		return errors.Wrap(err, "Failed to load account")
+	}
+
+	sequence, err := account.GetSequenceNumber()
+	if err != nil {
+		return errors.Wrap(err, "Failed to parse sequence number")
+	}
+
+	ma.sequence = sequence
+	ma.loaded = true
+	return nil
+}
+
+// CheckError checks the error returned by the submission of a transaction of the account. If
+// the transaction was rejected with tx_bad_seq, the sequence number is loaded from horizon
+// again before the next one is handed out, and CheckError returns true.
+func (ma *ManagedAccount) CheckError(err error) bool {
+	if !isBadSequence(err) {
+		return false
+	}
+
+	ma.mutex.Lock()
+	ma.loaded = false
+	ma.mutex.Unlock()
+	return true
+}
+
+// SubmitTransaction submits a transaction of the account, given in base 64 XDR, through the
+// client of the account and checks the error with CheckError.
+func (ma *ManagedAccount) SubmitTransaction(txeBase64 string) (hProtocol.TransactionSuccess, error) {
+	resp, err := ma.client.SubmitTransaction(txeBase64)
+	ma.CheckError(err)
+	return resp, err
+}
+
+// isBadSequence returns true if err is a horizon error for a transaction rejected with tx_bad_seq.
+func isBadSequence(err error) bool {
+	var herr *horizonclient.Error
+	switch e := errors.Cause(err).(type) {
+	case *horizonclient.Error:
+		herr = e
+	case horizonclient.Error:
+		herr = &e
+	default:
+		return false
+	}
+
+	codes, err := herr.ResultCodes()
+	return err == nil && codes.TransactionCode == "tx_bad_seq"
+}
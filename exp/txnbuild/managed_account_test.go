@@ -0,0 +1,122 @@
+package txnbuild
+
+import (
+	"sync"
+	"testing"
+
+	horizonclient "github.com/stellar/go/exp/clients/horizon"
+	"github.com/stellar/go/network"
+	hProtocol "github.com/stellar/go/protocols/horizon"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func badSequenceError() *horizonclient.Error {
+	return &horizonclient.Error{
+		Problem: problem.P{
+			Title: "Transaction Failed",
+			Extras: map[string]interface{}{
+				"result_codes": map[string]interface{}{"transaction": "tx_bad_seq"},
+			},
+		},
+	}
+}
+
+func TestManagedAccountConcurrentSequenceNumbers(t *testing.T) {
+	kp0 := newKeypair0()
+	client := &horizonclient.MockClient{}
+	client.On("AccountDetail", horizonclient.AccountRequest{AccountID: kp0.Address()}).
+		Return(hProtocol.Account{Sequence: "9605939170639897"}, nil).Once()
+
+	account := NewManagedAccount(kp0.Address(), client)
+	assert.Equal(t, kp0.Address(), account.GetAccountID())
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	seen := map[xdr.SequenceNumber]bool{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tx := Transaction{
+				SourceAccount: account,
+				Operations:    []Operation{&Inflation{}},
+				Network:       network.TestNetworkPassphrase,
+			}
+			assert.NoError(t, tx.Build())
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			assert.False(t, seen[tx.xdrTransaction.SeqNum], "sequence numbers must be unique")
+			seen[tx.xdrTransaction.SeqNum] = true
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, seen, 50)
+	for seq := xdr.SequenceNumber(9605939170639898); seq < 9605939170639898+50; seq++ {
+		assert.True(t, seen[seq], "missing sequence number %d", seq)
+	}
+	client.AssertExpectations(t)
+}
+
+func TestManagedAccountBadSequence(t *testing.T) {
+	kp0 := newKeypair0()
+	client := &horizonclient.MockClient{}
+	request := horizonclient.AccountRequest{AccountID: kp0.Address()}
+	client.On("AccountDetail", request).Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+	client.On("AccountDetail", request).Return(hProtocol.Account{Sequence: "200"}, nil).Once()
+	client.On("SubmitTransaction", "bad").Return(hProtocol.TransactionSuccess{}, badSequenceError()).Once()
+	client.On("SubmitTransaction", "good").Return(hProtocol.TransactionSuccess{Hash: "abc"}, nil).Once()
+
+	account := NewManagedAccount(kp0.Address(), client)
+	seq, err := account.IncrementSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, xdr.SequenceNumber(101), seq)
+
+	// other errors don't trigger a reload
+	resp, err := account.SubmitTransaction("good")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", resp.Hash)
+	assert.False(t, account.CheckError(errors.New("timeout")))
+	seq, err = account.IncrementSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, xdr.SequenceNumber(102), seq)
+
+	_, err = account.SubmitTransaction("bad")
+	assert.Error(t, err)
+	seq, err = account.IncrementSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, xdr.SequenceNumber(201), seq)
+
+	assert.True(t, account.CheckError(errors.Wrap(badSequenceError(), "submitting")))
+	client.AssertExpectations(t)
+}
+
+func TestManagedAccountLoadError(t *testing.T) {
+	kp0 := newKeypair0()
+	client := &horizonclient.MockClient{}
+	request := horizonclient.AccountRequest{AccountID: kp0.Address()}
+	client.On("AccountDetail", request).Return(hProtocol.Account{}, errors.New("horizon is down")).Once()
+	client.On("AccountDetail", request).Return(hProtocol.Account{Sequence: "100"}, nil).Once()
+
+	account := NewManagedAccount(kp0.Address(), client)
+	tx := Transaction{
+		SourceAccount: account,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	err := tx.Build()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "horizon is down")
+	}
+
+	require.NoError(t, account.Reload())
+	seq, err := account.IncrementSequenceNumber()
+	require.NoError(t, err)
+	assert.Equal(t, xdr.SequenceNumber(101), seq)
+	client.AssertExpectations(t)
+}
@@ -49,3 +49,22 @@ func (mh MemoHash) ToXDR() (xdr.Memo, error) {
 func (mr MemoReturn) ToXDR() (xdr.Memo, error) {
 	return xdr.NewMemo(xdr.MemoTypeMemoReturn, xdr.Hash(mr))
 }
+
+// memoFromXDR returns the Memo represented by an XDR memo, or nil if there
+// is no memo.
+func memoFromXDR(memo xdr.Memo) (Memo, error) {
+	switch memo.Type {
+	case xdr.MemoTypeMemoNone:
+		return nil, nil
+	case xdr.MemoTypeMemoText:
+		return MemoText(memo.MustText()), nil
+	case xdr.MemoTypeMemoId:
+		return MemoID(memo.MustId()), nil
+	case xdr.MemoTypeMemoHash:
+		return MemoHash(memo.MustHash()), nil
+	case xdr.MemoTypeMemoReturn:
+		return MemoReturn(memo.MustRetHash()), nil
+	default:
+		return nil, fmt.Errorf("Unknown memo type %d", memo.Type)
+	}
+}
@@ -1,10 +1,84 @@
 package txnbuild
 
 import (
+	"github.com/stellar/go/price"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
 
 // Operation represents the operation types of the Stellar network.
 type Operation interface {
 	BuildXDR() (xdr.Operation, error)
+	FromXDR(xdrOp xdr.Operation) error
+	Validate() error
+}
+
+// operationFromXDR returns the Operation of the right type for an XDR operation.
+func operationFromXDR(xdrOp xdr.Operation) (Operation, error) {
+	var op Operation
+	switch xdrOp.Body.Type {
+	case xdr.OperationTypeCreateAccount:
+		op = &CreateAccount{}
+	case xdr.OperationTypePayment:
+		op = &Payment{}
+	case xdr.OperationTypePathPayment:
+		op = &PathPayment{}
+	case xdr.OperationTypeManageOffer:
+		op = &ManageOffer{}
+	case xdr.OperationTypeCreatePassiveOffer:
+		op = &CreatePassiveOffer{}
+	case xdr.OperationTypeSetOptions:
+		op = &SetOptions{}
+	case xdr.OperationTypeChangeTrust:
+		op = &ChangeTrust{}
+	case xdr.OperationTypeAllowTrust:
+		op = &AllowTrust{}
+	case xdr.OperationTypeAccountMerge:
+		op = &AccountMerge{}
+	case xdr.OperationTypeInflation:
+		op = &Inflation{}
+	case xdr.OperationTypeManageData:
+		op = &ManageData{}
+	case xdr.OperationTypeBumpSequence:
+		op = &BumpSequence{}
+	default:
+		return nil, errors.Errorf("Unknown operation type %d", xdrOp.Body.Type)
+	}
+
+	err := op.FromXDR(xdrOp)
+	return op, err
+}
+
+// parsePrice parses a price given as a decimal string. The decimal form of a
+// price decoded from XDR may not parse back to the same fraction (think 1/3),
+// so if s is still the string form of the decoded price, that is used as is.
+func parsePrice(s string, decoded *xdr.Price) (xdr.Price, error) {
+	if decoded != nil && s == decoded.String() {
+		return *decoded, nil
+	}
+	return price.Parse(s)
+}
+
+// setOpSourceAccount sets the source account of an XDR operation, if sourceAccount is given.
+// Operations without one use the source account of their transaction.
+func setOpSourceAccount(op *xdr.Operation, sourceAccount Account) error {
+	if sourceAccount == nil {
+		return nil
+	}
+
+	var opSourceAccountID xdr.AccountId
+	err := opSourceAccountID.SetAddress(sourceAccount.GetAccountID())
+	if err != nil {
+		return err
+	}
+	op.SourceAccount = &opSourceAccountID
+	return nil
+}
+
+// accountFromXDR returns the source account of a decoded operation, or nil if it has none.
+func accountFromXDR(accountID *xdr.AccountId) Account {
+	if accountID == nil {
+		return nil
+	}
+	return &SimpleAccount{AccountID: accountID.Address()}
 }
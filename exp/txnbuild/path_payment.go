@@ -9,12 +9,13 @@ import (
 // PathPayment represents the Stellar path payment operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type PathPayment struct {
-	SendAsset   Asset
-	SendMax     string
-	Destination string
-	DestAsset   Asset
-	DestAmount  string
-	Path        []Asset
+	SendAsset     Asset
+	SendMax       string
+	Destination   string
+	DestAsset     Asset
+	DestAmount    string
+	Path          []Asset
+	SourceAccount Account
 }
 
 // BuildXDR for Payment returns a fully configured XDR Operation.
@@ -77,6 +78,73 @@ func (pp *PathPayment) BuildXDR() (xdr.Operation, error) {
 		Path:        xdrPath,
 	}
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, pp.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+// FromXDR for PathPayment initialises the txnbuild struct from the corresponding xdr Operation.
+func (pp *PathPayment) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetPathPaymentOp()
+	if !ok {
+		return errors.New("Error parsing path_payment operation from xdr")
+	}
+
+	sendAsset, err := assetFromXDR(result.SendAsset)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing send asset")
+	}
+	destAsset, err := assetFromXDR(result.DestAsset)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing destination asset")
+	}
+	var path []Asset
+	for _, xdrAsset := range result.Path {
+		asset, err := assetFromXDR(xdrAsset)
+		if err != nil {
+			return errors.Wrap(err, "Error parsing path asset")
+		}
+		path = append(path, asset)
+	}
+
+	pp.SendAsset = sendAsset
+	pp.SendMax = amount.String(result.SendMax)
+	pp.Destination = result.Destination.Address()
+	pp.DestAsset = destAsset
+	pp.DestAmount = amount.String(result.DestAmount)
+	pp.Path = path
+	pp.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
+
+// Validate for PathPayment checks the fields of the operation before it is built.
+func (pp *PathPayment) Validate() error {
+	if err := validateAsset("SendAsset", pp.SendAsset); err != nil {
+		return err
+	}
+	if err := validateAmount("SendMax", pp.SendMax, false); err != nil {
+		return err
+	}
+	if err := validateAccountID("Destination", pp.Destination); err != nil {
+		return err
+	}
+	if err := validateAsset("DestAsset", pp.DestAsset); err != nil {
+		return err
+	}
+	if err := validateAmount("DestAmount", pp.DestAmount, false); err != nil {
+		return err
+	}
+	if len(pp.Path) > 5 {
+		return newValidationError("Path", "Path has %d assets, the maximum is 5", len(pp.Path))
+	}
+	for _, asset := range pp.Path {
+		if err := validateAsset("Path", asset); err != nil {
+			return err
+		}
+	}
+	return validateSourceAccount(pp.SourceAccount)
 }
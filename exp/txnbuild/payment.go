@@ -9,9 +9,10 @@ import (
 // Payment represents the Stellar payment operation. See
 // https://www.stellar.org/developers/guides/concepts/list-of-operations.html
 type Payment struct {
-	Destination string
-	Amount      string
-	Asset       Asset
+	Destination   string
+	Amount        string
+	Asset         Asset
+	SourceAccount Account
 }
 
 // BuildXDR for Payment returns a fully configured XDR Operation.
@@ -43,6 +44,47 @@ func (p *Payment) BuildXDR() (xdr.Operation, error) {
 		Asset:       xdrAsset,
 	}
 	body, err := xdr.NewOperationBody(opType, xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
+
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, p.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
+}
+
+// FromXDR for Payment initialises the txnbuild struct from the corresponding xdr Operation.
+func (p *Payment) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetPaymentOp()
+	if !ok {
+		return errors.New("Error parsing payment operation from xdr")
+	}
+
+	asset, err := assetFromXDR(result.Asset)
+	if err != nil {
+		return errors.Wrap(err, "Error parsing asset in payment operation")
+	}
+
+	p.Destination = result.Destination.Address()
+	p.Amount = amount.String(result.Amount)
+	p.Asset = asset
+	p.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+// Validate for Payment checks the fields of the operation before it is built.
+func (p *Payment) Validate() error {
+	if err := validateAccountID("Destination", p.Destination); err != nil {
+		return err
+	}
+	if err := validateAmount("Amount", p.Amount, false); err != nil {
+		return err
+	}
+	if p.Asset == nil {
+		return newValidationError("Asset", "You must specify an asset for payment")
+	}
+	if err := validateAsset("Asset", p.Asset); err != nil {
+		return err
+	}
+	return validateSourceAccount(p.SourceAccount)
 }
@@ -1,6 +1,9 @@
 package txnbuild
 
 import (
+	"crypto/sha256"
+
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
@@ -27,11 +30,49 @@ type Threshold uint8
 // Signer represents the Signer in a SetOptions operation.
 // If the signer already exists, it is updated.
 // If the weight is 0, the signer is deleted.
+// Address is the strkey of any type of signer key: an account ID (G...), the hash of a
+// pre-authorized transaction (T...) or a hash(x) (X...). See NewPreAuthTxSigner and
+// NewHashXSigner for the latter two.
 type Signer struct {
 	Address string
 	Weight  Threshold
 }
 
+// NewPreAuthTxSigner returns a Signer that authorizes exactly the given transaction, once.
+// The transaction must already be built for the network it will be submitted to, so that
+// its hash is final. Its source account needs to have the signer added before submission.
+func NewPreAuthTxSigner(tx *Transaction, weight Threshold) (*Signer, error) {
+	if len(tx.xdrTransaction.Operations) == 0 {
+		return nil, errors.New("Transaction must be built before a pre-auth signer can be derived from it")
+	}
+
+	hash, err := tx.Hash()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to hash transaction")
+	}
+
+	address, err := strkey.Encode(strkey.VersionByteHashTx, hash[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode pre-auth transaction signer")
+	}
+	return &Signer{Address: address, Weight: weight}, nil
+}
+
+// NewHashXSigner returns a Signer for the SHA-256 hash of preimage. Transactions are signed by
+// it by revealing the preimage, see Transaction.SignHashX.
+func NewHashXSigner(preimage []byte, weight Threshold) (*Signer, error) {
+	if len(preimage) > xdr.Signature(preimage).XDRMaxSize() {
+		return nil, errors.New("preimage cannot be more than 64 bytes")
+	}
+
+	hash := sha256.Sum256(preimage)
+	address, err := strkey.Encode(strkey.VersionByteHashX, hash[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to encode hash(x) signer")
+	}
+	return &Signer{Address: address, Weight: weight}, nil
+}
+
 // NewHomeDomain is syntactic sugar that makes instantiating SetOptions more convenient.
 func NewHomeDomain(hd string) *string {
 	return &hd
@@ -59,6 +100,7 @@ type SetOptions struct {
 	HighThreshold        *Threshold
 	HomeDomain           *string
 	Signer               *Signer
+	SourceAccount        Account
 	xdrOp                xdr.SetOptionsOp
 }
 
@@ -86,8 +128,13 @@ func (so *SetOptions) BuildXDR() (xdr.Operation, error) {
 
 	opType := xdr.OperationTypeSetOptions
 	body, err := xdr.NewOperationBody(opType, so.xdrOp)
+	if err != nil {
+		return xdr.Operation{}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	}
 
-	return xdr.Operation{Body: body}, errors.Wrap(err, "Failed to build XDR OperationBody")
+	op := xdr.Operation{Body: body}
+	err = setOpSourceAccount(&op, so.SourceAccount)
+	return op, errors.Wrap(err, "Failed to set operation source account")
 }
 
 // handleInflation for SetOptions sets the XDR inflation destination.
@@ -194,3 +241,101 @@ func (so *SetOptions) handleSigner() (err error) {
 	}
 	return nil
 }
+
+// FromXDR for SetOptions initialises the txnbuild struct from the corresponding xdr Operation.
+func (so *SetOptions) FromXDR(xdrOp xdr.Operation) error {
+	result, ok := xdrOp.Body.GetSetOptionsOp()
+	if !ok {
+		return errors.New("Error parsing set_options operation from xdr")
+	}
+
+	*so = SetOptions{}
+	if result.InflationDest != nil {
+		so.InflationDestination = NewInflationDestination(result.InflationDest.Address())
+	}
+	if result.ClearFlags != nil {
+		so.ClearFlags = accountFlagsFromXDR(*result.ClearFlags)
+	}
+	if result.SetFlags != nil {
+		so.SetFlags = accountFlagsFromXDR(*result.SetFlags)
+	}
+	so.MasterWeight = thresholdFromXDR(result.MasterWeight)
+	so.LowThreshold = thresholdFromXDR(result.LowThreshold)
+	so.MediumThreshold = thresholdFromXDR(result.MedThreshold)
+	so.HighThreshold = thresholdFromXDR(result.HighThreshold)
+	if result.HomeDomain != nil {
+		so.HomeDomain = NewHomeDomain(string(*result.HomeDomain))
+	}
+	if result.Signer != nil {
+		so.Signer = &Signer{
+			Address: result.Signer.Key.Address(),
+			Weight:  Threshold(result.Signer.Weight),
+		}
+	}
+	so.SourceAccount = accountFromXDR(xdrOp.SourceAccount)
+	return nil
+}
+
+// accountFlagsFromXDR splits an XDR account flags bitmask into its flags. Unknown bits are
+// kept together as one more flag, and an empty bitmask gives a single zero flag, so that
+// BuildXDR produces the same bitmask again.
+func accountFlagsFromXDR(flags xdr.Uint32) []AccountFlag {
+	var res []AccountFlag
+	rest := AccountFlag(flags)
+	for _, flag := range []AccountFlag{AuthRequired, AuthRevocable, AuthImmutable} {
+		if rest&flag != 0 {
+			res = append(res, flag)
+			rest &^= flag
+		}
+	}
+	if rest != 0 || len(res) == 0 {
+		res = append(res, rest)
+	}
+	return res
+}
+
+func thresholdFromXDR(t *xdr.Uint32) *Threshold {
+	if t == nil {
+		return nil
+	}
+	return NewThreshold(Threshold(*t))
+}
+
+// Validate for SetOptions checks the fields of the operation before it is built.
+func (so *SetOptions) Validate() error {
+	if so.InflationDestination != nil {
+		if err := validateAccountID("InflationDestination", *so.InflationDestination); err != nil {
+			return err
+		}
+	}
+
+	knownFlags := AuthRequired | AuthRevocable | AuthImmutable
+	var setFlags, clearFlags AccountFlag
+	for _, flag := range so.SetFlags {
+		setFlags |= flag
+	}
+	for _, flag := range so.ClearFlags {
+		clearFlags |= flag
+	}
+	if setFlags&^knownFlags != 0 {
+		return newValidationError("SetFlags", "Unknown account flags %d", setFlags&^knownFlags)
+	}
+	if clearFlags&^knownFlags != 0 {
+		return newValidationError("ClearFlags", "Unknown account flags %d", clearFlags&^knownFlags)
+	}
+	if setFlags&clearFlags != 0 {
+		return newValidationError("ClearFlags", "Account flags %d can't be both set and cleared", setFlags&clearFlags)
+	}
+
+	if so.HomeDomain != nil && len(*so.HomeDomain) > 32 {
+		return newValidationError("HomeDomain", "HomeDomain must be 32 characters or less")
+	}
+
+	if so.Signer != nil {
+		var key xdr.SignerKey
+		if err := key.SetAddress(so.Signer.Address); err != nil {
+			return newValidationError("Signer", "Invalid signer address '%s'", so.Signer.Address)
+		}
+	}
+	return validateSourceAccount(so.SourceAccount)
+}
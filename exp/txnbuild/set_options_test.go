@@ -1,10 +1,13 @@
 package txnbuild
 
 import (
+	"crypto/sha256"
 	"testing"
 
+	"github.com/stellar/go/network"
 	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestHandleSetFlagsThreeDifferent(t *testing.T) {
@@ -136,3 +139,70 @@ func TestEmptyHomeDomainOK(t *testing.T) {
 	assert.Equal(t, string(*options.xdrOp.HomeDomain), "", "empty string home domain is set")
 
 }
+
+func TestPreAuthTxSigner(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	_, err := NewPreAuthTxSigner(&tx, 1)
+	assert.Error(t, err, "unbuilt transactions have no hash")
+
+	require.NoError(t, tx.Build())
+	signer, err := NewPreAuthTxSigner(&tx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, byte('T'), signer.Address[0])
+	assert.Equal(t, Threshold(1), signer.Weight)
+
+	options := SetOptions{Signer: signer}
+	xdrOp, err := options.BuildXDR()
+	require.NoError(t, err)
+
+	hash, err := tx.Hash()
+	require.NoError(t, err)
+	xdrSigner := xdrOp.Body.MustSetOptionsOp().Signer
+	require.NotNil(t, xdrSigner)
+	assert.Equal(t, xdr.SignerKeyTypeSignerKeyTypePreAuthTx, xdrSigner.Key.Type)
+	assert.Equal(t, xdr.Uint256(hash), xdrSigner.Key.MustPreAuthTx())
+
+	var decoded SetOptions
+	require.NoError(t, decoded.FromXDR(xdrOp))
+	assert.Equal(t, signer, decoded.Signer)
+}
+
+func TestHashXSigner(t *testing.T) {
+	preimage := []byte("open sesame")
+	signer, err := NewHashXSigner(preimage, 10)
+	require.NoError(t, err)
+	assert.Equal(t, byte('X'), signer.Address[0])
+
+	options := SetOptions{Signer: signer}
+	xdrOp, err := options.BuildXDR()
+	require.NoError(t, err)
+
+	xdrSigner := xdrOp.Body.MustSetOptionsOp().Signer
+	require.NotNil(t, xdrSigner)
+	assert.Equal(t, xdr.SignerKeyTypeSignerKeyTypeHashX, xdrSigner.Key.Type)
+	assert.Equal(t, xdr.Uint256(sha256.Sum256(preimage)), xdrSigner.Key.MustHashX())
+	assert.Equal(t, xdr.Uint32(10), xdrSigner.Weight)
+
+	// Signatures from SignHashX carry the hint of the signer.
+	kp0 := newKeypair0()
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	require.NoError(t, tx.Build())
+	require.NoError(t, tx.SignHashX(preimage))
+	key := xdrSigner.Key.MustHashX()
+	assert.Equal(t, key[28:], tx.Signatures()[0].Hint[:])
+
+	_, err = NewHashXSigner(make([]byte, 65), 1)
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package txnbuild
+
+import (
+	"time"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// TimeoutInfinite is the MaxTime of Timebounds without an upper bound. This is usually not what you want.
+const TimeoutInfinite = int64(0)
+
+// Timebounds represents the time window during which a Stellar transaction is considered valid.
+//
+// MinTime and MaxTime represent Stellar timebounds - a window of time over which the Transaction will be
+// considered valid. In general, almost all Transactions benefit from setting an upper timebound, because once submitted,
+// the status of a pending Transaction may remain unresolved for a long time if the network is congested.
+// With an upper timebound, the submitter has a guaranteed time at which the Transaction is known to have either
+// succeeded or failed, and can then take appropriate action (e.g. to resubmit or mark as resolved).
+//
+// Create a Timebounds struct using one of NewTimebounds(), NewTimeout(), or NewInfiniteTimeout(). A
+// Transaction with zero Timebounds has no time bounds at all.
+type Timebounds struct {
+	MinTime int64
+	MaxTime int64
+}
+
+// Validate for Timebounds sanity-checks the configured Timebound limits.
+func (tb *Timebounds) Validate() error {
+	if tb.MinTime < 0 {
+		return errors.New("invalid timebound: minTime cannot be negative")
+	}
+
+	if tb.MaxTime < 0 {
+		return errors.New("invalid timebound: maxTime cannot be negative")
+	}
+
+	if tb.MaxTime != TimeoutInfinite && tb.MaxTime < tb.MinTime {
+		return errors.New("invalid timebound: maxTime < minTime")
+	}
+
+	return nil
+}
+
+// NewTimebounds is a factory method that constructs a Timebounds object from a min and max time,
+// given as UNIX timestamps.
+func NewTimebounds(minTime, maxTime int64) Timebounds {
+	return Timebounds{minTime, maxTime}
+}
+
+// NewTimeout is a factory method that sets the MaxTime to be the duration in seconds in the
+// future specified by 'timeout'.
+func NewTimeout(timeout int64) Timebounds {
+	return Timebounds{0, time.Now().UTC().Unix() + timeout}
+}
+
+// NewInfiniteTimeout is a factory method that sets the MaxTime to a value representing an indefinite
+// upper time bound. This is rarely needed, but is helpful for certain smart contracts, and for
+// deterministic testing.
+func NewInfiniteTimeout() Timebounds {
+	return Timebounds{0, TimeoutInfinite}
+}
@@ -6,6 +6,7 @@ package txnbuild
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
 	"fmt"
 
@@ -30,6 +31,7 @@ type Transaction struct {
 	Memo           Memo
 	xdrEnvelope    *xdr.TransactionEnvelope
 	Network        string
+	Timebounds     Timebounds
 }
 
 // Hash provides a signable object representing the Transaction on the specified network.
@@ -72,27 +74,55 @@ func (tx *Transaction) SetDefaultFee() {
 }
 
 // Build for Transaction completely configures the Transaction. After calling Build,
-// the Transaction is ready to be serialised or signed.
+// the Transaction is ready to be serialised or signed. The Transaction and each of its
+// operations are validated first: invalid fields of the Transaction are reported as a
+// *ValidationError, and invalid operations as an *OperationError.
 func (tx *Transaction) Build() error {
+	err := tx.Validate()
+	if err != nil {
+		return err
+	}
+	for i, op := range tx.Operations {
+		err = op.Validate()
+		if err != nil {
+			return &OperationError{Index: i, Operation: op, Err: err}
+		}
+	}
+
 	// Set account ID in XDR
-	// TODO: Validate provided key before going further
-	tx.xdrTransaction.SourceAccount.SetAddress(tx.SourceAccount.GetAccountID())
+	err = tx.xdrTransaction.SourceAccount.SetAddress(tx.SourceAccount.GetAccountID())
+	if err != nil {
+		return errors.Wrap(err, "Failed to set source account address")
+	}
 
-	// TODO: Validate Seq Num is present in struct
 	seqnum, err := tx.SourceAccount.IncrementSequenceNumber()
 	if err != nil {
 		return errors.Wrap(err, "Failed to parse sequence number")
 	}
+	// Accounts that can't tell their sequence number before it's incremented are only
+	// checked here
+	err = validateSequenceNumber(seqnum)
+	if err != nil {
+		return err
+	}
 	tx.xdrTransaction.SeqNum = seqnum
 
-	for _, op := range tx.Operations {
+	for i, op := range tx.Operations {
 		xdrOperation, err := op.BuildXDR()
 		if err != nil {
-			return errors.Wrap(err, fmt.Sprintf("Failed to build operation %T", op))
+			return &OperationError{Index: i, Operation: op, Err: err}
 		}
 		tx.xdrTransaction.Operations = append(tx.xdrTransaction.Operations, xdrOperation)
 	}
 
+	// Set the time bounds, if any
+	if tx.Timebounds != (Timebounds{}) {
+		tx.xdrTransaction.TimeBounds = &xdr.TimeBounds{
+			MinTime: xdr.Uint64(tx.Timebounds.MinTime),
+			MaxTime: xdr.Uint64(tx.Timebounds.MaxTime),
+		}
+	}
+
 	// Handle the memo, if one is present
 	if tx.Memo != nil {
 		xdrMemo, err := tx.Memo.ToXDR()
@@ -108,16 +138,12 @@ func (tx *Transaction) Build() error {
 	return nil
 }
 
-// Sign for Transaction signs a previously built transaction. A signed transaction may be
-// submitted to the network.
-func (tx *Transaction) Sign(kp *keypair.Full) error {
+// Sign for Transaction signs a previously built transaction with each of the given keypairs,
+// in order. A signed transaction may be submitted to the network.
+func (tx *Transaction) Sign(kps ...*keypair.Full) error {
 	// TODO: Only sign if Transaction has been previously built
 	// TODO: Validate network set before sign
-	// Initialise transaction envelope
-	if tx.xdrEnvelope == nil {
-		tx.xdrEnvelope = &xdr.TransactionEnvelope{}
-		tx.xdrEnvelope.Tx = tx.xdrTransaction
-	}
+	tx.initEnvelope()
 
 	// Hash the transaction
 	hash, err := tx.Hash()
@@ -126,14 +152,101 @@ func (tx *Transaction) Sign(kp *keypair.Full) error {
 	}
 
 	// Sign the hash
-	// TODO: Allow multiple signers
-	sig, err := kp.SignDecorated(hash[:])
-	if err != nil {
-		return errors.Wrap(err, "Failed to sign transaction")
+	for _, kp := range kps {
+		sig, err := kp.SignDecorated(hash[:])
+		if err != nil {
+			return errors.Wrap(err, "Failed to sign transaction")
+		}
+
+		// Append the signature to the envelope
+		tx.xdrEnvelope.Signatures = append(tx.xdrEnvelope.Signatures, sig)
+	}
+
+	return nil
+}
+
+// SignHashX signs a previously built transaction with the preimage of a hash(x) signer of
+// its source account. The preimage is at most 64 bytes long.
+func (tx *Transaction) SignHashX(preimage []byte) error {
+	if len(preimage) > xdr.Signature(preimage).XDRMaxSize() {
+		return errors.New("preimage cannot be more than 64 bytes")
 	}
+	tx.initEnvelope()
 
-	// Append the signature to the envelope
+	// The hint is the last 4 bytes of the hash the signer was set up with
+	preimageHash := sha256.Sum256(preimage)
+	var hint [4]byte
+	copy(hint[:], preimageHash[len(preimageHash)-4:])
+
+	sig := xdr.DecoratedSignature{
+		Hint:      xdr.SignatureHint(hint),
+		Signature: xdr.Signature(preimage),
+	}
 	tx.xdrEnvelope.Signatures = append(tx.xdrEnvelope.Signatures, sig)
 
 	return nil
 }
+
+// initEnvelope initialises the transaction envelope before the first signature is added.
+func (tx *Transaction) initEnvelope() {
+	if tx.xdrEnvelope == nil {
+		tx.xdrEnvelope = &xdr.TransactionEnvelope{}
+		tx.xdrEnvelope.Tx = tx.xdrTransaction
+	}
+}
+
+// Signatures returns the signatures of the Transaction, in the order they were added.
+func (tx *Transaction) Signatures() []xdr.DecoratedSignature {
+	if tx.xdrEnvelope == nil {
+		return nil
+	}
+	return tx.xdrEnvelope.Signatures
+}
+
+// TransactionFromXDR parses the base 64 XDR representation of a transaction envelope, as
+// returned by Base64, back into a Transaction. The Transaction is already built and keeps
+// the signatures of the envelope: it can be inspected, signed further (once Network is set)
+// and serialised again, but must not be built again. Its SourceAccount is a SimpleAccount
+// holding the sequence number the transaction was built from.
+func TransactionFromXDR(txeB64 string) (Transaction, error) {
+	var xdrEnv xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(txeB64, &xdrEnv)
+	if err != nil {
+		return Transaction{}, errors.Wrap(err, "Unable to unmarshal transaction envelope")
+	}
+
+	xdrTx := xdrEnv.Tx
+	sourceAccount := NewSimpleAccount(xdrTx.SourceAccount.Address(), int64(xdrTx.SeqNum)-1)
+	tx := Transaction{
+		SourceAccount:  &sourceAccount,
+		xdrTransaction: xdrTx,
+		xdrEnvelope:    &xdrEnv,
+	}
+
+	if xdrTx.TimeBounds != nil {
+		tx.Timebounds = NewTimebounds(int64(xdrTx.TimeBounds.MinTime), int64(xdrTx.TimeBounds.MaxTime))
+	}
+
+	if len(xdrTx.Operations) > 0 {
+		tx.BaseFee = uint32(xdrTx.Fee) / uint32(len(xdrTx.Operations))
+	}
+
+	tx.Memo, err = memoFromXDR(xdrTx.Memo)
+	if err != nil {
+		return Transaction{}, errors.Wrap(err, "Unable to parse memo")
+	}
+
+	for i, xdrOp := range xdrTx.Operations {
+		op, err := operationFromXDR(xdrOp)
+		if err != nil {
+			return Transaction{}, errors.Wrap(err, fmt.Sprintf("Unable to parse operation %d", i))
+		}
+		// The asset of an allow trust operation is issued by its source account.
+		if at, ok := op.(*AllowTrust); ok && at.Type.GetIssuer() == "" {
+			at.Type = CreditAsset{Code: at.Type.GetCode(), Issuer: sourceAccount.AccountID}
+		}
+		tx.Operations = append(tx.Operations, op)
+	}
+
+	return tx, nil
+}
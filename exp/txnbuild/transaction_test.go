@@ -1,11 +1,14 @@
 package txnbuild
 
 import (
+	"crypto/sha256"
 	"testing"
+	"time"
 
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -610,3 +613,291 @@ func TestMemoReturn(t *testing.T) {
 	expected := "AAAAAH4RyzTWNfXhqwLUoCw91aWkZtgIzY8SAVkIPc0uFVmYAAAAZAAMLgoAAAABAAAAAAAAAAQBAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAEAAAAAAAAACwAAAAAAAAABAAAAAAAAAAEuFVmYAAAAQNhrY46fggs+TnOYvh3ILgWqmXjkW0968s00si5RLdxFh2/A7TTGgmBTarTEtF21hsAyNmW+0YkqVVzJ7eFAXAk="
 	assert.Equal(t, expected, received, "Base 64 XDR should match")
 }
+
+func TestTransactionFromXDR(t *testing.T) {
+	// Envelopes built by the tests above, keyed by test name.
+	envelopes := map[string]string{
+		"Inflation":                      "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAiII0AAAAaAAAAAAAAAAAAAAABAAAAAAAAAAkAAAAAAAAAAeoucsUAAABAWqznvTxLfn6Q+zIloGmLDXCJQWsFPlfIf/EVFF+FfpL/gNbsvTC/U2G/ZtxMTgvqTLsBJfZAailGvPS04rfYCw==",
+		"CreateAccount":                  "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAiII0AAAAaAAAAAAAAAAAAAAABAAAAAAAAAAAAAAAAhODe2rwbSS+e+giFk8xgmxj70pVXzEADo3GG0rEhdlQAAAAABfXhAAAAAAAAAAAB6i5yxQAAAEBa4swhXSxQ2SYXoT0FcwIrrslFrv/Q/pnXK2+f6XigqjxW0yjNQwIrpVZuNz4zNGXB3DULxyYkUi8wDwwbiKIB",
+		"Payment":                        "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAiII0AAAAbAAAAAAAAAAAAAAABAAAAAAAAAAEAAAAAfhHLNNY19eGrAtSgLD3VpaRm2AjNjxIBWQg9zS4VWZgAAAAAAAAAAAX14QAAAAAAAAAAAeoucsUAAABA5rSL7gy8OGiMq2Rocvv6l6HwOdePwhIMw2aJ2j5mVumAmeADjMeeCcGQIj3A7bISo6eWoF49w3qcd7uBS4j6AQ==",
+		"BumpSequence":                   "AAAAACXK8doPx27P6IReQlRRuweSSUiUfjqgyswxiu3Sh2R+AAAAZAAiILoAAAAIAAAAAAAAAAAAAAABAAAAAAAAAAsAIiC6AAAAbAAAAAAAAAAB0odkfgAAAEDLsgDc3tPETqlKxVMF16UePDbSXQ1X0i5b3U3DRHDEchU91YwsDb4oMZrCj0mwKhkiXzCUyg9pPmUG/vKtQVQD",
+		"AccountMerge":                   "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAALAAAAAAAAAAAAAAABAAAAAAAAAAgAAAAAJcrx2g/Hbs/ohF5CVFG7B5JJSJR+OqDKzDGK7dKHZH4AAAAAAAAAAeoucsUAAABAz5wZN8BluFTXbzGyKYTrQJayT/8Ze5tForHjgkXwY9fIB/hINwHHQ+2wdBN5v6tvA1L6dfS76AytudjkX8CjDg==",
+		"ManageData":                     "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAALAAAAAAAAAAAAAAABAAAAAAAAAAoAAAAQRnJ1aXQgcHJlZmVyZW5jZQAAAAEAAAAFQXBwbGUAAAAAAAAAAAAAAeoucsUAAABAncYXM9JYk3FN1rcmjN58P1SoWHgCYSK1ckueZF4Ii7f42HZX5+z/h3CjxhCCwA7QK6s4uZ4n5ba3Ujh0x27YAQ==",
+		"ManageDataRemoveDataEntry":      "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAWAAAAAAAAAAAAAAABAAAAAAAAAAoAAAAQRnJ1aXQgcHJlZmVyZW5jZQAAAAAAAAAAAAAAAeoucsUAAABAvxTjMVAHpIn8EJOznQ5ffLccnaEP1HJcHP/FkVMGzRvtSUOj/F55ABajmUe/WteiU7eJgzbKkgHvIMv1JB5XBw==",
+		"SetOptionsInflationDestination": "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAcAAAAAAAAAAAAAAABAAAAAAAAAAUAAAABAAAAACXK8doPx27P6IReQlRRuweSSUiUfjqgyswxiu3Sh2R+AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAeoucsUAAABAR/HVP3lr4CiR669LU1FZjO1uBQO36TduvYzOnSy786eNNNx+rSEhAt/w1iBdK9fKL8uw9FM+YH4eWOEixRu0Dw==",
+		"SetOptionsSetFlags":             "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAfAAAAAAAAAAAAAAABAAAAAAAAAAUAAAAAAAAAAAAAAAEAAAADAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAHqLnLFAAAAQJ5MwX8wWHVyF/QhY9qkD9+NoSGf9TH1dyfHxc2l9jL3/1sw8cgNYx1XRAEpaMq9BZtpZ0+zLjc0TAq2B+jSKAM=",
+		"SetOptionsClearFlags":           "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAgAAAAAAAAAAAAAAABAAAAAAAAAAUAAAAAAAAAAQAAAAMAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAHqLnLFAAAAQK2hb0/FTkNzS/C7CAWbrlgo6Wx5lJZdbt6cup723nGlGrkz92pvcrOQLZUBH3akI9Zdin51Wk4dvihghBFrcA8=",
+		"SetOptionsMasterWeight":         "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAhAAAAAAAAAAAAAAABAAAAAAAAAAUAAAAAAAAAAAAAAAAAAAABAAAACgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAHqLnLFAAAAQOjpX3xs5uRACzzIJ9JZYyYjTd3kdEhhNNEwTJPS3jqd+gnwefJ/HKsHCL3S6WociUyn1B6nlhO63ZIu/+SPTwc=",
+		"SetOptionsThresholds":           "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAjAAAAAAAAAAAAAAABAAAAAAAAAAUAAAAAAAAAAAAAAAAAAAAAAAAAAQAAAAEAAAABAAAAAgAAAAEAAAACAAAAAAAAAAAAAAAAAAAAAeoucsUAAABArWZCMkVyzoKl3ZAh4Pu+7/iy45ffPiC525qXWrFdWcC0NC18SMwg96gmamyIilDxCeN+8Xn+WzhziaSAbGbdBg==",
+		"SetOptionsHomeDomain":           "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAmAAAAAAAAAAAAAAABAAAAAAAAAAUAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAAABxMb3ZlbHlMdW1lbnNMb29rTHVtaW5vdXMuY29tAAAAAAAAAAAAAAAB6i5yxQAAAEAXjzYPYoUdQ617Ltn4wwefJLuy0P3S3dOeFTOWlZxi9KeKsVgqOQ+B+hms2JdpSWRodr0N0Nj6LsZhTjLbv4wO",
+		"SetOptionsSigner":               "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAAmAAAAAAAAAAAAAAABAAAAAAAAAAUAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAEAAAAAJcrx2g/Hbs/ohF5CVFG7B5JJSJR+OqDKzDGK7dKHZH4AAAAEAAAAAAAAAAHqLnLFAAAAQB1P8K0BXzpWdiXwBoMkGLJ8V/HhFQkq+NXmf7DhFVOHQid8Rz2K9cGvlclXWfUqKB60niWlCPTFtmzrKpWVTQ0=",
+		"MultipleOperations":             "AAAAACXK8doPx27P6IReQlRRuweSSUiUfjqgyswxiu3Sh2R+AAAAyAAiILoAAAAIAAAAAAAAAAAAAAACAAAAAAAAAAkAAAAAAAAACwAiILoAAABsAAAAAAAAAAHSh2R+AAAAQGx5xAPuF3rH3/KSHXduYYvE/Qw4CAseF2F0oSacIYi8e320OW07lr9VF8XEcDqMSVNhkFopoh5P0ZSixcTxyQI=",
+		"ChangeTrust":                    "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAAA9AAAAAAAAAAAAAAABAAAAAAAAAAYAAAABQUJDRAAAAAAlyvHaD8duz+iEXkJUUbsHkklIlH46oMrMMYrt0odkfgAAAAAF9eEAAAAAAAAAAAHqLnLFAAAAQCOIEK9f3CMCfb5CzB2G2q6PBNx1P0R71v1hf8JXEIICXjWwy6hT140PP8EV4/VcARlA9a09a4Rr8dRNnpeOwAI=",
+		"ChangeTrustDeleteTrustline":     "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAABDAAAAAAAAAAAAAAABAAAAAAAAAAYAAAABQUJDRAAAAAAlyvHaD8duz+iEXkJUUbsHkklIlH46oMrMMYrt0odkfgAAAAAAAAAAAAAAAAAAAAHqLnLFAAAAQEop/qQ5+2GTSQxZWzL4BPKsAi47VVNxnbtWgSAZvJOqz0yG0GJaTpUUYskuEo1haBg0UDbQF4M0PIK4l0Pzegg=",
+		"AllowTrust":                     "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAABPAAAAAAAAAAAAAAABAAAAAAAAAAcAAAAAJcrx2g/Hbs/ohF5CVFG7B5JJSJR+OqDKzDGK7dKHZH4AAAABQUJDRAAAAAEAAAAAAAAAAeoucsUAAABAlP4A5hdKUQU18MY6wmf4GugGNnCUklsV9/aRoTv8Q2yw7skm5nkFExnjhgEya6AM7iCR6oaf2C0VhrU4oEEODQ==",
+		"ManageOfferNewOffer":            "AAAAACXK8doPx27P6IReQlRRuweSSUiUfjqgyswxiu3Sh2R+AAAAZAAAJWoAAAAFAAAAAAAAAAAAAAABAAAAAAAAAAMAAAAAAAAAAUFCQ0QAAAAA4Nxt4XJcrGZRYrUvrOc1sooiQ+QdEk1suS1wo+oucsUAAAAAO5rKAAAAAAEAAABkAAAAAAAAAAAAAAAAAAAAAdKHZH4AAABAe/TZt+6EAWp8BxbOa+x8xZ+oKF83SKghhzfMaih0gn9Ark2kE+ZOdiftY+DDjLF8RVzbzWGFvHgGBCt5pY5lCg==",
+		"ManageOfferDeleteOffer":         "AAAAACXK8doPx27P6IReQlRRuweSSUiUfjqgyswxiu3Sh2R+AAAAZAAAJWoAAAASAAAAAAAAAAAAAAABAAAAAAAAAAMAAAAAAAAAAUZBS0UAAAAAQQeAZMSVhmLzYCQaIl1KNrY4FpTZoRzDCncBje0UnbEAAAAAAAAAAAAAAAEAAAABAAAAAAAslJYAAAAAAAAAAdKHZH4AAABAkj1T85v1atBk0k0QenWxbcDxRAJs3PdkijBFFGVGhGJYcaMdQoEBpvb8hJEzpaJ/feK9pa00YCMGyizGfr4rDw==",
+		"ManageOfferUpdateOffer":         "AAAAACXK8doPx27P6IReQlRRuweSSUiUfjqgyswxiu3Sh2R+AAAAZAAAJWoAAAAKAAAAAAAAAAAAAAABAAAAAAAAAAMAAAAAAAAAAUFCQ0QAAAAA4Nxt4XJcrGZRYrUvrOc1sooiQ+QdEk1suS1wo+oucsUAAAAAHc1lAAAAAAEAAAAyAAAAAAAmHFwAAAAAAAAAAdKHZH4AAABA7j/x1HuvyMiH9Q59sjLmFLak76hJGQvjx6ckTzuuI0tpBrB/7Wfra8JrWrzajTJGMoQGwdDND5rEi/jTxWMjCQ==",
+		"CreatePassiveOffer":             "AAAAACXK8doPx27P6IReQlRRuweSSUiUfjqgyswxiu3Sh2R+AAAAZAAAJWoAAAANAAAAAAAAAAAAAAABAAAAAAAAAAQAAAAAAAAAAUFCQ0QAAAAA4Nxt4XJcrGZRYrUvrOc1sooiQ+QdEk1suS1wo+oucsUAAAAABfXhAAAAAAEAAAABAAAAAAAAAAHSh2R+AAAAQIDB0yw4eH14RDnUI4Ef5eyTbkRYl2adTPAOgbZmodkhOsmXOZITw1B6RnwdDCIRSLk2ZPvq2FU8Mk50l0eK+Ag=",
+		"PathPayment":                    "AAAAAH4RyzTWNfXhqwLUoCw91aWkZtgIzY8SAVkIPc0uFVmYAAAAZAAAql0AAAADAAAAAAAAAAAAAAABAAAAAAAAAAIAAAAAAAAAAAX14QAAAAAAfhHLNNY19eGrAtSgLD3VpaRm2AjNjxIBWQg9zS4VWZgAAAAAAAAAAACYloAAAAABAAAAAUFCQ0QAAAAA4Nxt4XJcrGZRYrUvrOc1sooiQ+QdEk1suS1wo+oucsUAAAAAAAAAAS4VWZgAAABAZBS66leC0Y7UMg6jPYWh04lLWW9cLOdjWKKIWCjBTwRPmRhb5KyVsRepZdAvl8jmaLnbTk20uJ1yWbenbbbqCw==",
+		"MemoText":                       "AAAAAH4RyzTWNfXhqwLUoCw91aWkZtgIzY8SAVkIPc0uFVmYAAAAZAAMLgoAAAABAAAAAAAAAAEAAAAMVHdhcyBicmlsbGlnAAAAAQAAAAAAAAALAAAAAAAAAAEAAAAAAAAAAS4VWZgAAABAstxxDHhcXkfmDkHbe2ck2QFjh6w69VlBzqOeHbT0p0ZxS6cQrhlFZBdvBb4T5qlo0RF4D06z04ygqDqrXmiSDg==",
+		"MemoID":                         "AAAAAH4RyzTWNfXhqwLUoCw91aWkZtgIzY8SAVkIPc0uFVmYAAAAZAAMLgoAAAABAAAAAAAAAAIAAAAAAATLLwAAAAEAAAAAAAAACwAAAAAAAAABAAAAAAAAAAEuFVmYAAAAQCKqa1rqle3g8Ksdvl9J67sKdHoXvVXgsmV2QVMZskO+DhGSnyxAZBjGf7MFWuz1JoXr5VMo0zphTBRjtMWQvAA=",
+		"MemoHash":                       "AAAAAH4RyzTWNfXhqwLUoCw91aWkZtgIzY8SAVkIPc0uFVmYAAAAZAAMLgoAAAABAAAAAAAAAAMBAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAEAAAAAAAAACwAAAAAAAAABAAAAAAAAAAEuFVmYAAAAQJeRgV2MPt3E4IktlsDm6herfaR/5VTplcUUwFgBMbPyIxjZW8GEZAIUxjWBV7T9XWjzLrw7pEyldeOcC76PYwc=",
+		"MemoReturn":                     "AAAAAH4RyzTWNfXhqwLUoCw91aWkZtgIzY8SAVkIPc0uFVmYAAAAZAAMLgoAAAABAAAAAAAAAAQBAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAEAAAAAAAAACwAAAAAAAAABAAAAAAAAAAEuFVmYAAAAQNhrY46fggs+TnOYvh3ILgWqmXjkW0968s00si5RLdxFh2/A7TTGgmBTarTEtF21hsAyNmW+0YkqVVzJ7eFAXAk=",
+	}
+
+	for name, txeB64 := range envelopes {
+		tx, err := TransactionFromXDR(txeB64)
+		require.NoError(t, err, name)
+
+		received, err := tx.Base64()
+		require.NoError(t, err, name)
+		assert.Equal(t, txeB64, received, name)
+
+		// Rebuilding the decoded operations gives back the same XDR.
+		require.Len(t, tx.Operations, len(tx.xdrTransaction.Operations), name)
+		for i, op := range tx.Operations {
+			assert.NoError(t, op.Validate(), name)
+			xdrOp, err := op.BuildXDR()
+			require.NoError(t, err, name)
+			assert.Equal(t, tx.xdrTransaction.Operations[i], xdrOp, name)
+		}
+	}
+}
+
+func TestTransactionFromXDRFields(t *testing.T) {
+	kp0 := newKeypair0()
+	kp2 := newKeypair2()
+	txeB64 := "AAAAAH4RyzTWNfXhqwLUoCw91aWkZtgIzY8SAVkIPc0uFVmYAAAAZAAAql0AAAADAAAAAAAAAAAAAAABAAAAAAAAAAIAAAAAAAAAAAX14QAAAAAAfhHLNNY19eGrAtSgLD3VpaRm2AjNjxIBWQg9zS4VWZgAAAAAAAAAAACYloAAAAABAAAAAUFCQ0QAAAAA4Nxt4XJcrGZRYrUvrOc1sooiQ+QdEk1suS1wo+oucsUAAAAAAAAAAS4VWZgAAABAZBS66leC0Y7UMg6jPYWh04lLWW9cLOdjWKKIWCjBTwRPmRhb5KyVsRepZdAvl8jmaLnbTk20uJ1yWbenbbbqCw=="
+
+	tx, err := TransactionFromXDR(txeB64)
+	require.NoError(t, err)
+	assert.Equal(t, kp2.Address(), tx.SourceAccount.GetAccountID())
+	assert.Equal(t, uint32(100), tx.BaseFee)
+	assert.Nil(t, tx.Memo)
+	assert.Len(t, tx.Signatures(), 1)
+
+	require.Len(t, tx.Operations, 1)
+	expected := &PathPayment{
+		SendAsset:   NativeAsset{},
+		SendMax:     "10.0000000",
+		Destination: kp2.Address(),
+		DestAsset:   NativeAsset{},
+		DestAmount:  "1.0000000",
+		Path:        []Asset{CreditAsset{"ABCD", kp0.Address()}},
+	}
+	assert.Equal(t, expected, tx.Operations[0])
+
+	// The issuer of an AllowTrust asset is the source of the operation.
+	txeB64 = "AAAAAODcbeFyXKxmUWK1L6znNbKKIkPkHRJNbLktcKPqLnLFAAAAZAAAJLsAAABPAAAAAAAAAAAAAAABAAAAAAAAAAcAAAAAJcrx2g/Hbs/ohF5CVFG7B5JJSJR+OqDKzDGK7dKHZH4AAAABQUJDRAAAAAEAAAAAAAAAAeoucsUAAABAlP4A5hdKUQU18MY6wmf4GugGNnCUklsV9/aRoTv8Q2yw7skm5nkFExnjhgEya6AM7iCR6oaf2C0VhrU4oEEODQ=="
+	tx, err = TransactionFromXDR(txeB64)
+	require.NoError(t, err)
+	require.Len(t, tx.Operations, 1)
+	allowTrust, ok := tx.Operations[0].(*AllowTrust)
+	require.True(t, ok)
+	assert.Equal(t, CreditAsset{"ABCD", kp0.Address()}, allowTrust.Type)
+	assert.True(t, allowTrust.Authorize)
+
+	_, err = TransactionFromXDR("AAAA")
+	assert.Error(t, err)
+}
+
+func TestTransactionFromXDRAddSignature(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	sourceAccount := makeTestAccount(kp0, "9605939170639897")
+
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	require.NoError(t, tx.Build())
+	require.NoError(t, tx.Sign(kp0))
+	require.NoError(t, tx.Sign(kp1))
+	expected, err := tx.Base64()
+	require.NoError(t, err)
+
+	// Sign with the first key only and hand the envelope over to the second signer.
+	sourceAccount = makeTestAccount(kp0, "9605939170639897")
+	tx = Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	partial := buildSignEncode(tx, kp0, t)
+
+	decoded, err := TransactionFromXDR(partial)
+	require.NoError(t, err)
+	decoded.Network = network.TestNetworkPassphrase
+	require.NoError(t, decoded.Sign(kp1))
+	assert.Len(t, decoded.Signatures(), 2)
+
+	received, err := decoded.Base64()
+	require.NoError(t, err)
+	assert.Equal(t, expected, received)
+}
+
+func TestTimebounds(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Timebounds:    NewTimebounds(1546300800, 1546304400),
+		Network:       network.TestNetworkPassphrase,
+	}
+
+	received := buildSignEncode(tx, kp0, t)
+	var txe xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(received, &txe))
+	require.NotNil(t, txe.Tx.TimeBounds)
+	assert.Equal(t, xdr.Uint64(1546300800), txe.Tx.TimeBounds.MinTime)
+	assert.Equal(t, xdr.Uint64(1546304400), txe.Tx.TimeBounds.MaxTime)
+
+	decoded, err := TransactionFromXDR(received)
+	require.NoError(t, err)
+	assert.Equal(t, tx.Timebounds, decoded.Timebounds)
+
+	// Transactions without time bounds leave them out of the XDR.
+	tx = Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	require.NoError(t, tx.Build())
+	assert.Nil(t, tx.xdrTransaction.TimeBounds)
+}
+
+func TestTimeboundsInvalid(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Timebounds:    NewTimebounds(1546304400, 1546300800),
+		Network:       network.TestNetworkPassphrase,
+	}
+	err := tx.Build()
+	assert.EqualError(t, err, "invalid timebound: maxTime < minTime")
+
+	tb := NewTimebounds(-1, 0)
+	assert.Error(t, tb.Validate())
+	tb = NewInfiniteTimeout()
+	assert.NoError(t, tb.Validate())
+}
+
+func TestNewTimeout(t *testing.T) {
+	before := time.Now().UTC().Unix()
+	tb := NewTimeout(300)
+	after := time.Now().UTC().Unix()
+
+	assert.Equal(t, int64(0), tb.MinTime)
+	assert.True(t, tb.MaxTime >= before+300 && tb.MaxTime <= after+300)
+	assert.NoError(t, tb.Validate())
+}
+
+func TestOperationSourceAccount(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	kp2 := newKeypair2()
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+	opSourceAccount := NewSimpleAccount(kp1.Address(), 0)
+
+	payment := Payment{
+		Destination:   kp2.Address(),
+		Amount:        "10",
+		Asset:         NativeAsset{},
+		SourceAccount: &opSourceAccount,
+	}
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&payment, &Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+
+	received := buildSignEncode(tx, kp0, t)
+	decoded, err := TransactionFromXDR(received)
+	require.NoError(t, err)
+	require.Len(t, decoded.Operations, 2)
+
+	ops := decoded.xdrTransaction.Operations
+	require.NotNil(t, ops[0].SourceAccount)
+	assert.Equal(t, kp1.Address(), ops[0].SourceAccount.Address())
+	assert.Nil(t, ops[1].SourceAccount)
+
+	decodedPayment, ok := decoded.Operations[0].(*Payment)
+	require.True(t, ok)
+	require.NotNil(t, decodedPayment.SourceAccount)
+	assert.Equal(t, kp1.Address(), decodedPayment.SourceAccount.GetAccountID())
+	assert.Nil(t, decoded.Operations[1].(*Inflation).SourceAccount)
+
+	// An allow trust operation with its own source account has that account as issuer.
+	allowTrust := AllowTrust{
+		Trustor:       kp2.Address(),
+		Type:          CreditAsset{"ABCD", kp1.Address()},
+		Authorize:     true,
+		SourceAccount: &opSourceAccount,
+	}
+	xdrOp, err := allowTrust.BuildXDR()
+	require.NoError(t, err)
+	var decodedAllowTrust AllowTrust
+	require.NoError(t, decodedAllowTrust.FromXDR(xdrOp))
+	assert.Equal(t, CreditAsset{"ABCD", kp1.Address()}, decodedAllowTrust.Type)
+
+	invalidSource := NewSimpleAccount("GBADADDRESS", 0)
+	payment.SourceAccount = &invalidSource
+	_, err = payment.BuildXDR()
+	assert.Error(t, err)
+}
+
+func TestSignMultipleKeypairs(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	kp2 := newKeypair2()
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	require.NoError(t, tx.Build())
+	require.NoError(t, tx.Sign(kp0, kp1))
+	require.NoError(t, tx.Sign(kp2))
+
+	hash, err := tx.Hash()
+	require.NoError(t, err)
+	sigs := tx.Signatures()
+	require.Len(t, sigs, 3)
+	for i, kp := range []*keypair.Full{kp0, kp1, kp2} {
+		assert.Equal(t, xdr.SignatureHint(kp.Hint()), sigs[i].Hint)
+		assert.NoError(t, kp.Verify(hash[:], sigs[i].Signature))
+	}
+}
+
+func TestSignHashX(t *testing.T) {
+	kp0 := newKeypair0()
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+
+	tx := Transaction{
+		SourceAccount: &sourceAccount,
+		Operations:    []Operation{&Inflation{}},
+		Network:       network.TestNetworkPassphrase,
+	}
+	require.NoError(t, tx.Build())
+
+	preimage := []byte("this is a preimage for hashx transactions on the stellar network")
+	require.NoError(t, tx.SignHashX(preimage))
+
+	preimageHash := sha256.Sum256(preimage)
+	sigs := tx.Signatures()
+	require.Len(t, sigs, 1)
+	assert.Equal(t, preimageHash[28:], sigs[0].Hint[:])
+	assert.Equal(t, xdr.Signature(preimage), sigs[0].Signature)
+
+	err := tx.SignHashX(append(preimage, []byte("way too long")...))
+	assert.EqualError(t, err, "preimage cannot be more than 64 bytes")
+}
@@ -0,0 +1,173 @@
+package txnbuild
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// MaxOperations is the maximum number of operations in a Transaction.
+const MaxOperations = 100
+
+// ValidationError is returned when a field of a Transaction or an Operation is invalid. Field is
+// the name of the offending struct field.
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func newValidationError(field, format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// OperationError is returned by Transaction.Build when one of its operations is invalid or
+// can't be built. Index is the position of Operation in Transaction.Operations, and Err is the
+// underlying error, a *ValidationError if the operation failed validation.
+type OperationError struct {
+	Index     int
+	Operation Operation
+	Err       error
+}
+
+func (e *OperationError) Error() string {
+	return fmt.Sprintf("Failed to build operation %T: %s", e.Operation, e.Err)
+}
+
+// Cause returns the underlying error, so that errors.Cause finds it.
+func (e *OperationError) Cause() error {
+	return e.Err
+}
+
+// validateAccountID checks that address is a valid account ID (G...).
+func validateAccountID(field, address string) error {
+	if _, err := strkey.Decode(strkey.VersionByteAccountID, address); err != nil {
+		return newValidationError(field, "Invalid account address '%s'", address)
+	}
+	return nil
+}
+
+// validateSourceAccount checks the optional source account of an operation.
+func validateSourceAccount(sourceAccount Account) error {
+	if sourceAccount == nil {
+		return nil
+	}
+	return validateAccountID("SourceAccount", sourceAccount.GetAccountID())
+}
+
+// validateAmount checks that value parses as an amount, and that it is positive, or not
+// negative if allowZero is set.
+func validateAmount(field, value string, allowZero bool) error {
+	parsed, err := amount.Parse(value)
+	if err != nil {
+		return newValidationError(field, "Invalid amount '%s'", value)
+	}
+	if parsed < 0 || (parsed == 0 && !allowZero) {
+		return newValidationError(field, "Amount '%s' must be positive", value)
+	}
+	return nil
+}
+
+// validateAsset checks that asset is set and, for credit assets, has a valid code and issuer.
+func validateAsset(field string, asset Asset) error {
+	if asset == nil {
+		return newValidationError(field, "Asset is missing")
+	}
+	if asset.IsNative() {
+		return nil
+	}
+
+	if _, err := asset.GetType(); err != nil {
+		return newValidationError(field, "Asset code '%s' must be between 1 and 12 characters", asset.GetCode())
+	}
+	for _, c := range asset.GetCode() {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return newValidationError(field, "Asset code '%s' must be alphanumeric", asset.GetCode())
+		}
+	}
+	if _, err := strkey.Decode(strkey.VersionByteAccountID, asset.GetIssuer()); err != nil {
+		return newValidationError(field, "Invalid asset issuer '%s'", asset.GetIssuer())
+	}
+	return nil
+}
+
+// validatePrice checks that value parses as a price and is positive. decoded is the price
+// decoded by FromXDR, if any (see parsePrice).
+func validatePrice(field, value string, decoded *xdr.Price) error {
+	p, err := parsePrice(value, decoded)
+	if err != nil {
+		return newValidationError(field, "Invalid price '%s'", value)
+	}
+	if p.N <= 0 || p.D <= 0 {
+		return newValidationError(field, "Price '%s' must be positive", value)
+	}
+	return nil
+}
+
+// sequenceNumberGetter is implemented by accounts that can tell their current sequence number
+// without incrementing it, like SimpleAccount and horizon.Account.
+type sequenceNumberGetter interface {
+	GetSequenceNumber() (xdr.SequenceNumber, error)
+}
+
+// validateSequenceNumber checks the sequence number of a transaction, as returned by
+// Account.IncrementSequenceNumber. It is not positive when the sequence number of the account
+// was negative, or math.MaxInt64 which can't be incremented.
+func validateSequenceNumber(seqnum xdr.SequenceNumber) error {
+	if seqnum <= 0 {
+		return newValidationError("SourceAccount",
+			"Invalid transaction sequence number %d, the account sequence number must be between 0 and %d",
+			seqnum, int64(math.MaxInt64-1))
+	}
+	return nil
+}
+
+// Validate for Transaction checks the fields of the Transaction itself. The operations are
+// validated separately, see Build.
+func (tx *Transaction) Validate() error {
+	if tx.SourceAccount == nil {
+		return newValidationError("SourceAccount", "Transaction has no source account")
+	}
+	if err := validateAccountID("SourceAccount", tx.SourceAccount.GetAccountID()); err != nil {
+		return err
+	}
+	if account, ok := tx.SourceAccount.(sequenceNumberGetter); ok {
+		seqnum, err := account.GetSequenceNumber()
+		if err != nil {
+			return newValidationError("SourceAccount", "%s", err)
+		}
+		if err := validateSequenceNumber(seqnum + 1); err != nil {
+			return err
+		}
+	}
+
+	if len(tx.Operations) == 0 {
+		return newValidationError("Operations", "Transaction has no operations")
+	}
+	if len(tx.Operations) > MaxOperations {
+		return newValidationError("Operations", "Transaction has %d operations, the maximum is %d",
+			len(tx.Operations), MaxOperations)
+	}
+
+	if uint64(tx.BaseFee)*uint64(len(tx.Operations)) > math.MaxUint32 {
+		return newValidationError("BaseFee", "Fee of %d operations at %d stroops each overflows",
+			len(tx.Operations), tx.BaseFee)
+	}
+
+	if tx.Memo != nil {
+		if _, err := tx.Memo.ToXDR(); err != nil {
+			return newValidationError("Memo", "%s", err)
+		}
+	}
+
+	if err := tx.Timebounds.Validate(); err != nil {
+		return newValidationError("Timebounds", "%s", err)
+	}
+	return nil
+}
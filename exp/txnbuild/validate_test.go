@@ -0,0 +1,143 @@
+package txnbuild
+
+import (
+	"testing"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/support/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateTransaction(t *testing.T) {
+	kp0 := newKeypair0()
+
+	tooManyOps := make([]Operation, MaxOperations+1)
+	for i := range tooManyOps {
+		tooManyOps[i] = &Inflation{}
+	}
+	invalidSource := NewSimpleAccount("GBADADDRESS", 0)
+	negativeSequence := NewSimpleAccount(kp0.Address(), -1)
+	maxSequence := makeTestAccount(kp0, "9223372036854775807")
+
+	testCases := []struct {
+		name  string
+		tx    Transaction
+		field string
+	}{
+		{"no source account", Transaction{Operations: []Operation{&Inflation{}}}, "SourceAccount"},
+		{"invalid source account", Transaction{SourceAccount: &invalidSource, Operations: []Operation{&Inflation{}}}, "SourceAccount"},
+		{"negative sequence number", Transaction{SourceAccount: &negativeSequence, Operations: []Operation{&Inflation{}}}, "SourceAccount"},
+		{"sequence number overflow", Transaction{SourceAccount: &maxSequence, Operations: []Operation{&Inflation{}}}, "SourceAccount"},
+		{"no operations", Transaction{}, "Operations"},
+		{"too many operations", Transaction{Operations: tooManyOps}, "Operations"},
+		{"fee overflow", Transaction{Operations: []Operation{&Inflation{}, &Inflation{}}, BaseFee: 1 << 31}, "BaseFee"},
+		{"memo too long", Transaction{Operations: []Operation{&Inflation{}}, Memo: MemoText("This memo is far too long to fit")}, "Memo"},
+		{"invalid timebounds", Transaction{Operations: []Operation{&Inflation{}}, Timebounds: NewTimebounds(10, 5)}, "Timebounds"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sourceAccount := makeTestAccount(kp0, "9605939170639898")
+			tx := tc.tx
+			if tx.SourceAccount == nil && tc.field != "SourceAccount" {
+				tx.SourceAccount = &sourceAccount
+			}
+			tx.Network = network.TestNetworkPassphrase
+
+			err := tx.Build()
+			require.Error(t, err)
+			verr, ok := err.(*ValidationError)
+			require.True(t, ok, "expected a *ValidationError, got %T", err)
+			assert.Equal(t, tc.field, verr.Field)
+			assert.Equal(t, "9605939170639898", sourceAccount.Sequence, "sequence must not be consumed")
+		})
+	}
+
+	tx := Transaction{Operations: tooManyOps[:MaxOperations], BaseFee: 100}
+	sourceAccount := makeTestAccount(kp0, "9605939170639898")
+	tx.SourceAccount = &sourceAccount
+	assert.NoError(t, tx.Validate())
+}
+
+func TestValidateOperations(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	asset := CreditAsset{"ABCD", kp0.Address()}
+	invalidSource := NewSimpleAccount("GBADADDRESS", 0)
+
+	testCases := []struct {
+		name  string
+		op    Operation
+		field string
+	}{
+		{"create account destination", &CreateAccount{Destination: "GBAD", Amount: "10"}, "Destination"},
+		{"create account zero amount", &CreateAccount{Destination: kp1.Address(), Amount: "0"}, "Amount"},
+		{"payment negative amount", &Payment{Destination: kp1.Address(), Amount: "-1", Asset: NativeAsset{}}, "Amount"},
+		{"payment bad amount", &Payment{Destination: kp1.Address(), Amount: "ten", Asset: NativeAsset{}}, "Amount"},
+		{"payment asset code", &Payment{Destination: kp1.Address(), Amount: "1", Asset: CreditAsset{"AB-C", kp0.Address()}}, "Asset"},
+		{"payment asset issuer", &Payment{Destination: kp1.Address(), Amount: "1", Asset: CreditAsset{"ABC", "GBAD"}}, "Asset"},
+		{"payment source account", &Payment{Destination: kp1.Address(), Amount: "1", Asset: NativeAsset{}, SourceAccount: &invalidSource}, "SourceAccount"},
+		{"path payment path", &PathPayment{SendAsset: NativeAsset{}, SendMax: "1", Destination: kp1.Address(), DestAsset: NativeAsset{}, DestAmount: "1", Path: []Asset{asset, asset, asset, asset, asset, asset}}, "Path"},
+		{"path payment dest asset", &PathPayment{SendAsset: NativeAsset{}, SendMax: "1", Destination: kp1.Address(), DestAmount: "1"}, "DestAsset"},
+		{"manage offer price", &ManageOffer{Selling: NativeAsset{}, Buying: asset, Amount: "1", Price: "0"}, "Price"},
+		{"manage offer selling", &ManageOffer{Buying: asset, Amount: "1", Price: "1"}, "Selling"},
+		{"passive offer zero amount", &CreatePassiveOffer{Selling: NativeAsset{}, Buying: asset, Amount: "0", Price: "1"}, "Amount"},
+		{"set options inflation destination", &SetOptions{InflationDestination: NewInflationDestination("GBAD")}, "InflationDestination"},
+		{"set options unknown flag", &SetOptions{SetFlags: []AccountFlag{8}}, "SetFlags"},
+		{"set options conflicting flags", &SetOptions{SetFlags: []AccountFlag{AuthRequired}, ClearFlags: []AccountFlag{AuthRequired}}, "ClearFlags"},
+		{"set options home domain", &SetOptions{HomeDomain: NewHomeDomain("LovelyLumensLookLuminousLately.com")}, "HomeDomain"},
+		{"set options signer", &SetOptions{Signer: &Signer{Address: "GBAD", Weight: 1}}, "Signer"},
+		{"change trust native", &ChangeTrust{Line: NativeAsset{}, Limit: "10"}, "Line"},
+		{"change trust limit", &ChangeTrust{Line: asset, Limit: "-10"}, "Limit"},
+		{"allow trust trustor", &AllowTrust{Trustor: "GBAD", Type: asset, Authorize: true}, "Trustor"},
+		{"allow trust native", &AllowTrust{Trustor: kp1.Address(), Type: NativeAsset{}, Authorize: true}, "Type"},
+		{"account merge destination", &AccountMerge{Destination: "GBAD"}, "Destination"},
+		{"inflation source account", &Inflation{SourceAccount: &invalidSource}, "SourceAccount"},
+		{"manage data empty name", &ManageData{Value: []byte("value")}, "Name"},
+		{"manage data long value", &ManageData{Name: "name", Value: make([]byte, 65)}, "Value"},
+		{"bump sequence negative", &BumpSequence{BumpTo: -1}, "BumpTo"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sourceAccount := makeTestAccount(kp0, "9605939170639898")
+			tx := Transaction{
+				SourceAccount: &sourceAccount,
+				Operations:    []Operation{&Inflation{}, tc.op},
+				Network:       network.TestNetworkPassphrase,
+			}
+
+			err := tx.Build()
+			require.Error(t, err)
+			operr, ok := err.(*OperationError)
+			require.True(t, ok, "expected an *OperationError, got %T", err)
+			assert.Equal(t, 1, operr.Index)
+			assert.Equal(t, tc.op, operr.Operation)
+
+			verr, ok := errors.Cause(err).(*ValidationError)
+			require.True(t, ok, "expected a *ValidationError, got %T", errors.Cause(err))
+			assert.Equal(t, tc.field, verr.Field)
+		})
+	}
+}
+
+func TestValidateOperationsValid(t *testing.T) {
+	kp0 := newKeypair0()
+	kp1 := newKeypair1()
+	asset := CreditAsset{"ABCD", kp0.Address()}
+
+	deleteOffer := DeleteOfferOp(2921622)
+	removeTrustline := RemoveTrustlineOp(asset)
+	ops := []Operation{
+		&deleteOffer,
+		&removeTrustline,
+		&ManageData{Name: "name"},
+		&SetOptions{SetFlags: []AccountFlag{AuthRequired, AuthRevocable}, ClearFlags: []AccountFlag{AuthImmutable}},
+		&AllowTrust{Trustor: kp1.Address(), Type: CreditAsset{Code: "ABCD"}, Authorize: true},
+		&BumpSequence{},
+	}
+	for _, op := range ops {
+		assert.NoError(t, op.Validate(), "%T", op)
+	}
+}
@@ -27,7 +27,7 @@ func (s *CallbackStrategy) SanctionsCheck(data proto.AuthData, response *proto.A
 		return errors.Wrap(err, "Error connecting sanctions server")
 	}
 
-	err = parseResponse(resp, body, response)
+	err = parseResponse(resp, body, response, &response.TxStatus)
 	if err != nil {
 		return errors.Wrap(err, "Error parsing sanctions server response")
 	}
@@ -55,7 +55,7 @@ func (s *CallbackStrategy) GetUserData(data proto.AuthData, response *proto.Auth
 		return errors.Wrap(err, "Error connecting fetch info server")
 	}
 
-	err = parseResponse(resp, body, response)
+	err = parseResponse(resp, body, response, &response.InfoStatus)
 	if err != nil {
 		return errors.Wrap(err, "Error parsing fetch info server response")
 	}
@@ -80,13 +80,15 @@ func sendRequest(url string, params url.Values) (resp *http.Response, body []byt
 	return
 }
 
-func parseResponse(resp *http.Response, body []byte, response *proto.AuthResponse) error {
+// parseResponse sets status (TxStatus or InfoStatus of response) depending on the status code
+// of the callback response.
+func parseResponse(resp *http.Response, body []byte, response *proto.AuthResponse, status *proto.AuthStatus) error {
 	switch resp.StatusCode {
 	case http.StatusOK: // AuthStatusOk
-		response.TxStatus = proto.AuthStatusOk
+		*status = proto.AuthStatusOk
 		response.DestInfo = string(body)
 	case http.StatusAccepted: // AuthStatusPending
-		response.TxStatus = proto.AuthStatusPending
+		*status = proto.AuthStatusPending
 
 		var pending int
 		pendingResponseObj := pendingResponse{}
@@ -102,7 +104,7 @@ func parseResponse(resp *http.Response, body []byte, response *proto.AuthRespons
 			response.Pending = pending
 		}
 	case http.StatusForbidden: // AuthStatusDenied
-		response.TxStatus = proto.AuthStatusDenied
+		*status = proto.AuthStatusDenied
 	default:
 		return fmt.Errorf("Invalid status code from server: %d", resp.StatusCode)
 	}
@@ -0,0 +1,96 @@
+package compliance
+
+import (
+	proto "github.com/stellar/go/protocols/compliance"
+)
+
+// SanctionsCheck runs the sanctions check of the strategies and combines their decisions.
+func (s *CompositeStrategy) SanctionsCheck(data proto.AuthData, response *proto.AuthResponse) error {
+	return s.combine(
+		response,
+		func(strategy Strategy, r *proto.AuthResponse) error { return strategy.SanctionsCheck(data, r) },
+		func(r *proto.AuthResponse) *proto.AuthStatus { return &r.TxStatus },
+	)
+}
+
+// GetUserData runs the user data check of the strategies and combines their decisions.
+func (s *CompositeStrategy) GetUserData(data proto.AuthData, response *proto.AuthResponse) error {
+	return s.combine(
+		response,
+		func(strategy Strategy, r *proto.AuthResponse) error { return strategy.GetUserData(data, r) },
+		func(r *proto.AuthResponse) *proto.AuthStatus { return &r.InfoStatus },
+	)
+}
+
+// combine runs check with every strategy, as long as their decisions (the status returned by
+// status) can change the outcome, and sets the decision that takes precedence on response.
+func (s *CompositeStrategy) combine(
+	response *proto.AuthResponse,
+	check func(Strategy, *proto.AuthResponse) error,
+	status func(*proto.AuthResponse) *proto.AuthStatus,
+) error {
+	precedence := s.Precedence
+	if precedence == "" {
+		precedence = PrecedenceDenyOverrides
+	}
+
+	var decision *proto.AuthResponse
+	for _, strategy := range s.Strategies {
+		r := &proto.AuthResponse{}
+		err := check(strategy, r)
+		if err != nil {
+			return err
+		}
+
+		if *status(r) == "" {
+			continue
+		}
+
+		if decision == nil || precedence.rank(*status(r)) > precedence.rank(*status(decision)) {
+			decision = r
+		}
+
+		if precedence == PrecedenceFirstDecision || precedence.rank(*status(decision)) == maxRank {
+			break
+		}
+	}
+
+	if decision == nil {
+		*status(response) = s.Default
+		return nil
+	}
+
+	*status(response) = *status(decision)
+	if decision.Pending > response.Pending {
+		response.Pending = decision.Pending
+	}
+	if decision.DestInfo != "" {
+		response.DestInfo = decision.DestInfo
+	}
+	if decision.Error != "" {
+		response.Error = decision.Error
+	}
+	return nil
+}
+
+const maxRank = 4
+
+// rank returns how much a status takes precedence over the others.
+func (p Precedence) rank(status proto.AuthStatus) int {
+	var order []proto.AuthStatus
+	switch p {
+	case PrecedenceAllowOverrides:
+		order = []proto.AuthStatus{proto.AuthStatusDenied, proto.AuthStatusError, proto.AuthStatusPending, proto.AuthStatusOk}
+	case PrecedenceFirstDecision:
+		return maxRank
+	default:
+		order = []proto.AuthStatus{proto.AuthStatusOk, proto.AuthStatusPending, proto.AuthStatusError, proto.AuthStatusDenied}
+	}
+
+	for i, s := range order {
+		if s == status {
+			return i + 1
+		}
+	}
+	return 0
+}
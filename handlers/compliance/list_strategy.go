@@ -0,0 +1,72 @@
+package compliance
+
+import (
+	"strings"
+
+	"github.com/stellar/go/address"
+	proto "github.com/stellar/go/protocols/compliance"
+	"github.com/stellar/go/support/errors"
+)
+
+// SanctionsCheck allows or denies the transaction depending on the lists the sender is on.
+func (s *ListStrategy) SanctionsCheck(data proto.AuthData, response *proto.AuthResponse) error {
+	status, err := s.status(data.Sender)
+	if err != nil {
+		return err
+	}
+
+	response.TxStatus = status
+	return nil
+}
+
+// GetUserData allows access to customer data to the senders that are allowed, and denies it
+// to the ones that are denied.
+func (s *ListStrategy) GetUserData(data proto.AuthData, response *proto.AuthResponse) error {
+	if !data.NeedInfo {
+		response.InfoStatus = proto.AuthStatusOk
+		return nil
+	}
+
+	status, err := s.status(data.Sender)
+	if err != nil {
+		return err
+	}
+
+	response.InfoStatus = status
+	return nil
+}
+
+func (s *ListStrategy) status(sender string) (proto.AuthStatus, error) {
+	name, domain, err := address.Split(sender)
+	if err != nil {
+		return "", errors.Wrap(err, "Invalid sender address")
+	}
+
+	switch {
+	case listContains(s.Deny, name, domain):
+		return proto.AuthStatusDenied, nil
+	case listContains(s.Allow, name, domain):
+		return proto.AuthStatusOk, nil
+	default:
+		return s.Default, nil
+	}
+}
+
+// listContains returns true if list contains the address name*domain or its domain.
+func listContains(list []string, name, domain string) bool {
+	for _, entry := range list {
+		entryName, entryDomain, err := address.Split(entry)
+		if err != nil {
+			// Not an address: a domain
+			if strings.EqualFold(entry, domain) {
+				return true
+			}
+			continue
+		}
+
+		if strings.EqualFold(entryName, name) && strings.EqualFold(entryDomain, domain) {
+			return true
+		}
+	}
+	return false
+}
@@ -59,6 +59,64 @@ type CallbackStrategy struct {
 	GetUserDataURL string
 }
 
+// ListStrategy allows or denies transactions depending on the sender address. Allow and Deny
+// contain either addresses (`bob*stellar.org`) or domains (`stellar.org`) and are matched
+// case-insensitively. A sender that is on both lists is denied.
+type ListStrategy struct {
+	Allow []string
+	Deny  []string
+	// Default is the status of the senders that are not on any list. When empty the strategy
+	// makes no decision, which is useful in a CompositeStrategy.
+	Default compliance.AuthStatus
+}
+
+// DefaultMatchThreshold is the default similarity above which a name matches a sanctions
+// list entry.
+const DefaultMatchThreshold = 0.85
+
+// SanctionsListEntry is a person or organization on a sanctions list.
+type SanctionsListEntry struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	// DateOfBirth, when present, must be equal to the `date_of_birth` of the sender.
+	DateOfBirth string `json:"date_of_birth"`
+}
+
+// SanctionsListStrategy denies transactions whose sender info matches an entry of a
+// sanctions list, loaded from a CSV or JSON file with LoadSanctionsList. Names are matched
+// fuzzily: punctuation, case and word order are ignored and small spelling differences are
+// tolerated.
+type SanctionsListStrategy struct {
+	Entries []SanctionsListEntry
+	// Threshold is the similarity, between 0 and 1, above which a name matches an entry.
+	// Defaults to DefaultMatchThreshold.
+	Threshold float64
+}
+
+// Precedence decides which decision wins when the strategies of a CompositeStrategy disagree.
+type Precedence string
+
+const (
+	// PrecedenceFirstDecision uses the decision of the first strategy that makes one.
+	PrecedenceFirstDecision Precedence = "first_decision"
+	// PrecedenceDenyOverrides uses the most restrictive decision: denied, then error, then
+	// pending, then ok.
+	PrecedenceDenyOverrides Precedence = "deny_overrides"
+	// PrecedenceAllowOverrides uses the least restrictive decision: ok, then pending, then
+	// error, then denied.
+	PrecedenceAllowOverrides Precedence = "allow_overrides"
+)
+
+// CompositeStrategy combines the decisions of several strategies, for example an internal
+// allow list checked before a sanctions list and a CallbackStrategy.
+type CompositeStrategy struct {
+	Strategies []Strategy
+	// Precedence defaults to PrecedenceDenyOverrides.
+	Precedence Precedence
+	// Default is the status used when none of the strategies makes a decision.
+	Default compliance.AuthStatus
+}
+
 // AuthHandler ...
 type AuthHandler struct {
 	Strategy Strategy
@@ -68,3 +126,6 @@ type AuthHandler struct {
 }
 
 var _ Strategy = &CallbackStrategy{}
+var _ Strategy = &ListStrategy{}
+var _ Strategy = &SanctionsListStrategy{}
+var _ Strategy = &CompositeStrategy{}
@@ -0,0 +1,234 @@
+package compliance
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	proto "github.com/stellar/go/protocols/compliance"
+	"github.com/stellar/go/support/errors"
+)
+
+// NewSanctionsListStrategy is a factory method that creates a SanctionsListStrategy with the
+// entries of the sanctions list file at path, see LoadSanctionsList.
+func NewSanctionsListStrategy(path string, threshold float64) (*SanctionsListStrategy, error) {
+	entries, err := LoadSanctionsList(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SanctionsListStrategy{Entries: entries, Threshold: threshold}, nil
+}
+
+// LoadSanctionsList loads the entries of a sanctions list file. Files with a `.csv` extension
+// are read as CSV, with a header line naming the `name`, `aliases` (separated by `;`) and
+// `date_of_birth` columns. Other files are read as a JSON array of SanctionsListEntry.
+func LoadSanctionsList(path string) ([]SanctionsListEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to open sanctions list")
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return readSanctionsListCSV(file)
+	}
+	return readSanctionsListJSON(file)
+}
+
+func readSanctionsListJSON(r io.Reader) ([]SanctionsListEntry, error) {
+	var entries []SanctionsListEntry
+	err := json.NewDecoder(r).Decode(&entries)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to decode sanctions list")
+	}
+	return entries, nil
+}
+
+func readSanctionsListCSV(r io.Reader) ([]SanctionsListEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to read sanctions list header")
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["name"]; !ok {
+		return nil, errors.New("Sanctions list has no name column")
+	}
+
+	column := func(record []string, name string) string {
+		i, ok := columns[name]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var entries []SanctionsListEntry
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to read sanctions list")
+		}
+
+		entry := SanctionsListEntry{
+			Name:        column(record, "name"),
+			DateOfBirth: column(record, "date_of_birth"),
+		}
+		for _, alias := range strings.Split(column(record, "aliases"), ";") {
+			if alias = strings.TrimSpace(alias); alias != "" {
+				entry.Aliases = append(entry.Aliases, alias)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// SanctionsCheck denies the transaction if its sender, or the sender of one of its operations,
+// matches an entry of the list.
+func (s *SanctionsListStrategy) SanctionsCheck(data proto.AuthData, response *proto.AuthResponse) error {
+	attachment, err := data.Attachment()
+	if err != nil {
+		return errors.Wrap(err, "Failed to decode attachment")
+	}
+
+	senders := []map[string]string{attachment.Transaction.SenderInfo}
+	for _, operation := range attachment.Operations {
+		if operation.SenderInfo != nil {
+			senders = append(senders, operation.SenderInfo)
+		}
+	}
+
+	for _, sender := range senders {
+		if _, matched := s.Match(sender); matched {
+			response.TxStatus = proto.AuthStatusDenied
+			return nil
+		}
+	}
+
+	response.TxStatus = proto.AuthStatusOk
+	return nil
+}
+
+// GetUserData does not decide whether to share customer data, unless the sender does not
+// need it.
+func (s *SanctionsListStrategy) GetUserData(data proto.AuthData, response *proto.AuthResponse) error {
+	if !data.NeedInfo {
+		response.InfoStatus = proto.AuthStatusOk
+	}
+	return nil
+}
+
+// Match returns the first entry of the list matching senderInfo (see proto.SenderInfo). The
+// full name of the sender, with and without middle name, and its company name are compared
+// to the name and aliases of every entry. When both the entry and the sender have a date of
+// birth, they must be equal too.
+func (s *SanctionsListStrategy) Match(senderInfo map[string]string) (SanctionsListEntry, bool) {
+	threshold := s.Threshold
+	if threshold <= 0 {
+		threshold = DefaultMatchThreshold
+	}
+
+	var names []string
+	for _, name := range []string{
+		strings.Join([]string{senderInfo["first_name"], senderInfo["middle_name"], senderInfo["last_name"]}, " "),
+		strings.Join([]string{senderInfo["first_name"], senderInfo["last_name"]}, " "),
+		senderInfo["company_name"],
+	} {
+		if name = normalizeName(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	dateOfBirth := strings.TrimSpace(senderInfo["date_of_birth"])
+
+	for _, entry := range s.Entries {
+		if entry.DateOfBirth != "" && dateOfBirth != "" && entry.DateOfBirth != dateOfBirth {
+			continue
+		}
+
+		for _, entryName := range append([]string{entry.Name}, entry.Aliases...) {
+			entryName = normalizeName(entryName)
+			if entryName == "" {
+				continue
+			}
+
+			for _, name := range names {
+				if nameSimilarity(name, entryName) >= threshold {
+					return entry, true
+				}
+			}
+		}
+	}
+	return SanctionsListEntry{}, false
+}
+
+// normalizeName lowercases name, removes punctuation and sorts its words, so that "Doe, John"
+// and "john doe" are equal.
+func normalizeName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// nameSimilarity returns the similarity of two names, between 0 (nothing in common) and 1
+// (equal), based on their Levenshtein distance.
+func nameSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+func levenshtein(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+	return m
+}
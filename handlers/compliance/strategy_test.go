@@ -0,0 +1,195 @@
+package compliance
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	proto "github.com/stellar/go/protocols/compliance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListStrategy(t *testing.T) {
+	strategy := &ListStrategy{
+		Allow:   []string{"stellar.org", "alice*example.com"},
+		Deny:    []string{"mallory*stellar.org"},
+		Default: proto.AuthStatusPending,
+	}
+
+	cases := []struct {
+		sender string
+		status proto.AuthStatus
+	}{
+		{"bob*stellar.org", proto.AuthStatusOk},
+		{"bob*Stellar.org", proto.AuthStatusOk},
+		{"alice*example.com", proto.AuthStatusOk},
+		{"mallory*stellar.org", proto.AuthStatusDenied},
+		{"bob*example.com", proto.AuthStatusPending},
+	}
+
+	for _, kase := range cases {
+		response := &proto.AuthResponse{}
+		err := strategy.SanctionsCheck(proto.AuthData{Sender: kase.sender}, response)
+		require.NoError(t, err)
+		assert.Equal(t, kase.status, response.TxStatus, kase.sender)
+		assert.Equal(t, proto.AuthStatus(""), response.InfoStatus)
+	}
+
+	response := &proto.AuthResponse{}
+	err := strategy.GetUserData(proto.AuthData{Sender: "mallory*stellar.org"}, response)
+	require.NoError(t, err)
+	assert.Equal(t, proto.AuthStatusOk, response.InfoStatus)
+
+	response = &proto.AuthResponse{}
+	err = strategy.GetUserData(proto.AuthData{Sender: "mallory*stellar.org", NeedInfo: true}, response)
+	require.NoError(t, err)
+	assert.Equal(t, proto.AuthStatusDenied, response.InfoStatus)
+
+	err = strategy.SanctionsCheck(proto.AuthData{Sender: "invalid"}, &proto.AuthResponse{})
+	assert.Error(t, err)
+}
+
+func TestSanctionsListStrategy(t *testing.T) {
+	strategy := &SanctionsListStrategy{
+		Entries: []SanctionsListEntry{
+			{Name: "John Doe", DateOfBirth: "1970-01-01"},
+			{Name: "Evil Corp", Aliases: []string{"E Corp"}},
+		},
+	}
+
+	cases := []struct {
+		senderInfo map[string]string
+		matched    bool
+	}{
+		{map[string]string{"first_name": "John", "last_name": "Doe"}, true},
+		{map[string]string{"first_name": "DOE,", "last_name": "john"}, true},
+		{map[string]string{"first_name": "Jon", "last_name": "Doe"}, true},
+		{map[string]string{"first_name": "John", "middle_name": "Q", "last_name": "Doe"}, true},
+		{map[string]string{"first_name": "John", "last_name": "Doe", "date_of_birth": "1970-01-01"}, true},
+		{map[string]string{"first_name": "John", "last_name": "Doe", "date_of_birth": "1980-01-01"}, false},
+		{map[string]string{"first_name": "Jane", "last_name": "Smith"}, false},
+		{map[string]string{"company_name": "E-Corp"}, true},
+		{map[string]string{}, false},
+	}
+
+	for _, kase := range cases {
+		_, matched := strategy.Match(kase.senderInfo)
+		assert.Equal(t, kase.matched, matched, "%v", kase.senderInfo)
+	}
+
+	response := &proto.AuthResponse{}
+	err := strategy.SanctionsCheck(proto.AuthData{
+		AttachmentJSON: `{"transaction": {"sender_info": {"first_name": "Jane"}}, "operations": [{"sender_info": {"company_name": "Evil Corp."}}]}`,
+	}, response)
+	require.NoError(t, err)
+	assert.Equal(t, proto.AuthStatusDenied, response.TxStatus)
+
+	response = &proto.AuthResponse{}
+	err = strategy.SanctionsCheck(proto.AuthData{
+		AttachmentJSON: `{"transaction": {"sender_info": {"first_name": "Jane"}}}`,
+	}, response)
+	require.NoError(t, err)
+	assert.Equal(t, proto.AuthStatusOk, response.TxStatus)
+}
+
+func TestLoadSanctionsList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sanctions")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	csvPath := filepath.Join(dir, "list.csv")
+	err = ioutil.WriteFile(csvPath, []byte("name,aliases,date_of_birth\nJohn Doe,Johnny Doe; J. Doe,1970-01-01\nEvil Corp,,\n"), 0600)
+	require.NoError(t, err)
+
+	entries, err := LoadSanctionsList(csvPath)
+	require.NoError(t, err)
+	assert.Equal(t, []SanctionsListEntry{
+		{Name: "John Doe", Aliases: []string{"Johnny Doe", "J. Doe"}, DateOfBirth: "1970-01-01"},
+		{Name: "Evil Corp"},
+	}, entries)
+
+	jsonPath := filepath.Join(dir, "list.json")
+	err = ioutil.WriteFile(jsonPath, []byte(`[{"name": "John Doe", "aliases": ["Johnny Doe"]}]`), 0600)
+	require.NoError(t, err)
+
+	entries, err = LoadSanctionsList(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, []SanctionsListEntry{{Name: "John Doe", Aliases: []string{"Johnny Doe"}}}, entries)
+
+	_, err = LoadSanctionsList(filepath.Join(dir, "missing.csv"))
+	assert.Error(t, err)
+}
+
+type staticStrategy struct {
+	status  proto.AuthStatus
+	pending int
+	calls   int
+}
+
+func (s *staticStrategy) SanctionsCheck(data proto.AuthData, response *proto.AuthResponse) error {
+	s.calls++
+	response.TxStatus = s.status
+	response.Pending = s.pending
+	return nil
+}
+
+func (s *staticStrategy) GetUserData(data proto.AuthData, response *proto.AuthResponse) error {
+	s.calls++
+	response.InfoStatus = s.status
+	return nil
+}
+
+func TestCompositeStrategy(t *testing.T) {
+	cases := []struct {
+		precedence Precedence
+		statuses   []proto.AuthStatus
+		expected   proto.AuthStatus
+	}{
+		{PrecedenceDenyOverrides, []proto.AuthStatus{proto.AuthStatusOk, proto.AuthStatusDenied}, proto.AuthStatusDenied},
+		{PrecedenceDenyOverrides, []proto.AuthStatus{proto.AuthStatusOk, proto.AuthStatusPending}, proto.AuthStatusPending},
+		{"", []proto.AuthStatus{proto.AuthStatusError, proto.AuthStatusPending}, proto.AuthStatusError},
+		{PrecedenceAllowOverrides, []proto.AuthStatus{proto.AuthStatusDenied, proto.AuthStatusOk}, proto.AuthStatusOk},
+		{PrecedenceAllowOverrides, []proto.AuthStatus{proto.AuthStatusDenied, proto.AuthStatusPending}, proto.AuthStatusPending},
+		{PrecedenceFirstDecision, []proto.AuthStatus{"", proto.AuthStatusOk, proto.AuthStatusDenied}, proto.AuthStatusOk},
+		{PrecedenceDenyOverrides, []proto.AuthStatus{"", ""}, proto.AuthStatusPending},
+	}
+
+	for _, kase := range cases {
+		strategy := &CompositeStrategy{Precedence: kase.precedence, Default: proto.AuthStatusPending}
+		for _, status := range kase.statuses {
+			strategy.Strategies = append(strategy.Strategies, &staticStrategy{status: status})
+		}
+
+		response := &proto.AuthResponse{}
+		err := strategy.SanctionsCheck(proto.AuthData{}, response)
+		require.NoError(t, err)
+		assert.Equal(t, kase.expected, response.TxStatus, "%s %v", kase.precedence, kase.statuses)
+
+		response = &proto.AuthResponse{}
+		err = strategy.GetUserData(proto.AuthData{}, response)
+		require.NoError(t, err)
+		assert.Equal(t, kase.expected, response.InfoStatus, "%s %v", kase.precedence, kase.statuses)
+		assert.Equal(t, proto.AuthStatus(""), response.TxStatus)
+	}
+
+	// Strategies after a decision that cannot be overridden are not called
+	denied := &staticStrategy{status: proto.AuthStatusDenied}
+	skipped := &staticStrategy{status: proto.AuthStatusOk}
+	strategy := &CompositeStrategy{Strategies: []Strategy{denied, skipped}}
+	err := strategy.SanctionsCheck(proto.AuthData{}, &proto.AuthResponse{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, skipped.calls)
+
+	// Pending is the one of the decision
+	strategy = &CompositeStrategy{Strategies: []Strategy{
+		&staticStrategy{status: proto.AuthStatusOk},
+		&staticStrategy{status: proto.AuthStatusPending, pending: 3600},
+	}}
+	response := &proto.AuthResponse{}
+	err = strategy.SanctionsCheck(proto.AuthData{}, response)
+	require.NoError(t, err)
+	assert.Equal(t, proto.AuthStatusPending, response.TxStatus)
+	assert.Equal(t, 3600, response.Pending)
+}
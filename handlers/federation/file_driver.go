@@ -0,0 +1,172 @@
+package federation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/log"
+)
+
+// NewFileDriver creates a `FileDriver` serving the records of the file at
+// path. It returns an error if the file cannot be read.
+func NewFileDriver(path string) (*FileDriver, error) {
+	drv := &FileDriver{Path: path}
+
+	_, err := drv.current()
+	if err != nil {
+		return nil, err
+	}
+
+	return drv, nil
+}
+
+// LookupRecord implements `Driver` by searching the records of the file for
+// the provided name and domain.
+func (drv *FileDriver) LookupRecord(name, domain string) (*Record, error) {
+	records, err := drv.current()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range records.Records {
+		if strings.EqualFold(rec.Name, name) && strings.EqualFold(rec.Domain, domain) {
+			return &Record{AccountID: rec.AccountID, MemoType: rec.MemoType, Memo: rec.Memo}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// LookupReverseRecord implements `ReverseDriver` by searching the records of
+// the file for the provided account id.
+func (drv *FileDriver) LookupReverseRecord(accountid string) (*ReverseRecord, error) {
+	records, err := drv.current()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range records.Records {
+		if rec.AccountID == accountid {
+			return &ReverseRecord{Name: rec.Name, Domain: rec.Domain}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// LookupForwardingRecord implements `ForwardDriver` by searching the forward
+// records of the file for one whose fields are all present in query.
+func (drv *FileDriver) LookupForwardingRecord(query url.Values) (*Record, error) {
+	records, err := drv.current()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rec := range records.Forward {
+		if len(rec.Fields) == 0 {
+			continue
+		}
+
+		matched := true
+		for name, value := range rec.Fields {
+			if query.Get(name) != value {
+				matched = false
+				break
+			}
+		}
+
+		if matched {
+			return &Record{AccountID: rec.AccountID, MemoType: rec.MemoType, Memo: rec.Memo}, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// current returns the records of the file, reading it again if it changed.
+func (drv *FileDriver) current() (FileRecords, error) {
+	drv.lock.Lock()
+	defer drv.lock.Unlock()
+
+	interval := drv.ReloadInterval
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+
+	now := time.Now()
+	if drv.loaded && now.Sub(drv.checkedAt) < interval {
+		return drv.records, nil
+	}
+	drv.checkedAt = now
+
+	info, err := os.Stat(drv.Path)
+	if err == nil && drv.loaded && info.ModTime().Equal(drv.modTime) && info.Size() == drv.size {
+		return drv.records, nil
+	}
+
+	var records *FileRecords
+	if err == nil {
+		records, err = readFileRecords(drv.Path)
+	}
+
+	if err != nil {
+		if !drv.loaded {
+			return FileRecords{}, err
+		}
+
+		log.WithFields(log.F{"path": drv.Path, "err": err}).Error("federation records reload failed")
+		return drv.records, nil
+	}
+
+	if drv.loaded {
+		log.WithField("path", drv.Path).Info("federation records reloaded")
+	}
+
+	drv.records = *records
+	drv.modTime = info.ModTime()
+	drv.size = info.Size()
+	drv.loaded = true
+	return drv.records, nil
+}
+
+func readFileRecords(path string) (*FileRecords, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read records file failed")
+	}
+
+	var records FileRecords
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(content, &records)
+	} else {
+		_, err = toml.Decode(string(content), &records)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "decode records file failed")
+	}
+
+	for i, rec := range records.Records {
+		if rec.Name == "" || rec.Domain == "" || rec.AccountID == "" {
+			return nil, errors.Errorf("record %d: name, domain and account_id are required", i+1)
+		}
+	}
+
+	for i, rec := range records.Forward {
+		if len(rec.Fields) == 0 || rec.AccountID == "" {
+			return nil, errors.Errorf("forward record %d: fields and account_id are required", i+1)
+		}
+	}
+
+	return &records, nil
+}
+
+var _ Driver = &FileDriver{}
+var _ ReverseDriver = &FileDriver{}
+var _ ForwardDriver = &FileDriver{}
@@ -0,0 +1,145 @@
+package federation
+
+import (
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRecordsTOML = `
+[[records]]
+name = "scott"
+domain = "stellar.org"
+account_id = "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG"
+memo_type = "id"
+memo = "1"
+
+[[forward]]
+account_id = "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3"
+memo_type = "text"
+memo = "BOPBPHMM 2382376"
+[forward.fields]
+forward_type = "bank_account"
+swift = "BOPBPHMM"
+acct = "2382376"
+`
+
+const testRecordsJSON = `{
+  "records": [
+    {"name": "bartek", "domain": "stellar.org", "account_id": "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3"}
+  ]
+}`
+
+func writeTestRecords(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestFileDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "federation")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	drv, err := NewFileDriver(writeTestRecords(t, dir, "records.toml", testRecordsTOML))
+	require.NoError(t, err)
+
+	rec, err := drv.LookupRecord("Scott", "STELLAR.org")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG", rec.AccountID)
+	assert.Equal(t, "id", rec.MemoType)
+	assert.Equal(t, "1", rec.Memo)
+
+	rec, err = drv.LookupRecord("jed", "stellar.org")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	rev, err := drv.LookupReverseRecord("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG")
+	require.NoError(t, err)
+	require.NotNil(t, rev)
+	assert.Equal(t, "scott", rev.Name)
+	assert.Equal(t, "stellar.org", rev.Domain)
+
+	rec, err = drv.LookupForwardingRecord(url.Values{
+		"type":         {"forward"},
+		"forward_type": {"bank_account"},
+		"swift":        {"BOPBPHMM"},
+		"acct":         {"2382376"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3", rec.AccountID)
+	assert.Equal(t, "BOPBPHMM 2382376", rec.Memo)
+
+	rec, err = drv.LookupForwardingRecord(url.Values{
+		"forward_type": {"bank_account"},
+		"swift":        {"BOPBPHMM"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
+
+func TestFileDriver_JSON(t *testing.T) {
+	dir, err := ioutil.TempDir("", "federation")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	drv, err := NewFileDriver(writeTestRecords(t, dir, "records.json", testRecordsJSON))
+	require.NoError(t, err)
+
+	rec, err := drv.LookupRecord("bartek", "stellar.org")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3", rec.AccountID)
+}
+
+func TestFileDriver_Invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "federation")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = NewFileDriver(filepath.Join(dir, "missing.toml"))
+	assert.Error(t, err)
+
+	_, err = NewFileDriver(writeTestRecords(t, dir, "records.toml", "[[records]]\nname = \"scott\"\n"))
+	assert.Error(t, err)
+}
+
+func TestFileDriver_Reload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "federation")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := writeTestRecords(t, dir, "records.toml", testRecordsTOML)
+	drv, err := NewFileDriver(path)
+	require.NoError(t, err)
+	drv.ReloadInterval = time.Nanosecond
+
+	// An invalid version keeps the previous records
+	writeTestRecords(t, dir, "records.toml", "not toml")
+	rec, err := drv.LookupRecord("scott", "stellar.org")
+	require.NoError(t, err)
+	assert.NotNil(t, rec)
+
+	writeTestRecords(t, dir, "records.toml", `
+[[records]]
+name = "jed"
+domain = "stellar.org"
+account_id = "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3"
+`)
+	rec, err = drv.LookupRecord("scott", "stellar.org")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	rec, err = drv.LookupRecord("jed", "stellar.org")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3", rec.AccountID)
+}
@@ -0,0 +1,78 @@
+package federation
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/go/support/errors"
+)
+
+// LookupReverseRecord implements `ReverseDriver` by performing
+// `drv.LookupReverseRecordQuery` if it is set.
+func (drv *ForwardSQLDriver) LookupReverseRecord(
+	accountid string,
+) (*ReverseRecord, error) {
+	if drv.LookupReverseRecordQuery == "" {
+		return nil, ErrorResponse{
+			StatusCode: http.StatusNotImplemented,
+			Code:       "not_implemented",
+			Message:    "id type queries are not supported",
+		}
+	}
+
+	return drv.ReverseSQLDriver.LookupReverseRecord(accountid)
+}
+
+// LookupForwardingRecord implements `ForwardDriver` by performing the
+// `ForwardQuery` of the `forward_type` of query against `drv.DB`, using the
+// request parameters of the query as parameters.
+func (drv *ForwardSQLDriver) LookupForwardingRecord(query url.Values) (*Record, error) {
+	forwardType := query.Get("forward_type")
+	fq, ok := drv.ForwardQueries[forwardType]
+	if !ok {
+		return nil, ErrorResponse{
+			StatusCode: http.StatusBadRequest,
+			Code:       "invalid_request",
+			Message:    "unsupported forward_type: '" + forwardType + "'",
+		}
+	}
+
+	args := make([]interface{}, 0, len(fq.Params))
+	for _, param := range fq.Params {
+		value := query.Get(param.Name)
+		if value == "" {
+			return nil, ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Code:       "invalid_request",
+				Message:    param.Name + " parameter is blank",
+			}
+		}
+
+		if param.Pattern != nil && !param.Pattern.MatchString(value) {
+			return nil, ErrorResponse{
+				StatusCode: http.StatusBadRequest,
+				Code:       "invalid_request",
+				Message:    "invalid " + param.Name + " parameter",
+			}
+		}
+
+		args = append(args, value)
+	}
+
+	drv.initDB()
+	var result Record
+
+	err := drv.db.GetRaw(&result, fq.Query, args...)
+
+	if drv.db.NoRows(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Wrap(err, "db get")
+	}
+
+	return &result, nil
+}
+
+var _ Driver = &ForwardSQLDriver{}
+var _ ReverseDriver = &ForwardSQLDriver{}
+var _ ForwardDriver = &ForwardSQLDriver{}
@@ -0,0 +1,88 @@
+package federation
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForwardSQLDriver(t *testing.T) {
+	db := dbtest.Sqlite(t).Load(`
+    CREATE TABLE people (id character varying, name character varying, domain character varying);
+    INSERT INTO people (id, name, domain) VALUES
+      ('GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG', 'scott', 'stellar.org');
+    CREATE TABLE bank_accounts (id character varying, swift character varying, acct character varying);
+    INSERT INTO bank_accounts (id, swift, acct) VALUES
+      ('GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3', 'BOPBPHMM', '2382376');
+  `)
+	defer db.Close()
+
+	driver := &ForwardSQLDriver{
+		ReverseSQLDriver: ReverseSQLDriver{
+			SQLDriver: SQLDriver{
+				DB:                db.Open().DB,
+				Dialect:           db.Dialect,
+				LookupRecordQuery: "SELECT id FROM people WHERE name = ? AND domain = ?",
+			},
+		},
+		ForwardQueries: map[string]ForwardQuery{
+			"bank_account": {
+				Query: "SELECT id, 'text' as memo_type, swift || ' ' || acct as memo FROM bank_accounts WHERE swift = ? AND acct = ?",
+				Params: []ForwardParam{
+					{Name: "swift", Pattern: regexp.MustCompile("^[A-Z0-9]{8,11}$")},
+					{Name: "acct"},
+				},
+			},
+		},
+	}
+	defer driver.DB.Close()
+
+	rec, err := driver.LookupRecord("scott", "stellar.org")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG", rec.AccountID)
+
+	rec, err = driver.LookupForwardingRecord(url.Values{
+		"forward_type": {"bank_account"},
+		"swift":        {"BOPBPHMM"},
+		"acct":         {"2382376"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "GCYMGWPZ6NC2U7SO6SMXOP5ZLXOEC5SYPKITDMVEONLCHFSCCQR2J4S3", rec.AccountID)
+	assert.Equal(t, "text", rec.MemoType)
+	assert.Equal(t, "BOPBPHMM 2382376", rec.Memo)
+
+	rec, err = driver.LookupForwardingRecord(url.Values{
+		"forward_type": {"bank_account"},
+		"swift":        {"BOPBPHMM"},
+		"acct":         {"1"},
+	})
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	for _, query := range []url.Values{
+		{"forward_type": {"crypto"}},
+		{"forward_type": {"bank_account"}, "swift": {"BOPBPHMM"}},
+		{"forward_type": {"bank_account"}, "swift": {"bop' OR 1=1"}, "acct": {"1"}},
+	} {
+		_, err = driver.LookupForwardingRecord(query)
+		problem, ok := errors.Cause(err).(ErrorResponse)
+		if assert.True(t, ok, query.Encode()) {
+			assert.Equal(t, http.StatusBadRequest, problem.StatusCode)
+			assert.Equal(t, "invalid_request", problem.Code)
+		}
+	}
+
+	_, err = driver.LookupReverseRecord("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG")
+	problem, ok := errors.Cause(err).(ErrorResponse)
+	if assert.True(t, ok) {
+		assert.Equal(t, http.StatusNotImplemented, problem.StatusCode)
+	}
+}
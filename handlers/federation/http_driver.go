@@ -0,0 +1,122 @@
+package federation
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/stellar/go/address"
+	proto "github.com/stellar/go/protocols/federation"
+	"github.com/stellar/go/support/errors"
+)
+
+// upstreamResponseMaxSize is the maximum size of a response from the upstream
+// server of an `HTTPDriver`
+const upstreamResponseMaxSize = 100 * 1024
+
+// LookupRecord implements `Driver` by sending a "name" federation request to
+// the upstream server.
+func (drv *HTTPDriver) LookupRecord(name, domain string) (*Record, error) {
+	var resp proto.NameResponse
+	found, err := drv.get(url.Values{
+		"type": []string{"name"},
+		"q":    []string{address.New(name, domain)},
+	}, &resp)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &Record{AccountID: resp.AccountID, MemoType: resp.MemoType, Memo: resp.Memo.Value}, nil
+}
+
+// LookupReverseRecord implements `ReverseDriver` by sending an "id" federation
+// request to the upstream server.
+func (drv *HTTPDriver) LookupReverseRecord(accountid string) (*ReverseRecord, error) {
+	var resp proto.IDResponse
+	found, err := drv.get(url.Values{
+		"type": []string{"id"},
+		"q":    []string{accountid},
+	}, &resp)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	name, domain, err := address.Split(resp.Address)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid upstream stellar_address")
+	}
+
+	return &ReverseRecord{Name: name, Domain: domain}, nil
+}
+
+// LookupForwardingRecord implements `ForwardDriver` by sending a "forward"
+// federation request, with the parameters of query, to the upstream server.
+func (drv *HTTPDriver) LookupForwardingRecord(query url.Values) (*Record, error) {
+	params := url.Values{}
+	for name, values := range query {
+		params[name] = values
+	}
+	params.Set("type", "forward")
+
+	var resp proto.NameResponse
+	found, err := drv.get(params, &resp)
+	if err != nil || !found {
+		return nil, err
+	}
+
+	return &Record{AccountID: resp.AccountID, MemoType: resp.MemoType, Memo: resp.Memo.Value}, nil
+}
+
+// get sends a federation request to the upstream server and decodes its
+// response into dest. It returns false if the upstream server responded with
+// 404 Not Found.
+func (drv *HTTPDriver) get(params url.Values, dest interface{}) (bool, error) {
+	u, err := url.Parse(drv.URL)
+	if err != nil {
+		return false, errors.Wrap(err, "parse upstream url failed")
+	}
+
+	query := u.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	u.RawQuery = query.Encode()
+
+	client := drv.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return false, errors.Wrap(err, "upstream request failed")
+	}
+	defer resp.Body.Close()
+
+	body := io.LimitReader(resp.Body, upstreamResponseMaxSize)
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return false, nil
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		err = json.NewDecoder(body).Decode(dest)
+		if err != nil {
+			return false, errors.Wrap(err, "decode upstream response failed")
+		}
+		return true, nil
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		var problem ErrorResponse
+		err = json.NewDecoder(body).Decode(&problem)
+		if err == nil && problem.Code != "" {
+			problem.StatusCode = resp.StatusCode
+			return false, problem
+		}
+	}
+
+	return false, errors.Errorf("upstream responded with (%d) status code", resp.StatusCode)
+}
+
+var _ Driver = &HTTPDriver{}
+var _ ReverseDriver = &HTTPDriver{}
+var _ ForwardDriver = &HTTPDriver{}
@@ -0,0 +1,81 @@
+package federation
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPDriver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "federation")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	upstreamDriver, err := NewFileDriver(writeTestRecords(t, dir, "records.toml", testRecordsTOML))
+	require.NoError(t, err)
+
+	upstream := httptest.NewServer(&Handler{Driver: upstreamDriver})
+	defer upstream.Close()
+
+	drv := &HTTPDriver{URL: upstream.URL + "/federation"}
+
+	rec, err := drv.LookupRecord("scott", "stellar.org")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG", rec.AccountID)
+	assert.Equal(t, "id", rec.MemoType)
+	assert.Equal(t, "1", rec.Memo)
+
+	rec, err = drv.LookupRecord("jed", "stellar.org")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+
+	rev, err := drv.LookupReverseRecord("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG")
+	require.NoError(t, err)
+	require.NotNil(t, rev)
+	assert.Equal(t, "scott", rev.Name)
+	assert.Equal(t, "stellar.org", rev.Domain)
+
+	rec, err = drv.LookupForwardingRecord(url.Values{
+		"forward_type": {"bank_account"},
+		"swift":        {"BOPBPHMM"},
+		"acct":         {"2382376"},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "BOPBPHMM 2382376", rec.Memo)
+}
+
+func TestHTTPDriver_Errors(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("type") {
+		case "name":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code": "invalid_query", "message": "Invalid query"}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer upstream.Close()
+
+	drv := &HTTPDriver{URL: upstream.URL}
+
+	_, err := drv.LookupRecord("scott", "stellar.org")
+	require.Error(t, err)
+	problem, ok := errors.Cause(err).(ErrorResponse)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, problem.StatusCode)
+	assert.Equal(t, "invalid_query", problem.Code)
+
+	_, err = drv.LookupReverseRecord("GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG")
+	require.Error(t, err)
+	_, ok = errors.Cause(err).(ErrorResponse)
+	assert.False(t, ok)
+}
@@ -8,12 +8,17 @@
 //
 // A pre-baked implementation of `Driver` and `ReverseDriver` that provides
 // simple access to SQL systems is included. See `SQLDriver` for more details.
+// `FileDriver` serves records from a TOML or JSON file and `HTTPDriver` forwards
+// queries to another federation server.
 package federation
 
 import (
 	"database/sql"
+	"net/http"
 	"net/url"
+	"regexp"
 	"sync"
+	"time"
 
 	"github.com/stellar/go/support/db"
 )
@@ -121,3 +126,123 @@ type SQLDriver struct {
 	init sync.Once
 	db   *db.Session
 }
+
+// ForwardSQLDriver provides a `ForwardDriver` implementation based upon a SQL
+// Server, on top of the "name" and "id" lookups of the embedded
+// `ReverseSQLDriver`. Leave `LookupReverseRecordQuery` empty if "id" requests
+// are not supported.
+type ForwardSQLDriver struct {
+	ReverseSQLDriver
+
+	// ForwardQueries maps the `forward_type` parameter of "forward" requests
+	// to the query serving them.
+	ForwardQueries map[string]ForwardQuery
+}
+
+// ForwardQuery is a query serving "forward" federation requests.  Like
+// `SQLDriver.LookupRecordQuery`, it should return an `id` column and
+// optionally `memo` and `memo_type` columns, for example:
+//
+//	SELECT account_id as id, 'text' as memo_type, swift || ' ' || acct as memo
+//	FROM bank_accounts WHERE swift = ? AND acct = ?
+type ForwardQuery struct {
+	// Query is the SQL query. Its placeholders are bound, in order, to the
+	// request parameters of Params.
+	Query string
+
+	// Params are the request parameters bound to the placeholders of Query.
+	Params []ForwardParam
+}
+
+// ForwardParam is a request parameter of a `ForwardQuery`. Requests missing
+// the parameter, or whose value does not match Pattern, are rejected with a
+// 400 Bad Request response.
+type ForwardParam struct {
+	Name string
+
+	// Pattern is an optional regular expression the value of the parameter
+	// must match.
+	Pattern *regexp.Regexp
+}
+
+// FileDriver provides a `Driver`, `ReverseDriver` and `ForwardDriver`
+// implementation based upon a file of records, for example:
+//
+//	[[records]]
+//	name = "alice"
+//	domain = "stellar.org"
+//	account_id = "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG"
+//	memo_type = "id"
+//	memo = "1"
+//
+//	[[forward]]
+//	account_id = "GD2GJPL3UOK5LX7TWXOACK2ZPWPFSLBNKL3GTGH6BLBNISK4BGWMFBBG"
+//	memo_type = "text"
+//	memo = "BOPBPHMM 2382376"
+//	[forward.fields]
+//	forward_type = "bank_account"
+//	swift = "BOPBPHMM"
+//	acct = "2382376"
+//
+// Names and domains are matched case-insensitively. The file is read again
+// when it changes: if the new version is invalid, the records of the previous
+// version keep being served.
+type FileDriver struct {
+	// Path is the path of the records file. Files with a `.json` extension
+	// are read as JSON (see `FileRecords`), other files as TOML.
+	Path string
+
+	// ReloadInterval is the minimum time between two checks for changes to
+	// the file. Defaults to `DefaultReloadInterval`.
+	ReloadInterval time.Duration
+
+	lock      sync.Mutex
+	loaded    bool
+	records   FileRecords
+	modTime   time.Time
+	size      int64
+	checkedAt time.Time
+}
+
+// DefaultReloadInterval is the default `FileDriver.ReloadInterval`.
+const DefaultReloadInterval = time.Second
+
+// FileRecords represents the content of a `FileDriver` file.
+type FileRecords struct {
+	Records []FileRecord        `toml:"records" json:"records"`
+	Forward []FileForwardRecord `toml:"forward" json:"forward"`
+}
+
+// FileRecord is a record of a `FileDriver` file, returned for "name" and "id"
+// federation requests.
+type FileRecord struct {
+	Name      string `toml:"name" json:"name"`
+	Domain    string `toml:"domain" json:"domain"`
+	AccountID string `toml:"account_id" json:"account_id"`
+	MemoType  string `toml:"memo_type" json:"memo_type"`
+	Memo      string `toml:"memo" json:"memo"`
+}
+
+// FileForwardRecord is a record of a `FileDriver` file returned for "forward"
+// federation requests whose parameters include all of `Fields`.
+type FileForwardRecord struct {
+	Fields    map[string]string `toml:"fields" json:"fields"`
+	AccountID string            `toml:"account_id" json:"account_id"`
+	MemoType  string            `toml:"memo_type" json:"memo_type"`
+	Memo      string            `toml:"memo" json:"memo"`
+}
+
+// HTTPDriver provides a `Driver`, `ReverseDriver` and `ForwardDriver`
+// implementation that sends queries to an upstream server implementing the
+// federation protocol, for example an internal HTTP API. "Not found" responses
+// of the upstream server are returned as nil records and its error responses
+// are passed to the end user.
+type HTTPDriver struct {
+	// URL is the federation endpoint of the upstream server, for example
+	// "http://accounts.internal/federation".
+	URL string
+
+	// Client is the HTTP client sending queries. Defaults to
+	// `http.DefaultClient`.
+	Client *http.Client
+}
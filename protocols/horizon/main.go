@@ -504,6 +504,7 @@ type AccountData struct {
 
 // EffectsPage contains page of effects returned by Horizon.
 type EffectsPage struct {
+	Links    hal.Links `json:"_links"`
 	Embedded struct {
 		Records []effects.Base
 	} `json:"_embedded"`
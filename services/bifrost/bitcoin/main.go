@@ -26,7 +26,6 @@ var (
 // Listener tracks only P2PKH payments.
 // You can run multiple Listeners if Storage is implemented correctly.
 type Listener struct {
-	Enabled            bool
 	Client             Client  `inject:""`
 	Storage            Storage `inject:""`
 	TransactionHandler TransactionHandler
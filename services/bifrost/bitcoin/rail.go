@@ -0,0 +1,53 @@
+package bitcoin
+
+import (
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/services/bifrost/rail"
+)
+
+// Rail implements rail.Rail for Bitcoin.
+type Rail struct {
+	Listener         *Listener
+	AddressGenerator *AddressGenerator
+	// MinimumValueBtc is the minimum value of accepted transactions in BTC.
+	MinimumValueBtc string
+	// TokenPriceBtc is the price of one token in BTC.
+	TokenPriceBtc string
+}
+
+func (r *Rail) Chain() database.Chain {
+	return database.ChainBitcoin
+}
+
+func (r *Rail) AssetCode() queue.AssetCode {
+	return queue.AssetCodeBTC
+}
+
+func (r *Rail) TokenPrice() string {
+	return r.TokenPriceBtc
+}
+
+func (r *Rail) MinimumAmount() string {
+	return r.MinimumValueBtc
+}
+
+func (r *Rail) GenerateAddress(index uint32) (string, error) {
+	return r.AddressGenerator.Generate(index)
+}
+
+// Start starts the Listener and converts the transactions it receives to
+// rail.Transaction.
+func (r *Rail) Start(handler rail.TransactionHandler) error {
+	r.Listener.TransactionHandler = func(transaction Transaction) error {
+		return handler(rail.Transaction{
+			Hash:   transaction.Hash,
+			To:     transaction.To,
+			Amount: transaction.ValueToStellar(),
+		})
+	}
+
+	return r.Listener.Start()
+}
+
+var _ rail.Rail = &Rail{}
@@ -10,6 +10,9 @@ type Config struct {
 	Bitcoin                        *bitcoinConfig  `valid:"optional" toml:"bitcoin"`
 	Ethereum                       *ethereumConfig `valid:"optional" toml:"ethereum"`
 	AccessControlAllowOriginHeader string          `valid:"optional" toml:"access_control_allow_origin_header"`
+	// AdminPort is the port of admin HTTP server. Admin server is not started when empty.
+	// It must not be accessible from the outside world.
+	AdminPort int `valid:"optional" toml:"admin_port"`
 
 	Stellar struct {
 		Horizon           string `valid:"required" toml:"horizon"`
@@ -36,11 +39,27 @@ type Config struct {
 		StartingBalance string `valid:"optional,stellar_amount" toml:"starting_balance"`
 		// LockUnixTimestamp defines unix timestamp when user account will be unlocked.
 		LockUnixTimestamp uint64 `valid:"optional" toml:"lock_unix_timestamp"`
+		// SignerTimeout is the number of seconds Bifrost waits for the user to add the
+		// temporary signer to a new account. No timeout when empty.
+		SignerTimeout int `valid:"optional" toml:"signer_timeout"`
 	} `valid:"required" toml:"stellar"`
 	Database struct {
 		Type string `valid:"matches(^postgres$)"`
 		DSN  string `valid:"required"`
 	} `valid:"required"`
+	Queue *queueConfig `valid:"optional" toml:"queue"`
+}
+
+type queueConfig struct {
+	// VisibilityTimeout is the number of seconds a transaction received from the queue is
+	// hidden from other Bifrost instances.
+	VisibilityTimeout int `valid:"optional" toml:"visibility_timeout"`
+	// RetryDelay is the number of seconds before a failed transaction is retried,
+	// multiplied by the number of attempts.
+	RetryDelay int `valid:"optional" toml:"retry_delay"`
+	// MaxAttempts is the number of attempts after which a failed transaction is not
+	// retried anymore.
+	MaxAttempts int `valid:"optional" toml:"max_attempts"`
 }
 
 type bitcoinConfig struct {
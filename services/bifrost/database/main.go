@@ -3,6 +3,7 @@ package database
 import (
 	"time"
 
+	"github.com/stellar/go/services/bifrost/queue"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
 )
@@ -11,21 +12,52 @@ type Chain string
 
 // Scan implements database/sql.Scanner interface
 func (s *Chain) Scan(src interface{}) error {
-	value, ok := src.([]byte)
-	if !ok {
+	switch value := src.(type) {
+	case []byte:
+		*s = Chain(value)
+	case string:
+		*s = Chain(value)
+	default:
 		return errors.New("Cannot convert value to Chain")
 	}
-	*s = Chain(value)
 	return nil
 }
 
 const (
-	SchemaVersion uint32 = 2
+	SchemaVersion uint32 = 4
 
 	ChainBitcoin  Chain = "bitcoin"
 	ChainEthereum Chain = "ethereum"
 )
 
+// ConversionStatus describes status of a conversion. In-progress statuses are equal
+// to stellar.Status values of the account being configured.
+type ConversionStatus string
+
+const (
+	// ConversionStatusPending means that a conversion is waiting in the transactions queue.
+	ConversionStatusPending            ConversionStatus = "pending"
+	ConversionStatusCreatingAccount    ConversionStatus = "creating_account"
+	ConversionStatusWaitingForSigner   ConversionStatus = "waiting_for_signer"
+	ConversionStatusConfiguringAccount ConversionStatus = "configuring_account"
+	ConversionStatusRemovingSigner     ConversionStatus = "removing_signer"
+	ConversionStatusCompleted          ConversionStatus = "completed"
+	// ConversionStatusFailed means that the maximum number of attempts was reached.
+	// Failed conversions can be retried or refunded.
+	ConversionStatusFailed   ConversionStatus = "failed"
+	ConversionStatusRefunded ConversionStatus = "refunded"
+)
+
+// InProgressConversionStatuses contains statuses of conversions that are not completed,
+// failed or refunded, in the order a conversion goes through them.
+var InProgressConversionStatuses = []ConversionStatus{
+	ConversionStatusPending,
+	ConversionStatusCreatingAccount,
+	ConversionStatusWaitingForSigner,
+	ConversionStatusConfiguringAccount,
+	ConversionStatusRemovingSigner,
+}
+
 type Database interface {
 	// CreateAddressAssociation creates Bitcoin/Ethereum-Stellar association. `addressIndex`
 	// is the chain (Bitcoin/Ethereum) address derivation index (BIP-32).
@@ -41,7 +73,8 @@ type Database interface {
 	AddProcessedTransaction(chain Chain, transactionID, receivingAddress string) (alreadyProcessing bool, err error)
 	// IncrementAddressIndex returns the current value of index used for `chain` key
 	// derivation and then increments it. This operation must be atomic so this function
-	// should never return the same value more than once.
+	// should never return the same value more than once. Indexes of chains used for the
+	// first time are created.
 	IncrementAddressIndex(chain Chain) (uint32, error)
 
 	// ResetBlockCounters changes last processed bitcoin and ethereum block to default value.
@@ -50,9 +83,75 @@ type Database interface {
 
 	// AddRecoveryTransaction inserts recovery account ID and transaction envelope
 	AddRecoveryTransaction(sourceAccount string, txEnvelope string) error
+
+	// AddConversion saves a new conversion of a chain transaction. It should return nil
+	// if the conversion already exists.
+	AddConversion(conversion Conversion) error
+	// SetConversionStatus updates status and the last error of a conversion identified
+	// by transactions queue ID (`transactionID` and `assetCode`). Refunded conversions
+	// must not be updated.
+	SetConversionStatus(transactionID string, assetCode queue.AssetCode, status ConversionStatus, lastError string) error
+	// SetConversionStep updates the step (the last account configuration status) of a
+	// conversion identified by transactions queue ID. The step only moves forward: it's
+	// not changed when a retried conversion goes through the earlier statuses again.
+	SetConversionStep(transactionID string, assetCode queue.AssetCode, step ConversionStatus) error
+	// GetQueuedConversion returns a conversion identified by transactions queue ID.
+	// Should return nil if not found.
+	GetQueuedConversion(transactionID string, assetCode queue.AssetCode) (*Conversion, error)
+	// GetConversion returns a conversion of `chain` transaction. Should return nil if
+	// not found.
+	GetConversion(chain Chain, transactionID string) (*Conversion, error)
+	// GetConversions returns conversions with one of `statuses` not updated since
+	// `updatedBefore`, the oldest first.
+	GetConversions(statuses []ConversionStatus, updatedBefore time.Time) ([]Conversion, error)
+	// RetryConversion returns a failed or stuck conversion to the transactions queue and
+	// sets its status to ConversionStatusPending. A conversion in progress is stuck when it
+	// hasn't been updated since `stuckBefore`: its queue lease is taken over so that the
+	// instance processing it stops. Steps that have been done (see Conversion.TokensSent)
+	// are not repeated.
+	RetryConversion(chain Chain, transactionID string, stuckBefore time.Time) error
+	// RefundConversion records that a failed or stuck (see RetryConversion) conversion has
+	// been refunded in `refundTransactionID` chain transaction and removes it from the
+	// transactions queue. Conversions whose tokens may have been sent (see
+	// Conversion.TokensMayHaveBeenSent) cannot be refunded.
+	RefundConversion(chain Chain, transactionID, refundTransactionID string, stuckBefore time.Time) error
 }
 
+var (
+	// ErrConversionNotFound is returned when a conversion does not exist.
+	ErrConversionNotFound = errors.New("Conversion not found")
+	// ErrConversionNotFailed is returned when a conversion that is neither failed nor
+	// stuck is retried or refunded.
+	ErrConversionNotFailed = errors.New("Conversion is not failed or stuck")
+	// ErrConversionTokensSent is returned when a conversion whose tokens may have been
+	// sent is refunded.
+	ErrConversionTokensSent = errors.New("Conversion tokens may have been sent")
+)
+
+// Default values of PostgresDatabase queue parameters.
+const (
+	DefaultQueueVisibilityTimeout = 2 * time.Minute
+	DefaultQueueRetryDelay        = time.Minute
+	DefaultQueueMaxAttempts       = 5
+)
+
+// PostgresDatabase implements Database, queue.Queue and storage interfaces of listeners
+// using Postgres.
+//
+// The queue can be shared by many Bifrost instances: transactions are received using
+// `SELECT ... FOR UPDATE SKIP LOCKED` and hidden from other instances for
+// QueueVisibilityTimeout.
 type PostgresDatabase struct {
+	// QueueVisibilityTimeout is the time a transaction received from the queue is hidden
+	// from other consumers. Defaults to DefaultQueueVisibilityTimeout.
+	QueueVisibilityTimeout time.Duration
+	// QueueRetryDelay is the delay before a failed transaction is retried, multiplied by
+	// the number of attempts. Defaults to DefaultQueueRetryDelay.
+	QueueRetryDelay time.Duration
+	// QueueMaxAttempts is the number of attempts after which a failed transaction is
+	// not retried anymore. Defaults to DefaultQueueMaxAttempts.
+	QueueMaxAttempts int
+
 	session *db.Session
 }
 
@@ -65,3 +164,36 @@ type AddressAssociation struct {
 	StellarPublicKey string    `db:"stellar_public_key"`
 	CreatedAt        time.Time `db:"created_at"`
 }
+
+// Conversion is a chain transaction being converted to tokens sent to a Stellar account.
+type Conversion struct {
+	Chain         Chain  `db:"chain" json:"chain"`
+	TransactionID string `db:"transaction_id" json:"transaction_id"`
+	// Address is the receiving chain address.
+	Address          string           `db:"address" json:"address"`
+	StellarPublicKey string           `db:"stellar_public_key" json:"stellar_public_key"`
+	AssetCode        queue.AssetCode  `db:"asset_code" json:"asset_code"`
+	Amount           string           `db:"amount" json:"amount"`
+	Status           ConversionStatus `db:"status" json:"status"`
+	// Step is the last account configuration status reached by the conversion. It's kept
+	// when the conversion is returned to the queue or fails so that steps that cannot be
+	// repeated are skipped, see TokensSent.
+	Step                ConversionStatus `db:"step" json:"step"`
+	LastError           string           `db:"last_error" json:"last_error"`
+	RefundTransactionID string           `db:"refund_transaction_id" json:"refund_transaction_id"`
+	CreatedAt           time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt           time.Time        `db:"updated_at" json:"updated_at"`
+}
+
+// TokensSent returns true if tokens have been sent to the Stellar account of the
+// conversion. They must not be sent again when the conversion is retried.
+func (c Conversion) TokensSent() bool {
+	return c.Step == ConversionStatusRemovingSigner
+}
+
+// TokensMayHaveBeenSent returns true if tokens have been sent or if sending them was
+// started: the transaction sending them may have been applied even if the conversion
+// stopped in ConversionStatusConfiguringAccount. Such conversions cannot be refunded.
+func (c Conversion) TokensMayHaveBeenSent() bool {
+	return c.Step == ConversionStatusConfiguringAccount || c.TokensSent()
+}
@@ -0,0 +1,27 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConversionTokensMayHaveBeenSent(t *testing.T) {
+	tests := []struct {
+		step      ConversionStatus
+		sent      bool
+		maybeSent bool
+	}{
+		{"", false, false},
+		{ConversionStatusCreatingAccount, false, false},
+		{ConversionStatusWaitingForSigner, false, false},
+		{ConversionStatusConfiguringAccount, false, true},
+		{ConversionStatusRemovingSigner, true, true},
+	}
+
+	for _, test := range tests {
+		conversion := Conversion{Status: ConversionStatusFailed, Step: test.step}
+		assert.Equal(t, test.sent, conversion.TokensSent(), "step %q", test.step)
+		assert.Equal(t, test.maybeSent, conversion.TokensMayHaveBeenSent(), "step %q", test.step)
+	}
+}
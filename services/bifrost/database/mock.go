@@ -1,6 +1,9 @@
 package database
 
 import (
+	"time"
+
+	"github.com/stellar/go/services/bifrost/queue"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -46,3 +49,49 @@ func (m *MockDatabase) AddRecoveryTransaction(sourceAccount string, txEnvelope s
 	a := m.Called(sourceAccount, txEnvelope)
 	return a.Error(0)
 }
+
+func (m *MockDatabase) AddConversion(conversion Conversion) error {
+	a := m.Called(conversion)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) SetConversionStatus(transactionID string, assetCode queue.AssetCode, status ConversionStatus, lastError string) error {
+	a := m.Called(transactionID, assetCode, status, lastError)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) SetConversionStep(transactionID string, assetCode queue.AssetCode, step ConversionStatus) error {
+	a := m.Called(transactionID, assetCode, step)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) GetQueuedConversion(transactionID string, assetCode queue.AssetCode) (*Conversion, error) {
+	a := m.Called(transactionID, assetCode)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*Conversion), a.Error(1)
+}
+
+func (m *MockDatabase) GetConversion(chain Chain, transactionID string) (*Conversion, error) {
+	a := m.Called(chain, transactionID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*Conversion), a.Error(1)
+}
+
+func (m *MockDatabase) GetConversions(statuses []ConversionStatus, updatedBefore time.Time) ([]Conversion, error) {
+	a := m.Called(statuses, updatedBefore)
+	return a.Get(0).([]Conversion), a.Error(1)
+}
+
+func (m *MockDatabase) RetryConversion(chain Chain, transactionID string, stuckBefore time.Time) error {
+	a := m.Called(chain, transactionID, stuckBefore)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) RefundConversion(chain Chain, transactionID, refundTransactionID string, stuckBefore time.Time) error {
+	a := m.Called(chain, transactionID, refundTransactionID, stuckBefore)
+	return a.Error(0)
+}
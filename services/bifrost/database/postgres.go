@@ -1,11 +1,14 @@
 package database
 
 import (
+	"crypto/rand"
 	"database/sql"
+	"encoding/hex"
 	"strconv"
 	"strings"
 	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/lib/pq"
 	"github.com/stellar/go/services/bifrost/queue"
 	"github.com/stellar/go/services/bifrost/sse"
@@ -16,11 +19,12 @@ import (
 const (
 	schemaVersionKey = "schema_version"
 
-	ethereumAddressIndexKey = "ethereum_address_index"
-	ethereumLastBlockKey    = "ethereum_last_block"
+	// addressIndexKeySuffix is appended to chain name to create address index key,
+	// ex. `bitcoin_address_index`.
+	addressIndexKeySuffix = "_address_index"
 
-	bitcoinAddressIndexKey = "bitcoin_address_index"
-	bitcoinLastBlockKey    = "bitcoin_last_block"
+	ethereumLastBlockKey = "ethereum_last_block"
+	bitcoinLastBlockKey  = "bitcoin_last_block"
 
 	addressAssociationTableName   = "address_association"
 	broadcastedEventTableName     = "broadcasted_event"
@@ -28,6 +32,7 @@ const (
 	processedTransactionTableName = "processed_transaction"
 	transactionsQueueTableName    = "transactions_queue"
 	recoveryTransactionTableName  = "recovery_transaction"
+	conversionTableName           = "conversion"
 )
 
 type keyValueStoreRow struct {
@@ -55,6 +60,8 @@ type transactionsQueueRow struct {
 	AssetCode        queue.AssetCode `db:"asset_code"`
 	Amount           string          `db:"amount"`
 	StellarPublicKey string          `db:"stellar_public_key"`
+	Attempts         int             `db:"attempts"`
+	LeaseID          string          `db:"lease_id"`
 }
 
 type processedTransactionRow struct {
@@ -88,6 +95,8 @@ func (r *transactionsQueueRow) toQueueTransaction() *queue.Transaction {
 		AssetCode:        r.AssetCode,
 		Amount:           r.Amount,
 		StellarPublicKey: r.StellarPublicKey,
+		Attempts:         r.Attempts,
+		LeaseID:          r.LeaseID,
 	}
 }
 
@@ -106,6 +115,23 @@ func (d *PostgresDatabase) Import() error {
 	return d.session.ExecAll(schema)
 }
 
+// Migrate migrates DB schema from `version` to SchemaVersion.
+func (d *PostgresDatabase) Migrate(version uint32) error {
+	for ; version < SchemaVersion; version++ {
+		migration, ok := migrations[version]
+		if !ok {
+			return errors.Errorf("No migration from schema version %d", version)
+		}
+
+		err := d.session.ExecAll(migration)
+		if err != nil {
+			return errors.Wrapf(err, "Error migrating from schema version %d", version)
+		}
+	}
+
+	return nil
+}
+
 // GetSchemaVersion returns schema version of Bifrost DB. Returns 0.
 func (d *PostgresDatabase) GetSchemaVersion() (uint32, error) {
 	keyValueStore := d.getTable(keyValueStoreTableName, nil)
@@ -205,22 +231,27 @@ func (d *PostgresDatabase) AddProcessedTransaction(chain Chain, transactionID, r
 }
 
 func (d *PostgresDatabase) IncrementAddressIndex(chain Chain) (uint32, error) {
-	var key string
-	switch chain {
-	case ChainBitcoin:
-		key = bitcoinAddressIndexKey
-	case ChainEthereum:
-		key = ethereumAddressIndexKey
-	default:
+	if chain == "" {
 		return 0, errors.New("Invalid chain")
 	}
 
+	key := string(chain) + addressIndexKeySuffix
+
+	// Create the index if it's the first address of a chain
+	_, err := d.session.ExecRaw(
+		"INSERT INTO "+keyValueStoreTableName+" (key, value) VALUES (?, '0') ON CONFLICT (key) DO NOTHING",
+		key,
+	)
+	if err != nil {
+		return 0, errors.Wrap(err, "Error creating `"+key+"`")
+	}
+
 	row := keyValueStoreRow{}
 
 	session := d.session.Clone()
 	keyValueStore := d.getTable(keyValueStoreTableName, session)
 
-	err := session.Begin()
+	err = session.Begin()
 	if err != nil {
 		return 0, errors.Wrap(err, "Error starting a new transaction")
 	}
@@ -358,46 +389,191 @@ func (d *PostgresDatabase) QueueAdd(tx queue.Transaction) error {
 	return err
 }
 
-// QueuePool receives and removes the head of this queue. Returns nil if no elements found.
+// QueuePool receives the head of this queue and hides it from other consumers for
+// QueueVisibilityTimeout. Returns nil if no elements found.
 // QueuePool implements queue.Queue interface.
 func (d *PostgresDatabase) QueuePool() (*queue.Transaction, error) {
+	leaseID, err := newLeaseID()
+	if err != nil {
+		return nil, err
+	}
+
 	row := transactionsQueueRow{}
+	// SKIP LOCKED allows many Bifrost instances to receive transactions concurrently.
+	err = d.session.GetRaw(&row, `UPDATE `+transactionsQueueTableName+`
+		SET attempts = attempts + 1, lease_id = ?, visible_at = now() + ? * interval '1 millisecond'
+		WHERE id = (
+			SELECT id FROM `+transactionsQueueTableName+`
+			WHERE pooled = false AND failed = false AND visible_at <= now()
+			ORDER BY id ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING transaction_id, asset_code, amount, stellar_public_key, attempts, lease_id`,
+		leaseID,
+		milliseconds(d.queueVisibilityTimeout()),
+	)
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return nil, nil
+		default:
+			return nil, errors.Wrap(err, "Error getting transaction from a queue")
+		}
+	}
 
-	session := d.session.Clone()
-	transactionsQueueTable := d.getTable(transactionsQueueTableName, session)
+	return row.toQueueTransaction(), nil
+}
 
-	err := session.Begin()
+// QueueExtend extends the visibility timeout of a transaction received from QueuePool.
+// QueueExtend implements queue.Queue interface.
+func (d *PostgresDatabase) QueueExtend(tx queue.Transaction) error {
+	result, err := d.session.ExecRaw(`UPDATE `+transactionsQueueTableName+`
+		SET visible_at = now() + ? * interval '1 millisecond'
+		WHERE transaction_id = ? AND asset_code = ? AND lease_id = ? AND pooled = false AND failed = false`,
+		milliseconds(d.queueVisibilityTimeout()),
+		tx.TransactionID,
+		tx.AssetCode,
+		tx.LeaseID,
+	)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error starting a new transaction")
+		return errors.Wrap(err, "Error extending transaction visibility timeout")
 	}
-	defer session.Rollback()
 
-	err = transactionsQueueTable.Get(&row, map[string]interface{}{"pooled": false}).OrderBy("id ASC").Suffix("FOR UPDATE").Exec()
+	return checkLease(result)
+}
+
+// QueueAck removes a transaction received from QueuePool from this queue.
+// QueueAck implements queue.Queue interface.
+func (d *PostgresDatabase) QueueAck(tx queue.Transaction) error {
+	result, err := d.session.ExecRaw(`UPDATE `+transactionsQueueTableName+`
+		SET pooled = true, lease_id = ''
+		WHERE transaction_id = ? AND asset_code = ? AND lease_id = ?`,
+		tx.TransactionID,
+		tx.AssetCode,
+		tx.LeaseID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Error setting transaction as pooled in a queue")
+	}
+
+	return checkLease(result)
+}
+
+// QueueNack makes a transaction received from QueuePool visible again after
+// QueueRetryDelay multiplied by the number of attempts, or marks it as failed if
+// QueueMaxAttempts was reached.
+// QueueNack implements queue.Queue interface.
+func (d *PostgresDatabase) QueueNack(tx queue.Transaction, reason error) (bool, error) {
+	var lastError string
+	if reason != nil {
+		lastError = reason.Error()
+	}
+
+	row := struct {
+		Failed bool `db:"failed"`
+	}{}
+	err := d.session.GetRaw(&row, `UPDATE `+transactionsQueueTableName+`
+		SET
+			lease_id = '',
+			last_error = ?,
+			failed = attempts >= ?,
+			visible_at = now() + attempts * ? * interval '1 millisecond'
+		WHERE transaction_id = ? AND asset_code = ? AND lease_id = ?
+		RETURNING failed`,
+		lastError,
+		d.queueMaxAttempts(),
+		milliseconds(d.queueRetryDelay()),
+		tx.TransactionID,
+		tx.AssetCode,
+		tx.LeaseID,
+	)
 	if err != nil {
 		switch errors.Cause(err) {
 		case sql.ErrNoRows:
-			return nil, nil
+			return false, errors.New("Transaction lease expired")
 		default:
-			return nil, errors.Wrap(err, "Error getting transaction from a queue")
+			return false, errors.Wrap(err, "Error returning transaction to a queue")
 		}
 	}
 
-	// TODO: something's wrong with db.Table.Update(). Setting the first argument does not work as expected.
-	where := map[string]interface{}{"transaction_id": row.TransactionID, "asset_code": row.AssetCode}
-	_, err = transactionsQueueTable.Update(nil, where).Set("pooled", true).Exec()
+	return row.Failed, nil
+}
+
+// QueueFail marks a transaction received from QueuePool as failed without retrying it.
+// QueueFail implements queue.Queue interface.
+func (d *PostgresDatabase) QueueFail(tx queue.Transaction, reason error) error {
+	var lastError string
+	if reason != nil {
+		lastError = reason.Error()
+	}
+
+	result, err := d.session.ExecRaw(`UPDATE `+transactionsQueueTableName+`
+		SET lease_id = '', last_error = ?, failed = true
+		WHERE transaction_id = ? AND asset_code = ? AND lease_id = ?`,
+		lastError,
+		tx.TransactionID,
+		tx.AssetCode,
+		tx.LeaseID,
+	)
 	if err != nil {
-		return nil, errors.Wrap(err, "Error setting transaction as pooled in a queue")
+		return errors.Wrap(err, "Error marking transaction as failed in a queue")
 	}
 
-	err = session.Commit()
+	return checkLease(result)
+}
+
+func (d *PostgresDatabase) queueVisibilityTimeout() time.Duration {
+	if d.QueueVisibilityTimeout <= 0 {
+		return DefaultQueueVisibilityTimeout
+	}
+	return d.QueueVisibilityTimeout
+}
+
+func (d *PostgresDatabase) queueRetryDelay() time.Duration {
+	if d.QueueRetryDelay <= 0 {
+		return DefaultQueueRetryDelay
+	}
+	return d.QueueRetryDelay
+}
+
+func (d *PostgresDatabase) queueMaxAttempts() int {
+	if d.QueueMaxAttempts <= 0 {
+		return DefaultQueueMaxAttempts
+	}
+	return d.QueueMaxAttempts
+}
+
+// checkLease returns error if no rows were updated using a lease ID.
+func checkLease(result sql.Result) error {
+	rows, err := result.RowsAffected()
 	if err != nil {
-		return nil, errors.Wrap(err, "Error commiting a transaction")
+		return errors.Wrap(err, "Error getting number of updated rows")
 	}
 
-	return row.toQueueTransaction(), nil
+	if rows == 0 {
+		return errors.New("Transaction lease expired")
+	}
+
+	return nil
 }
 
-// AddEvent adds a new server-sent event to the storage.
+func newLeaseID() (string, error) {
+	raw := make([]byte, 16)
+	_, err := rand.Read(raw)
+	if err != nil {
+		return "", errors.Wrap(err, "Error generating lease ID")
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+func milliseconds(d time.Duration) int64 {
+	return int64(d / time.Millisecond)
+}
+
+// AddEvent adds a new server-sent event to the storage. Events other than conversion
+// events (sse.ConversionFailedEvent, sse.ConversionRetriedEvent and sse.RefundedEvent)
+// are added once per address, duplicates are ignored.
 func (d *PostgresDatabase) AddEvent(event sse.Event) error {
 	broadcastedEventTable := d.getTable(broadcastedEventTableName, nil)
 	_, err := broadcastedEventTable.Insert(event).Exec()
@@ -474,3 +650,262 @@ func (d *PostgresDatabase) AddRecoveryTransaction(sourceAccount string, txEnvelo
 	_, err := recoveryTransactionTable.Insert(recoveryTransaction).Exec()
 	return err
 }
+
+// AddConversion implements Database interface.
+func (d *PostgresDatabase) AddConversion(conversion Conversion) error {
+	conversionTable := d.getTable(conversionTableName, nil)
+
+	now := time.Now()
+	conversion.CreatedAt = now
+	conversion.UpdatedAt = now
+
+	_, err := conversionTable.Insert(conversion).Exec()
+	if err != nil && isDuplicateError(err) {
+		return nil
+	}
+	return err
+}
+
+// SetConversionStatus implements Database interface.
+func (d *PostgresDatabase) SetConversionStatus(transactionID string, assetCode queue.AssetCode, status ConversionStatus, lastError string) error {
+	_, err := d.session.ExecRaw(`UPDATE `+conversionTableName+`
+		SET status = ?, last_error = ?, updated_at = ?
+		WHERE transaction_id = ? AND asset_code = ? AND status <> ?`,
+		status,
+		lastError,
+		time.Now(),
+		transactionID,
+		assetCode,
+		ConversionStatusRefunded,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Error updating conversion status")
+	}
+
+	return nil
+}
+
+// SetConversionStep implements Database interface.
+func (d *PostgresDatabase) SetConversionStep(transactionID string, assetCode queue.AssetCode, step ConversionStatus) error {
+	_, err := d.session.Exec(sq.Update(conversionTableName).
+		Set("step", step).
+		Where(sq.Eq{
+			"transaction_id": transactionID,
+			"asset_code":     assetCode,
+			"step":           stepsBefore(step),
+		}),
+	)
+	if err != nil {
+		return errors.Wrap(err, "Error updating conversion step")
+	}
+
+	return nil
+}
+
+// stepsBefore returns the steps a conversion goes through before reaching `step`,
+// including the empty step of a conversion that hasn't been processed yet.
+func stepsBefore(step ConversionStatus) []string {
+	steps := []string{""}
+	for _, status := range InProgressConversionStatuses {
+		if status == step {
+			break
+		}
+		steps = append(steps, string(status))
+	}
+	return steps
+}
+
+// GetConversion implements Database interface.
+func (d *PostgresDatabase) GetConversion(chain Chain, transactionID string) (*Conversion, error) {
+	where := map[string]interface{}{"chain": chain, "transaction_id": transactionID}
+	return d.getConversion(where, d.session, false)
+}
+
+// GetQueuedConversion implements Database interface.
+func (d *PostgresDatabase) GetQueuedConversion(transactionID string, assetCode queue.AssetCode) (*Conversion, error) {
+	where := map[string]interface{}{"transaction_id": transactionID, "asset_code": assetCode}
+	return d.getConversion(where, d.session, false)
+}
+
+func (d *PostgresDatabase) getConversion(where map[string]interface{}, session *db.Session, forUpdate bool) (*Conversion, error) {
+	conversionTable := d.getTable(conversionTableName, session)
+	row := &Conversion{}
+
+	get := conversionTable.Get(row, where)
+	if forUpdate {
+		get = get.Suffix("FOR UPDATE")
+	}
+
+	err := get.Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return nil, nil
+		default:
+			return nil, errors.Wrap(err, "Error getting conversion from DB")
+		}
+	}
+
+	return row, nil
+}
+
+// GetConversions implements Database interface.
+func (d *PostgresDatabase) GetConversions(statuses []ConversionStatus, updatedBefore time.Time) ([]Conversion, error) {
+	conversionTable := d.getTable(conversionTableName, nil)
+
+	values := make([]string, len(statuses))
+	for i, status := range statuses {
+		values[i] = string(status)
+	}
+
+	rows := []Conversion{}
+	err := conversionTable.Select(&rows, sq.And{
+		sq.Eq{"status": values},
+		sq.Lt{"updated_at": updatedBefore},
+	}).OrderBy("updated_at ASC").Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return rows, nil
+		default:
+			return nil, errors.Wrap(err, "Error getting conversions from DB")
+		}
+	}
+
+	return rows, nil
+}
+
+// RetryConversion implements Database interface.
+func (d *PostgresDatabase) RetryConversion(chain Chain, transactionID string, stuckBefore time.Time) error {
+	session := d.session.Clone()
+
+	err := session.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Error starting a new transaction")
+	}
+	defer session.Rollback()
+
+	conversion, err := d.getFailedOrStuckConversion(chain, transactionID, stuckBefore, session)
+	if err != nil {
+		return err
+	}
+
+	// Resetting lease_id takes over the lease of an instance processing a stuck conversion.
+	result, err := session.ExecRaw(`UPDATE `+transactionsQueueTableName+`
+		SET failed = false, attempts = 0, last_error = '', lease_id = '', visible_at = now()
+		WHERE transaction_id = ? AND asset_code = ? AND pooled = false`,
+		conversion.TransactionID,
+		conversion.AssetCode,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Error returning transaction to a queue")
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "Error getting number of updated rows")
+	}
+
+	if rows == 0 {
+		return errors.New("Transaction not found in a queue")
+	}
+
+	err = d.updateConversionStatus(session, chain, transactionID, ConversionStatusPending, "")
+	if err != nil {
+		return err
+	}
+
+	err = session.Commit()
+	if err != nil {
+		return errors.Wrap(err, "Error commiting a transaction")
+	}
+
+	return nil
+}
+
+// RefundConversion implements Database interface.
+func (d *PostgresDatabase) RefundConversion(chain Chain, transactionID, refundTransactionID string, stuckBefore time.Time) error {
+	session := d.session.Clone()
+
+	err := session.Begin()
+	if err != nil {
+		return errors.Wrap(err, "Error starting a new transaction")
+	}
+	defer session.Rollback()
+
+	conversion, err := d.getFailedOrStuckConversion(chain, transactionID, stuckBefore, session)
+	if err != nil {
+		return err
+	}
+
+	if conversion.TokensMayHaveBeenSent() {
+		return ErrConversionTokensSent
+	}
+
+	// The transaction is marked as failed so it's not processed anymore, resetting
+	// lease_id releases the lease of an instance processing a stuck conversion.
+	_, err = session.ExecRaw(`UPDATE `+transactionsQueueTableName+`
+		SET failed = true, last_error = 'Refunded', lease_id = ''
+		WHERE transaction_id = ? AND asset_code = ? AND pooled = false`,
+		conversion.TransactionID,
+		conversion.AssetCode,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Error removing transaction from a queue")
+	}
+
+	err = d.updateConversionStatus(session, chain, transactionID, ConversionStatusRefunded, refundTransactionID)
+	if err != nil {
+		return err
+	}
+
+	err = session.Commit()
+	if err != nil {
+		return errors.Wrap(err, "Error commiting a transaction")
+	}
+
+	return nil
+}
+
+// getFailedOrStuckConversion returns a conversion locked for update in `session` if it's
+// failed or in progress and not updated since `stuckBefore`.
+func (d *PostgresDatabase) getFailedOrStuckConversion(chain Chain, transactionID string, stuckBefore time.Time, session *db.Session) (*Conversion, error) {
+	where := map[string]interface{}{"chain": chain, "transaction_id": transactionID}
+	conversion, err := d.getConversion(where, session, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if conversion == nil {
+		return nil, ErrConversionNotFound
+	}
+
+	if conversion.Status == ConversionStatusFailed {
+		return conversion, nil
+	}
+
+	for _, status := range InProgressConversionStatuses {
+		if conversion.Status == status && conversion.UpdatedAt.Before(stuckBefore) {
+			return conversion, nil
+		}
+	}
+
+	return nil, ErrConversionNotFailed
+}
+
+func (d *PostgresDatabase) updateConversionStatus(session *db.Session, chain Chain, transactionID string, status ConversionStatus, refundTransactionID string) error {
+	_, err := session.ExecRaw(`UPDATE `+conversionTableName+`
+		SET status = ?, refund_transaction_id = ?, updated_at = ?
+		WHERE chain = ? AND transaction_id = ?`,
+		status,
+		refundTransactionID,
+		time.Now(),
+		chain,
+		transactionID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Error updating conversion status")
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDatabase(t *testing.T) (*PostgresDatabase, func()) {
+	tdb := dbtest.Postgres(t)
+	database := &PostgresDatabase{session: &db.Session{DB: tdb.Open()}}
+	require.NoError(t, database.Import())
+
+	return database, func() {
+		database.session.DB.Close()
+		tdb.Close()
+	}
+}
+
+func TestStepsBefore(t *testing.T) {
+	assert.Equal(t, []string{"", "pending"}, stepsBefore(ConversionStatusCreatingAccount))
+	assert.Equal(t,
+		[]string{"", "pending", "creating_account", "waiting_for_signer", "configuring_account"},
+		stepsBefore(ConversionStatusRemovingSigner),
+	)
+}
+
+func TestConversionStepRetried(t *testing.T) {
+	database, closeDatabase := openTestDatabase(t)
+	defer closeDatabase()
+
+	transaction := queue.Transaction{
+		TransactionID:    "109fa1c369680c2f27643fdd160620d010851a376d25b9b00ef71afe789ea6ed",
+		AssetCode:        queue.AssetCodeBTC,
+		Amount:           "1.0000000",
+		StellarPublicKey: "GDULKYRRVOMASFMXBYD4BYFRSHAKQDREEVVP2TMH2CER3DW2KATIOASB",
+	}
+	require.NoError(t, database.QueueAdd(transaction))
+	require.NoError(t, database.AddConversion(Conversion{
+		Chain:            ChainBitcoin,
+		TransactionID:    transaction.TransactionID,
+		Address:          "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf",
+		StellarPublicKey: transaction.StellarPublicKey,
+		AssetCode:        transaction.AssetCode,
+		Amount:           transaction.Amount,
+		Status:           ConversionStatusPending,
+	}))
+
+	// process runs an attempt of processing the transaction going through `steps`.
+	process := func(steps ...ConversionStatus) {
+		for _, step := range steps {
+			require.NoError(t, database.SetConversionStatus(transaction.TransactionID, transaction.AssetCode, step, ""))
+			require.NoError(t, database.SetConversionStep(transaction.TransactionID, transaction.AssetCode, step))
+		}
+	}
+	retry := func() {
+		require.NoError(t, database.RetryConversion(ChainBitcoin, transaction.TransactionID, time.Now().Add(time.Second)))
+	}
+	assertStep := func(expected ConversionStatus) {
+		conversion, err := database.GetConversion(ChainBitcoin, transaction.TransactionID)
+		require.NoError(t, err)
+		assert.Equal(t, expected, conversion.Step)
+	}
+
+	// The first attempt stops while sending tokens.
+	process(ConversionStatusCreatingAccount, ConversionStatusWaitingForSigner, ConversionStatusConfiguringAccount)
+	assertStep(ConversionStatusConfiguringAccount)
+	retry()
+
+	// The retried attempt is interrupted before reaching the step again.
+	process(ConversionStatusCreatingAccount, ConversionStatusWaitingForSigner)
+	assertStep(ConversionStatusConfiguringAccount)
+	err := database.RefundConversion(ChainBitcoin, transaction.TransactionID, "refund", time.Now().Add(time.Second))
+	assert.Equal(t, ErrConversionTokensSent, err)
+	retry()
+
+	process(ConversionStatusCreatingAccount, ConversionStatusWaitingForSigner, ConversionStatusConfiguringAccount, ConversionStatusRemovingSigner)
+	assertStep(ConversionStatusRemovingSigner)
+}
@@ -4,10 +4,8 @@ import (
 	"fmt"
 )
 
-var schema = fmt.Sprintf(`CREATE TYPE chain AS ENUM ('bitcoin', 'ethereum');
-
-CREATE TABLE address_association (
-  chain chain NOT NULL,
+var schema = fmt.Sprintf(`CREATE TABLE address_association (
+  chain varchar(32) NOT NULL,
   address_index bigint NOT NULL,
   /* bitcoin 34 characters */
   /* ethereum 42 characters */
@@ -33,7 +31,7 @@ INSERT INTO key_value_store (key, value) VALUES ('bitcoin_address_index', '0');
 INSERT INTO key_value_store (key, value) VALUES ('bitcoin_last_block', '0');
 
 CREATE TABLE processed_transaction (
-  chain chain NOT NULL,
+  chain varchar(32) NOT NULL,
   /* Ethereum: "0x"+hash (so 64+2) */
   transaction_id varchar(66) NOT NULL,
   /* bitcoin 34 characters */
@@ -48,35 +46,115 @@ CREATE TABLE transactions_queue (
   id bigserial,
   /* Ethereum: "0x"+hash (so 64+2) */
   transaction_id varchar(66) NOT NULL,
-  asset_code varchar(3) NOT NULL,
+  asset_code varchar(12) NOT NULL,
   /* Amount in the base unit of currency (BTC or ETH). */
   /* ethereum: 100000000 in year 2128 + 7 decimal precision in Stellar + dot */
   /* bitcoin:   21000000              + 7 decimal precision in Stellar + dot */
   amount varchar(20) NOT NULL,
   stellar_public_key varchar(56) NOT NULL,
+  /* true when processed (acknowledged) */
   pooled boolean NOT NULL DEFAULT false,
+  /* true when the maximum number of attempts was reached */
+  failed boolean NOT NULL DEFAULT false,
+  attempts integer NOT NULL DEFAULT 0,
+  /* the transaction is hidden from consumers until visible_at */
+  visible_at timestamp NOT NULL DEFAULT now(),
+  lease_id varchar(32) NOT NULL DEFAULT '',
+  last_error text NOT NULL DEFAULT '',
   PRIMARY KEY (id),
   UNIQUE (transaction_id, asset_code),
-  CONSTRAINT valid_asset_code CHECK (char_length(asset_code) = 3),
+  CONSTRAINT valid_asset_code CHECK (char_length(asset_code) BETWEEN 1 AND 12),
   CONSTRAINT valid_stellar_public_key CHECK (char_length(stellar_public_key) = 56)
 );
 
-CREATE TYPE event AS ENUM ('transaction_received', 'account_created', 'exchanged', 'exchanged_timelocked');
+CREATE INDEX transactions_queue_visible_at_index ON transactions_queue (visible_at) WHERE pooled = false AND failed = false;
 
 CREATE TABLE broadcasted_event (
   id bigserial,
   /* bitcoin 34 characters */
   /* ethereum 42 characters */
   address varchar(42) NOT NULL,
-  event event NOT NULL,
+  event varchar(32) NOT NULL,
   data text NOT NULL,
-  PRIMARY KEY (id),
-  UNIQUE (address, event)
+  PRIMARY KEY (id)
 );
 
+/* Conversion events can be sent many times for the same address, other events only once */
+CREATE UNIQUE INDEX broadcasted_event_address_event_index ON broadcasted_event (address, event)
+  WHERE event NOT IN ('conversion_failed', 'conversion_retried', 'refunded');
+
 CREATE TABLE recovery_transaction (
   source varchar(56) NOT NULL,
   envelope_xdr text NOT NULL
 );
 
-CREATE INDEX source_index ON recovery_transaction (source);`, SchemaVersion)
+CREATE INDEX source_index ON recovery_transaction (source);
+
+CREATE TABLE conversion (
+  chain varchar(32) NOT NULL,
+  /* Ethereum: "0x"+hash (so 64+2) */
+  transaction_id varchar(66) NOT NULL,
+  /* bitcoin 34 characters */
+  /* ethereum 42 characters */
+  address varchar(42) NOT NULL,
+  stellar_public_key varchar(56) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  amount varchar(20) NOT NULL,
+  status varchar(32) NOT NULL,
+  /* the last status of account configuration, kept when the status changes to pending or failed */
+  step varchar(32) NOT NULL DEFAULT '',
+  last_error text NOT NULL DEFAULT '',
+  refund_transaction_id varchar(66) NOT NULL DEFAULT '',
+  created_at timestamp NOT NULL,
+  updated_at timestamp NOT NULL,
+  PRIMARY KEY (chain, transaction_id),
+  UNIQUE (transaction_id, asset_code)
+);
+
+CREATE INDEX conversion_status_index ON conversion (status, updated_at);`, SchemaVersion)
+
+// migrations contains scripts migrating DB schema from a version (map key) to the next one.
+var migrations = map[uint32]string{
+	2: `ALTER TABLE address_association ALTER COLUMN chain TYPE varchar(32) USING chain::text;
+ALTER TABLE processed_transaction ALTER COLUMN chain TYPE varchar(32) USING chain::text;
+DROP TYPE chain;
+
+ALTER TABLE transactions_queue ALTER COLUMN asset_code TYPE varchar(12);
+ALTER TABLE transactions_queue DROP CONSTRAINT valid_asset_code;
+ALTER TABLE transactions_queue ADD CONSTRAINT valid_asset_code CHECK (char_length(asset_code) BETWEEN 1 AND 12);
+ALTER TABLE transactions_queue ADD COLUMN failed boolean NOT NULL DEFAULT false;
+ALTER TABLE transactions_queue ADD COLUMN attempts integer NOT NULL DEFAULT 0;
+ALTER TABLE transactions_queue ADD COLUMN visible_at timestamp NOT NULL DEFAULT now();
+ALTER TABLE transactions_queue ADD COLUMN lease_id varchar(32) NOT NULL DEFAULT '';
+ALTER TABLE transactions_queue ADD COLUMN last_error text NOT NULL DEFAULT '';
+
+CREATE INDEX transactions_queue_visible_at_index ON transactions_queue (visible_at) WHERE pooled = false AND failed = false;
+
+UPDATE key_value_store SET value = '3' WHERE key = 'schema_version';`,
+	3: `ALTER TABLE broadcasted_event ALTER COLUMN event TYPE varchar(32) USING event::text;
+DROP TYPE event;
+ALTER TABLE broadcasted_event DROP CONSTRAINT broadcasted_event_address_event_key;
+CREATE UNIQUE INDEX broadcasted_event_address_event_index ON broadcasted_event (address, event)
+  WHERE event NOT IN ('conversion_failed', 'conversion_retried', 'refunded');
+
+CREATE TABLE conversion (
+  chain varchar(32) NOT NULL,
+  transaction_id varchar(66) NOT NULL,
+  address varchar(42) NOT NULL,
+  stellar_public_key varchar(56) NOT NULL,
+  asset_code varchar(12) NOT NULL,
+  amount varchar(20) NOT NULL,
+  status varchar(32) NOT NULL,
+  step varchar(32) NOT NULL DEFAULT '',
+  last_error text NOT NULL DEFAULT '',
+  refund_transaction_id varchar(66) NOT NULL DEFAULT '',
+  created_at timestamp NOT NULL,
+  updated_at timestamp NOT NULL,
+  PRIMARY KEY (chain, transaction_id),
+  UNIQUE (transaction_id, asset_code)
+);
+
+CREATE INDEX conversion_status_index ON conversion (status, updated_at);
+
+UPDATE key_value_store SET value = '4' WHERE key = 'schema_version';`,
+}
@@ -26,7 +26,6 @@ var (
 // Listener ignores contract creation transactions.
 // Listener requires geth 1.7.0.
 type Listener struct {
-	Enabled            bool
 	Client             Client  `inject:""`
 	Storage            Storage `inject:""`
 	NetworkID          string
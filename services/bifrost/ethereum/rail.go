@@ -0,0 +1,55 @@
+package ethereum
+
+import (
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/services/bifrost/rail"
+)
+
+// Rail implements rail.Rail for Ethereum.
+type Rail struct {
+	Listener         *Listener
+	AddressGenerator *AddressGenerator
+	// RpcServer is the host of geth RPC server.
+	RpcServer string
+	// MinimumValueEth is the minimum value of accepted transactions in ETH.
+	MinimumValueEth string
+	// TokenPriceEth is the price of one token in ETH.
+	TokenPriceEth string
+}
+
+func (r *Rail) Chain() database.Chain {
+	return database.ChainEthereum
+}
+
+func (r *Rail) AssetCode() queue.AssetCode {
+	return queue.AssetCodeETH
+}
+
+func (r *Rail) TokenPrice() string {
+	return r.TokenPriceEth
+}
+
+func (r *Rail) MinimumAmount() string {
+	return r.MinimumValueEth
+}
+
+func (r *Rail) GenerateAddress(index uint32) (string, error) {
+	return r.AddressGenerator.Generate(index)
+}
+
+// Start starts the Listener and converts the transactions it receives to
+// rail.Transaction.
+func (r *Rail) Start(handler rail.TransactionHandler) error {
+	r.Listener.TransactionHandler = func(transaction Transaction) error {
+		return handler(rail.Transaction{
+			Hash:   transaction.Hash,
+			To:     transaction.To,
+			Amount: transaction.ValueToStellar(),
+		})
+	}
+
+	return r.Listener.Start(r.RpcServer)
+}
+
+var _ rail.Rail = &Rail{}
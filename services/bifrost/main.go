@@ -20,6 +20,7 @@ import (
 	"github.com/stellar/go/services/bifrost/config"
 	"github.com/stellar/go/services/bifrost/database"
 	"github.com/stellar/go/services/bifrost/ethereum"
+	"github.com/stellar/go/services/bifrost/rail"
 	"github.com/stellar/go/services/bifrost/server"
 	"github.com/stellar/go/services/bifrost/sse"
 	"github.com/stellar/go/services/bifrost/stellar"
@@ -83,7 +84,7 @@ This command will create 3 server.Server's listening on ports 8000-8002.`,
 		ethereumClient := &stress.RandomEthereumClient{}
 		ethereumClient.Start(ethereumAccounts)
 
-		db, err := createDatabase(cfg.Database.DSN)
+		db, err := createDatabase(cfg)
 		if err != nil {
 			log.WithField("err", err).Error("Error connecting to database")
 			os.Exit(-1)
@@ -112,8 +113,14 @@ This command will create 3 server.Server's listening on ports 8000-8002.`,
 				cfg.Stellar.SignerSecretKey = signers[i]
 				server := createServer(cfg, true)
 				// Replace clients in listeners with random transactions generators
-				server.BitcoinListener.Client = bitcoinClient
-				server.EthereumListener.Client = ethereumClient
+				for _, r := range server.Rails {
+					switch r := r.(type) {
+					case *bitcoin.Rail:
+						r.Listener.Client = bitcoinClient
+					case *ethereum.Rail:
+						r.Listener.Client = ethereumClient
+					}
+				}
 				err := server.Start()
 				if err != nil {
 					log.WithField("err", err).Error("Error starting the server")
@@ -259,10 +266,22 @@ func readConfig(cfgPath string) config.Config {
 	return cfg
 }
 
-// createDatabase opens a DB connection and imports schema if DB is empty.
-func createDatabase(dsn string) (*database.PostgresDatabase, error) {
+// createDatabase opens a DB connection, imports schema if DB is empty and
+// migrates schema if it's outdated.
+func createDatabase(cfg config.Config) (*database.PostgresDatabase, error) {
 	db := &database.PostgresDatabase{}
-	err := db.Open(dsn)
+
+	if cfg.Queue != nil {
+		db.QueueVisibilityTimeout = time.Duration(cfg.Queue.VisibilityTimeout) * time.Second
+		db.QueueRetryDelay = time.Duration(cfg.Queue.RetryDelay) * time.Second
+		db.QueueMaxAttempts = cfg.Queue.MaxAttempts
+
+		if db.QueueVisibilityTimeout != 0 && db.QueueVisibilityTimeout < 2*server.QueueHeartbeatInterval {
+			return nil, errors.Errorf("queue.visibility_timeout must be at least %d seconds", int(2*server.QueueHeartbeatInterval/time.Second))
+		}
+	}
+
+	err := db.Open(cfg.Database.DSN)
 	if err != nil {
 		return nil, err
 	}
@@ -278,6 +297,12 @@ func createDatabase(dsn string) (*database.PostgresDatabase, error) {
 		if err != nil {
 			return nil, errors.Wrap(err, "Error importing DB schema")
 		}
+	} else if currentSchemaVersion >= 2 && currentSchemaVersion < database.SchemaVersion {
+		log.WithFields(log.F{"from": currentSchemaVersion, "to": database.SchemaVersion}).Info("Migrating DB schema")
+		err := db.Migrate(currentSchemaVersion)
+		if err != nil {
+			return nil, errors.Wrap(err, "Error migrating DB schema")
+		}
 	} else if currentSchemaVersion != database.SchemaVersion {
 		// Schema version invalid
 		return nil, errors.New("Schema version is invalid. Please create an empty DB and start Bifrost again.")
@@ -289,7 +314,7 @@ func createDatabase(dsn string) (*database.PostgresDatabase, error) {
 func createServer(cfg config.Config, stressTest bool) *server.Server {
 	var g inject.Graph
 
-	db, err := createDatabase(cfg.Database.DSN)
+	db, err := createDatabase(cfg)
 	if err != nil {
 		log.WithField("err", err).Error("Error connecting to database")
 		os.Exit(-1)
@@ -323,9 +348,7 @@ func createServer(cfg config.Config, stressTest bool) *server.Server {
 				os.Exit(-1)
 			}
 
-			bitcoinListener.Enabled = true
 			bitcoinListener.Testnet = cfg.Bitcoin.Testnet
-			server.MinimumValueBtc = cfg.Bitcoin.MinimumValueBtc
 
 			var chainParams *chaincfg.Params
 			if cfg.Bitcoin.Testnet {
@@ -338,6 +361,13 @@ func createServer(cfg config.Config, stressTest bool) *server.Server {
 				log.Error(err)
 				os.Exit(-1)
 			}
+
+			server.Rails = append(server.Rails, &bitcoin.Rail{
+				Listener:         bitcoinListener,
+				AddressGenerator: bitcoinAddressGenerator,
+				MinimumValueBtc:  cfg.Bitcoin.MinimumValueBtc,
+				TokenPriceBtc:    cfg.Bitcoin.TokenPrice,
+			})
 		}
 
 		if cfg.Ethereum != nil {
@@ -347,18 +377,23 @@ func createServer(cfg config.Config, stressTest bool) *server.Server {
 				os.Exit(-1)
 			}
 
-			ethereumListener.Enabled = true
 			ethereumListener.NetworkID = cfg.Ethereum.NetworkID
-			server.MinimumValueEth = cfg.Ethereum.MinimumValueEth
 
 			ethereumAddressGenerator, err = ethereum.NewAddressGenerator(cfg.Ethereum.MasterPublicKey)
 			if err != nil {
 				log.Error(err)
 				os.Exit(-1)
 			}
+
+			server.Rails = append(server.Rails, &ethereum.Rail{
+				Listener:         ethereumListener,
+				AddressGenerator: ethereumAddressGenerator,
+				RpcServer:        cfg.Ethereum.RpcServer,
+				MinimumValueEth:  cfg.Ethereum.MinimumValueEth,
+				TokenPriceEth:    cfg.Ethereum.TokenPrice,
+			})
 		}
 	} else {
-		bitcoinListener.Enabled = true
 		bitcoinListener.Testnet = true
 		bitcoinAddressGenerator, err = bitcoin.NewAddressGenerator(cfg.Bitcoin.MasterPublicKey, &chaincfg.TestNet3Params)
 		if err != nil {
@@ -366,13 +401,28 @@ func createServer(cfg config.Config, stressTest bool) *server.Server {
 			os.Exit(-1)
 		}
 
-		ethereumListener.Enabled = true
 		ethereumListener.NetworkID = "3"
 		ethereumAddressGenerator, err = ethereum.NewAddressGenerator(cfg.Ethereum.MasterPublicKey)
 		if err != nil {
 			log.Error(err)
 			os.Exit(-1)
 		}
+
+		server.Rails = []rail.Rail{
+			&bitcoin.Rail{
+				Listener:         bitcoinListener,
+				AddressGenerator: bitcoinAddressGenerator,
+				MinimumValueBtc:  cfg.Bitcoin.MinimumValueBtc,
+				TokenPriceBtc:    cfg.Bitcoin.TokenPrice,
+			},
+			&ethereum.Rail{
+				Listener:         ethereumListener,
+				AddressGenerator: ethereumAddressGenerator,
+				RpcServer:        cfg.Ethereum.RpcServer,
+				MinimumValueEth:  cfg.Ethereum.MinimumValueEth,
+				TokenPriceEth:    cfg.Ethereum.TokenPrice,
+			},
+		}
 	}
 
 	stellarAccountConfigurator := &stellar.AccountConfigurator{
@@ -384,18 +434,16 @@ func createServer(cfg config.Config, stressTest bool) *server.Server {
 		TokenAssetCode:        cfg.Stellar.TokenAssetCode,
 		StartingBalance:       cfg.Stellar.StartingBalance,
 		LockUnixTimestamp:     cfg.Stellar.LockUnixTimestamp,
+		SignerTimeout:         time.Duration(cfg.Stellar.SignerTimeout) * time.Second,
 	}
 
 	if cfg.Stellar.StartingBalance == "" {
 		stellarAccountConfigurator.StartingBalance = "2.1"
 	}
 
-	if cfg.Bitcoin != nil {
-		stellarAccountConfigurator.TokenPriceBTC = cfg.Bitcoin.TokenPrice
-	}
-
-	if cfg.Ethereum != nil {
-		stellarAccountConfigurator.TokenPriceETH = cfg.Ethereum.TokenPrice
+	stellarAccountConfigurator.TokenPrices = make(map[string]string)
+	for _, r := range server.Rails {
+		stellarAccountConfigurator.TokenPrices[string(r.AssetCode())] = r.TokenPrice()
 	}
 
 	horizonClient := &horizon.Client{
@@ -18,6 +18,12 @@ type Transaction struct {
 	// Currently, the length of Amount string shouldn't be longer than 17 characters.
 	Amount           string
 	StellarPublicKey string
+	// Attempts is the number of times the transaction has been received from the queue,
+	// including the current one. Set by QueuePool.
+	Attempts int
+	// LeaseID identifies the current receipt of the transaction. It's set by QueuePool and
+	// must be passed back (in Transaction) to QueueExtend, QueueAck and QueueNack.
+	LeaseID string
 }
 
 // Queue implements transactions queue.
@@ -26,12 +32,31 @@ type Transaction struct {
 // entry with the same ID was added.
 // This is a critical requirement! Otherwise ETH/BTC may be sent twice to Stellar account.
 // If you don't know what to do, use default AWS SQS FIFO queue or DB queue.
+//
+// A transaction received from the queue is hidden from other consumers for a visibility
+// timeout. The consumer must call QueueAck when the transaction is processed or QueueNack
+// if it failed so it's retried later. If it does neither (ex. the process crashed) the
+// transaction is visible again when the timeout expires.
 type Queue interface {
 	// QueueAdd inserts the element to this queue. If element already exists in a queue, it should
 	// return nil.
 	QueueAdd(tx Transaction) error
-	// QueuePool receives and removes the head of this queue. Returns nil if no elements found.
+	// QueuePool receives the head of this queue and hides it from other consumers for the
+	// visibility timeout. Returns nil if no elements found.
 	QueuePool() (*Transaction, error)
+	// QueueExtend extends the visibility timeout of a transaction received from QueuePool.
+	// It must be called periodically while the transaction is processed. Returns error if
+	// the lease expired and the transaction may be processed by another consumer.
+	QueueExtend(tx Transaction) error
+	// QueueAck removes a transaction received from QueuePool from this queue.
+	QueueAck(tx Transaction) error
+	// QueueNack makes a transaction received from QueuePool visible again after a retry
+	// delay, or marks it as failed if the maximum number of attempts was reached.
+	// Returns true if the transaction has been marked as failed and won't be retried.
+	QueueNack(tx Transaction, reason error) (failed bool, err error)
+	// QueueFail marks a transaction received from QueuePool as failed without retrying it,
+	// ex. when retrying it is not safe.
+	QueueFail(tx Transaction, reason error) error
 }
 
 type SQSFiFo struct{}
@@ -18,3 +18,23 @@ func (m *MockQueue) QueuePool() (*Transaction, error) {
 	a := m.Called()
 	return a.Get(0).(*Transaction), a.Error(1)
 }
+
+func (m *MockQueue) QueueExtend(tx Transaction) error {
+	a := m.Called(tx)
+	return a.Error(0)
+}
+
+func (m *MockQueue) QueueAck(tx Transaction) error {
+	a := m.Called(tx)
+	return a.Error(0)
+}
+
+func (m *MockQueue) QueueNack(tx Transaction, reason error) (bool, error) {
+	a := m.Called(tx, reason)
+	return a.Bool(0), a.Error(1)
+}
+
+func (m *MockQueue) QueueFail(tx Transaction, reason error) error {
+	a := m.Called(tx, reason)
+	return a.Error(0)
+}
@@ -0,0 +1,45 @@
+// Package rail defines the interface a blockchain must implement so Bifrost
+// can accept payments on it. Bitcoin and Ethereum rails are implemented in
+// `bitcoin` and `ethereum` packages, new chains can be added by implementing
+// `Rail` and passing it to `server.Server`.
+package rail
+
+import (
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/queue"
+)
+
+// Rail represents a blockchain Bifrost accepts payments on.
+type Rail interface {
+	// Chain returns the name of the chain, ex. `bitcoin`. It is used to store
+	// address associations and processed transactions in the database and in
+	// the `/generate-{chain}-address` endpoint path.
+	Chain() database.Chain
+	// AssetCode returns the code of the Stellar asset representing the chain
+	// currency, ex. `BTC`.
+	AssetCode() queue.AssetCode
+	// TokenPrice returns the price of one token in the chain currency.
+	TokenPrice() string
+	// MinimumAmount returns the minimum value of accepted payments in the chain
+	// currency, ex. `0.0001`. Payments below this value are ignored.
+	MinimumAmount() string
+	// GenerateAddress returns the receiving address derived using `index`.
+	GenerateAddress(index uint32) (string, error)
+	// Start starts listening for payments. `handler` is called for each
+	// payment and the payment must be processed again if it returns error.
+	Start(handler TransactionHandler) error
+}
+
+// TransactionHandler is called by a `Rail` for each payment.
+type TransactionHandler func(transaction Transaction) error
+
+// Transaction represents a payment received on a chain.
+type Transaction struct {
+	// Hash is the ID of the transaction in the chain.
+	Hash string
+	// To is the receiving address.
+	To string
+	// Amount is the value of the payment in the chain currency, in the
+	// format described in queue.Transaction.
+	Amount string
+}
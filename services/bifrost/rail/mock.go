@@ -0,0 +1,42 @@
+package rail
+
+import (
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stretchr/testify/mock"
+)
+
+// MockRail is a mockable rail.
+type MockRail struct {
+	mock.Mock
+}
+
+func (m *MockRail) Chain() database.Chain {
+	a := m.Called()
+	return a.Get(0).(database.Chain)
+}
+
+func (m *MockRail) AssetCode() queue.AssetCode {
+	a := m.Called()
+	return a.Get(0).(queue.AssetCode)
+}
+
+func (m *MockRail) TokenPrice() string {
+	a := m.Called()
+	return a.String(0)
+}
+
+func (m *MockRail) MinimumAmount() string {
+	a := m.Called()
+	return a.String(0)
+}
+
+func (m *MockRail) GenerateAddress(index uint32) (string, error) {
+	a := m.Called(index)
+	return a.String(0), a.Error(1)
+}
+
+func (m *MockRail) Start(handler TransactionHandler) error {
+	a := m.Called(handler)
+	return a.Error(0)
+}
@@ -0,0 +1,255 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	stdhttp "net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/sse"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/http"
+	"github.com/stellar/go/support/log"
+)
+
+// defaultStuckAfter is the default time after which a conversion in progress that
+// hasn't changed its status is considered stuck.
+const defaultStuckAfter = time.Hour
+
+// Values of `status` param of `GET /conversions` admin endpoint.
+const (
+	conversionsStatusStuck    = "stuck"
+	conversionsStatusFailed   = "failed"
+	conversionsStatusRefunded = "refunded"
+)
+
+func (s *Server) startAdminHTTPServer() {
+	addr := fmt.Sprintf("0.0.0.0:%d", s.Config.AdminPort)
+
+	http.Run(http.Config{
+		ListenAddr: addr,
+		Handler:    s.adminMux(),
+		OnStarting: func() {
+			log.Infof("admin server listening on %s", addr)
+		},
+	})
+}
+
+func (s *Server) adminMux() *chi.Mux {
+	mux := http.NewMux(false)
+
+	mux.Get("/conversions", s.HandlerConversions)
+	mux.Post("/conversions/{chain}/{transaction_id}/retry", s.HandlerRetryConversion)
+	mux.Post("/conversions/{chain}/{transaction_id}/refund", s.HandlerRefundConversion)
+
+	return mux
+}
+
+// HandlerConversions lists stuck and failed conversions. `status` param limits the
+// list to `stuck`, `failed` or `refunded` conversions. Conversions in progress are
+// stuck when their status hasn't changed for `stuck_after` seconds (1 hour by default).
+func (s *Server) HandlerConversions(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	stuckAfter, ok := parseStuckAfter(w, r, 0)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	stuck := func() ([]database.Conversion, error) {
+		return s.Database.GetConversions(database.InProgressConversionStatuses, now.Add(-stuckAfter))
+	}
+	withStatus := func(status database.ConversionStatus) func() ([]database.Conversion, error) {
+		return func() ([]database.Conversion, error) {
+			return s.Database.GetConversions([]database.ConversionStatus{status}, now)
+		}
+	}
+
+	var queries []func() ([]database.Conversion, error)
+	switch status := r.URL.Query().Get("status"); status {
+	case "":
+		queries = append(queries, stuck, withStatus(database.ConversionStatusFailed))
+	case conversionsStatusStuck:
+		queries = append(queries, stuck)
+	case conversionsStatusFailed:
+		queries = append(queries, withStatus(database.ConversionStatusFailed))
+	case conversionsStatusRefunded:
+		queries = append(queries, withStatus(database.ConversionStatusRefunded))
+	default:
+		log.WithField("status", status).Warn("Invalid status")
+		w.WriteHeader(stdhttp.StatusBadRequest)
+		return
+	}
+
+	response := ConversionsResponse{Conversions: []database.Conversion{}}
+	for _, query := range queries {
+		conversions, err := query()
+		if err != nil {
+			log.WithField("err", err).Error("Error getting conversions")
+			w.WriteHeader(stdhttp.StatusInternalServerError)
+			return
+		}
+		response.Conversions = append(response.Conversions, conversions...)
+	}
+
+	s.writeJSON(w, response)
+}
+
+// HandlerRetryConversion returns a failed or stuck (see HandlerConversions) conversion
+// to the transactions queue. Tokens are not sent again if they have been sent.
+// `stuck_after` must not be lower than the visibility timeout of the transactions queue.
+func (s *Server) HandlerRetryConversion(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	chain := database.Chain(chi.URLParam(r, "chain"))
+	transactionID := chi.URLParam(r, "transaction_id")
+	localLog := log.WithFields(log.F{"chain": chain, "transaction_id": transactionID})
+
+	stuckAfter, ok := parseStuckAfter(w, r, s.minimumStuckAfter())
+	if !ok {
+		return
+	}
+
+	err := s.Database.RetryConversion(chain, transactionID, time.Now().Add(-stuckAfter))
+	if err != nil {
+		s.writeConversionError(w, localLog, err, "Error retrying conversion")
+		return
+	}
+
+	localLog.Info("Conversion returned to the transactions queue")
+
+	conversion := s.getConversion(w, localLog, chain, transactionID)
+	if conversion == nil {
+		return
+	}
+
+	s.broadcastConversionEvent(conversion, sse.ConversionRetriedEvent, map[string]string{
+		"transaction_id": conversion.TransactionID,
+	})
+	s.writeJSON(w, conversion)
+}
+
+// HandlerRefundConversion records that a failed or stuck (see HandlerConversions)
+// conversion has been refunded in `refund_transaction_id` chain transaction. Bifrost does
+// not send refunds itself. Conversions whose tokens may have been sent cannot be refunded.
+// `stuck_after` must not be lower than the visibility timeout of the transactions queue.
+func (s *Server) HandlerRefundConversion(w stdhttp.ResponseWriter, r *stdhttp.Request) {
+	chain := database.Chain(chi.URLParam(r, "chain"))
+	transactionID := chi.URLParam(r, "transaction_id")
+	localLog := log.WithFields(log.F{"chain": chain, "transaction_id": transactionID})
+
+	stuckAfter, ok := parseStuckAfter(w, r, s.minimumStuckAfter())
+	if !ok {
+		return
+	}
+
+	refundTransactionID := r.PostFormValue("refund_transaction_id")
+	if refundTransactionID == "" {
+		localLog.Warn("Invalid input. No refund_transaction_id")
+		w.WriteHeader(stdhttp.StatusBadRequest)
+		return
+	}
+
+	err := s.Database.RefundConversion(chain, transactionID, refundTransactionID, time.Now().Add(-stuckAfter))
+	if err != nil {
+		s.writeConversionError(w, localLog, err, "Error refunding conversion")
+		return
+	}
+
+	localLog.WithField("refund_transaction_id", refundTransactionID).Info("Conversion refunded")
+
+	conversion := s.getConversion(w, localLog, chain, transactionID)
+	if conversion == nil {
+		return
+	}
+
+	s.broadcastConversionEvent(conversion, sse.RefundedEvent, map[string]string{
+		"transaction_id":        conversion.TransactionID,
+		"refund_transaction_id": conversion.RefundTransactionID,
+	})
+	s.writeJSON(w, conversion)
+}
+
+func (s *Server) getConversion(w stdhttp.ResponseWriter, localLog *log.Entry, chain database.Chain, transactionID string) *database.Conversion {
+	conversion, err := s.Database.GetConversion(chain, transactionID)
+	if err != nil {
+		localLog.WithField("err", err).Error("Error getting conversion")
+		w.WriteHeader(stdhttp.StatusInternalServerError)
+		return nil
+	}
+
+	if conversion == nil {
+		w.WriteHeader(stdhttp.StatusNotFound)
+		return nil
+	}
+
+	return conversion
+}
+
+func (s *Server) writeConversionError(w stdhttp.ResponseWriter, localLog *log.Entry, err error, message string) {
+	switch errors.Cause(err) {
+	case database.ErrConversionNotFound:
+		w.WriteHeader(stdhttp.StatusNotFound)
+	case database.ErrConversionNotFailed:
+		localLog.Warn("Conversion is not failed or stuck")
+		w.WriteHeader(stdhttp.StatusConflict)
+	case database.ErrConversionTokensSent:
+		localLog.Warn("Conversion tokens may have been sent")
+		w.WriteHeader(stdhttp.StatusConflict)
+	default:
+		localLog.WithField("err", err).Error(message)
+		w.WriteHeader(stdhttp.StatusInternalServerError)
+	}
+}
+
+// parseStuckAfter returns the value of `stuck_after` param, the time after which a
+// conversion in progress that hasn't changed its status is stuck. It writes a bad request
+// response and returns false if the param is invalid or lower than `minimum`.
+func parseStuckAfter(w stdhttp.ResponseWriter, r *stdhttp.Request, minimum time.Duration) (time.Duration, bool) {
+	value := r.URL.Query().Get("stuck_after")
+	if value == "" {
+		return defaultStuckAfter, true
+	}
+
+	seconds, err := strconv.ParseUint(value, 10, 32)
+	if err != nil || time.Duration(seconds)*time.Second < minimum {
+		log.WithFields(log.F{"stuck_after": value, "minimum": minimum}).Warn("Invalid stuck_after")
+		w.WriteHeader(stdhttp.StatusBadRequest)
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+// minimumStuckAfter returns the minimum `stuck_after` of conversions that are retried or
+// refunded: the visibility timeout of the transactions queue. A conversion whose status
+// changed more recently may still be processed by a Bifrost instance, retrying it could
+// make another instance send tokens at the same time.
+func (s *Server) minimumStuckAfter() time.Duration {
+	if s.Config == nil || s.Config.Queue == nil || s.Config.Queue.VisibilityTimeout == 0 {
+		return database.DefaultQueueVisibilityTimeout
+	}
+	return time.Duration(s.Config.Queue.VisibilityTimeout) * time.Second
+}
+
+func (s *Server) broadcastConversionEvent(conversion *database.Conversion, event sse.AddressEvent, data map[string]string) {
+	j, err := json.Marshal(data)
+	if err != nil {
+		log.WithField("data", data).Error("Error marshalling json")
+		return
+	}
+
+	s.SSEServer.BroadcastEvent(conversion.Address, event, j)
+}
+
+func (s *Server) writeJSON(w stdhttp.ResponseWriter, response interface{}) {
+	responseBytes, err := json.Marshal(response)
+	if err != nil {
+		log.WithField("err", err).Error("Error encoding JSON")
+		w.WriteHeader(stdhttp.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(responseBytes)
+}
@@ -0,0 +1,242 @@
+// Skip this test file in Go <1.8 because it's using http.Server.Shutdown
+// +build go1.8
+
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/services/bifrost/sse"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type AdminTestSuite struct {
+	suite.Suite
+	Server        *Server
+	Mux           *chi.Mux
+	MockDatabase  *database.MockDatabase
+	MockSSEServer *sse.MockServer
+}
+
+func (suite *AdminTestSuite) SetupTest() {
+	suite.MockDatabase = &database.MockDatabase{}
+	suite.MockSSEServer = &sse.MockServer{}
+
+	suite.Server = &Server{
+		Database:  suite.MockDatabase,
+		SSEServer: suite.MockSSEServer,
+	}
+	suite.Server.initLogger()
+	suite.Mux = suite.Server.adminMux()
+}
+
+func (suite *AdminTestSuite) TearDownTest() {
+	suite.MockDatabase.AssertExpectations(suite.T())
+	suite.MockSSEServer.AssertExpectations(suite.T())
+}
+
+func (suite *AdminTestSuite) conversion(status database.ConversionStatus) database.Conversion {
+	return database.Conversion{
+		Chain:            database.ChainBitcoin,
+		TransactionID:    "109fa1c369680c2f27643fdd160620d010851a376d25b9b00ef71afe789ea6ed",
+		Address:          "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf",
+		StellarPublicKey: "GDULKYRRVOMASFMXBYD4BYFRSHAKQDREEVVP2TMH2CER3DW2KATIOASB",
+		AssetCode:        queue.AssetCodeBTC,
+		Amount:           "1.0000000",
+		Status:           status,
+	}
+}
+
+func (suite *AdminTestSuite) request(method, path string, form url.Values) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	w := httptest.NewRecorder()
+	suite.Mux.ServeHTTP(w, r)
+	return w
+}
+
+func (suite *AdminTestSuite) TestConversions() {
+	stuck := suite.conversion(database.ConversionStatusWaitingForSigner)
+	failed := suite.conversion(database.ConversionStatusFailed)
+	failed.TransactionID = "0a190d17ba0405bce37fafd3a7a7bef51264ea4083ffae3b2de90ed61ee5264e"
+
+	suite.MockDatabase.
+		On("GetConversions", database.InProgressConversionStatuses, mock.AnythingOfType("time.Time")).
+		Return([]database.Conversion{stuck}, nil)
+	suite.MockDatabase.
+		On("GetConversions", []database.ConversionStatus{database.ConversionStatusFailed}, mock.AnythingOfType("time.Time")).
+		Return([]database.Conversion{failed}, nil)
+
+	w := suite.request("GET", "/conversions", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	var response ConversionsResponse
+	suite.Require().NoError(json.Unmarshal(w.Body.Bytes(), &response))
+	suite.Require().Len(response.Conversions, 2)
+	suite.Assert().Equal(database.ConversionStatusWaitingForSigner, response.Conversions[0].Status)
+	suite.Assert().Equal(failed.TransactionID, response.Conversions[1].TransactionID)
+}
+
+func (suite *AdminTestSuite) TestConversionsInvalidParams() {
+	suite.MockDatabase.AssertNotCalled(suite.T(), "GetConversions")
+
+	w := suite.request("GET", "/conversions?status=completed", nil)
+	suite.Assert().Equal(http.StatusBadRequest, w.Code)
+
+	w = suite.request("GET", "/conversions?stuck_after=-1", nil)
+	suite.Assert().Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *AdminTestSuite) TestRetryConversion() {
+	conversion := suite.conversion(database.ConversionStatusPending)
+
+	suite.MockDatabase.
+		On("RetryConversion", database.ChainBitcoin, conversion.TransactionID, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	suite.MockDatabase.
+		On("GetConversion", database.ChainBitcoin, conversion.TransactionID).
+		Return(&conversion, nil)
+	suite.MockSSEServer.
+		On("BroadcastEvent", conversion.Address, sse.ConversionRetriedEvent, mock.AnythingOfType("[]uint8"))
+
+	w := suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/retry", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+	suite.Assert().Contains(w.Body.String(), `"status":"pending"`)
+}
+
+func (suite *AdminTestSuite) TestRetryConversionNotFailed() {
+	conversion := suite.conversion(database.ConversionStatusCompleted)
+
+	suite.MockDatabase.
+		On("RetryConversion", database.ChainBitcoin, conversion.TransactionID, mock.AnythingOfType("time.Time")).
+		Return(database.ErrConversionNotFailed)
+	suite.MockSSEServer.AssertNotCalled(suite.T(), "BroadcastEvent")
+
+	w := suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/retry", nil)
+	suite.Assert().Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *AdminTestSuite) TestRefundConversion() {
+	conversion := suite.conversion(database.ConversionStatusRefunded)
+	conversion.RefundTransactionID = "c2a1e0f1d3a4c4b1d1b3a5f3c47c7e1ed0b93a0e6a1a1d7b0d9d8e8e4f3c2b1a"
+
+	suite.MockDatabase.
+		On("RefundConversion", database.ChainBitcoin, conversion.TransactionID, conversion.RefundTransactionID, mock.AnythingOfType("time.Time")).
+		Return(nil)
+	suite.MockDatabase.
+		On("GetConversion", database.ChainBitcoin, conversion.TransactionID).
+		Return(&conversion, nil)
+	suite.MockSSEServer.
+		On("BroadcastEvent", conversion.Address, sse.RefundedEvent, mock.AnythingOfType("[]uint8")).
+		Run(func(args mock.Arguments) {
+			var data map[string]string
+			suite.Require().NoError(json.Unmarshal(args.Get(2).([]byte), &data))
+			suite.Assert().Equal(conversion.RefundTransactionID, data["refund_transaction_id"])
+		})
+
+	w := suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/refund", url.Values{
+		"refund_transaction_id": []string{conversion.RefundTransactionID},
+	})
+	suite.Require().Equal(http.StatusOK, w.Code)
+	suite.Assert().Contains(w.Body.String(), `"status":"refunded"`)
+}
+
+func (suite *AdminTestSuite) TestRefundConversionErrors() {
+	conversion := suite.conversion(database.ConversionStatusFailed)
+
+	suite.MockDatabase.AssertNotCalled(suite.T(), "RefundConversion")
+	w := suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/refund", nil)
+	suite.Assert().Equal(http.StatusBadRequest, w.Code)
+
+	suite.MockDatabase.
+		On("RefundConversion", database.ChainEthereum, conversion.TransactionID, "refund", mock.AnythingOfType("time.Time")).
+		Return(database.ErrConversionNotFound)
+	w = suite.request("POST", "/conversions/ethereum/"+conversion.TransactionID+"/refund", url.Values{
+		"refund_transaction_id": []string{"refund"},
+	})
+	suite.Assert().Equal(http.StatusNotFound, w.Code)
+
+	suite.MockDatabase.
+		On("RefundConversion", database.ChainBitcoin, conversion.TransactionID, "refund", mock.AnythingOfType("time.Time")).
+		Return(database.ErrConversionTokensSent)
+	w = suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/refund", url.Values{
+		"refund_transaction_id": []string{"refund"},
+	})
+	suite.Assert().Equal(http.StatusConflict, w.Code)
+}
+
+func (suite *AdminTestSuite) TestRefundConversionConfiguringAccount() {
+	// The transaction sending tokens may have been applied when the conversion stopped
+	// while configuring the account.
+	conversion := suite.conversion(database.ConversionStatusConfiguringAccount)
+	conversion.Step = database.ConversionStatusConfiguringAccount
+
+	suite.MockDatabase.
+		On("RefundConversion", database.ChainBitcoin, conversion.TransactionID, "refund", mock.AnythingOfType("time.Time")).
+		Return(database.ErrConversionTokensSent)
+
+	w := suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/refund?stuck_after=600", url.Values{
+		"refund_transaction_id": []string{"refund"},
+	})
+	suite.Assert().Equal(http.StatusConflict, w.Code)
+	suite.MockSSEServer.AssertNotCalled(suite.T(), "BroadcastEvent", conversion.Address, sse.RefundedEvent, mock.Anything)
+}
+
+func (suite *AdminTestSuite) TestRetryConversionStuck() {
+	conversion := suite.conversion(database.ConversionStatusPending)
+
+	before := time.Now()
+	suite.MockDatabase.
+		On("RetryConversion", database.ChainBitcoin, conversion.TransactionID, mock.AnythingOfType("time.Time")).
+		Run(func(args mock.Arguments) {
+			// conversions not updated for 10 minutes are stuck
+			stuckBefore := args.Get(2).(time.Time)
+			suite.Assert().WithinDuration(before.Add(-10*time.Minute), stuckBefore, time.Minute)
+		}).
+		Return(nil)
+	suite.MockDatabase.
+		On("GetConversion", database.ChainBitcoin, conversion.TransactionID).
+		Return(&conversion, nil)
+	suite.MockSSEServer.
+		On("BroadcastEvent", conversion.Address, sse.ConversionRetriedEvent, mock.AnythingOfType("[]uint8"))
+
+	w := suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/retry?stuck_after=600", nil)
+	suite.Require().Equal(http.StatusOK, w.Code)
+
+	w = suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/retry?stuck_after=soon", nil)
+	suite.Assert().Equal(http.StatusBadRequest, w.Code)
+}
+
+func (suite *AdminTestSuite) TestRetryConversionStuckAfterVisibilityTimeout() {
+	conversion := suite.conversion(database.ConversionStatusConfiguringAccount)
+
+	// A conversion updated more recently than the visibility timeout of the transactions
+	// queue may still be processed by another instance.
+	w := suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/retry?stuck_after=0", nil)
+	suite.Assert().Equal(http.StatusBadRequest, w.Code)
+
+	// the default visibility timeout is 2 minutes
+	w = suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/retry?stuck_after=60", nil)
+	suite.Assert().Equal(http.StatusBadRequest, w.Code)
+	w = suite.request("POST", "/conversions/bitcoin/"+conversion.TransactionID+"/refund?stuck_after=60", url.Values{
+		"refund_transaction_id": []string{"refund"},
+	})
+	suite.Assert().Equal(http.StatusBadRequest, w.Code)
+
+	suite.MockDatabase.AssertNotCalled(suite.T(), "RetryConversion", mock.Anything, mock.Anything, mock.Anything)
+	suite.MockDatabase.AssertNotCalled(suite.T(), "RefundConversion", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAdminTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminTestSuite))
+}
@@ -1,17 +1,16 @@
 package server
 
 import (
-	"math/big"
 	"net/http"
 
-	"github.com/stellar/go/services/bifrost/bitcoin"
 	"github.com/stellar/go/services/bifrost/config"
 	"github.com/stellar/go/services/bifrost/database"
-	"github.com/stellar/go/services/bifrost/ethereum"
 	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/services/bifrost/rail"
 	"github.com/stellar/go/services/bifrost/sse"
 	"github.com/stellar/go/services/bifrost/stellar"
 	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/xdr"
 )
 
 // ProtocolVersion is the version of the protocol that Bifrost server and
@@ -19,24 +18,20 @@ import (
 const ProtocolVersion int = 2
 
 type Server struct {
-	BitcoinListener            *bitcoin.Listener            `inject:""`
-	BitcoinAddressGenerator    *bitcoin.AddressGenerator    `inject:""`
 	Config                     *config.Config               `inject:""`
 	Database                   database.Database            `inject:""`
-	EthereumListener           *ethereum.Listener           `inject:""`
-	EthereumAddressGenerator   *ethereum.AddressGenerator   `inject:""`
 	StellarAccountConfigurator *stellar.AccountConfigurator `inject:""`
 	TransactionsQueue          queue.Queue                  `inject:""`
 	SSEServer                  sse.ServerInterface          `inject:""`
 
-	MinimumValueBtc string
-	MinimumValueEth string
+	// Rails are the chains payments are accepted on.
+	Rails           []rail.Rail
 	SignerPublicKey string
 
-	minimumValueSat int64
-	minimumValueWei *big.Int
-	httpServer      *http.Server
-	log             *log.Entry
+	// minimumAmounts are minimum values of accepted payments by chain.
+	minimumAmounts map[database.Chain]xdr.Int64
+	httpServer     *http.Server
+	log            *log.Entry
 }
 
 type GenerateAddressResponse struct {
@@ -45,3 +40,8 @@ type GenerateAddressResponse struct {
 	Address         string `json:"address"`
 	Signer          string `json:"signer"`
 }
+
+// ConversionsResponse is a response of `GET /conversions` admin endpoint.
+type ConversionsResponse struct {
+	Conversions []database.Conversion `json:"conversions"`
+}
@@ -1,9 +1,21 @@
 package server
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/services/bifrost/sse"
+	"github.com/stellar/go/services/bifrost/stellar"
+	"github.com/stellar/go/support/log"
 )
 
+// QueueHeartbeatInterval is the interval between extensions of the visibility timeout
+// of a transaction being processed. It must be lower than the visibility timeout of
+// the transactions queue.
+const QueueHeartbeatInterval = 30 * time.Second
+
 // poolTransactionsQueue pools transactions queue which contains only processed and
 // validated transactions and sends it to StellarAccountConfigurator for account configuration.
 func (s *Server) poolTransactionsQueue() {
@@ -23,10 +35,156 @@ func (s *Server) poolTransactionsQueue() {
 		}
 
 		s.log.WithField("transaction", transaction).Info("Received transaction from transactions queue")
-		go s.StellarAccountConfigurator.ConfigureAccount(
-			transaction.StellarPublicKey,
-			string(transaction.AssetCode),
-			transaction.Amount,
-		)
+		go s.processTransaction(*transaction)
+	}
+}
+
+// processTransaction configures the account of a transaction received from the queue
+// and acknowledges it, or returns it to the queue to be retried later if it failed.
+// Conversion status is updated on every step. A transaction that failed after tokens
+// were sent is not retried: it's marked as failed to be handled manually.
+func (s *Server) processTransaction(transaction queue.Transaction) {
+	localLog := s.log.WithField("transaction", transaction)
+
+	// Steps of a previous attempt that cannot be repeated are skipped.
+	conversion, err := s.Database.GetQueuedConversion(transaction.TransactionID, transaction.AssetCode)
+	if err != nil {
+		localLog.WithField("err", err).Error("Error getting conversion, returning transaction to the queue")
+		_, err = s.TransactionsQueue.QueueNack(transaction, err)
+		if err != nil {
+			localLog.WithField("err", err).Error("Error returning transaction to the queue")
+		}
+		return
+	}
+
+	var step stellar.Status
+	if conversion != nil {
+		step = stellar.Status(conversion.Step)
+	}
+
+	done := make(chan struct{})
+	go s.extendTransactionVisibility(transaction, done)
+
+	err = s.StellarAccountConfigurator.ConfigureAccount(
+		transaction.StellarPublicKey,
+		string(transaction.AssetCode),
+		transaction.Amount,
+		step,
+		func(status stellar.Status) error {
+			// The lease is lost when the conversion was retried or refunded using Admin API
+			// in the meantime.
+			err := s.TransactionsQueue.QueueExtend(transaction)
+			if err != nil {
+				return err
+			}
+
+			step = status
+			s.setConversionStatus(transaction, database.ConversionStatus(status), "")
+			s.setConversionStep(transaction, database.ConversionStatus(status))
+			return nil
+		},
+	)
+	close(done)
+
+	if err != nil {
+		if step == stellar.StatusRemovingSigner {
+			localLog.WithField("err", err).Error("Error processing transaction after tokens were sent, conversion failed")
+			failErr := s.TransactionsQueue.QueueFail(transaction, err)
+			if failErr != nil {
+				localLog.WithField("err", failErr).Error("Error marking transaction as failed in the queue")
+				return
+			}
+
+			s.setConversionStatus(transaction, database.ConversionStatusFailed, err.Error())
+			s.onConversionFailed(transaction)
+			return
+		}
+
+		localLog.WithField("err", err).Error("Error processing transaction, returning it to the queue")
+		failed, nackErr := s.TransactionsQueue.QueueNack(transaction, err)
+		if nackErr != nil {
+			localLog.WithField("err", nackErr).Error("Error returning transaction to the queue")
+			return
+		}
+
+		if failed {
+			localLog.Error("Maximum number of attempts reached, conversion failed")
+			s.setConversionStatus(transaction, database.ConversionStatusFailed, err.Error())
+			s.onConversionFailed(transaction)
+		} else {
+			s.setConversionStatus(transaction, database.ConversionStatusPending, err.Error())
+		}
+		return
+	}
+
+	s.setConversionStatus(transaction, database.ConversionStatusCompleted, "")
+
+	err = s.TransactionsQueue.QueueAck(transaction)
+	if err != nil {
+		localLog.WithField("err", err).Error("Error acknowledging transaction")
+	}
+}
+
+func (s *Server) setConversionStatus(transaction queue.Transaction, status database.ConversionStatus, lastError string) {
+	err := s.Database.SetConversionStatus(transaction.TransactionID, transaction.AssetCode, status, lastError)
+	if err != nil {
+		s.log.WithFields(log.F{"err": err, "transaction": transaction, "status": status}).
+			Error("Error updating conversion status")
+	}
+}
+
+func (s *Server) setConversionStep(transaction queue.Transaction, step database.ConversionStatus) {
+	err := s.Database.SetConversionStep(transaction.TransactionID, transaction.AssetCode, step)
+	if err != nil {
+		s.log.WithFields(log.F{"err": err, "transaction": transaction, "step": step}).
+			Error("Error updating conversion step")
+	}
+}
+
+// onConversionFailed broadcasts ConversionFailedEvent to the stream of the address
+// associated with the transaction's Stellar account.
+func (s *Server) onConversionFailed(transaction queue.Transaction) {
+	association, err := s.Database.GetAssociationByStellarPublicKey(transaction.StellarPublicKey)
+	if err != nil {
+		s.log.WithField("err", err).Error("Error getting association")
+		return
+	}
+
+	if association == nil {
+		s.log.WithField("stellarPublicKey", transaction.StellarPublicKey).Error("Association not found")
+		return
+	}
+
+	data := map[string]string{
+		"transaction_id": transaction.TransactionID,
+	}
+
+	j, err := json.Marshal(data)
+	if err != nil {
+		s.log.WithField("data", data).Error("Error marshalling json")
+		return
+	}
+
+	s.SSEServer.BroadcastEvent(association.Address, sse.ConversionFailedEvent, j)
+}
+
+// extendTransactionVisibility extends the visibility timeout of `transaction` every
+// QueueHeartbeatInterval until `done` is closed so other Bifrost instances don't
+// process it at the same time.
+func (s *Server) extendTransactionVisibility(transaction queue.Transaction, done <-chan struct{}) {
+	ticker := time.NewTicker(QueueHeartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			err := s.TransactionsQueue.QueueExtend(transaction)
+			if err != nil {
+				s.log.WithFields(log.F{"err": err, "transaction": transaction}).
+					Error("Error extending transaction visibility timeout")
+			}
+		}
 	}
 }
@@ -1,38 +1,46 @@
 package server
 
 import (
-	"github.com/stellar/go/services/bifrost/bitcoin"
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/services/bifrost/database"
 	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/services/bifrost/rail"
 	"github.com/stellar/go/services/bifrost/sse"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
 )
 
-// onNewBitcoinTransaction checks if transaction is valid and adds it to
-// the transactions queue for StellarAccountConfigurator to consume.
+// onNewTransaction checks if transaction received on rail `r` is valid and adds
+// it to the transactions queue for StellarAccountConfigurator to consume.
 //
 // Transaction added to transactions queue should be in a format described in
 // queue.Transaction (especialy amounts). Pooling service should not have to deal with any
 // conversions.
 //
-// This is very unlikely but it's possible that a single transaction will have more than
-// one output going to bifrost account. Then only first output will be processed.
+// This is very unlikely but it's possible that a single bitcoin transaction will have more
+// than one output going to bifrost account. Then only first output will be processed.
 // Because it's very unlikely that this will happen and it's not a security issue this
 // will be fixed in a future release.
-func (s *Server) onNewBitcoinTransaction(transaction bitcoin.Transaction) error {
-	localLog := s.log.WithFields(log.F{"transaction": transaction, "rail": "bitcoin"})
+func (s *Server) onNewTransaction(r rail.Rail, transaction rail.Transaction) error {
+	chain := r.Chain()
+	localLog := s.log.WithFields(log.F{"transaction": transaction, "rail": chain})
 	localLog.Debug("Processing transaction")
 
 	// Let's check if tx is valid first.
 
 	// Check if value is above minimum required
-	if transaction.ValueSat < s.minimumValueSat {
+	value, err := amount.Parse(transaction.Amount)
+	if err != nil {
+		localLog.WithField("err", err).Warn("Invalid transaction value, skipping")
+		return nil
+	}
+
+	if value < s.minimumAmounts[chain] {
 		localLog.Debug("Value is below minimum required amount, skipping")
 		return nil
 	}
 
-	addressAssociation, err := s.Database.GetAssociationByChainAddress(database.ChainBitcoin, transaction.To)
+	addressAssociation, err := s.Database.GetAssociationByChainAddress(chain, transaction.To)
 	if err != nil {
 		return errors.Wrap(err, "Error getting association")
 	}
@@ -43,7 +51,7 @@ func (s *Server) onNewBitcoinTransaction(transaction bitcoin.Transaction) error
 	}
 
 	// Add transaction as processing.
-	processed, err := s.Database.AddProcessedTransaction(database.ChainBitcoin, transaction.Hash, transaction.To)
+	processed, err := s.Database.AddProcessedTransaction(chain, transaction.Hash, transaction.To)
 	if err != nil {
 		return err
 	}
@@ -53,12 +61,25 @@ func (s *Server) onNewBitcoinTransaction(transaction bitcoin.Transaction) error
 		return nil
 	}
 
+	err = s.Database.AddConversion(database.Conversion{
+		Chain:            chain,
+		TransactionID:    transaction.Hash,
+		Address:          transaction.To,
+		StellarPublicKey: addressAssociation.StellarPublicKey,
+		AssetCode:        r.AssetCode(),
+		Amount:           transaction.Amount,
+		Status:           database.ConversionStatusPending,
+	})
+	if err != nil {
+		return errors.Wrap(err, "Error adding conversion")
+	}
+
 	// Add tx to the processing queue
 	queueTx := queue.Transaction{
 		TransactionID: transaction.Hash,
-		AssetCode:     queue.AssetCodeBTC,
+		AssetCode:     r.AssetCode(),
 		// Amount in the base unit of currency.
-		Amount:           transaction.ValueToStellar(),
+		Amount:           transaction.Amount,
 		StellarPublicKey: addressAssociation.StellarPublicKey,
 	}
 
@@ -0,0 +1,202 @@
+// Skip this test file in Go <1.8 because it's using http.Server.Shutdown
+// +build go1.8
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/services/bifrost/database"
+	"github.com/stellar/go/services/bifrost/queue"
+	"github.com/stellar/go/services/bifrost/rail"
+	"github.com/stellar/go/services/bifrost/sse"
+	"github.com/stellar/go/services/bifrost/stellar"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type RailTestSuite struct {
+	suite.Suite
+	Server        *Server
+	MockBitcoin   *rail.MockRail
+	MockEthereum  *rail.MockRail
+	MockDatabase  *database.MockDatabase
+	MockQueue     *queue.MockQueue
+	MockSSEServer *sse.MockServer
+}
+
+func (suite *RailTestSuite) SetupTest() {
+	suite.MockBitcoin = &rail.MockRail{}
+	suite.MockBitcoin.On("Chain").Return(database.ChainBitcoin)
+	suite.MockBitcoin.On("AssetCode").Return(queue.AssetCodeBTC)
+
+	suite.MockEthereum = &rail.MockRail{}
+	suite.MockEthereum.On("Chain").Return(database.ChainEthereum)
+	suite.MockEthereum.On("AssetCode").Return(queue.AssetCodeETH)
+
+	suite.MockDatabase = &database.MockDatabase{}
+	suite.MockQueue = &queue.MockQueue{}
+	suite.MockSSEServer = &sse.MockServer{}
+
+	suite.Server = &Server{
+		Database:          suite.MockDatabase,
+		TransactionsQueue: suite.MockQueue,
+		SSEServer:         suite.MockSSEServer,
+		Rails:             []rail.Rail{suite.MockBitcoin, suite.MockEthereum},
+		minimumAmounts: map[database.Chain]xdr.Int64{
+			database.ChainBitcoin:  10000000, // 1 BTC
+			database.ChainEthereum: 10000000, // 1 ETH
+		},
+	}
+	suite.Server.initLogger()
+}
+
+func (suite *RailTestSuite) TearDownTest() {
+	suite.MockDatabase.AssertExpectations(suite.T())
+	suite.MockQueue.AssertExpectations(suite.T())
+	suite.MockSSEServer.AssertExpectations(suite.T())
+}
+
+func (suite *RailTestSuite) TestInvalidValue() {
+	transaction := rail.Transaction{
+		Hash:   "109fa1c369680c2f27643fdd160620d010851a376d25b9b00ef71afe789ea6ed",
+		Amount: "0.5000000",
+		To:     "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf",
+	}
+	suite.MockDatabase.AssertNotCalled(suite.T(), "AddProcessedTransaction")
+	suite.MockQueue.AssertNotCalled(suite.T(), "QueueAdd")
+	err := suite.Server.onNewTransaction(suite.MockBitcoin, transaction)
+	suite.Require().NoError(err)
+}
+
+func (suite *RailTestSuite) TestAssociationNotExist() {
+	transaction := rail.Transaction{
+		Hash:   "109fa1c369680c2f27643fdd160620d010851a376d25b9b00ef71afe789ea6ed",
+		Amount: "1.0000000",
+		To:     "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf",
+	}
+	suite.MockDatabase.
+		On("GetAssociationByChainAddress", database.ChainBitcoin, "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf").
+		Return(nil, nil)
+	suite.MockDatabase.AssertNotCalled(suite.T(), "AddProcessedTransaction")
+	suite.MockQueue.AssertNotCalled(suite.T(), "QueueAdd")
+	err := suite.Server.onNewTransaction(suite.MockBitcoin, transaction)
+	suite.Require().NoError(err)
+}
+
+func (suite *RailTestSuite) TestAssociationAlreadyProcessed() {
+	transaction := rail.Transaction{
+		Hash:   "109fa1c369680c2f27643fdd160620d010851a376d25b9b00ef71afe789ea6ed",
+		Amount: "1.0000000",
+		To:     "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf",
+	}
+	association := &database.AddressAssociation{
+		Chain:            database.ChainBitcoin,
+		AddressIndex:     1,
+		Address:          "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf",
+		StellarPublicKey: "GDULKYRRVOMASFMXBYD4BYFRSHAKQDREEVVP2TMH2CER3DW2KATIOASB",
+		CreatedAt:        time.Now(),
+	}
+	suite.MockDatabase.
+		On("GetAssociationByChainAddress", database.ChainBitcoin, transaction.To).
+		Return(association, nil)
+	suite.MockDatabase.
+		On("AddProcessedTransaction", database.ChainBitcoin, transaction.Hash, transaction.To).
+		Return(true, nil)
+	suite.MockDatabase.AssertNotCalled(suite.T(), "AddConversion")
+	suite.MockQueue.AssertNotCalled(suite.T(), "QueueAdd")
+	err := suite.Server.onNewTransaction(suite.MockBitcoin, transaction)
+	suite.Require().NoError(err)
+}
+
+func (suite *RailTestSuite) TestAssociationSuccess() {
+	transactions := []struct {
+		Rail        *rail.MockRail
+		Transaction rail.Transaction
+		Chain       database.Chain
+		AssetCode   queue.AssetCode
+	}{
+		{
+			suite.MockBitcoin,
+			rail.Transaction{
+				Hash:   "109fa1c369680c2f27643fdd160620d010851a376d25b9b00ef71afe789ea6ed",
+				Amount: "1.0000000",
+				To:     "1Q74qRud8bXUn6FMtXWZwJa5pj56s3mdyf",
+			},
+			database.ChainBitcoin,
+			queue.AssetCodeBTC,
+		},
+		{
+			suite.MockEthereum,
+			rail.Transaction{
+				Hash:   "0x0a190d17ba0405bce37fafd3a7a7bef51264ea4083ffae3b2de90ed61ee5264e",
+				Amount: "1.0000000",
+				To:     "0x80D3ee1268DC1A2d1b9E73D49050083E75Ef7c2D",
+			},
+			database.ChainEthereum,
+			queue.AssetCodeETH,
+		},
+	}
+
+	for _, test := range transactions {
+		transaction := test.Transaction
+		association := &database.AddressAssociation{
+			Chain:            test.Chain,
+			AddressIndex:     1,
+			Address:          transaction.To,
+			StellarPublicKey: "GDULKYRRVOMASFMXBYD4BYFRSHAKQDREEVVP2TMH2CER3DW2KATIOASB",
+			CreatedAt:        time.Now(),
+		}
+		suite.MockDatabase.
+			On("GetAssociationByChainAddress", test.Chain, transaction.To).
+			Return(association, nil)
+		suite.MockDatabase.
+			On("AddProcessedTransaction", test.Chain, transaction.Hash, transaction.To).
+			Return(false, nil)
+		suite.MockDatabase.
+			On("AddConversion", mock.MatchedBy(func(conversion database.Conversion) bool {
+				return conversion.TransactionID == transaction.Hash
+			})).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				conversion := args.Get(0).(database.Conversion)
+				suite.Assert().Equal(test.Chain, conversion.Chain)
+				suite.Assert().Equal(transaction.To, conversion.Address)
+				suite.Assert().Equal(test.AssetCode, conversion.AssetCode)
+				suite.Assert().Equal(association.StellarPublicKey, conversion.StellarPublicKey)
+				suite.Assert().Equal(database.ConversionStatusPending, conversion.Status)
+			})
+		suite.MockQueue.
+			On("QueueAdd", mock.MatchedBy(func(queueTransaction queue.Transaction) bool {
+				return queueTransaction.TransactionID == transaction.Hash
+			})).
+			Return(nil).
+			Run(func(args mock.Arguments) {
+				queueTransaction := args.Get(0).(queue.Transaction)
+				suite.Assert().Equal(test.AssetCode, queueTransaction.AssetCode)
+				suite.Assert().Equal("1.0000000", queueTransaction.Amount)
+				suite.Assert().Equal(association.StellarPublicKey, queueTransaction.StellarPublicKey)
+			})
+		suite.MockSSEServer.
+			On("BroadcastEvent", transaction.To, sse.TransactionReceivedAddressEvent, []byte(nil))
+		err := suite.Server.onNewTransaction(test.Rail, transaction)
+		suite.Require().NoError(err)
+	}
+}
+
+func (suite *RailTestSuite) TestStartInvalidMinimumAmount() {
+	suite.MockBitcoin.On("MinimumAmount").Return("0")
+	suite.MockBitcoin.AssertNotCalled(suite.T(), "Start")
+	suite.Server.Rails = []rail.Rail{suite.MockBitcoin}
+	suite.Server.StellarAccountConfigurator = &stellar.AccountConfigurator{}
+
+	err := suite.Server.Start()
+	suite.Require().Error(err)
+	suite.Assert().Contains(err.Error(), "must be larger than 0")
+}
+
+func TestRailTestSuite(t *testing.T) {
+	suite.Run(t, new(RailTestSuite))
+}
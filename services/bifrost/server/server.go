@@ -7,17 +7,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"math/big"
 	stdhttp "net/http"
 	"os"
 	"os/signal"
 	"time"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/keypair"
-	"github.com/stellar/go/services/bifrost/bitcoin"
 	"github.com/stellar/go/services/bifrost/common"
 	"github.com/stellar/go/services/bifrost/database"
-	"github.com/stellar/go/services/bifrost/ethereum"
+	"github.com/stellar/go/services/bifrost/rail"
 	"github.com/stellar/go/support/app"
 	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/http"
@@ -30,52 +29,41 @@ func (s *Server) Start() error {
 	s.log.Info("Server starting")
 
 	// Register callbacks
-	s.BitcoinListener.TransactionHandler = s.onNewBitcoinTransaction
-	s.EthereumListener.TransactionHandler = s.onNewEthereumTransaction
 	s.StellarAccountConfigurator.OnAccountCreated = s.onStellarAccountCreated
 	s.StellarAccountConfigurator.OnExchanged = s.onExchanged
 	s.StellarAccountConfigurator.OnExchangedTimelocked = s.OnExchangedTimelocked
 
-	if !s.BitcoinListener.Enabled && !s.EthereumListener.Enabled {
-		return errors.New("At least one listener (BitcoinListener or EthereumListener) must be enabled")
+	if len(s.Rails) == 0 {
+		return errors.New("At least one rail (ex. bitcoin or ethereum) must be configured")
 	}
 
-	if s.BitcoinListener.Enabled {
-		var err error
-		s.minimumValueSat, err = bitcoin.BtcToSat(s.MinimumValueBtc)
-		if err != nil {
-			return errors.Wrap(err, "Invalid minimum accepted Bitcoin transaction value: "+s.MinimumValueBtc)
-		}
+	s.minimumAmounts = make(map[database.Chain]xdr.Int64)
 
-		if s.minimumValueSat == 0 {
-			return errors.New("Minimum accepted Bitcoin transaction value must be larger than 0")
+	for _, r := range s.Rails {
+		chain := r.Chain()
+		if _, exists := s.minimumAmounts[chain]; exists {
+			return errors.Errorf("Duplicate %s rail", chain)
 		}
 
-		err = s.BitcoinListener.Start()
+		minimumAmount, err := amount.Parse(r.MinimumAmount())
 		if err != nil {
-			return errors.Wrap(err, "Error starting BitcoinListener")
+			return errors.Wrapf(err, "Invalid minimum accepted %s transaction value: %s", chain, r.MinimumAmount())
 		}
-	} else {
-		s.log.Warn("BitcoinListener disabled")
-	}
 
-	if s.EthereumListener.Enabled {
-		var err error
-		s.minimumValueWei, err = ethereum.EthToWei(s.MinimumValueEth)
-		if err != nil {
-			return errors.Wrap(err, "Invalid minimum accepted Ethereum transaction value")
+		if minimumAmount == 0 {
+			return errors.Errorf("Minimum accepted %s transaction value must be larger than 0", chain)
 		}
 
-		if s.minimumValueWei.Cmp(new(big.Int)) == 0 {
-			return errors.New("Minimum accepted Ethereum transaction value must be larger than 0")
-		}
+		s.minimumAmounts[chain] = minimumAmount
 
-		err = s.EthereumListener.Start(s.Config.Ethereum.RpcServer)
+		// Copy rail for the closure
+		r := r
+		err = r.Start(func(transaction rail.Transaction) error {
+			return s.onNewTransaction(r, transaction)
+		})
 		if err != nil {
-			return errors.Wrap(err, "Error starting EthereumListener")
+			return errors.Wrapf(err, "Error starting %s rail", chain)
 		}
-	} else {
-		s.log.Warn("EthereumListener disabled")
 	}
 
 	err := s.StellarAccountConfigurator.Start()
@@ -94,6 +82,10 @@ func (s *Server) Start() error {
 	go s.poolTransactionsQueue()
 	go s.startHTTPServer()
 
+	if s.Config.AdminPort != 0 {
+		go s.startAdminHTTPServer()
+	}
+
 	<-signalInterrupt
 	s.shutdown()
 
@@ -117,8 +109,9 @@ func (s *Server) startHTTPServer() {
 	mux := http.NewMux(s.Config.UsingProxy)
 
 	mux.Get("/events", s.HandlerEvents)
-	mux.Post("/generate-bitcoin-address", s.HandlerGenerateBitcoinAddress)
-	mux.Post("/generate-ethereum-address", s.HandlerGenerateEthereumAddress)
+	for _, r := range s.Rails {
+		mux.Post("/generate-"+string(r.Chain())+"-address", s.HandlerGenerateAddress(r))
+	}
 	mux.Post("/recovery-transaction", s.HandlerRecoveryTransaction)
 
 	addr := fmt.Sprintf("0.0.0.0:%d", s.Config.Port)
@@ -138,46 +131,41 @@ func (s *Server) HandlerEvents(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 	// This is required to restart a stream after server restart or failure.
 	address := r.URL.Query().Get("stream")
 	if !s.SSEServer.StreamExists(address) {
-		var chain database.Chain
-
 		if len(address) == 0 {
 			w.WriteHeader(stdhttp.StatusBadRequest)
 			return
 		}
 
-		if address[0] == '0' {
-			chain = database.ChainEthereum
-		} else {
-			// 1 or m, n in testnet
-			chain = database.ChainBitcoin
-		}
-
-		association, err := s.Database.GetAssociationByChainAddress(chain, address)
-		if err != nil {
-			log.WithField("err", err).Error("Error getting address association")
-			w.WriteHeader(stdhttp.StatusInternalServerError)
-			return
-		}
-
-		if association != nil {
-			s.SSEServer.CreateStream(address)
+		for _, rail := range s.Rails {
+			association, err := s.Database.GetAssociationByChainAddress(rail.Chain(), address)
+			if err != nil {
+				log.WithField("err", err).Error("Error getting address association")
+				w.WriteHeader(stdhttp.StatusInternalServerError)
+				return
+			}
+
+			if association != nil {
+				s.SSEServer.CreateStream(address)
+				break
+			}
 		}
 	}
 
 	s.SSEServer.HTTPHandler(w, r)
 }
 
-func (s *Server) HandlerGenerateBitcoinAddress(w stdhttp.ResponseWriter, r *stdhttp.Request) {
-	s.handlerGenerateAddress(w, r, database.ChainBitcoin)
-}
-
-func (s *Server) HandlerGenerateEthereumAddress(w stdhttp.ResponseWriter, r *stdhttp.Request) {
-	s.handlerGenerateAddress(w, r, database.ChainEthereum)
+// HandlerGenerateAddress returns the handler of `/generate-{chain}-address` endpoint of rail `r`.
+func (s *Server) HandlerGenerateAddress(r rail.Rail) stdhttp.HandlerFunc {
+	return func(w stdhttp.ResponseWriter, req *stdhttp.Request) {
+		s.handlerGenerateAddress(w, req, r)
+	}
 }
 
-func (s *Server) handlerGenerateAddress(w stdhttp.ResponseWriter, r *stdhttp.Request, chain database.Chain) {
+func (s *Server) handlerGenerateAddress(w stdhttp.ResponseWriter, r *stdhttp.Request, rail rail.Rail) {
 	w.Header().Set("Access-Control-Allow-Origin", s.Config.AccessControlAllowOriginHeader)
 
+	chain := rail.Chain()
+
 	stellarPublicKey := r.PostFormValue("stellar_public_key")
 	_, err := keypair.Parse(stellarPublicKey)
 	if err != nil || (err == nil && stellarPublicKey[0] != 'G') {
@@ -193,19 +181,7 @@ func (s *Server) handlerGenerateAddress(w stdhttp.ResponseWriter, r *stdhttp.Req
 		return
 	}
 
-	var address string
-
-	switch chain {
-	case database.ChainBitcoin:
-		address, err = s.BitcoinAddressGenerator.Generate(index)
-	case database.ChainEthereum:
-		address, err = s.EthereumAddressGenerator.Generate(index)
-	default:
-		log.WithField("chain", chain).Error("Invalid chain")
-		w.WriteHeader(stdhttp.StatusInternalServerError)
-		return
-	}
-
+	address, err := rail.GenerateAddress(index)
 	if err != nil {
 		log.WithFields(log.F{"err": err, "index": index}).Error("Error generating address")
 		w.WriteHeader(stdhttp.StatusInternalServerError)
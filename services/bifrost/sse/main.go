@@ -16,6 +16,9 @@ const (
 	AccountCreatedAddressEvent      AddressEvent = "account_created"
 	ExchangedEvent                  AddressEvent = "exchanged"
 	ExchangedTimelockedEvent        AddressEvent = "exchanged_timelocked"
+	ConversionFailedEvent           AddressEvent = "conversion_failed"
+	ConversionRetriedEvent          AddressEvent = "conversion_retried"
+	RefundedEvent                   AddressEvent = "refunded"
 )
 
 type Server struct {
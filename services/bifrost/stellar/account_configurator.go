@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/services/bifrost/common"
@@ -56,34 +57,92 @@ func (ac *AccountConfigurator) Start() error {
 		return err
 	}
 
-	ac.accountStatus = make(map[string]Status)
-
-	go ac.logStats()
 	return nil
 }
 
-func (ac *AccountConfigurator) logStats() {
-	for {
-		ac.log.WithField("statuses", ac.accountStatus).Info("Stats")
-		time.Sleep(15 * time.Second)
-	}
-}
-
 // ConfigureAccount configures a new account that participated in ICO.
 // * First it creates a new account.
 // * Once a signer is replaced on the account, it creates trust lines and exchanges assets.
-func (ac *AccountConfigurator) ConfigureAccount(destination, assetCode, amount string) {
+// It returns error if the account could not be configured. `onStatusChanged` (optional)
+// is called every time the status of account processing changes.
+//
+// `step` is the last status reached by a previous attempt of configuring the account for
+// the same transaction, empty on the first attempt. Sending tokens cannot be repeated: if
+// tokens have been sent (StatusRemovingSigner) only the temporary signer is removed, and if
+// the previous attempt stopped while sending tokens (StatusConfiguringAccount) they are not
+// sent again when the account already holds them.
+func (ac *AccountConfigurator) ConfigureAccount(destination, assetCode, amount string, step Status, onStatusChanged StatusHandler) error {
 	localLog := ac.log.WithFields(log.F{
 		"destination": destination,
 		"assetCode":   assetCode,
 		"amount":      amount,
+		"step":        step,
 	})
 	localLog.Info("Configuring Stellar account")
 
-	ac.setAccountStatus(destination, StatusCreatingAccount)
-	defer func() {
-		ac.removeAccountStatus(destination)
-	}()
+	setStatus := func(status Status) error {
+		if onStatusChanged == nil {
+			return nil
+		}
+
+		err := onStatusChanged(status)
+		if err != nil {
+			localLog.WithFields(log.F{"err": err, "status": status}).Error("Stopping account processing")
+			return errors.Wrap(err, "Error changing status")
+		}
+		return nil
+	}
+
+	if step != StatusRemovingSigner {
+		err := ac.sendTokens(localLog, destination, assetCode, amount, step, setStatus)
+		if err != nil {
+			return err
+		}
+	} else {
+		localLog.Info("Tokens already sent")
+	}
+
+	err := setStatus(StatusRemovingSigner)
+	if err != nil {
+		return err
+	}
+
+	if ac.LockUnixTimestamp == 0 {
+		localLog.Info("Removing temporary signer")
+		err = ac.removeTemporarySigner(destination)
+		if err != nil {
+			localLog.WithField("err", err).Error("Error removing temporary signer")
+			return errors.Wrap(err, "Error removing temporary signer")
+		}
+
+		if ac.OnExchanged != nil {
+			ac.OnExchanged(destination)
+		}
+	} else {
+		localLog.Info("Creating unlock transaction to remove temporary signer")
+		transaction, err := ac.buildUnlockAccountTransaction(destination)
+		if err != nil {
+			localLog.WithField("err", err).Error("Error creating unlock transaction")
+			return errors.Wrap(err, "Error creating unlock transaction")
+		}
+
+		if ac.OnExchangedTimelocked != nil {
+			ac.OnExchangedTimelocked(destination, transaction)
+		}
+	}
+
+	localLog.Info("Account successully configured")
+	return nil
+}
+
+// sendTokens creates the account, waits for the temporary signer and sends tokens to the
+// account, unless a previous attempt stopped in StatusConfiguringAccount (`step`) after
+// they were sent.
+func (ac *AccountConfigurator) sendTokens(localLog *log.Entry, destination, assetCode, amount string, step Status, setStatus StatusHandler) error {
+	err := setStatus(StatusCreatingAccount)
+	if err != nil {
+		return err
+	}
 
 	// Check if account exists. If it is, skip creating it.
 	for {
@@ -113,11 +172,21 @@ func (ac *AccountConfigurator) ConfigureAccount(destination, assetCode, amount s
 		ac.OnAccountCreated(destination)
 	}
 
-	ac.setAccountStatus(destination, StatusWaitingForSigner)
+	err = setStatus(StatusWaitingForSigner)
+	if err != nil {
+		return err
+	}
 
 	// Wait for signer changes...
+	var account horizon.Account
+	waitingSince := time.Now()
 	for {
-		account, err := ac.Horizon.LoadAccount(destination)
+		if ac.SignerTimeout > 0 && time.Since(waitingSince) > ac.SignerTimeout {
+			localLog.Warn("Timeout waiting for signer")
+			return ErrSignerTimeout
+		}
+
+		account, err = ac.Horizon.LoadAccount(destination)
 		if err != nil {
 			localLog.WithField("err", err).Error("Error loading account to check trustline")
 			time.Sleep(2 * time.Second)
@@ -133,56 +202,28 @@ func (ac *AccountConfigurator) ConfigureAccount(destination, assetCode, amount s
 
 	localLog.Info("Signer found")
 
-	ac.setAccountStatus(destination, StatusConfiguringAccount)
+	err = setStatus(StatusConfiguringAccount)
+	if err != nil {
+		return err
+	}
+
+	// The transaction sending tokens of the previous attempt may have been applied even
+	// if submitting it failed.
+	if step == StatusConfiguringAccount && ac.tokensReceived(account, assetCode) {
+		localLog.Info("Tokens already received by the account")
+		return nil
+	}
 
 	// When signer was created we can configure account in Bifrost without requiring
 	// the user to share the account's secret key.
 	localLog.Info("Sending token")
-	err := ac.configureAccountTransaction(destination, assetCode, amount, ac.NeedsAuthorize)
+	err = ac.configureAccountTransaction(destination, assetCode, amount, ac.NeedsAuthorize)
 	if err != nil {
 		localLog.WithField("err", err).Error("Error configuring an account")
-		return
+		return errors.Wrap(err, "Error configuring an account")
 	}
 
-	ac.setAccountStatus(destination, StatusRemovingSigner)
-
-	if ac.LockUnixTimestamp == 0 {
-		localLog.Info("Removing temporary signer")
-		err = ac.removeTemporarySigner(destination)
-		if err != nil {
-			localLog.WithField("err", err).Error("Error removing temporary signer")
-			return
-		}
-
-		if ac.OnExchanged != nil {
-			ac.OnExchanged(destination)
-		}
-	} else {
-		localLog.Info("Creating unlock transaction to remove temporary signer")
-		transaction, err := ac.buildUnlockAccountTransaction(destination)
-		if err != nil {
-			localLog.WithField("err", err).Error("Error creating unlock transaction")
-			return
-		}
-
-		if ac.OnExchangedTimelocked != nil {
-			ac.OnExchangedTimelocked(destination, transaction)
-		}
-	}
-
-	localLog.Info("Account successully configured")
-}
-
-func (ac *AccountConfigurator) setAccountStatus(account string, status Status) {
-	ac.accountStatusMutex.Lock()
-	defer ac.accountStatusMutex.Unlock()
-	ac.accountStatus[account] = status
-}
-
-func (ac *AccountConfigurator) removeAccountStatus(account string) {
-	ac.accountStatusMutex.Lock()
-	defer ac.accountStatusMutex.Unlock()
-	delete(ac.accountStatus, account)
+	return nil
 }
 
 func (ac *AccountConfigurator) getAccount(account string) (horizon.Account, bool, error) {
@@ -218,3 +259,25 @@ func (ac *AccountConfigurator) signerExistsOnly(account horizon.Account) bool {
 
 	return tempSignerFound
 }
+
+// tokensReceived returns true if account holds the intermediate asset (`assetCode`) or
+// the token. Trust lines of these assets are only created by the transaction sending
+// tokens, while the account can only be changed by the temporary signer.
+func (ac *AccountConfigurator) tokensReceived(account horizon.Account, assetCode string) bool {
+	for _, balance := range account.Balances {
+		if balance.Issuer != ac.IssuerPublicKey {
+			continue
+		}
+
+		if balance.Code != assetCode && balance.Code != ac.TokenAssetCode {
+			continue
+		}
+
+		value, err := amount.Parse(balance.Balance)
+		if err == nil && value > 0 {
+			return true
+		}
+	}
+
+	return false
+}
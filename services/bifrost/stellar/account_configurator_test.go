@@ -0,0 +1,136 @@
+package stellar
+
+import (
+	"testing"
+
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/services/bifrost/common"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type configureAccountTest struct {
+	ac          *AccountConfigurator
+	horizon     *horizon.MockClient
+	destination string
+	statuses    []Status
+	statusErr   error
+}
+
+func newConfigureAccountTest(t *testing.T) *configureAccountTest {
+	issuer, err := keypair.Random()
+	require.NoError(t, err)
+	signer, err := keypair.Random()
+	require.NoError(t, err)
+	destination, err := keypair.Random()
+	require.NoError(t, err)
+
+	test := &configureAccountTest{
+		horizon:     &horizon.MockClient{},
+		destination: destination.Address(),
+	}
+	test.ac = &AccountConfigurator{
+		Horizon:               test.horizon,
+		NetworkPassphrase:     network.TestNetworkPassphrase,
+		IssuerPublicKey:       issuer.Address(),
+		DistributionPublicKey: issuer.Address(),
+		SignerSecretKey:       signer.Seed(),
+		TokenAssetCode:        "TOKE",
+		TokenPrices:           map[string]string{"BTC": "0.001"},
+		signerPublicKey:       signer.Address(),
+		log:                   common.CreateLogger("StellarAccountConfigurator"),
+	}
+
+	test.horizon.On("SequenceForAccount", test.destination).Return(xdr.SequenceNumber(100), nil)
+	return test
+}
+
+func (test *configureAccountTest) configureAccount(step Status) error {
+	return test.ac.ConfigureAccount(test.destination, "BTC", "1", step, func(status Status) error {
+		test.statuses = append(test.statuses, status)
+		return test.statusErr
+	})
+}
+
+// account returns the account of the destination with the temporary signer only,
+// holding `balances`.
+func (test *configureAccountTest) account(balances ...horizon.Balance) horizon.Account {
+	return horizon.Account{
+		Signers: []horizon.Signer{
+			{Key: test.destination, Weight: 0},
+			{Key: test.ac.signerPublicKey, Weight: 1},
+		},
+		Balances: balances,
+	}
+}
+
+func TestConfigureAccountTokensSent(t *testing.T) {
+	test := newConfigureAccountTest(t)
+
+	// only the temporary signer is removed
+	test.horizon.On("SubmitTransaction", mock.AnythingOfType("string")).
+		Return(horizon.TransactionSuccess{}, nil).Once()
+
+	err := test.configureAccount(StatusRemovingSigner)
+	require.NoError(t, err)
+	assert.Equal(t, []Status{StatusRemovingSigner}, test.statuses)
+
+	test.horizon.AssertNotCalled(t, "LoadAccount", test.destination)
+	test.horizon.AssertExpectations(t)
+}
+
+func TestConfigureAccountTokensReceived(t *testing.T) {
+	test := newConfigureAccountTest(t)
+
+	// the payment of the previous attempt was applied
+	balance := horizon.Balance{
+		Balance: "1.0000000",
+		Asset:   base.Asset{Type: "credit_alphanum4", Code: "BTC", Issuer: test.ac.IssuerPublicKey},
+	}
+	test.horizon.On("LoadAccount", test.destination).Return(test.account(balance), nil)
+	test.horizon.On("SubmitTransaction", mock.AnythingOfType("string")).
+		Return(horizon.TransactionSuccess{}, nil).Once()
+
+	err := test.configureAccount(StatusConfiguringAccount)
+	require.NoError(t, err)
+	assert.Equal(t, []Status{
+		StatusCreatingAccount,
+		StatusWaitingForSigner,
+		StatusConfiguringAccount,
+		StatusRemovingSigner,
+	}, test.statuses)
+
+	test.horizon.AssertExpectations(t)
+}
+
+func TestConfigureAccountTokensNotReceived(t *testing.T) {
+	test := newConfigureAccountTest(t)
+
+	// tokens are sent, then the temporary signer is removed
+	test.horizon.On("LoadAccount", test.destination).Return(test.account(), nil)
+	test.horizon.On("SubmitTransaction", mock.AnythingOfType("string")).
+		Return(horizon.TransactionSuccess{}, nil).Twice()
+
+	err := test.configureAccount(StatusConfiguringAccount)
+	require.NoError(t, err)
+
+	test.horizon.AssertExpectations(t)
+}
+
+func TestConfigureAccountStatusError(t *testing.T) {
+	test := newConfigureAccountTest(t)
+
+	// processing stops when the status cannot be changed
+	test.statusErr = errors.New("Transaction lease expired")
+	err := test.configureAccount(StatusRemovingSigner)
+	assert.Error(t, err)
+	assert.Equal(t, []Status{StatusRemovingSigner}, test.statuses)
+
+	test.horizon.AssertNotCalled(t, "SubmitTransaction", mock.Anything)
+}
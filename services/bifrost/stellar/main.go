@@ -2,8 +2,10 @@ package stellar
 
 import (
 	"sync"
+	"time"
 
 	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/log"
 )
 
@@ -17,6 +19,15 @@ const (
 	StatusRemovingSigner     Status = "removing_signer"
 )
 
+// StatusHandler is called when the status of account processing changes. Account
+// processing stops if it returns an error, ex. when the transaction is processed by
+// another instance.
+type StatusHandler func(status Status) error
+
+// ErrSignerTimeout is returned by ConfigureAccount when the temporary signer has not
+// been added to the account within SignerTimeout.
+var ErrSignerTimeout = errors.New("Timeout waiting for signer")
+
 // AccountConfigurator is responsible for configuring new Stellar accounts that
 // participate in ICO.
 type AccountConfigurator struct {
@@ -28,9 +39,13 @@ type AccountConfigurator struct {
 	LockUnixTimestamp     uint64
 	NeedsAuthorize        bool
 	TokenAssetCode        string
-	TokenPriceBTC         string
-	TokenPriceETH         string
-	StartingBalance       string
+	// TokenPrices maps intermediate asset codes (ex. BTC) to the price of one token in
+	// this asset.
+	TokenPrices     map[string]string
+	StartingBalance string
+	// SignerTimeout is the maximum time ConfigureAccount waits for the temporary signer
+	// to be added to the account. Zero means no timeout.
+	SignerTimeout         time.Duration
 	OnAccountCreated      func(destination string)
 	OnExchanged           func(destination string)
 	OnExchangedTimelocked func(destination, transaction string)
@@ -38,7 +53,5 @@ type AccountConfigurator struct {
 	signerPublicKey     string
 	signerSequence      uint64
 	signerSequenceMutex sync.Mutex
-	accountStatus       map[string]Status
-	accountStatusMutex  sync.Mutex
 	log                 *log.Entry
 }
@@ -58,13 +58,8 @@ func (ac *AccountConfigurator) configureAccountTransaction(destination, intermed
 		)
 	}
 
-	var tokenPrice string
-	switch intermediateAssetCode {
-	case "BTC":
-		tokenPrice = ac.TokenPriceBTC
-	case "ETH":
-		tokenPrice = ac.TokenPriceETH
-	default:
+	tokenPrice, ok := ac.TokenPrices[intermediateAssetCode]
+	if !ok {
 		return errors.Errorf("Invalid intermediateAssetCode: $%s", intermediateAssetCode)
 	}
 
@@ -22,6 +22,9 @@ type Config struct {
 	Database          *Database `valid:"optional"`
 	Accounts          Accounts  `valid:"optional" toml:"accounts"`
 	Callbacks         Callbacks `valid:"optional" toml:"callbacks"`
+	// ReceivingAccounts are the accounts whose incoming payments are sent to a receive callback,
+	// in addition to accounts.receiving_account_id
+	ReceivingAccounts []ReceivingAccount `valid:"optional" toml:"receiving_accounts"`
 }
 
 // Asset represents credit asset
@@ -38,12 +41,80 @@ type Accounts struct {
 	ReceivingAccountID string `valid:"optional" toml:"receiving_account_id"`
 }
 
+// ReceivingAccount contains values of a `receiving_accounts` config group. Assets, Callback and
+// MACKey default to the values of the `assets`, `callbacks.receive` and `mac_key` params.
+type ReceivingAccount struct {
+	AccountID string  `valid:"required" toml:"account_id"`
+	Assets    []Asset `valid:"optional" toml:"assets"`
+	Callback  string  `valid:"optional" toml:"callback"`
+	MACKey    string  `valid:"optional" toml:"mac_key"`
+}
+
+// GetReceivingAccounts returns every receiving account, accounts.receiving_account_id first,
+// with their default values set.
+func (c *Config) GetReceivingAccounts() []ReceivingAccount {
+	accounts := []ReceivingAccount{}
+	if c.Accounts.ReceivingAccountID != "" {
+		accounts = append(accounts, ReceivingAccount{AccountID: c.Accounts.ReceivingAccountID})
+	}
+	accounts = append(accounts, c.ReceivingAccounts...)
+
+	for i := range accounts {
+		if len(accounts[i].Assets) == 0 {
+			accounts[i].Assets = c.Assets
+		}
+		if accounts[i].Callback == "" {
+			accounts[i].Callback = c.Callbacks.Receive
+		}
+		if accounts[i].MACKey == "" {
+			accounts[i].MACKey = c.MACKey
+		}
+	}
+	return accounts
+}
+
+// GetListenedAccounts returns the receiving accounts that have a receive callback, with their
+// default values set. Payments to the other receiving accounts are not listened for.
+func (c *Config) GetListenedAccounts() []ReceivingAccount {
+	accounts := []ReceivingAccount{}
+	for _, account := range c.GetReceivingAccounts() {
+		if account.Callback != "" {
+			accounts = append(accounts, account)
+		}
+	}
+	return accounts
+}
+
+// GetReceivingAccount returns the receiving account with the given ID, with its default values
+// set, or nil if there is no such account.
+func (c *Config) GetReceivingAccount(accountID string) *ReceivingAccount {
+	for _, account := range c.GetReceivingAccounts() {
+		if account.AccountID == accountID {
+			return &account
+		}
+	}
+	return nil
+}
+
 // Callbacks contains values of `callbacks` config group
 type Callbacks struct {
 	Receive string `valid:"optional"`
 	Error   string `valid:"optional"`
+	// MaxAttempts is the number of times the receive callback is called for a payment before the
+	// delivery is marked as failed
+	MaxAttempts int `valid:"optional" toml:"max_attempts"`
+	// InitialBackoff is the number of seconds before the first retry of the receive callback,
+	// doubled after every failed attempt
+	InitialBackoff int `valid:"optional" toml:"initial_backoff"`
 }
 
+const (
+	// DefaultCallbackMaxAttempts is the default value of callbacks.max_attempts
+	DefaultCallbackMaxAttempts = 10
+	// DefaultCallbackInitialBackoff is the default value of callbacks.initial_backoff
+	DefaultCallbackInitialBackoff = 5
+)
+
 // Database contains values of `database` config group
 type Database struct {
 	Type string `valid:"required"`
@@ -73,31 +144,9 @@ func (c *Config) Validate() (err error) {
 		return
 	}
 
-	for _, asset := range c.Assets {
-		if asset.Issuer == "" {
-			if asset.Code != "XLM" {
-				err = errors.New("Issuer param is required for " + asset.Code)
-				return
-			}
-		}
-
-		if asset.Issuer != "" {
-			_, err = keypair.Parse(asset.Issuer)
-			if err != nil {
-				err = errors.New("Issuing account is invalid for " + asset.Code)
-				return
-			}
-		}
-
-		var matched bool
-		matched, err = regexp.MatchString("^[a-zA-Z0-9]{1,12}$", asset.Code)
-		if err != nil {
-			return err
-		}
-
-		if !matched {
-			return errors.New("Invalid asset code: " + asset.Code)
-		}
+	err = validateAssets(c.Assets)
+	if err != nil {
+		return
 	}
 
 	var dbURL *url.URL
@@ -156,6 +205,39 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	seen := map[string]bool{}
+	for _, account := range c.ReceivingAccounts {
+		_, err = keypair.Parse(account.AccountID)
+		if err != nil {
+			err = errors.New("receiving_accounts.account_id is invalid: " + account.AccountID)
+			return
+		}
+
+		if seen[account.AccountID] || account.AccountID == c.Accounts.ReceivingAccountID {
+			err = errors.New("Duplicate receiving account: " + account.AccountID)
+			return
+		}
+		seen[account.AccountID] = true
+
+		err = validateAssets(account.Assets)
+		if err != nil {
+			return
+		}
+
+		if account.Callback == "" && c.Callbacks.Receive == "" {
+			err = errors.New("receiving_accounts.callback param is required for " + account.AccountID)
+			return
+		}
+
+		if account.Callback != "" {
+			_, err = url.Parse(account.Callback)
+			if err != nil {
+				err = errors.New("Cannot parse receiving_accounts.callback param for " + account.AccountID)
+				return
+			}
+		}
+	}
+
 	if c.Callbacks.Receive != "" {
 		_, err = url.Parse(c.Callbacks.Receive)
 		if err != nil {
@@ -172,5 +254,52 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	switch {
+	case c.Callbacks.MaxAttempts < 0:
+		err = errors.New("callbacks.max_attempts must be positive")
+		return
+	case c.Callbacks.MaxAttempts == 0:
+		c.Callbacks.MaxAttempts = DefaultCallbackMaxAttempts
+	}
+
+	switch {
+	case c.Callbacks.InitialBackoff < 0:
+		err = errors.New("callbacks.initial_backoff must be positive")
+		return
+	case c.Callbacks.InitialBackoff == 0:
+		c.Callbacks.InitialBackoff = DefaultCallbackInitialBackoff
+	}
+
+	return
+}
+
+func validateAssets(assets []Asset) (err error) {
+	for _, asset := range assets {
+		if asset.Issuer == "" {
+			if asset.Code != "XLM" {
+				err = errors.New("Issuer param is required for " + asset.Code)
+				return
+			}
+		}
+
+		if asset.Issuer != "" {
+			_, err = keypair.Parse(asset.Issuer)
+			if err != nil {
+				err = errors.New("Issuing account is invalid for " + asset.Code)
+				return
+			}
+		}
+
+		var matched bool
+		matched, err = regexp.MatchString("^[a-zA-Z0-9]{1,12}$", asset.Code)
+		if err != nil {
+			return err
+		}
+
+		if !matched {
+			return errors.New("Invalid asset code: " + asset.Code)
+		}
+	}
+
 	return
 }
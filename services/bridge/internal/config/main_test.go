@@ -0,0 +1,29 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetListenedAccounts(t *testing.T) {
+	c := Config{
+		Accounts: Accounts{ReceivingAccountID: "GAJ2HGPVZHCH6Q3HXQJMBZNIJFAHUZUGAEUQ5S7JPKDJGPVYOX54RBML"},
+		ReceivingAccounts: []ReceivingAccount{
+			{AccountID: "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z", Callback: "http://example.com/receive"},
+		},
+	}
+
+	// accounts.receiving_account_id has no callback without callbacks.receive
+	accounts := c.GetListenedAccounts()
+	if assert.Len(t, accounts, 1) {
+		assert.Equal(t, "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z", accounts[0].AccountID)
+	}
+
+	c.Callbacks.Receive = "http://example.com/default"
+	accounts = c.GetListenedAccounts()
+	if assert.Len(t, accounts, 2) {
+		assert.Equal(t, "http://example.com/default", accounts[0].Callback)
+		assert.Equal(t, "http://example.com/receive", accounts[1].Callback)
+	}
+}
@@ -5,6 +5,9 @@
 // migrations/02_payment_id.sql
 // migrations/03_transaction_id.sql
 // migrations/04_table_names.sql
+// migrations/05_callback_delivery.sql
+// migrations/06_receiving_accounts.sql
+// migrations/07_result_xdr_text.sql
 // DO NOT EDIT!
 
 package db
@@ -72,7 +75,7 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
-var _latestSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xb4\x58\xdb\x6e\xe2\x48\x13\xbe\xfe\x79\x8a\xba\x23\xd1\x6f\x18\xc8\x42\x26\x03\xca\x85\x07\x1c\x0d\x5a\x30\x19\x30\x3b\x13\x69\x25\xab\xb1\x0b\xa7\x15\xbb\xed\x74\xb7\x73\xd8\xa7\x5f\xb5\x0d\xc1\x27\x0e\x49\xd8\x4b\x77\x57\xd7\xe9\xab\xfa\xaa\xa0\xd1\xa8\x35\x1a\x70\x1b\x0a\xe9\x71\x9c\xff\x1c\x83\x4b\x24\x59\x12\x81\xe0\xc6\x41\x54\x6b\x34\x6a\xea\x7e\x18\x07\x11\xba\xb0\xe2\x61\xb0\x15\x78\x42\x2e\x68\xc8\xe0\x5b\xf3\xb2\xd9\xce\x48\x2d\x5f\x21\xf2\x6c\xf5\xbc\x20\x52\x9b\x1b\x16\x08\x49\x24\x06\xc8\xa4\x2d\x69\x80\x61\x2c\xe1\x1a\x5a\xfd\xe4\xca\x0f\x9d\x87\xf2\x29\x75\x7d\xb4\x29\xb3\x25\x27\x4c\x10\x47\xd2\x90\xd9\x02\x85\xd2\x5b\x16\x76\x7c\xaa\x54\x23\x73\x42\x97\x32\x0f\xae\xa1\xbe\xb0\x6e\xae\xea\xfd\x8d\x6d\xe6\x12\xee\xda\x4e\xc8\x56\x21\x0f\x28\xf3\x6c\x21\x39\x65\x9e\x80\x6b\x08\xd9\x5a\xc7\x3d\x3a\x0f\xf6\x2a\x66\xa9\xad\x65\xe8\x52\x54\xf7\x2b\xe2\x0b\xcc\x99\x09\x28\xb3\x03\x14\x82\x78\x89\xc0\x33\xe1\x8c\x32\x2f\x15\xe1\xe1\xb3\x2d\xd0\x89\x39\x95\xaf\x4a\xf9\x6a\xd5\x57\xa9\x54\x79\x32\x49\x80\x3d\x88\xfc\xc8\x13\x8f\x7e\x1f\xac\xd7\x08\x7b\x60\xfc\xb6\x0c\x73\x3e\x9a\x9a\x7d\x98\x3b\xf7\x18\x90\x1e\x34\xfa\x30\x7d\x66\xc8\x7b\x90\xe0\x30\x98\x19\xba\x65\x6c\x05\x61\x74\x03\xe6\xd4\x02\xe3\xf7\x68\x6e\xcd\x37\xfa\xe0\xd7\xc8\xfa\x01\xf3\xc1\x0f\x63\xa2\x2b\x1c\x1c\x22\x89\x1f\x7a\xfd\x5a\xde\xfa\x56\x4b\xc1\x8f\xc1\x74\x32\x31\x4c\x6b\xb7\x17\xe9\x3d\x4c\xcd\xb2\x0e\x18\xcd\xa1\x7e\x3b\xfe\x12\x79\xaa\x92\x22\x1e\x3a\xe8\xc6\x9c\xf8\xe0\x13\xe6\xc5\xc4\xc3\xba\x72\x23\x41\x02\x09\x77\xee\xed\x88\xc8\x7b\xb8\x86\x28\x5e\xfa\xd4\xd1\xf2\xee\x2a\x31\x17\x57\x24\xf6\xa5\x2d\xc9\xd2\x47\x11\x11\x07\x15\xa2\xf5\xc2\xed\x33\x95\xf7\x76\x48\xdd\x0c\x48\xb9\x58\xbd\x90\x47\x76\x40\x3d\x4e\x14\xa0\x62\x13\xa9\xa5\x7f\x1f\x1b\xdb\x38\x53\x27\xde\x82\x5d\x12\x2e\xf1\x21\x9b\xf8\x44\xbe\xa8\x0c\xce\x6a\x00\x00\xd4\x05\x89\x2f\x32\xc1\xc3\x5c\x8c\xc7\x5a\x72\x4a\xa2\xc8\xa7\xe8\xda\x44\x82\xaa\x54\x21\x49\x10\x81\xf2\x36\xf9\x84\x7f\x42\x86\xb5\x73\x95\x12\x7d\x6c\x19\xb3\x1d\x06\xa6\xbf\x4c\x75\x37\x5d\x7b\x54\x88\x8d\xa3\x83\xf4\x09\x5d\x3b\x22\xaf\xaa\xab\x3e\x17\x5c\x51\xdb\x36\xba\x25\xf5\x28\x2b\xc6\x17\x46\x98\x7a\x69\x53\x17\x9c\x7b\xc2\x89\x23\x91\xc3\x13\xe1\xaf\x94\x79\x67\x17\xdd\xee\x79\xe1\x45\x52\x13\x42\x54\xe5\x44\xf5\xf1\x5b\x5a\x8a\xcf\x88\xa7\x7a\x55\x86\x0f\xc8\x8e\x33\xa4\x68\x26\x16\xc7\xc9\x66\x99\xa5\x32\x90\xcb\xce\x39\x0c\x8d\x1b\x7d\x31\xb6\xa0\x6e\x7e\xd1\xeb\xbd\x5e\x49\xa8\x0c\x64\x29\x99\xc7\x21\xb9\x96\xb6\xa9\x6b\x0b\x7c\xdc\xe0\x39\x37\x7e\x2e\x0c\x73\xf0\x1e\x48\x37\x4f\x76\x68\x4e\x42\x9f\x5b\xfa\xcc\x4a\x29\xa3\x9d\x1c\x8c\xcc\xc1\xcc\x48\x1a\xfc\xfb\xdd\xfa\xc8\x9c\xc2\x64\x64\xfe\xa5\x8f\x17\xc6\xdb\xb7\xfe\x7b\xfb\x3d\xd0\x07\x3f\x0c\x68\xef\x0a\x3f\x6f\xf5\x24\x49\x48\x94\x0c\xe1\xfb\xdd\x31\xd9\x48\x7d\x3a\x90\x8c\x37\x8d\x25\xd4\x9a\xd4\x2d\x72\xa7\x48\xa6\xd7\xb6\x68\x3e\xd7\x73\x45\x6d\xdb\x9e\xa3\x4c\xa2\x87\xfc\x23\xd5\x7a\x64\x33\xb4\x5b\x25\xd1\x30\xe6\x0e\x56\x88\x76\x2f\x4b\xa2\xf1\x32\xa0\x52\xbe\xb7\x97\x45\xec\x38\x88\xee\xc1\x67\xa9\xb4\x8f\xae\x4a\x41\x4a\x3f\xe9\x11\xb2\x27\xf4\xc3\x08\xed\x17\x97\x57\xb1\x2e\x47\xa1\xa6\x82\xba\xdd\xd1\xfe\x9b\x5e\x56\x6f\x2a\x5a\x79\xc3\x39\x9b\x02\xf9\xa0\x9a\x32\x23\x94\xa0\xde\xdf\x0c\x4a\x3c\x8f\xf6\xa9\x18\xa1\x5a\xf3\x7f\xcd\x08\xd5\x56\x4f\x92\x84\xcf\x30\xc2\x1e\xb7\x12\x46\x28\xa2\x56\xc1\x08\x25\xaa\xa7\xee\xc6\xc3\x75\x91\x1c\xef\x57\x9a\xab\xa9\x39\x2e\x73\x11\xa4\x12\x83\xe9\x78\x31\x31\x15\x43\xa8\x2d\x68\x53\x85\x0c\x5f\xe4\x13\xf1\xcf\xea\xd5\x0c\x57\xef\xf5\x38\x7a\x8e\x4f\x84\x38\x3f\x44\x68\x27\x72\xbf\xa4\xf6\x28\xf7\xab\xe1\xa8\x76\x7f\x48\x24\x81\x55\xc8\x8f\x58\xf4\x60\xa8\x5b\xfa\x51\xbd\x32\xbd\xbd\x2b\x6f\x79\xd4\xd5\x32\xeb\xdc\x39\xdc\xcc\xa6\x13\x10\xd2\xa5\xac\x5f\x6b\xb5\x6d\xca\xa8\x6c\x8a\x47\xff\x7f\x17\xad\xf6\x55\xa3\xd5\x69\x5c\x74\xa1\x7d\xd5\xbb\xe8\xf4\x3a\x9d\x66\xb7\xfb\xf5\xdb\x55\xfb\xff\xad\x8b\x5a\xeb\xc2\xde\xa2\xb2\x5b\xfe\x6b\xfb\xb2\xd3\x4d\xe4\xff\xb0\xf3\xa9\xd8\xf3\xe6\xea\xeb\xb7\xf4\x4d\x27\x5d\x97\x6d\x46\x02\x14\xbb\x1f\x5c\xb5\x3b\x4a\xfc\xef\xe6\xae\x6c\xee\x5d\x2d\xdf\x97\xce\xf2\x5e\xa9\xf2\x99\x5d\x1f\xb5\xdc\x6a\xa8\xe5\x36\x3e\x6d\x3d\xc0\xb4\xc2\xec\xcb\xa3\x90\x09\xe4\x5d\xbb\xc4\xdc\x38\xaa\xba\xe7\xc6\xd8\x18\x58\x99\x9f\x28\x4d\x81\x7b\xfb\x4d\x83\xb6\x96\xfe\x1c\xd9\x5d\xb0\x7b\x37\x89\xf7\xa5\xb8\xbc\x46\xa8\xa4\xe6\x13\xb6\x4d\x64\x3a\xe6\xb5\xdc\x0c\xd7\x72\xa3\x59\x5b\x8f\x5e\x2d\x37\x6f\xb5\xcc\x74\xd5\x32\x23\xf2\x00\x14\x47\x92\xf8\xa7\xa1\xd8\xc1\x1d\x95\x50\x54\x52\x46\xf1\xdb\x8e\x1e\xf0\x75\xfb\xd3\xd8\x9c\x5b\x33\x7d\x64\x7e\x8c\x0e\x0b\xaa\x93\x49\xa9\x0f\x87\x19\xb5\x95\xd6\xe1\x76\x36\x9a\xe8\xb3\x3b\xf8\xd3\xb8\x53\xa0\x1e\x66\xef\x4c\x21\xc6\x8c\x3e\xc6\x78\xa2\x00\x8a\x86\xaa\x22\x28\xd9\x86\x85\x39\xfa\xb9\x30\xe0\x2c\x53\x2c\x07\xc7\x67\xb1\xa5\xb2\x4c\x61\x9f\x0e\x91\xa2\xdd\xaa\x80\x0e\xb9\xf2\x16\x5f\xf6\xe2\xfd\x11\x9e\xb0\xce\x3e\x12\xd5\xc7\x0a\xad\xd8\x6e\x27\x0c\xe2\x98\x5a\xab\x32\xbf\x27\x88\x5d\x7f\x72\x82\x13\x06\x91\x8f\x12\x13\x4f\xfe\x0d\x00\x00\xff\xff\x7e\x47\x0b\xb3\x11\x15\x00\x00")
+var _latestSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xc5\x58\x5b\x73\xa2\x48\x14\x7e\x5e\x7f\x45\xbf\x99\xd4\xa2\x23\xae\x26\x46\x6b\x1f\x18\x25\x35\xd6\x20\x66\x04\x77\x26\x55\x5b\x45\xb5\xd0\x12\x2a\x08\xa4\x1b\x92\x71\x7f\xfd\x9e\x06\x0d\x57\x15\x27\x6e\x6d\x5e\x12\xe8\xaf\xcf\xed\x3b\x37\xd2\x6a\x35\x5a\x2d\xf4\xe0\xb3\xd0\xa6\x44\xfb\xa6\x20\x0b\x87\x78\x85\x19\x41\x56\xb4\x09\xe0\xac\xc1\xcf\x27\xf0\x37\xb1\xd0\x9a\xfa\x9b\x14\xf0\x4a\x28\x73\x7c\x0f\xdd\xb5\x6f\xda\x62\x06\xb5\xda\xa2\xc0\x36\xf8\xf5\x02\xa4\xa1\xc9\x3a\x62\x21\x0e\xc9\x86\x78\xa1\x11\x3a\x1b\xe2\x47\x21\xfa\x13\x75\x46\xf1\x91\xeb\x9b\xcf\xe5\xb7\x8e\xe5\x12\xc3\xf1\x8c\x90\x62\x8f\x61\x33\x04\x79\x06\x23\x8c\xcb\x2d\x83\x4d\xd7\xe1\xa2\x89\x67\xfa\x96\xe3\xd9\x70\xd0\x5c\xea\xf7\x83\xe6\x68\xaf\xdb\xb3\x30\xb5\x0c\xd3\xf7\xd6\x3e\xdd\x00\xc2\x60\x21\x85\x5f\x0c\x90\xbe\xb7\x93\xf1\x44\xc0\x8e\x75\xe4\x25\xba\x56\x20\x89\xf0\xf3\x35\x76\x19\xc9\xa9\x01\x01\xc6\x06\x4c\xc1\x76\x0c\x78\xc3\xd4\x03\x59\x09\x84\xfa\x6f\x60\xa6\x19\x51\x27\xdc\x72\xe1\xeb\xf5\x88\x87\x92\xc7\x49\xc5\x1b\x32\x44\x81\x1b\xd8\xec\xc5\x1d\x21\x7d\x1b\xc0\xa3\xfc\x43\x97\x55\x6d\x3a\x57\x47\x48\x03\x0b\x36\x78\x88\x5a\x23\x34\x7f\xf3\x08\x1d\xa2\x98\x87\xf1\x42\x96\x74\x39\x05\xa2\xe9\x3d\x52\xe7\x3a\xbc\x98\x6a\xba\xb6\x97\x87\xbe\x4f\xf5\x2f\x48\x1b\x7f\x91\x67\x12\xe7\xc1\x04\xba\x5c\x1f\x8c\xca\x6b\x4f\xa5\x14\xec\x18\xcf\x67\x33\x59\xd5\x0f\x5b\x91\x9c\x23\xb8\x59\x92\x81\xa6\x1a\x6a\x3e\x28\x9f\x02\x9b\x67\x52\x40\x7d\x93\x58\x11\xc5\x2e\x72\xb1\x67\x47\x10\xa5\x26\x37\x23\x66\x82\x60\x6a\x3e\x19\x01\x0e\x9f\x20\x38\x41\xb4\x72\x1d\x53\xc8\x9b\xcb\x61\x16\x59\xe3\xc8\x85\x54\xc1\x2b\x97\xb0\x00\x9b\x84\x33\xda\x2c\x9c\xbe\x39\xe1\x93\xe1\x3b\x56\x86\xa4\x9c\xaf\xb6\x4f\x03\xe0\xca\xa6\x98\x13\xca\xf6\x9e\xea\xd2\x67\x45\x4e\xfd\x4c\x8c\x78\x77\x76\x85\x69\x48\x9e\xb3\x81\x8f\xf1\x45\x61\xe8\xaa\x81\xe0\xc7\xb1\x50\x48\x7e\x86\x31\x1f\xea\x52\x51\x84\xf8\x2d\x0e\x02\x48\x14\xcb\xc0\x21\xe2\x99\x0a\xe9\x07\x35\xc1\xad\x8d\x1f\xd1\x3f\xbe\x47\x1a\xd7\x3c\x24\x92\xa2\xcb\x8b\x03\x0a\xe6\xdf\x55\x7e\x36\xdf\x59\x54\xf0\x8d\x12\x93\x38\xaf\xa0\x23\xc0\x5b\x5e\x55\x1f\x73\xae\x28\x2d\xf5\x6e\xe5\xd8\x8e\x57\xf4\xcf\x0f\x48\x62\xa5\x01\x08\xf3\x09\x53\x28\x4f\x42\xd1\x2b\xa6\x5b\xa8\x83\xab\x6e\xbf\x7f\x5d\xb8\x11\xe7\x04\x63\x55\x31\xe1\x75\xfc\x1e\x96\xe2\x35\x6c\xf3\x5a\x0d\xfd\x67\xe2\xd5\x53\xc4\xdb\x4c\xc4\xea\x61\xb3\x9d\xa5\xd2\x91\x9b\xde\x35\x9a\xc8\xf7\xd2\x52\xd1\x51\x53\xfd\x24\x35\x87\xc3\x12\xa8\x4c\x64\x29\x98\xf5\x98\xdc\xa1\xc1\x12\xe8\x1f\x2f\x7b\x3e\x35\xf9\xdb\x52\x56\xc7\xe7\x50\xba\xbf\x72\x40\x72\xec\xba\xa6\x4b\x0b\x3d\x69\x19\x62\xfc\x62\xaa\xc2\xed\xb8\xc0\x3f\x3f\xee\x5e\xa9\x73\x34\x9b\xaa\x7f\x49\xca\x52\x7e\x7f\x96\x7e\xa4\xcf\x63\x09\x9a\x0d\x12\x0f\xb9\x9f\xd7\x7a\x91\x20\xc4\x42\x26\x60\x61\x9d\x68\x24\x36\x9d\x08\xc6\xbb\xc4\x12\x6b\x6d\xc7\x2a\xf6\x4e\x16\x4f\xaf\x34\x69\x3e\x56\x73\x45\x69\x69\xcd\x41\xc1\x11\x1b\x32\xec\x17\xb2\xb5\x66\x31\x88\x9d\x12\xd4\x8f\x28\x74\xd9\x32\xb4\x7f\x53\x82\x46\xab\x8d\x13\x86\xe7\xd6\x32\x8b\x4c\x93\x10\xeb\xe4\xb5\x04\xed\x12\x8b\x87\x20\x69\x3f\xc9\x2b\xe2\xbd\x12\x17\x5a\x8f\xf1\xd3\xa2\x55\x5d\x97\x12\xc6\xa7\xc2\xfe\x74\xdf\x41\xf6\x74\x1f\xea\x09\xfb\x02\xe7\x82\x6a\xd5\x77\x89\xb8\xe3\xa9\xcd\xe1\x79\xee\x2e\x55\xdf\xd5\x92\xff\xeb\xfa\xae\xd6\x7a\x91\x20\x7c\xa4\xbe\x8f\x98\x15\xd7\x77\x91\xb5\x8a\xfa\x2e\x35\x6e\x80\xec\x2c\xdc\x25\x49\x7d\xbb\x92\x58\xcd\x55\xa5\xdc\x59\x50\x82\x18\xcf\x95\xe5\x4c\xe5\xf5\xce\x77\x9a\x7d\x16\x7a\x90\xb9\xaf\xd8\xbd\x6a\x56\xf7\x2b\x98\x40\x94\xd8\xa6\x8b\x19\xbb\x3e\xd5\x9e\x2e\x64\x7e\x49\x6c\x2d\xf3\xab\xe9\xa8\x36\x7f\x02\xbb\x1f\x82\xbd\xbc\xc6\xda\x86\x26\x92\x2e\xd5\xaa\x95\xf9\xc3\x63\x79\x67\x73\x2c\x21\xb3\x9c\x5d\xa3\xfb\xc5\x7c\x06\x5d\x12\x3e\x19\x46\x8d\x8e\x08\x5f\x1b\x4e\xd8\x86\x7d\xf6\xb7\x6e\x47\x1c\xb4\x3a\xbd\x56\xb7\x8f\xc4\xc1\xb0\xdb\x1b\xf6\x7a\xed\x7e\xff\xf6\x6e\x20\xfe\xde\xe9\x36\x3a\x5d\x23\x65\xe5\x30\xfe\x56\xbc\xe9\xf5\x63\xfc\x1f\x46\x3e\x14\x47\xee\x0c\x6e\xef\x92\x3b\xbd\x64\xf9\x35\x3c\x88\x09\x3b\x7c\x61\x20\xf6\x38\xfc\xef\xf6\xa1\x68\x1e\x5d\x14\xcf\x0b\x67\x79\x4b\xe4\xf1\xcc\x2e\x83\x42\x6e\xd1\x13\x72\xfb\x9b\xb0\x1b\x47\x42\x61\x92\xe5\x59\xc8\x38\x72\xd6\x66\x00\x49\x58\xc7\x0f\x4d\x56\xe4\xb1\x9e\xf9\xe0\x68\x33\x72\xb4\xde\x04\x24\x0a\xc9\xc7\xc5\xe1\x84\x3d\xba\x17\x9c\x17\xe2\xf2\x52\xc0\x83\x9a\x0f\x58\x1a\xc8\x64\x68\x0b\xb9\x89\x2c\xe4\x06\xad\xb0\x1b\xa4\x42\x6e\x7a\x0a\x99\x59\x29\x64\x46\xe4\x09\x2a\x6a\x36\xf1\x0f\x53\x71\xa0\x77\x54\x52\x51\xd9\x32\x8a\xcf\x46\xf0\x4c\xb6\xe9\x87\xae\xaa\xe9\x0b\x69\xaa\xfe\x5a\x3b\x2c\x88\x8e\x27\xa5\x34\x99\x64\xc4\x56\x6a\x47\x0f\x8b\xe9\x4c\x5a\x3c\xa2\xaf\xf2\x23\x27\xf5\x74\xf7\xce\x24\x62\xe4\x39\x2f\x11\xb9\x90\x03\x45\x45\x55\x1e\x94\x74\xa3\xa5\x3a\x05\x7a\xd1\x55\x26\x59\x4e\x8e\xcf\x62\x49\x65\x3b\x85\x71\x39\x46\x8a\x7a\xab\x1c\x3a\x65\xca\xbb\x7f\xd9\x83\xf3\x3d\x0c\xfe\x5f\xaf\x7e\x2d\xd1\x8a\xe5\x76\x41\x27\xea\xe4\x5a\x95\xfa\x23\x4e\x1c\xfa\x97\x25\x32\xfd\x4d\xe0\x92\x90\xc4\x96\xfc\x0b\x5f\xa9\xce\xfb\xdf\x14\x00\x00")
 
 func latestSqlBytes() ([]byte, error) {
 	return bindataRead(
@@ -87,7 +90,7 @@ func latestSql() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "latest.sql", size: 5393, mode: os.FileMode(420), modTime: time.Unix(1530096833, 0)}
+	info := bindataFileInfo{name: "latest.sql", size: 5343, mode: os.FileMode(420), modTime: time.Unix(1530096833, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -172,6 +175,66 @@ func migrations04_table_namesSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations05_callback_deliverySql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x75\x52\xcb\x6e\x83\x30\x10\xbc\xfb\x2b\xf6\x08\x6a\x22\xa5\xe7\x9c\x68\x70\x24\x54\x0a\x29\x05\xa9\x39\x59\x0e\xac\xa8\x55\xf3\x90\xd9\xa4\xcd\xdf\xd7\x94\xd0\x36\xd0\x1c\xed\x99\xd9\x19\xcd\xee\x72\x09\x77\x95\x2a\x8d\x24\x84\xac\x65\x9b\x84\x7b\x29\x87\xd4\x7b\x08\x39\xe4\x52\xeb\x83\xcc\xdf\x45\x81\x5a\x9d\xd0\x9c\xc1\x61\x00\xaa\x80\x83\x2a\x3b\x34\x4a\xea\x85\x7d\x1b\xcc\xd1\xa2\x85\x68\xe5\xb9\xc2\x9a\xc4\x40\x50\x35\x41\x16\x05\xcf\x19\x87\x28\x4e\x21\xca\xc2\x10\x12\xbe\xe5\x09\x8f\x36\xfc\x65\xa6\x02\x47\x15\x6e\x3f\xee\x68\x34\x10\x7e\xd2\x8f\xaa\xff\xb4\x24\xdd\xc8\x62\x0e\x74\x24\xe9\xd8\xc1\x49\x9a\xfc\x4d\x1a\xe7\x7e\xe5\x5e\xc1\x92\x08\xab\x96\x3a\xb0\x69\xb0\x44\xf3\x1b\xc5\xe7\x5b\x2f\x0b\x53\x58\xf5\x34\x2d\x3b\x12\x68\x4c\x63\x06\x87\x11\x1c\xc7\xe4\x06\x6d\x3f\x85\x90\x04\xa4\x2a\xb4\xa6\x55\x7b\xe5\x53\x5b\x95\xb8\x98\xdd\x66\x5d\x6a\x9c\x0e\x9a\xba\xed\x92\xe0\xc9\x4b\xf6\xf0\xc8\xf7\xdf\xad\x30\x77\xcd\xc6\xc5\x04\x91\xcf\x5f\xe7\x8b\x11\x43\x0f\x62\x9a\x23\x8e\xfe\x5b\xe2\x40\x5e\x4c\x53\xf7\x36\xcb\x3f\xe7\xe0\x37\x1f\x35\xf3\x93\x78\x77\xeb\x1c\xd6\xec\x0b\x97\x5b\x35\x74\x3e\x02\x00\x00")
+
+func migrations05_callback_deliverySqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations05_callback_deliverySql,
+		"migrations/05_callback_delivery.sql",
+	)
+}
+
+func migrations05_callback_deliverySql() (*asset, error) {
+	bytes, err := migrations05_callback_deliverySqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/05_callback_delivery.sql", size: 574, mode: os.FileMode(420), modTime: time.Unix(1791590400, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations06_receiving_accountsSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x8f\xb1\x0e\x82\x30\x14\x45\xf7\x7e\xc5\xdb\x80\x28\x8b\x09\x2e\x4c\xd5\xd6\xc4\x58\x85\x34\x65\x60\x22\xb5\x34\xd8\x88\xd4\x54\xc4\xf8\xf7\xa2\x51\xd1\x89\xf5\xe5\xbc\x93\x73\xc3\x10\x26\x27\x53\x39\xd9\x6a\xc8\xce\x68\xc9\x29\x16\x14\x04\x5e\x30\x0a\x4e\x2b\x6d\x3a\xd3\x54\x85\x54\xca\x5e\x9b\xb6\x50\x57\x77\xb1\x0e\x7c\x04\xf0\x39\x99\x12\x3a\xe9\xd4\x41\x3a\x3f\x9a\x07\xb0\x4b\x04\xec\x32\xc6\xa6\x3d\x72\x96\xd5\xf3\xb9\xb5\x47\xdd\x7c\xa1\x59\x14\xfd\x53\x29\x5f\x6f\x31\xcf\x61\x43\x73\xf0\x07\x6b\x80\x82\x18\x21\xcc\x04\xe5\xef\x1c\x25\xeb\x7a\x2f\xd5\xb1\x28\x75\x6d\x3a\xed\xee\x80\x09\x19\xeb\x00\x42\x57\x38\x63\x02\x3c\xaf\xd7\x85\x3f\x6b\x89\xbd\x35\x88\xf0\x24\x1d\x59\x1b\x8f\x44\xbc\x14\x43\x45\x8c\x1e\x4b\xa7\xd4\xc9\x53\x01\x00\x00")
+
+func migrations06_receiving_accountsSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations06_receiving_accountsSql,
+		"migrations/06_receiving_accounts.sql",
+	)
+}
+
+func migrations06_receiving_accountsSql() (*asset, error) {
+	bytes, err := migrations06_receiving_accountsSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/06_receiving_accounts.sql", size: 339, mode: os.FileMode(420), modTime: time.Unix(1791676800, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _migrations07_result_xdr_textSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xd3\xd5\x55\xd0\xce\xcd\x4c\x2f\x4a\x2c\x49\x55\x08\x2d\xe0\x72\xf4\x09\x71\x0d\x52\x08\x71\x74\xf2\x71\x55\x28\x4e\xcd\x2b\x89\x2f\x29\x4a\xcc\x2b\x4e\x4c\x2e\xc9\xcc\xcf\x53\x80\x48\x3a\xfb\xfb\x84\xfa\xfa\x29\x14\xa5\x16\x97\xe6\x94\xc4\x57\xa4\x14\x29\x84\x44\x06\xb8\x2a\x94\xa4\x56\x94\x58\x73\x71\xe9\x22\x99\xe7\x92\x5f\x9e\x47\x81\x89\x65\x89\x45\xc9\x19\x89\x45\x1a\x46\xa6\xa6\x9a\xd6\x5c\x00\xe5\x5d\x55\x12\xa9\x00\x00\x00")
+
+func migrations07_result_xdr_textSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations07_result_xdr_textSql,
+		"migrations/07_result_xdr_text.sql",
+	)
+}
+
+func migrations07_result_xdr_textSql() (*asset, error) {
+	bytes, err := migrations07_result_xdr_textSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/07_result_xdr_text.sql", size: 169, mode: os.FileMode(420), modTime: time.Unix(1791763200, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -224,22 +287,27 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"latest.sql":                       latestSql,
-	"migrations/01_init.sql":           migrations01_initSql,
-	"migrations/02_payment_id.sql":     migrations02_payment_idSql,
-	"migrations/03_transaction_id.sql": migrations03_transaction_idSql,
-	"migrations/04_table_names.sql":    migrations04_table_namesSql,
+	"latest.sql":                           latestSql,
+	"migrations/01_init.sql":               migrations01_initSql,
+	"migrations/02_payment_id.sql":         migrations02_payment_idSql,
+	"migrations/03_transaction_id.sql":     migrations03_transaction_idSql,
+	"migrations/04_table_names.sql":        migrations04_table_namesSql,
+	"migrations/05_callback_delivery.sql":  migrations05_callback_deliverySql,
+	"migrations/06_receiving_accounts.sql": migrations06_receiving_accountsSql,
+	"migrations/07_result_xdr_text.sql":    migrations07_result_xdr_textSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"}
 // AssetDir("data/img") would return []string{"a.png", "b.png"}
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error
@@ -274,10 +342,13 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"latest.sql": &bintree{latestSql, map[string]*bintree{}},
 	"migrations": &bintree{nil, map[string]*bintree{
-		"01_init.sql":           &bintree{migrations01_initSql, map[string]*bintree{}},
-		"02_payment_id.sql":     &bintree{migrations02_payment_idSql, map[string]*bintree{}},
-		"03_transaction_id.sql": &bintree{migrations03_transaction_idSql, map[string]*bintree{}},
-		"04_table_names.sql":    &bintree{migrations04_table_namesSql, map[string]*bintree{}},
+		"01_init.sql":               &bintree{migrations01_initSql, map[string]*bintree{}},
+		"02_payment_id.sql":         &bintree{migrations02_payment_idSql, map[string]*bintree{}},
+		"03_transaction_id.sql":     &bintree{migrations03_transaction_idSql, map[string]*bintree{}},
+		"04_table_names.sql":        &bintree{migrations04_table_namesSql, map[string]*bintree{}},
+		"05_callback_delivery.sql":  &bintree{migrations05_callback_deliverySql, map[string]*bintree{}},
+		"06_receiving_accounts.sql": &bintree{migrations06_receiving_accountsSql, map[string]*bintree{}},
+		"07_result_xdr_text.sql":    &bintree{migrations07_result_xdr_textSql, map[string]*bintree{}},
 	}},
 }}
 
@@ -96,7 +96,7 @@ CREATE TABLE sent_transaction (
     succeeded_at timestamp without time zone,
     ledger bigint,
     envelope_xdr text NOT NULL,
-    result_xdr character varying(255) DEFAULT NULL::character varying,
+    result_xdr text,
     payment_id character varying(255) DEFAULT NULL::character varying
 );
 
@@ -19,6 +19,8 @@ var Migrations migrate.MigrationSource = &migrate.AssetMigrationSource{
 
 type Database interface {
 	GetLastCursorValue() (cursor *string, err error)
+	GetCursorValue(accountID string) (cursor *string, err error)
+	SaveCursorValue(accountID, cursor string) error
 
 	InsertReceivedPayment(payment *ReceivedPayment) error
 	UpdateReceivedPayment(payment *ReceivedPayment) error
@@ -30,6 +32,14 @@ type Database interface {
 	UpdateSentTransaction(transaction *SentTransaction) error
 	GetSentTransactionByPaymentID(paymentID string) (*SentTransaction, error)
 	GetSentTransactions(page, limit uint64) ([]*SentTransaction, error)
+	GetSentTransactionsByStatus(status SentTransactionStatus, submittedBefore time.Time, limit uint64) ([]*SentTransaction, error)
+
+	InsertCallbackDelivery(delivery *CallbackDelivery) error
+	UpdateCallbackDelivery(delivery *CallbackDelivery) error
+	GetCallbackDeliveryByID(id int64) (*CallbackDelivery, error)
+	GetCallbackDeliveryByReceivedPaymentID(receivedPaymentID int64) (*CallbackDelivery, error)
+	GetDueCallbackDeliveries(now time.Time, limit uint64) ([]*CallbackDelivery, error)
+	GetCallbackDeliveries(status CallbackDeliveryStatus, page, limit uint64) ([]*CallbackDelivery, error)
 }
 
 type PostgresDatabase struct {
@@ -71,3 +81,33 @@ type SentTransaction struct {
 	EnvelopeXdr   string                `db:"envelope_xdr" json:"envelope_xdr"`
 	ResultXdr     *string               `db:"result_xdr" json:"result_xdr"`
 }
+
+// CallbackDeliveryStatus type represents callback delivery status
+type CallbackDeliveryStatus string
+
+const (
+	// CallbackDeliveryStatusPending is a status indicating that callback has not been delivered yet
+	// and will be retried
+	CallbackDeliveryStatusPending CallbackDeliveryStatus = "pending"
+	// CallbackDeliveryStatusDelivered is a status indicating that callback responded with 200 OK
+	CallbackDeliveryStatusDelivered CallbackDeliveryStatus = "delivered"
+	// CallbackDeliveryStatusFailed is a status indicating that callback delivery has been given up
+	// after the maximum number of attempts (dead letter). It can be replayed by an admin.
+	CallbackDeliveryStatusFailed CallbackDeliveryStatus = "failed"
+)
+
+// CallbackDelivery represents a request to the receive callback for a received payment, kept
+// until the callback accepts it
+type CallbackDelivery struct {
+	ID                int64                  `db:"id" json:"id"`
+	ReceivedPaymentID int64                  `db:"received_payment_id" json:"received_payment_id"`
+	AccountID         string                 `db:"account_id" json:"account_id"` // receiving account
+	URL               string                 `db:"url" json:"url"`
+	Payload           string                 `db:"payload" json:"payload"` // form encoded request body
+	Status            CallbackDeliveryStatus `db:"status" json:"status"`   // pending/delivered/failed
+	Attempts          int32                  `db:"attempts" json:"attempts"`
+	LastError         *string                `db:"last_error" json:"last_error"`
+	CreatedAt         time.Time              `db:"created_at" json:"created_at"`
+	NextAttemptAt     time.Time              `db:"next_attempt_at" json:"next_attempt_at"`
+	DeliveredAt       *time.Time             `db:"delivered_at" json:"delivered_at"`
+}
@@ -0,0 +1,19 @@
+-- +migrate Up
+CREATE TABLE callback_delivery (
+  id bigserial,
+  received_payment_id bigint UNIQUE NOT NULL REFERENCES received_payment (id),
+  url text NOT NULL,
+  payload text NOT NULL,
+  status varchar(10) NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  last_error text DEFAULT NULL,
+  created_at timestamp NOT NULL,
+  next_attempt_at timestamp NOT NULL,
+  delivered_at timestamp DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX callback_delivery_status_next_attempt_at ON callback_delivery (status, next_attempt_at);
+
+-- +migrate Down
+DROP TABLE callback_delivery;
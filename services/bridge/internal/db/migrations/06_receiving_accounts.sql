@@ -0,0 +1,12 @@
+-- +migrate Up
+CREATE TABLE receiving_account_cursor (
+  account_id varchar(56) NOT NULL,
+  paging_token varchar(255) NOT NULL,
+  PRIMARY KEY (account_id)
+);
+
+ALTER TABLE callback_delivery ADD account_id varchar(56) NOT NULL DEFAULT '';
+
+-- +migrate Down
+DROP TABLE receiving_account_cursor;
+ALTER TABLE callback_delivery DROP account_id;
@@ -0,0 +1,5 @@
+-- +migrate Up
+ALTER TABLE sent_transaction ALTER COLUMN result_xdr TYPE text;
+
+-- +migrate Down
+ALTER TABLE sent_transaction ALTER COLUMN result_xdr TYPE varchar(255);
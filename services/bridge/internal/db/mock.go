@@ -0,0 +1,128 @@
+package db
+
+import (
+	"time"
+
+	"github.com/stretchr/testify/mock"
+)
+
+// MockDatabase is a mockable database.
+type MockDatabase struct {
+	mock.Mock
+}
+
+func (m *MockDatabase) GetLastCursorValue() (cursor *string, err error) {
+	a := m.Called()
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*string), a.Error(1)
+}
+
+func (m *MockDatabase) GetCursorValue(accountID string) (cursor *string, err error) {
+	a := m.Called(accountID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*string), a.Error(1)
+}
+
+func (m *MockDatabase) SaveCursorValue(accountID, cursor string) error {
+	a := m.Called(accountID, cursor)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) InsertReceivedPayment(payment *ReceivedPayment) error {
+	a := m.Called(payment)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) UpdateReceivedPayment(payment *ReceivedPayment) error {
+	a := m.Called(payment)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) GetReceivedPaymentByID(id int64) (*ReceivedPayment, error) {
+	a := m.Called(id)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*ReceivedPayment), a.Error(1)
+}
+
+func (m *MockDatabase) GetReceivedPaymentByOperationID(operationID string) (*ReceivedPayment, error) {
+	a := m.Called(operationID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*ReceivedPayment), a.Error(1)
+}
+
+func (m *MockDatabase) GetReceivedPayments(page, limit uint64) ([]*ReceivedPayment, error) {
+	a := m.Called(page, limit)
+	return a.Get(0).([]*ReceivedPayment), a.Error(1)
+}
+
+func (m *MockDatabase) InsertSentTransaction(transaction *SentTransaction) error {
+	a := m.Called(transaction)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) UpdateSentTransaction(transaction *SentTransaction) error {
+	a := m.Called(transaction)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) GetSentTransactionByPaymentID(paymentID string) (*SentTransaction, error) {
+	a := m.Called(paymentID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*SentTransaction), a.Error(1)
+}
+
+func (m *MockDatabase) GetSentTransactions(page, limit uint64) ([]*SentTransaction, error) {
+	a := m.Called(page, limit)
+	return a.Get(0).([]*SentTransaction), a.Error(1)
+}
+
+func (m *MockDatabase) GetSentTransactionsByStatus(status SentTransactionStatus, submittedBefore time.Time, limit uint64) ([]*SentTransaction, error) {
+	a := m.Called(status, submittedBefore, limit)
+	return a.Get(0).([]*SentTransaction), a.Error(1)
+}
+
+func (m *MockDatabase) InsertCallbackDelivery(delivery *CallbackDelivery) error {
+	a := m.Called(delivery)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) UpdateCallbackDelivery(delivery *CallbackDelivery) error {
+	a := m.Called(delivery)
+	return a.Error(0)
+}
+
+func (m *MockDatabase) GetCallbackDeliveryByID(id int64) (*CallbackDelivery, error) {
+	a := m.Called(id)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*CallbackDelivery), a.Error(1)
+}
+
+func (m *MockDatabase) GetCallbackDeliveryByReceivedPaymentID(receivedPaymentID int64) (*CallbackDelivery, error) {
+	a := m.Called(receivedPaymentID)
+	if a.Get(0) == nil {
+		return nil, a.Error(1)
+	}
+	return a.Get(0).(*CallbackDelivery), a.Error(1)
+}
+
+func (m *MockDatabase) GetDueCallbackDeliveries(now time.Time, limit uint64) ([]*CallbackDelivery, error) {
+	a := m.Called(now, limit)
+	return a.Get(0).([]*CallbackDelivery), a.Error(1)
+}
+
+func (m *MockDatabase) GetCallbackDeliveries(status CallbackDeliveryStatus, page, limit uint64) ([]*CallbackDelivery, error) {
+	a := m.Called(status, page, limit)
+	return a.Get(0).([]*CallbackDelivery), a.Error(1)
+}
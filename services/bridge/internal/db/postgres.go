@@ -2,14 +2,17 @@ package db
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
 )
 
 const (
-	receivedPaymentTableName = "received_payment"
-	sentTransactionTableName = "sent_transaction"
+	receivedPaymentTableName        = "received_payment"
+	sentTransactionTableName        = "sent_transaction"
+	callbackDeliveryTableName       = "callback_delivery"
+	receivingAccountCursorTableName = "receiving_account_cursor"
 )
 
 func (d *PostgresDatabase) Open(dsn string) error {
@@ -111,6 +114,40 @@ func (d *PostgresDatabase) UpdateReceivedPayment(payment *ReceivedPayment) error
 	return nil
 }
 
+// InsertCallbackDelivery inserts a new callback delivery into DB. After successful insert ID
+// field on `delivery` will updated to ID of a new row.
+func (d *PostgresDatabase) InsertCallbackDelivery(delivery *CallbackDelivery) error {
+	callbackDeliveryTable := d.getTable(callbackDeliveryTableName, nil)
+	_, err := callbackDeliveryTable.Insert(delivery).IgnoreCols("id").Exec()
+	if err != nil {
+		return errors.Wrap(err, "Error inserting callback delivery")
+	}
+
+	newDelivery, err := d.GetCallbackDeliveryByReceivedPaymentID(delivery.ReceivedPaymentID)
+	if err != nil {
+		return errors.Wrap(err, "Error getting new callback delivery")
+	}
+
+	delivery.ID = newDelivery.ID
+	return nil
+}
+
+func (d *PostgresDatabase) UpdateCallbackDelivery(delivery *CallbackDelivery) error {
+	if delivery.ID == 0 {
+		return errors.New("ID equals 0")
+	}
+
+	callbackDeliveryTable := d.getTable(callbackDeliveryTableName, nil)
+	_, err := callbackDeliveryTable.Update(nil, map[string]interface{}{"id": delivery.ID}).
+		SetStruct(delivery, []string{"id"}).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "Error updating callback delivery")
+	}
+
+	return nil
+}
+
 // GetLastCursorValue returns last cursor value from a DB
 func (d *PostgresDatabase) GetLastCursorValue() (cursor *string, err error) {
 	receivedPayment, err := d.getLastReceivedPayment()
@@ -123,6 +160,39 @@ func (d *PostgresDatabase) GetLastCursorValue() (cursor *string, err error) {
 	}
 }
 
+// GetCursorValue returns the cursor value of the payments stream of a receiving account
+func (d *PostgresDatabase) GetCursorValue(accountID string) (cursor *string, err error) {
+	cursorTable := d.getTable(receivingAccountCursorTableName, nil)
+	var row struct {
+		PagingToken string `db:"paging_token"`
+	}
+	err = cursorTable.Get(&row, map[string]interface{}{"account_id": accountID}).Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return nil, nil
+		default:
+			return nil, errors.Wrap(err, "Error getting cursor value")
+		}
+	}
+
+	return &row.PagingToken, nil
+}
+
+// SaveCursorValue saves the cursor value of the payments stream of a receiving account
+func (d *PostgresDatabase) SaveCursorValue(accountID, cursor string) error {
+	_, err := d.session.ExecRaw(
+		"INSERT INTO "+receivingAccountCursorTableName+" (account_id, paging_token) VALUES (?, ?) "+
+			"ON CONFLICT (account_id) DO UPDATE SET paging_token = EXCLUDED.paging_token",
+		accountID, cursor,
+	)
+	if err != nil {
+		return errors.Wrap(err, "Error saving cursor value")
+	}
+
+	return nil
+}
+
 // GetSentTransactionByPaymentID returns sent transaction searching by payment ID
 func (d *PostgresDatabase) GetSentTransactionByPaymentID(paymentID string) (*SentTransaction, error) {
 	sentTransactionTable := d.getTable(sentTransactionTableName, nil)
@@ -183,6 +253,82 @@ func (d *PostgresDatabase) getReceivedPayment(params map[string]interface{}) (*R
 	return &receivedPayment, nil
 }
 
+// GetCallbackDeliveryByID returns callback delivery by id
+func (d *PostgresDatabase) GetCallbackDeliveryByID(id int64) (*CallbackDelivery, error) {
+	return d.getCallbackDelivery(map[string]interface{}{"id": id})
+}
+
+// GetCallbackDeliveryByReceivedPaymentID returns callback delivery of a received payment
+func (d *PostgresDatabase) GetCallbackDeliveryByReceivedPaymentID(receivedPaymentID int64) (*CallbackDelivery, error) {
+	return d.getCallbackDelivery(map[string]interface{}{"received_payment_id": receivedPaymentID})
+}
+
+func (d *PostgresDatabase) getCallbackDelivery(params map[string]interface{}) (*CallbackDelivery, error) {
+	callbackDeliveryTable := d.getTable(callbackDeliveryTableName, nil)
+	var delivery CallbackDelivery
+	err := callbackDeliveryTable.Get(&delivery, params).Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return nil, nil
+		default:
+			return nil, errors.Wrap(err, "Error getting callback delivery")
+		}
+	}
+
+	return &delivery, nil
+}
+
+// GetDueCallbackDeliveries returns pending callback deliveries whose next attempt is due at
+// `now`, oldest first
+func (d *PostgresDatabase) GetDueCallbackDeliveries(now time.Time, limit uint64) ([]*CallbackDelivery, error) {
+	callbackDeliveryTable := d.getTable(callbackDeliveryTableName, nil)
+	deliveries := []*CallbackDelivery{}
+
+	err := callbackDeliveryTable.Select(&deliveries, "status = ? AND next_attempt_at <= ?", CallbackDeliveryStatusPending, now).
+		Limit(limit).OrderBy("next_attempt_at asc").Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return deliveries, nil
+		default:
+			return deliveries, errors.Wrap(err, "Error getting due callback deliveries")
+		}
+	}
+
+	return deliveries, nil
+}
+
+// GetCallbackDeliveries returns callback deliveries, only the ones with the given status if it
+// is not empty
+func (d *PostgresDatabase) GetCallbackDeliveries(status CallbackDeliveryStatus, page, limit uint64) ([]*CallbackDelivery, error) {
+	callbackDeliveryTable := d.getTable(callbackDeliveryTableName, nil)
+	deliveries := []*CallbackDelivery{}
+
+	if page == 0 {
+		page = 1
+	}
+
+	offset := (page - 1) * limit
+
+	var where interface{} = "1=1"
+	if status != "" {
+		where = map[string]interface{}{"status": status}
+	}
+
+	err := callbackDeliveryTable.Select(&deliveries, where).Limit(limit).Offset(offset).OrderBy("id desc").Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return deliveries, nil
+		default:
+			return deliveries, errors.Wrap(err, "Error getting callback deliveries")
+		}
+	}
+
+	return deliveries, nil
+}
+
 // GetReceivedPayments returns received payments
 func (d *PostgresDatabase) GetReceivedPayments(page, limit uint64) ([]*ReceivedPayment, error) {
 	receivedPaymentTable := d.getTable(receivedPaymentTableName, nil)
@@ -231,6 +377,26 @@ func (d *PostgresDatabase) GetSentTransactions(page, limit uint64) ([]*SentTrans
 	return transactions, nil
 }
 
+// GetSentTransactionsByStatus returns the transactions with the given status submitted before
+// `submittedBefore`, oldest first
+func (d *PostgresDatabase) GetSentTransactionsByStatus(status SentTransactionStatus, submittedBefore time.Time, limit uint64) ([]*SentTransaction, error) {
+	sentTransactionTable := d.getTable(sentTransactionTableName, nil)
+	transactions := []*SentTransaction{}
+
+	err := sentTransactionTable.Select(&transactions, "status = ? AND submitted_at < ?", status, submittedBefore).
+		Limit(limit).OrderBy("submitted_at asc").Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return transactions, nil
+		default:
+			return transactions, errors.Wrap(err, "Error getting sent transactions by status")
+		}
+	}
+
+	return transactions, nil
+}
+
 // getLastReceivedPayment returns the last received payment
 func (d *PostgresDatabase) getLastReceivedPayment() (*ReceivedPayment, error) {
 	receivedPaymentTable := d.getTable(receivedPaymentTableName, nil)
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/db/dbtest"
+	"github.com/stellar/go/support/db/schema"
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openTestDatabase(t *testing.T) (*PostgresDatabase, func()) {
+	tdb := dbtest.Postgres(t)
+	database := &PostgresDatabase{session: &db.Session{DB: tdb.Open()}}
+
+	_, err := schema.Migrate(database.GetDB(), Migrations, schema.MigrateUp, 0)
+	require.NoError(t, err)
+
+	return database, func() {
+		database.session.DB.Close()
+		tdb.Close()
+	}
+}
+
+// pathPaymentResult returns the result of a successful path payment that
+// crossed the given number of offers.
+func pathPaymentResult(t *testing.T, offers int) string {
+	var seller, destination xdr.AccountId
+	require.NoError(t, seller.SetAddress("GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"))
+	require.NoError(t, destination.SetAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"))
+
+	var native, usd xdr.Asset
+	require.NoError(t, native.SetNative())
+	require.NoError(t, usd.SetCredit("USD", seller))
+
+	success := xdr.PathPaymentResultSuccess{
+		Last: xdr.SimplePaymentResult{Destination: destination, Asset: usd, Amount: 100000000},
+	}
+	for i := 0; i < offers; i++ {
+		success.Offers = append(success.Offers, xdr.ClaimOfferAtom{
+			SellerId:     seller,
+			OfferId:      xdr.Uint64(i + 1),
+			AssetSold:    usd,
+			AmountSold:   100000000,
+			AssetBought:  native,
+			AmountBought: 200000000,
+		})
+	}
+
+	results := []xdr.OperationResult{{
+		Code: xdr.OperationResultCodeOpInner,
+		Tr: &xdr.OperationResultTr{
+			Type: xdr.OperationTypePathPayment,
+			PathPaymentResult: &xdr.PathPaymentResult{
+				Code:    xdr.PathPaymentResultCodePathPaymentSuccess,
+				Success: &success,
+			},
+		},
+	}}
+	result, err := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &results},
+	})
+	require.NoError(t, err)
+	return result
+}
+
+func TestSentTransactionPathPaymentResult(t *testing.T) {
+	database, closeDatabase := openTestDatabase(t)
+	defer closeDatabase()
+
+	result := pathPaymentResult(t, 3)
+	require.True(t, len(result) > 255, "result should not fit in varchar(255)")
+
+	transaction := &SentTransaction{
+		TransactionID: "2374e99349b9ef7dba9a5db3339b78fda8f34777b1af33ba468ad5c0df946d4d",
+		Status:        SentTransactionStatusSending,
+		Source:        "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z",
+		SubmittedAt:   time.Now(),
+		EnvelopeXdr:   "envelope",
+	}
+	require.NoError(t, database.InsertSentTransaction(transaction))
+
+	transaction.Status = SentTransactionStatusSuccess
+	transaction.ResultXdr = &result
+	require.NoError(t, database.UpdateSentTransaction(transaction))
+
+	stored, err := database.GetSentTransactionByHash(transaction.TransactionID)
+	require.NoError(t, err)
+	assert.Equal(t, SentTransactionStatusSuccess, stored.Status)
+	if assert.NotNil(t, stored.ResultXdr) {
+		assert.Equal(t, result, *stored.ResultXdr)
+	}
+}
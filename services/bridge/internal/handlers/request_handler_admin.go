@@ -8,11 +8,13 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi"
+	metrics "github.com/rcrowley/go-metrics"
 	log "github.com/sirupsen/logrus"
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/protocols/compliance"
 	"github.com/stellar/go/services/bridge/internal/db"
 	"github.com/stellar/go/services/internal/bridge-compliance-shared/http/helpers"
+	"github.com/stellar/go/services/internal/bridge-compliance-shared/protocols/bridge"
 	callback "github.com/stellar/go/services/internal/bridge-compliance-shared/protocols/compliance"
 	"github.com/stellar/go/support/errors"
 )
@@ -152,3 +154,62 @@ func (rh *RequestHandler) AdminSentTransactions(w http.ResponseWriter, r *http.R
 		return
 	}
 }
+
+// AdminCallbackDeliveries implements /admin/callback-deliveries endpoint
+func (rh *RequestHandler) AdminCallbackDeliveries(w http.ResponseWriter, r *http.Request) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	status := db.CallbackDeliveryStatus(r.URL.Query().Get("status"))
+	limit := 10
+
+	deliveries, err := rh.Database.GetCallbackDeliveries(status, uint64(page), uint64(limit))
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading CallbackDeliveries")
+		helpers.Write(w, helpers.InternalServerError)
+		return
+	}
+
+	encoder := json.NewEncoder(w)
+	err = encoder.Encode(deliveries)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err, "deliveries": deliveries}).Error("Error encoding CallbackDeliveries")
+		helpers.Write(w, helpers.InternalServerError)
+		return
+	}
+}
+
+// AdminReplayCallbackDelivery implements /admin/callback-deliveries/{id}/replay endpoint
+func (rh *RequestHandler) AdminReplayCallbackDelivery(w http.ResponseWriter, r *http.Request) {
+	id, _ := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+
+	if rh.PaymentListener == nil || !rh.PaymentListener.Running() {
+		helpers.Write(w, &bridge.ReprocessResponse{Status: "error", Message: "Payment listener is not running"})
+		return
+	}
+
+	err := rh.PaymentListener.ReplayCallback(id)
+	if err != nil {
+		helpers.Write(w, &bridge.ReprocessResponse{Status: "error", Message: err.Error()})
+		return
+	}
+
+	helpers.Write(w, &bridge.ReprocessResponse{Status: "ok"})
+}
+
+// AdminMetrics implements /admin/metrics endpoint
+func (rh *RequestHandler) AdminMetrics(w http.ResponseWriter, r *http.Request) {
+	var registry metrics.Registry
+	if rh.PaymentListener != nil {
+		registry = rh.PaymentListener.Metrics()
+	}
+	if registry == nil {
+		registry = metrics.NewRegistry()
+	}
+
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(registry)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding metrics")
+		helpers.Write(w, helpers.InternalServerError)
+		return
+	}
+}
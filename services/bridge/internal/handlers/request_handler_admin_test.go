@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/stellar/go/services/bridge/internal/listener"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminReplayCallbackDeliveryNotRunning(t *testing.T) {
+	rh := &RequestHandler{PaymentListener: &listener.PaymentListener{}}
+	mux := chi.NewRouter()
+	mux.Post("/admin/callback-deliveries/{id}/replay", rh.AdminReplayCallbackDelivery)
+
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest("POST", "/admin/callback-deliveries/1/replay", nil))
+	assert.Contains(t, w.Body.String(), "Payment listener is not running")
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/stellar/go/address"
 	b "github.com/stellar/go/build"
+	federationClient "github.com/stellar/go/clients/federation"
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/protocols/compliance"
 	"github.com/stellar/go/protocols/federation"
@@ -30,6 +31,11 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// The idempotency key can be sent in a header instead of the `id` param
+	if request.ID == "" {
+		request.ID = r.Header.Get("Idempotency-Key")
+	}
+
 	err = helpers.Validate(request, rh.Config.Accounts.BaseSeed)
 	if err != nil {
 		switch err := err.(type) {
@@ -46,6 +52,23 @@ func (rh *RequestHandler) Payment(w http.ResponseWriter, r *http.Request) {
 		request.Source = rh.Config.Accounts.BaseSeed
 	}
 
+	// Return the result of the transaction already sent for this payment ID, if any
+	if request.ID != "" {
+		sentTransaction, err := rh.Database.GetSentTransactionByPaymentID(request.ID)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting sent transaction")
+			helpers.Write(w, helpers.InternalServerError)
+			return
+		}
+
+		if sentTransaction != nil {
+			log.WithFields(log.Fields{"paymentID": request.ID, "tx": sentTransaction.TransactionID}).Info("Transaction with given ID already exists")
+			submitResponse, err := rh.TransactionSubmitter.TransactionResult(sentTransaction)
+			rh.handleTransactionSubmitResponse(w, submitResponse, err)
+			return
+		}
+	}
+
 	// Will use compliance if compliance server is connected and:
 	// * User passed extra memo OR
 	// * User explicitly wants to use compliance protocol
@@ -129,29 +152,8 @@ func (rh *RequestHandler) complianceProtocolPayment(w http.ResponseWriter, reque
 
 func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge.PaymentRequest) {
 	var paymentID *string
-
 	if request.ID != "" {
-		sentTransaction, err := rh.Database.GetSentTransactionByPaymentID(request.ID)
-		if err != nil {
-			log.WithFields(log.Fields{"err": err}).Error("Error getting sent transaction")
-			helpers.Write(w, helpers.InternalServerError)
-			return
-		}
-
-		if sentTransaction == nil {
-			paymentID = &request.ID
-		} else {
-			log.WithFields(log.Fields{"paymentID": request.ID, "tx": sentTransaction.EnvelopeXdr}).Info("Transaction with given ID already exists, resubmitting...")
-			submitResponse, err := rh.Horizon.SubmitTransaction(sentTransaction.EnvelopeXdr)
-			if err != nil {
-				log.WithFields(log.Fields{"error": err}).Error("Error submitting transaction")
-				helpers.Write(w, helpers.InternalServerError)
-				return
-			}
-
-			rh.handleTransactionSubmitResponse(w, submitResponse, err)
-			return
-		}
+		paymentID = &request.ID
 	}
 
 	destinationObject := &federation.NameResponse{}
@@ -170,12 +172,14 @@ func (rh *RequestHandler) standardPayment(w http.ResponseWriter, request *bridge
 			}
 		}
 	} else {
-		destinationObject, err = rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Fields)
+		var forwardResponse *federationClient.ForwardResponse
+		forwardResponse, err = rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Fields)
 		if err != nil {
 			log.WithFields(log.Fields{"destination": request.Destination, "err": err}).Print("Cannot resolve address")
 			helpers.Write(w, bridge.PaymentCannotResolveDestination)
 			return
 		}
+		destinationObject = &forwardResponse.NameResponse
 	}
 
 	if !shared.IsValidAccountID(destinationObject.AccountID) {
@@ -0,0 +1,304 @@
+package listener
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/services/bridge/internal/config"
+	"github.com/stellar/go/services/bridge/internal/db"
+	"github.com/stellar/go/support/errors"
+)
+
+const (
+	// maxCallbackBackoff is the longest delay between two attempts to deliver a callback
+	maxCallbackBackoff = time.Hour
+	// callbackRetryInterval is how often the outbox is checked for callbacks due for a retry
+	callbackRetryInterval = 5 * time.Second
+	// callbackRetryBatch is the maximum number of callbacks retried every callbackRetryInterval
+	callbackRetryBatch = 100
+)
+
+// callbackMetrics contains the metrics of the callback outbox
+type callbackMetrics struct {
+	registry metrics.Registry
+	// lag is the time between a callback being queued and being delivered
+	lag            metrics.Timer
+	delivered      metrics.Counter
+	failedAttempts metrics.Counter
+	deadLettered   metrics.Counter
+}
+
+func newCallbackMetrics() *callbackMetrics {
+	registry := metrics.NewRegistry()
+	return &callbackMetrics{
+		registry:       registry,
+		lag:            metrics.NewRegisteredTimer("callbacks.delivery_lag", registry),
+		delivered:      metrics.NewRegisteredCounter("callbacks.delivered", registry),
+		failedAttempts: metrics.NewRegisteredCounter("callbacks.failed_attempts", registry),
+		deadLettered:   metrics.NewRegisteredCounter("callbacks.dead_lettered", registry),
+	}
+}
+
+// onDelivered, onFailedAttempt and onDeadLettered record the outcome of an attempt to deliver a
+// callback. They do nothing if the metrics have not been created.
+func (m *callbackMetrics) onDelivered(lag time.Duration) {
+	if m == nil {
+		return
+	}
+	m.delivered.Inc(1)
+	m.lag.Update(lag)
+}
+
+func (m *callbackMetrics) onFailedAttempt() {
+	if m == nil {
+		return
+	}
+	m.failedAttempts.Inc(1)
+}
+
+func (m *callbackMetrics) onDeadLettered() {
+	if m == nil {
+		return
+	}
+	m.deadLettered.Inc(1)
+}
+
+// Metrics returns the metrics of the receive callback deliveries. It returns nil if the
+// listener has not been created with NewPaymentListener.
+func (pl *PaymentListener) Metrics() metrics.Registry {
+	if pl.metrics == nil {
+		return nil
+	}
+	return pl.metrics.registry
+}
+
+// ReplayCallback sends the receive callback of a delivery that failed again, starting a new
+// series of attempts if it fails again.
+func (pl *PaymentListener) ReplayCallback(id int64) error {
+	delivery, err := pl.database.GetCallbackDeliveryByID(id)
+	if err != nil {
+		return errors.Wrap(err, "Error getting callback delivery")
+	}
+
+	if delivery == nil {
+		return errors.New("Callback delivery not found")
+	}
+
+	if delivery.Status == db.CallbackDeliveryStatusDelivered {
+		return errors.New("Callback has already been delivered")
+	}
+
+	pl.log.WithFields(logrus.Fields{"id": delivery.ID}).Info("Replaying callback")
+
+	delivery.Status = db.CallbackDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = pl.now().Add(callbackTimeout)
+	err = pl.database.UpdateCallbackDelivery(delivery)
+	if err != nil {
+		return errors.Wrap(err, "Error updating callback delivery")
+	}
+
+	err = pl.deliverCallback(delivery)
+	pl.updatePaymentStatus(delivery)
+	return err
+}
+
+// sendCallback stores the receive callback request of a received payment in the callback
+// outbox and makes the first attempt to deliver it. Failed deliveries are retried by
+// retryCallbacks with an exponential backoff.
+func (pl *PaymentListener) sendCallback(payment *db.ReceivedPayment, account *config.ReceivingAccount, form url.Values) error {
+	now := pl.now()
+
+	delivery, err := pl.database.GetCallbackDeliveryByReceivedPaymentID(payment.ID)
+	if err != nil {
+		return errors.Wrap(err, "Error getting callback delivery")
+	}
+
+	isNew := delivery == nil
+	if isNew {
+		delivery = &db.CallbackDelivery{ReceivedPaymentID: payment.ID}
+	}
+
+	// The payment may be reprocessed, in which case its delivery starts over
+	delivery.AccountID = account.AccountID
+	delivery.URL = account.Callback
+	delivery.Payload = form.Encode()
+	delivery.Status = db.CallbackDeliveryStatusPending
+	delivery.Attempts = 0
+	delivery.LastError = nil
+	delivery.CreatedAt = now
+	delivery.DeliveredAt = nil
+	// Keep retryCallbacks away from the delivery while the first attempt is made here
+	delivery.NextAttemptAt = now.Add(callbackTimeout)
+
+	if isNew {
+		err = pl.database.InsertCallbackDelivery(delivery)
+	} else {
+		err = pl.database.UpdateCallbackDelivery(delivery)
+	}
+	if err != nil {
+		return errors.Wrap(err, "Error saving callback delivery")
+	}
+
+	return pl.deliverCallback(delivery)
+}
+
+// retryCallbacks sends the callbacks that are due for a retry until the process exits.
+func (pl *PaymentListener) retryCallbacks() {
+	for {
+		deliveries, err := pl.database.GetDueCallbackDeliveries(pl.now(), callbackRetryBatch)
+		if err != nil {
+			pl.log.WithFields(logrus.Fields{"err": err}).Error("Error getting callbacks to retry")
+		}
+
+		for _, delivery := range deliveries {
+			pl.log.WithFields(logrus.Fields{
+				"id":       delivery.ID,
+				"attempts": delivery.Attempts,
+			}).Info("Retrying callback")
+
+			err = pl.deliverCallback(delivery)
+			if err != nil {
+				pl.log.WithFields(logrus.Fields{"id": delivery.ID, "err": err}).Error("Callback retry failed")
+			}
+			pl.updatePaymentStatus(delivery)
+		}
+
+		if len(deliveries) < callbackRetryBatch {
+			time.Sleep(callbackRetryInterval)
+		}
+	}
+}
+
+// deliverCallback makes an attempt to deliver a callback and saves its outcome: the delivery is
+// either delivered, scheduled for another attempt or, after the maximum number of attempts,
+// failed.
+func (pl *PaymentListener) deliverCallback(delivery *db.CallbackDelivery) error {
+	form, err := url.ParseQuery(delivery.Payload)
+	if err != nil {
+		return errors.Wrap(err, "Cannot parse callback payload")
+	}
+
+	// The MAC key of the account may have changed since the delivery was created
+	macKey := pl.config.MACKey
+	if account := pl.config.GetReceivingAccount(delivery.AccountID); account != nil {
+		macKey = account.MACKey
+	}
+
+	callbackErr := pl.postCallback(delivery.URL, form, macKey)
+
+	now := pl.now()
+	delivery.Attempts++
+	if callbackErr == nil {
+		delivery.Status = db.CallbackDeliveryStatusDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+		pl.metrics.onDelivered(now.Sub(delivery.CreatedAt))
+	} else {
+		message := callbackErr.Error()
+		delivery.LastError = &message
+		pl.metrics.onFailedAttempt()
+
+		if int(delivery.Attempts) >= pl.callbackMaxAttempts() {
+			delivery.Status = db.CallbackDeliveryStatusFailed
+			pl.metrics.onDeadLettered()
+			pl.log.WithFields(logrus.Fields{
+				"id":       delivery.ID,
+				"attempts": delivery.Attempts,
+			}).Error("Giving up on callback")
+		} else {
+			delivery.NextAttemptAt = now.Add(pl.callbackBackoff(delivery.Attempts))
+		}
+	}
+
+	err = pl.database.UpdateCallbackDelivery(delivery)
+	if err != nil {
+		return errors.Wrap(err, "Error updating callback delivery")
+	}
+
+	return callbackErr
+}
+
+// updatePaymentStatus updates the status of the received payment of a delivery that has been
+// attempted outside of onPayment and ReprocessPayment.
+func (pl *PaymentListener) updatePaymentStatus(delivery *db.CallbackDelivery) {
+	var status string
+	switch delivery.Status {
+	case db.CallbackDeliveryStatusDelivered:
+		status = "Success"
+	case db.CallbackDeliveryStatusFailed:
+		status = fmt.Sprintf("Callback failed after %d attempts", delivery.Attempts)
+		if delivery.LastError != nil {
+			status += ": " + *delivery.LastError
+		}
+	default:
+		return
+	}
+
+	payment, err := pl.database.GetReceivedPaymentByID(delivery.ReceivedPaymentID)
+	if err != nil || payment == nil {
+		pl.log.WithFields(logrus.Fields{"id": delivery.ReceivedPaymentID, "err": err}).Error("Error getting received payment")
+		return
+	}
+
+	payment.Status = status
+	payment.ProcessedAt = pl.now()
+	err = pl.database.UpdateReceivedPayment(payment)
+	if err != nil {
+		pl.log.WithFields(logrus.Fields{"err": err}).Error("Error updating payment")
+	}
+}
+
+// postCallback sends a request to the receive callback and returns an error unless it
+// responds with 200 OK.
+func (pl *PaymentListener) postCallback(callbackURL string, form url.Values, macKey string) error {
+	resp, err := pl.postForm(callbackURL, form, macKey)
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to receive callback")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return errors.Wrap(err, "Error reading receive callback response")
+		}
+
+		pl.log.WithFields(logrus.Fields{
+			"status": resp.StatusCode,
+			"body":   string(body),
+		}).Error("Error response from receive callback")
+		return errors.New("Error response from receive callback")
+	}
+
+	return nil
+}
+
+// callbackBackoff returns the delay before the next attempt to deliver a callback that has
+// failed attempts times.
+func (pl *PaymentListener) callbackBackoff(attempts int32) time.Duration {
+	initial := pl.config.Callbacks.InitialBackoff
+	if initial <= 0 {
+		initial = config.DefaultCallbackInitialBackoff
+	}
+
+	backoff := time.Duration(initial) * time.Second
+	for i := int32(1); i < attempts && backoff < maxCallbackBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxCallbackBackoff {
+		backoff = maxCallbackBackoff
+	}
+	return backoff
+}
+
+func (pl *PaymentListener) callbackMaxAttempts() int {
+	if pl.config.Callbacks.MaxAttempts <= 0 {
+		return config.DefaultCallbackMaxAttempts
+	}
+	return pl.config.Callbacks.MaxAttempts
+}
@@ -0,0 +1,139 @@
+package listener
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stellar/go/services/bridge/internal/config"
+	"github.com/stellar/go/services/bridge/internal/db"
+	"github.com/stellar/go/support/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testCallbackURL = "http://receive.example.com/"
+
+func newTestPaymentListener(cfg *config.Config) (*PaymentListener, *db.MockDatabase, *httptest.Client) {
+	mockDatabase := &db.MockDatabase{}
+	mockHTTP := httptest.NewClient()
+	now := time.Unix(1500000000, 0)
+
+	pl := &PaymentListener{
+		client:   mockHTTP,
+		config:   cfg,
+		database: mockDatabase,
+		log:      logrus.WithField("service", "PaymentListener"),
+		now:      func() time.Time { return now },
+		metrics:  newCallbackMetrics(),
+	}
+	return pl, mockDatabase, mockHTTP
+}
+
+func newTestDelivery() *db.CallbackDelivery {
+	return &db.CallbackDelivery{
+		ID:                1,
+		ReceivedPaymentID: 2,
+		AccountID:         "GAJGR4O4O3FQ3JFJ6OHT6YQY4UFEGG3FGIH4DD7B7FTDJO57D3OHQYWC",
+		URL:               testCallbackURL,
+		Payload:           url.Values{"id": []string{"123"}}.Encode(),
+		Status:            db.CallbackDeliveryStatusPending,
+		CreatedAt:         time.Unix(1500000000, 0).Add(-time.Minute),
+	}
+}
+
+func TestCallbackBackoff(t *testing.T) {
+	pl, _, _ := newTestPaymentListener(&config.Config{})
+
+	initial := time.Duration(config.DefaultCallbackInitialBackoff) * time.Second
+	assert.Equal(t, initial, pl.callbackBackoff(1))
+	assert.Equal(t, 2*initial, pl.callbackBackoff(2))
+	assert.Equal(t, 4*initial, pl.callbackBackoff(3))
+	assert.Equal(t, maxCallbackBackoff, pl.callbackBackoff(30))
+
+	pl.config.Callbacks.InitialBackoff = 1
+	assert.Equal(t, 8*time.Second, pl.callbackBackoff(4))
+}
+
+func TestDeliverCallbackRetry(t *testing.T) {
+	pl, mockDatabase, mockHTTP := newTestPaymentListener(&config.Config{
+		Callbacks: config.Callbacks{MaxAttempts: 2, InitialBackoff: 10},
+	})
+	delivery := newTestDelivery()
+
+	mockDatabase.On("UpdateCallbackDelivery", delivery).Return(nil)
+	mockHTTP.On("POST", testCallbackURL).ReturnString(500, "error")
+
+	// first failed attempt is scheduled for a retry
+	err := pl.deliverCallback(delivery)
+	assert.Error(t, err)
+	assert.Equal(t, db.CallbackDeliveryStatusPending, delivery.Status)
+	assert.Equal(t, int32(1), delivery.Attempts)
+	assert.Equal(t, pl.now().Add(10*time.Second), delivery.NextAttemptAt)
+	require.NotNil(t, delivery.LastError)
+
+	// the last attempt fails the delivery
+	err = pl.deliverCallback(delivery)
+	assert.Error(t, err)
+	assert.Equal(t, db.CallbackDeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, int32(2), delivery.Attempts)
+	assert.Equal(t, int64(1), pl.metrics.deadLettered.Count())
+	assert.Equal(t, int64(2), pl.metrics.failedAttempts.Count())
+
+	mockDatabase.AssertNumberOfCalls(t, "UpdateCallbackDelivery", 2)
+}
+
+func TestDeliverCallbackSuccess(t *testing.T) {
+	pl, mockDatabase, mockHTTP := newTestPaymentListener(&config.Config{})
+	// metrics are optional
+	pl.metrics = nil
+	delivery := newTestDelivery()
+
+	mockDatabase.On("UpdateCallbackDelivery", delivery).Return(nil)
+	mockHTTP.On("POST", testCallbackURL).ReturnString(200, "ok")
+
+	err := pl.deliverCallback(delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, db.CallbackDeliveryStatusDelivered, delivery.Status)
+	require.NotNil(t, delivery.DeliveredAt)
+	assert.Equal(t, pl.now(), *delivery.DeliveredAt)
+	assert.Nil(t, delivery.LastError)
+	mockDatabase.AssertExpectations(t)
+}
+
+func TestReplayCallback(t *testing.T) {
+	pl, mockDatabase, mockHTTP := newTestPaymentListener(&config.Config{})
+	delivery := newTestDelivery()
+	delivery.Status = db.CallbackDeliveryStatusFailed
+	delivery.Attempts = 10
+	payment := &db.ReceivedPayment{ID: delivery.ReceivedPaymentID, Status: "Callback failed after 10 attempts"}
+
+	mockDatabase.On("GetCallbackDeliveryByID", delivery.ID).Return(delivery, nil)
+	mockDatabase.On("UpdateCallbackDelivery", delivery).Return(nil)
+	mockDatabase.On("GetReceivedPaymentByID", delivery.ReceivedPaymentID).Return(payment, nil)
+	mockDatabase.On("UpdateReceivedPayment", mock.MatchedBy(func(p *db.ReceivedPayment) bool {
+		return p.Status == "Success"
+	})).Return(nil)
+	mockHTTP.On("POST", testCallbackURL).ReturnString(200, "ok")
+
+	err := pl.ReplayCallback(delivery.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, db.CallbackDeliveryStatusDelivered, delivery.Status)
+	// a new series of attempts is started
+	assert.Equal(t, int32(1), delivery.Attempts)
+	mockDatabase.AssertExpectations(t)
+
+	// delivered callbacks are not replayed
+	err = pl.ReplayCallback(delivery.ID)
+	assert.EqualError(t, err, "Callback has already been delivered")
+}
+
+func TestReplayCallbackNotFound(t *testing.T) {
+	pl, mockDatabase, _ := newTestPaymentListener(&config.Config{})
+	mockDatabase.On("GetCallbackDeliveryByID", int64(3)).Return(nil, nil)
+
+	err := pl.ReplayCallback(3)
+	assert.EqualError(t, err, "Callback delivery not found")
+}
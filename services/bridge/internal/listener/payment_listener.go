@@ -31,6 +31,8 @@ type PaymentListener struct {
 	horizon  horizon.ClientInterface
 	log      *logrus.Entry
 	now      func() time.Time
+	metrics  *callbackMetrics
+	running  bool
 }
 
 // HTTP represents an http client that a payment listener can use to make HTTP
@@ -55,52 +57,102 @@ func NewPaymentListener(
 	pl.database = database
 	pl.horizon = horizon
 	pl.now = now
+	pl.metrics = newCallbackMetrics()
 	pl.log = logrus.WithFields(logrus.Fields{
 		"service": "PaymentListener",
 	})
 	return
 }
 
-// Listen starts listening for new payments
+// Listen starts listening for new payments to every receiving account that has a receive callback
 func (pl *PaymentListener) Listen() (err error) {
-	accountID := pl.config.Accounts.ReceivingAccountID
+	accounts := pl.config.GetListenedAccounts()
 
-	_, err = pl.horizon.LoadAccount(accountID)
-	if err != nil {
-		return
+	for _, account := range accounts {
+		_, err = pl.horizon.LoadAccount(account.AccountID)
+		if err != nil {
+			return
+		}
 	}
 
-	go func() {
-		for {
-			cursorValue, err := pl.database.GetLastCursorValue()
-			if err != nil {
-				pl.log.WithFields(logrus.Fields{"error": err}).Error("Could not load last cursor from the DB")
-				return
-			}
+	go pl.retryCallbacks()
 
-			var cursor horizon.Cursor
-			if cursorValue != nil {
-				cursor = horizon.Cursor(*cursorValue)
-			} else {
-				// If no last cursor saved set it to: `now`
-				cursor = horizon.Cursor("now")
-			}
+	for _, account := range accounts {
+		go pl.stream(account.AccountID)
+	}
 
-			pl.log.WithFields(logrus.Fields{
-				"accountId": accountID,
-				"cursor":    cursor,
-			}).Info("Started listening for new payments")
-
-			err = pl.horizon.StreamPayments(context.Background(), accountID, &cursor, pl.onPayment)
-			if err != nil {
-				pl.log.Error("Error while streaming: ", err)
-				pl.log.Info("Sleeping...")
-				time.Sleep(10 * time.Second)
-			}
+	pl.running = true
+	return
+}
+
+// Running returns true if the listener has been started with Listen.
+func (pl *PaymentListener) Running() bool {
+	return pl.running
+}
+
+// stream streams the payments of a receiving account, starting from the cursor saved for it.
+func (pl *PaymentListener) stream(accountID string) {
+	for {
+		cursorValue, err := pl.getCursorValue(accountID)
+		if err != nil {
+			pl.log.WithFields(logrus.Fields{"error": err}).Error("Could not load last cursor from the DB")
+			return
 		}
-	}()
 
-	return
+		var cursor horizon.Cursor
+		if cursorValue != nil {
+			cursor = horizon.Cursor(*cursorValue)
+		} else {
+			// If no last cursor saved set it to: `now`
+			cursor = horizon.Cursor("now")
+		}
+
+		pl.log.WithFields(logrus.Fields{
+			"accountId": accountID,
+			"cursor":    cursor,
+		}).Info("Started listening for new payments")
+
+		err = pl.horizon.StreamPayments(context.Background(), accountID, &cursor, func(payment horizon.Payment) {
+			pl.onPayment(accountID, payment)
+		})
+		if err != nil {
+			pl.log.Error("Error while streaming: ", err)
+			pl.log.Info("Sleeping...")
+			time.Sleep(10 * time.Second)
+		}
+	}
+}
+
+// getCursorValue returns the cursor saved for a receiving account. Before cursors were saved
+// for each account, the cursor of accounts.receiving_account_id was the paging token of the
+// last received payment, which is still used if no cursor has been saved for it yet.
+func (pl *PaymentListener) getCursorValue(accountID string) (*string, error) {
+	cursor, err := pl.database.GetCursorValue(accountID)
+	if err != nil || cursor != nil {
+		return cursor, err
+	}
+
+	if accountID == pl.config.Accounts.ReceivingAccountID {
+		return pl.database.GetLastCursorValue()
+	}
+
+	return nil, nil
+}
+
+func (pl *PaymentListener) saveCursorValue(accountID string, payment horizon.Payment) {
+	err := pl.database.SaveCursorValue(accountID, payment.PagingToken)
+	if err != nil {
+		pl.log.WithFields(logrus.Fields{"err": err, "accountId": accountID}).Error("Error saving cursor")
+	}
+}
+
+// receivingAccount returns the receiving account a payment has been sent to, or nil if it has
+// not been sent to any of them.
+func (pl *PaymentListener) receivingAccount(payment horizon.Payment) *config.ReceivingAccount {
+	if account := pl.config.GetReceivingAccount(payment.To); account != nil {
+		return account
+	}
+	return pl.config.GetReceivingAccount(payment.Into)
 }
 
 func (pl *PaymentListener) ReprocessPayment(payment horizon.Payment, force bool) error {
@@ -122,6 +174,15 @@ func (pl *PaymentListener) ReprocessPayment(payment horizon.Payment, force bool)
 		return errors.New("Trying to reprocess successful transaction without force")
 	}
 
+	account := pl.receivingAccount(payment)
+	if account == nil {
+		return errors.New("Payment has not been sent to a receiving account")
+	}
+
+	if account.Callback == "" {
+		return errors.New("Receiving account has no receive callback")
+	}
+
 	existingPayment.Status = "Reprocessing..."
 	existingPayment.ProcessedAt = pl.now()
 
@@ -130,7 +191,7 @@ func (pl *PaymentListener) ReprocessPayment(payment horizon.Payment, force bool)
 		return err
 	}
 
-	err = pl.process(payment)
+	err = pl.process(existingPayment, account, payment)
 
 	if err != nil {
 		pl.log.WithFields(logrus.Fields{"err": err}).Error("Payment reprocessed with errors")
@@ -143,8 +204,23 @@ func (pl *PaymentListener) ReprocessPayment(payment horizon.Payment, force bool)
 	return pl.database.UpdateReceivedPayment(existingPayment)
 }
 
-func (pl *PaymentListener) onPayment(payment horizon.Payment) {
-	pl.log.WithFields(logrus.Fields{"id": payment.ID}).Info("New received payment")
+// onPayment processes a payment streamed for the receiving account accountID.
+func (pl *PaymentListener) onPayment(accountID string, payment horizon.Payment) {
+	pl.log.WithFields(logrus.Fields{"id": payment.ID, "accountId": accountID}).Info("New received payment")
+
+	account := pl.config.GetReceivingAccount(accountID)
+	if account == nil {
+		pl.log.WithFields(logrus.Fields{"accountId": accountID}).Error("Not a receiving account")
+		return
+	}
+
+	if to := pl.receivingAccount(payment); to != nil && to.AccountID != accountID && to.Callback != "" {
+		// Payment between two receiving accounts: it is processed by the stream of the receiver.
+		// Receiving accounts without a callback are not streamed, payments to them are recorded
+		// below like any other payment sent by accountID.
+		pl.saveCursorValue(accountID, payment)
+		return
+	}
 
 	existingPayment, err := pl.database.GetReceivedPaymentByOperationID(payment.ID)
 	if err != nil {
@@ -154,6 +230,7 @@ func (pl *PaymentListener) onPayment(payment horizon.Payment) {
 
 	if existingPayment != nil {
 		pl.log.WithFields(logrus.Fields{"id": payment.ID}).Info("Payment already exists")
+		pl.saveCursorValue(accountID, payment)
 		return
 	}
 
@@ -170,12 +247,12 @@ func (pl *PaymentListener) onPayment(payment horizon.Payment) {
 		return
 	}
 
-	process, status := pl.shouldProcessPayment(payment)
+	process, status := pl.shouldProcessPayment(account, payment)
 	if !process {
 		dbPayment.Status = status
 		pl.log.Info(status)
 	} else {
-		err = pl.process(payment)
+		err = pl.process(dbPayment, account, payment)
 
 		if err != nil {
 			pl.log.WithFields(logrus.Fields{"err": err}).Error("Payment processed with errors")
@@ -191,11 +268,13 @@ func (pl *PaymentListener) onPayment(payment horizon.Payment) {
 		pl.log.WithFields(logrus.Fields{"err": err}).Error("Error updating payment")
 		return
 	}
+
+	pl.saveCursorValue(accountID, payment)
 }
 
 // shouldProcessPayment returns false and text status if payment should not be processed
 // (ex. asset is different than allowed assets).
-func (pl *PaymentListener) shouldProcessPayment(payment horizon.Payment) (bool, string) {
+func (pl *PaymentListener) shouldProcessPayment(account *config.ReceivingAccount, payment horizon.Payment) (bool, string) {
 	if payment.Type != "payment" && payment.Type != "path_payment" && payment.Type != "account_merge" {
 		return false, "Not a payment operation"
 	}
@@ -204,18 +283,20 @@ func (pl *PaymentListener) shouldProcessPayment(payment horizon.Payment) (bool,
 		payment.AssetType = "native"
 	}
 
-	if payment.To != pl.config.Accounts.ReceivingAccountID && payment.Into != pl.config.Accounts.ReceivingAccountID {
+	if payment.To != account.AccountID && payment.Into != account.AccountID {
 		return false, "Operation sent not received"
 	}
 
-	if !pl.isAssetAllowed(payment.AssetType, payment.AssetCode, payment.AssetIssuer) {
+	if !isAssetAllowed(account.Assets, payment.AssetType, payment.AssetCode, payment.AssetIssuer) {
 		return false, "Asset not allowed"
 	}
 
 	return true, ""
 }
 
-func (pl *PaymentListener) process(payment horizon.Payment) error {
+// process sends the receive callback of account for a payment. If the callback fails, it is
+// retried in the background (see sendCallback) and the error of the first attempt is returned.
+func (pl *PaymentListener) process(receivedPayment *db.ReceivedPayment, account *config.ReceivingAccount, payment horizon.Payment) error {
 	if payment.Type == "account_merge" {
 		payment.AssetType = "native"
 		payment.From = payment.Account
@@ -244,7 +325,7 @@ func (pl *PaymentListener) process(payment horizon.Payment) error {
 
 		pl.log.WithFields(logrus.Fields{"url": complianceRequestURL, "body": complianceRequestBody}).Info("Sending request to compliance server")
 		var resp *http.Response
-		resp, err = pl.postForm(complianceRequestURL, complianceRequestBody)
+		resp, err = pl.postForm(complianceRequestURL, complianceRequestBody, pl.config.MACKey)
 		if err != nil {
 			return errors.Wrap(err, "Error sending request to compliance server")
 		}
@@ -286,8 +367,9 @@ func (pl *PaymentListener) process(payment horizon.Payment) error {
 		route = payment.Memo.Value
 	}
 
-	resp, err := pl.postForm(
-		pl.config.Callbacks.Receive,
+	return pl.sendCallback(
+		receivedPayment,
+		account,
 		url.Values{
 			"id":             {payment.ID},
 			"from":           {payment.From},
@@ -301,29 +383,10 @@ func (pl *PaymentListener) process(payment horizon.Payment) error {
 			"transaction_id": {payment.TransactionHash},
 		},
 	)
-	if err != nil {
-		return errors.Wrap(err, "Error sending request to receive callback")
-	}
-
-	if resp.StatusCode != 200 {
-		defer resp.Body.Close()
-		body, err := ioutil.ReadAll(resp.Body)
-		if err != nil {
-			return errors.Wrap(err, "Error reading receive callback response")
-		}
-
-		pl.log.WithFields(logrus.Fields{
-			"status": resp.StatusCode,
-			"body":   string(body),
-		}).Error("Error response from receive callback")
-		return errors.New("Error response from receive callback")
-	}
-
-	return nil
 }
 
-func (pl *PaymentListener) isAssetAllowed(asset_type string, code string, issuer string) bool {
-	for _, asset := range pl.config.Assets {
+func isAssetAllowed(assets []config.Asset, asset_type string, code string, issuer string) bool {
+	for _, asset := range assets {
 		if asset.Code == code && asset.Issuer == issuer {
 			return true
 		}
@@ -339,6 +402,7 @@ func (pl *PaymentListener) isAssetAllowed(asset_type string, code string, issuer
 func (pl *PaymentListener) postForm(
 	url string,
 	form url.Values,
+	macKey string,
 ) (*http.Response, error) {
 
 	strbody := form.Encode()
@@ -349,9 +413,9 @@ func (pl *PaymentListener) postForm(
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	if pl.config.MACKey != "" {
+	if macKey != "" {
 		var rawMAC []byte
-		rawMAC, err = pl.getMAC(pl.config.MACKey, []byte(strbody))
+		rawMAC, err = pl.getMAC(macKey, []byte(strbody))
 		if err != nil {
 			return nil, errors.Wrap(err, "getMAC failed")
 		}
@@ -371,7 +435,7 @@ func (pl *PaymentListener) postForm(
 
 func (pl *PaymentListener) getMAC(key string, raw []byte) ([]byte, error) {
 
-	rawkey, err := strkey.Decode(strkey.VersionByteSeed, pl.config.MACKey)
+	rawkey, err := strkey.Decode(strkey.VersionByteSeed, key)
 	if err != nil {
 		return nil, errors.Wrap(err, "invalid MAC key")
 	}
@@ -0,0 +1,126 @@
+package listener
+
+import (
+	"testing"
+
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/services/bridge/internal/config"
+	"github.com/stellar/go/services/bridge/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const (
+	testIssuer = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	testSender = "GCSXHKIT7S7DSSMJOOMBDKMWUJVCUSCCC4EMXKQWB5XVQRAFJ3OFNBMD"
+	// accounts.receiving_account_id, without a callback
+	testDefaultAccount = "GAJ2HGPVZHCH6Q3HXQJMBZNIJFAHUZUGAEUQ5S7JPKDJGPVYOX54RBML"
+	testUSDAccount     = "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"
+	testEURAccount     = "GAJGR4O4O3FQ3JFJ6OHT6YQY4UFEGG3FGIH4DD7B7FTDJO57D3OHQYWC"
+	testUSDCallbackURL = "http://usd.example.com/receive"
+	testEURCallbackURL = "http://eur.example.com/receive"
+)
+
+// newTestReceivingAccountsListener returns a payment listener of three receiving accounts:
+// testUSDAccount and testEURAccount have their own callbacks and assets, testDefaultAccount
+// has no callback.
+func newTestReceivingAccountsListener() (*PaymentListener, *db.MockDatabase, *horizon.MockClient) {
+	pl, mockDatabase, mockHTTP := newTestPaymentListener(&config.Config{
+		Assets:   []config.Asset{{Code: "USD", Issuer: testIssuer}},
+		Accounts: config.Accounts{ReceivingAccountID: testDefaultAccount},
+		ReceivingAccounts: []config.ReceivingAccount{
+			{AccountID: testUSDAccount, Callback: testUSDCallbackURL},
+			{AccountID: testEURAccount, Callback: testEURCallbackURL, Assets: []config.Asset{{Code: "EUR", Issuer: testIssuer}}},
+		},
+	})
+	mockHTTP.On("POST", testUSDCallbackURL).ReturnString(200, "ok")
+	mockHTTP.On("POST", testEURCallbackURL).ReturnString(200, "ok")
+
+	mockHorizon := &horizon.MockClient{}
+	mockHorizon.On("LoadMemo", mock.AnythingOfType("*horizon.Payment")).Return(nil)
+	pl.horizon = mockHorizon
+	return pl, mockDatabase, mockHorizon
+}
+
+func testPayment(id, from, to, assetCode string) horizon.Payment {
+	return horizon.Payment{
+		ID:              id,
+		Type:            "payment",
+		PagingToken:     id,
+		From:            from,
+		To:              to,
+		AssetType:       "credit_alphanum4",
+		AssetCode:       assetCode,
+		AssetIssuer:     testIssuer,
+		Amount:          "10.0000000",
+		TransactionHash: "tx" + id,
+	}
+}
+
+func withPaymentStatus(status string) interface{} {
+	return mock.MatchedBy(func(payment *db.ReceivedPayment) bool {
+		return payment.Status == status
+	})
+}
+
+func TestOnPaymentReceivingAccounts(t *testing.T) {
+	pl, mockDatabase, _ := newTestReceivingAccountsListener()
+
+	// payment to testEURAccount is sent to its callback
+	payment := testPayment("1", testSender, testEURAccount, "EUR")
+	mockDatabase.On("GetReceivedPaymentByOperationID", "1").Return(nil, nil).Once()
+	mockDatabase.On("InsertReceivedPayment", withPaymentStatus("Processing...")).Return(nil).Once()
+	mockDatabase.On("GetCallbackDeliveryByReceivedPaymentID", int64(0)).Return(nil, nil).Once()
+	mockDatabase.On("InsertCallbackDelivery", mock.MatchedBy(func(delivery *db.CallbackDelivery) bool {
+		return delivery.AccountID == testEURAccount && delivery.URL == testEURCallbackURL
+	})).Return(nil).Once()
+	mockDatabase.On("UpdateCallbackDelivery", mock.AnythingOfType("*db.CallbackDelivery")).Return(nil).Once()
+	mockDatabase.On("UpdateReceivedPayment", withPaymentStatus("Success")).Return(nil).Once()
+	mockDatabase.On("SaveCursorValue", testEURAccount, "1").Return(nil).Once()
+	pl.onPayment(testEURAccount, payment)
+	mockDatabase.AssertExpectations(t)
+
+	// the asset of testUSDAccount defaults to assets
+	payment = testPayment("2", testSender, testUSDAccount, "EUR")
+	mockDatabase.On("GetReceivedPaymentByOperationID", "2").Return(nil, nil).Once()
+	mockDatabase.On("InsertReceivedPayment", withPaymentStatus("Processing...")).Return(nil).Once()
+	mockDatabase.On("UpdateReceivedPayment", withPaymentStatus("Asset not allowed")).Return(nil).Once()
+	mockDatabase.On("SaveCursorValue", testUSDAccount, "2").Return(nil).Once()
+	pl.onPayment(testUSDAccount, payment)
+	mockDatabase.AssertExpectations(t)
+}
+
+func TestOnPaymentBetweenReceivingAccounts(t *testing.T) {
+	pl, mockDatabase, _ := newTestReceivingAccountsListener()
+
+	// a payment to testEURAccount streamed for testUSDAccount is left to the stream of
+	// testEURAccount
+	payment := testPayment("1", testUSDAccount, testEURAccount, "EUR")
+	mockDatabase.On("SaveCursorValue", testUSDAccount, "1").Return(nil).Once()
+	pl.onPayment(testUSDAccount, payment)
+	mockDatabase.AssertExpectations(t)
+	mockDatabase.AssertNotCalled(t, "GetReceivedPaymentByOperationID", "1")
+
+	// testDefaultAccount has no callback and no stream: the payment is recorded as sent by
+	// testUSDAccount
+	payment = testPayment("2", testUSDAccount, testDefaultAccount, "USD")
+	mockDatabase.On("GetReceivedPaymentByOperationID", "2").Return(nil, nil).Once()
+	mockDatabase.On("InsertReceivedPayment", withPaymentStatus("Processing...")).Return(nil).Once()
+	mockDatabase.On("UpdateReceivedPayment", withPaymentStatus("Operation sent not received")).Return(nil).Once()
+	mockDatabase.On("SaveCursorValue", testUSDAccount, "2").Return(nil).Once()
+	pl.onPayment(testUSDAccount, payment)
+	mockDatabase.AssertExpectations(t)
+	mockDatabase.AssertNotCalled(t, "InsertCallbackDelivery", mock.Anything)
+}
+
+func TestReprocessPaymentWithoutCallback(t *testing.T) {
+	pl, mockDatabase, _ := newTestReceivingAccountsListener()
+
+	payment := testPayment("1", testSender, testDefaultAccount, "USD")
+	mockDatabase.On("GetReceivedPaymentByOperationID", "1").
+		Return(&db.ReceivedPayment{OperationID: "1", Status: "Operation sent not received"}, nil).Once()
+
+	err := pl.ReprocessPayment(payment, false)
+	assert.EqualError(t, err, "Receiving account has no receive callback")
+	mockDatabase.AssertNotCalled(t, "UpdateReceivedPayment", mock.Anything)
+}
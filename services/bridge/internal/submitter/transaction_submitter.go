@@ -3,7 +3,10 @@ package submitter
 import (
 	"database/sql"
 	"encoding/hex"
+	"encoding/json"
+	"net/http"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -21,8 +24,19 @@ import (
 type TransactionSubmitterInterface interface {
 	SubmitTransaction(paymentID *string, seed string, operation, memo interface{}) (response horizon.TransactionSuccess, err error)
 	SignAndSubmitRawTransaction(paymentID *string, seed string, tx *xdr.Transaction) (response horizon.TransactionSuccess, err error)
+	TransactionResult(sentTransaction *db.SentTransaction) (response horizon.TransactionSuccess, err error)
 }
 
+const (
+	// sendingTimeout is how long a transaction can be sending before it is resolved by
+	// ResolveSendingTransactions
+	sendingTimeout = 2 * time.Minute
+	// resolveInterval is how often ResolveSendingTransactions looks for transactions to resolve
+	resolveInterval = 30 * time.Second
+	// resolveBatch is the maximum number of transactions resolved every resolveInterval
+	resolveBatch = 100
+)
+
 // TransactionSubmitter submits transactions to Stellar Network
 type TransactionSubmitter struct {
 	Horizon       horizon.ClientInterface
@@ -114,68 +128,44 @@ func (ts *TransactionSubmitter) InitAccount(seed string) (err error) {
 // - update sequence number of the transaction to the current one,
 // - sign it,
 // - submit it to the network.
+//
+// If a transaction has already been sent for paymentID, its result is returned instead, see
+// TransactionResult.
 func (ts *TransactionSubmitter) SignAndSubmitRawTransaction(paymentID *string, seed string, tx *xdr.Transaction) (response horizon.TransactionSuccess, err error) {
-	account, err := ts.LoadAccount(seed)
-	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Error("Error loading account")
-		return
-	}
-
-	account.Mutex.Lock()
-	account.SequenceNumber++
-	tx.SeqNum = xdr.SequenceNumber(account.SequenceNumber)
-	account.Mutex.Unlock()
-
-	hash, err := shared.TransactionHash(tx, ts.Network.Passphrase)
-	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Error("Error calculating transaction hash")
-		return
-	}
-
-	sig, err := account.Keypair.SignDecorated(hash[:])
-	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Error("Error signing a transaction")
-		return
-	}
+	if paymentID != nil {
+		var sentTransaction *db.SentTransaction
+		sentTransaction, err = ts.Database.GetSentTransactionByPaymentID(*paymentID)
+		if err != nil {
+			ts.log.WithFields(logrus.Fields{"err": err}).Error("Error getting sent transaction")
+			return
+		}
 
-	envelopeXdr := xdr.TransactionEnvelope{
-		Tx:         *tx,
-		Signatures: []xdr.DecoratedSignature{sig},
+		if sentTransaction != nil {
+			ts.log.WithFields(logrus.Fields{"paymentID": *paymentID}).Info("Transaction with given ID already exists")
+			return ts.TransactionResult(sentTransaction)
+		}
 	}
 
-	txeB64, err := xdr.MarshalBase64(envelopeXdr)
+	account, err := ts.LoadAccount(seed)
 	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Error("Cannot encode transaction envelope")
+		ts.log.WithFields(logrus.Fields{"err": err}).Error("Error loading account")
 		return
 	}
 
-	transactionHashBytes, err := shared.TransactionHash(tx, ts.Network.Passphrase)
+	sentTransaction, err := ts.insertTransaction(account, paymentID, tx)
 	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Warn("Error calculating tx hash")
-		return
-	}
+		ts.log.WithFields(logrus.Fields{"err": err}).Error("Error inserting sent transaction")
 
-	nullPaymentID := sql.NullString{Valid: false}
-	if paymentID != nil {
-		nullPaymentID = sql.NullString{
-			String: *paymentID,
-			Valid:  true,
+		// A transaction may have been sent for the same payment ID in the meantime
+		if paymentID != nil {
+			existingTransaction, gerr := ts.Database.GetSentTransactionByPaymentID(*paymentID)
+			if gerr == nil && existingTransaction != nil {
+				return ts.TransactionResult(existingTransaction)
+			}
 		}
-	}
-
-	sentTransaction := &db.SentTransaction{
-		PaymentID:     nullPaymentID,
-		TransactionID: hex.EncodeToString(transactionHashBytes[:]),
-		Status:        db.SentTransactionStatusSending,
-		Source:        account.Keypair.Address(),
-		SubmittedAt:   ts.now(),
-		EnvelopeXdr:   txeB64,
-	}
-	err = ts.Database.InsertSentTransaction(sentTransaction)
-	if err != nil {
-		ts.log.WithFields(logrus.Fields{"err": err}).Error("Error inserting sent transaction")
 		return
 	}
+	txeB64 := sentTransaction.EnvelopeXdr
 
 	ts.log.WithFields(logrus.Fields{"tx": txeB64}).Info("Submitting transaction")
 
@@ -184,12 +174,15 @@ func (ts *TransactionSubmitter) SignAndSubmitRawTransaction(paymentID *string, s
 	if err == nil {
 		sentTransaction.Status = db.SentTransactionStatusSuccess
 		sentTransaction.Ledger = &response.Ledger
+		sentTransaction.ResultXdr = &response.Result
 		now := time.Now()
 		sentTransaction.SucceededAt = &now
 	} else {
 		var isHorizonError bool
 		herr, isHorizonError = err.(*horizon.Error)
-		if !isHorizonError {
+		// After any other error the transaction may still be included, it is left as sending
+		// for ResolveSendingTransactions
+		if !isHorizonError || !isTransactionFailed(herr) {
 			ts.log.WithFields(logrus.Fields{"err": err}).Error("Error submitting transaction ", err)
 			return
 		}
@@ -271,3 +264,222 @@ func (ts *TransactionSubmitter) SubmitTransaction(paymentID *string, seed string
 
 	return ts.SignAndSubmitRawTransaction(paymentID, seed, txBuilder.TX)
 }
+
+// TransactionResult returns the result of a transaction that has already been sent, as it was
+// returned by SignAndSubmitRawTransaction: the transaction for a successful one, a
+// transaction_failed horizon error for a failed one. A transaction that is still sending is
+// resolved with ResubmitTransaction first.
+func (ts *TransactionSubmitter) TransactionResult(sentTransaction *db.SentTransaction) (horizon.TransactionSuccess, error) {
+	if sentTransaction.Status == db.SentTransactionStatusSending {
+		err := ts.ResubmitTransaction(sentTransaction)
+		if err != nil {
+			return horizon.TransactionSuccess{}, err
+		}
+	}
+
+	if sentTransaction.Status != db.SentTransactionStatusSuccess {
+		return horizon.TransactionSuccess{}, failedTransactionError(sentTransaction)
+	}
+
+	response := horizon.TransactionSuccess{
+		Hash: sentTransaction.TransactionID,
+		Env:  sentTransaction.EnvelopeXdr,
+	}
+	if sentTransaction.Ledger != nil {
+		response.Ledger = *sentTransaction.Ledger
+	}
+	if sentTransaction.ResultXdr != nil {
+		response.Result = *sentTransaction.ResultXdr
+	}
+	return response, nil
+}
+
+// insertTransaction sets the next sequence number of account in tx, signs it and inserts it as
+// sending. The sequence number is only used once the transaction has been inserted.
+func (ts *TransactionSubmitter) insertTransaction(account *Account, paymentID *string, tx *xdr.Transaction) (*db.SentTransaction, error) {
+	account.Mutex.Lock()
+	defer account.Mutex.Unlock()
+
+	tx.SeqNum = xdr.SequenceNumber(account.SequenceNumber + 1)
+
+	hash, err := shared.TransactionHash(tx, ts.Network.Passphrase)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error calculating transaction hash")
+	}
+
+	sig, err := account.Keypair.SignDecorated(hash[:])
+	if err != nil {
+		return nil, errors.Wrap(err, "Error signing a transaction")
+	}
+
+	envelopeXdr := xdr.TransactionEnvelope{
+		Tx:         *tx,
+		Signatures: []xdr.DecoratedSignature{sig},
+	}
+
+	txeB64, err := xdr.MarshalBase64(envelopeXdr)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot encode transaction envelope")
+	}
+
+	nullPaymentID := sql.NullString{Valid: false}
+	if paymentID != nil {
+		nullPaymentID = sql.NullString{
+			String: *paymentID,
+			Valid:  true,
+		}
+	}
+
+	sentTransaction := &db.SentTransaction{
+		PaymentID:     nullPaymentID,
+		TransactionID: hex.EncodeToString(hash[:]),
+		Status:        db.SentTransactionStatusSending,
+		Source:        account.Keypair.Address(),
+		SubmittedAt:   ts.now(),
+		EnvelopeXdr:   txeB64,
+	}
+	err = ts.Database.InsertSentTransaction(sentTransaction)
+	if err != nil {
+		return nil, err
+	}
+
+	account.SequenceNumber++
+	return sentTransaction, nil
+}
+
+// ResubmitTransaction resolves a transaction that is still sending, for instance because the
+// bridge server stopped while submitting it. The transaction is looked up in horizon and, if it
+// is not there, its envelope is submitted again: as it is the same envelope, with the same
+// sequence number, the payment cannot be made twice. The transaction is left as sending if
+// horizon cannot be reached.
+func (ts *TransactionSubmitter) ResubmitTransaction(sentTransaction *db.SentTransaction) error {
+	found, err := ts.loadSentTransaction(sentTransaction)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		ts.log.WithFields(logrus.Fields{"tx": sentTransaction.EnvelopeXdr}).Info("Resubmitting transaction")
+
+		var response horizon.TransactionSuccess
+		response, err = ts.Horizon.SubmitTransaction(sentTransaction.EnvelopeXdr)
+		if err == nil {
+			sentTransaction.Status = db.SentTransactionStatusSuccess
+			sentTransaction.Ledger = &response.Ledger
+			sentTransaction.ResultXdr = &response.Result
+			now := ts.now()
+			sentTransaction.SucceededAt = &now
+		} else {
+			herr, isHorizonError := err.(*horizon.Error)
+			if !isHorizonError {
+				return errors.Wrap(err, "Error resubmitting transaction")
+			}
+
+			// tx_bad_seq: the transaction may have been included since it was looked up
+			codes, rerr := herr.ResultCodes()
+			if rerr == nil && codes.TransactionCode == "tx_bad_seq" {
+				found, err = ts.loadSentTransaction(sentTransaction)
+				if err != nil {
+					return err
+				}
+			}
+
+			if !found {
+				if !isTransactionFailed(herr) {
+					return errors.Wrap(err, "Error resubmitting transaction")
+				}
+
+				result, rerr := herr.ResultString()
+				if rerr != nil {
+					result = errors.Wrap(rerr, "Error getting tx result").Error()
+				}
+				sentTransaction.Status = db.SentTransactionStatusFailure
+				sentTransaction.ResultXdr = &result
+			}
+		}
+	}
+
+	err = ts.Database.UpdateSentTransaction(sentTransaction)
+	if err != nil {
+		return errors.Wrap(err, "Error updating sent transaction")
+	}
+
+	return nil
+}
+
+// ResolveSendingTransactions resolves the transactions that have been sending for longer than
+// sendingTimeout with ResubmitTransaction, until the process exits.
+func (ts *TransactionSubmitter) ResolveSendingTransactions() {
+	for {
+		transactions, err := ts.Database.GetSentTransactionsByStatus(
+			db.SentTransactionStatusSending,
+			ts.now().Add(-sendingTimeout),
+			resolveBatch,
+		)
+		if err != nil {
+			ts.log.WithFields(logrus.Fields{"err": err}).Error("Error getting sending transactions")
+		}
+
+		for _, transaction := range transactions {
+			err = ts.ResubmitTransaction(transaction)
+			if err != nil {
+				ts.log.WithFields(logrus.Fields{"err": err, "id": transaction.TransactionID}).Error("Error resolving transaction")
+			}
+		}
+
+		time.Sleep(resolveInterval)
+	}
+}
+
+// loadSentTransaction looks up a transaction in horizon and, if it is found, updates its
+// status. It returns false if horizon does not know the transaction.
+func (ts *TransactionSubmitter) loadSentTransaction(sentTransaction *db.SentTransaction) (bool, error) {
+	transaction, err := ts.Horizon.LoadTransaction(sentTransaction.TransactionID)
+	if err != nil {
+		if herr, ok := err.(*horizon.Error); ok && herr.Problem.Status == http.StatusNotFound {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "Error loading transaction")
+	}
+
+	if transaction.Successful {
+		sentTransaction.Status = db.SentTransactionStatusSuccess
+		sentTransaction.SucceededAt = &transaction.LedgerCloseTime
+	} else {
+		sentTransaction.Status = db.SentTransactionStatusFailure
+	}
+	sentTransaction.Ledger = &transaction.Ledger
+	sentTransaction.ResultXdr = &transaction.ResultXdr
+	return true, nil
+}
+
+// isTransactionFailed returns true if herr is the error of a transaction rejected by
+// stellar-core: a transaction_failed problem with result codes. After any other error, like a
+// timeout, the transaction may still be included in a ledger.
+func isTransactionFailed(herr *horizon.Error) bool {
+	// horizon sends the full URL of the problem type
+	if !strings.HasSuffix(herr.Problem.Type, "transaction_failed") {
+		return false
+	}
+	_, err := herr.ResultCodes()
+	return err == nil
+}
+
+// failedTransactionError returns the horizon error of a failed transaction.
+func failedTransactionError(sentTransaction *db.SentTransaction) *horizon.Error {
+	extras := map[string]json.RawMessage{}
+	extras["envelope_xdr"], _ = json.Marshal(sentTransaction.EnvelopeXdr)
+	if sentTransaction.ResultXdr != nil {
+		extras["result_xdr"], _ = json.Marshal(*sentTransaction.ResultXdr)
+	}
+
+	return &horizon.Error{
+		Problem: horizon.Problem{
+			Type:   "transaction_failed",
+			Title:  "Transaction Failed",
+			Status: http.StatusBadRequest,
+			Detail: "The transaction has already been sent for this payment ID and failed.",
+			Extras: extras,
+		},
+	}
+}
@@ -0,0 +1,128 @@
+package submitter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/build"
+	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/services/bridge/internal/db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSubmitter(t *testing.T) (*TransactionSubmitter, *horizon.MockClient, *db.MockDatabase, string) {
+	mockHorizon := &horizon.MockClient{}
+	mockDatabase := &db.MockDatabase{}
+	ts := NewTransactionSubmitter(mockHorizon, mockDatabase, network.TestNetworkPassphrase, time.Now)
+
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	ts.Accounts[kp.Seed()] = &Account{Seed: kp.Seed(), Keypair: kp, SequenceNumber: 100}
+	return &ts, mockHorizon, mockDatabase, kp.Seed()
+}
+
+func submitTestPayment(ts *TransactionSubmitter, paymentID *string, seed string) (horizon.TransactionSuccess, error) {
+	operation := build.CreateAccount(
+		build.Destination{"GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z"},
+		build.NativeAmount{"10"},
+	)
+	return ts.SubmitTransaction(paymentID, seed, operation, nil)
+}
+
+func withStatus(status db.SentTransactionStatus) interface{} {
+	return mock.MatchedBy(func(transaction *db.SentTransaction) bool {
+		return transaction.Status == status
+	})
+}
+
+func TestSubmitTransactionDuplicateID(t *testing.T) {
+	ts, mockHorizon, mockDatabase, seed := newTestSubmitter(t)
+	paymentID := "payment-1"
+
+	existing := &db.SentTransaction{
+		TransactionID: "existing",
+		Status:        db.SentTransactionStatusSuccess,
+		EnvelopeXdr:   "envelope",
+	}
+	mockDatabase.On("GetSentTransactionByPaymentID", paymentID).Return(nil, nil).Once()
+	mockDatabase.On("InsertSentTransaction", mock.AnythingOfType("*db.SentTransaction")).
+		Return(errors.New("duplicate key value violates unique constraint")).Once()
+	mockDatabase.On("GetSentTransactionByPaymentID", paymentID).Return(existing, nil).Once()
+
+	response, err := submitTestPayment(ts, &paymentID, seed)
+	require.NoError(t, err)
+	assert.Equal(t, "existing", response.Hash)
+	assert.Equal(t, uint64(100), ts.Accounts[seed].SequenceNumber, "sequence number must not be consumed")
+
+	mockHorizon.AssertNotCalled(t, "SubmitTransaction", mock.Anything)
+	mockDatabase.AssertExpectations(t)
+}
+
+func TestSubmitTransactionTimeout(t *testing.T) {
+	ts, mockHorizon, mockDatabase, seed := newTestSubmitter(t)
+
+	timeout := &horizon.Error{Problem: horizon.Problem{Type: "timeout", Status: http.StatusGatewayTimeout}}
+	mockDatabase.On("InsertSentTransaction", withStatus(db.SentTransactionStatusSending)).Return(nil).Once()
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).
+		Return(horizon.TransactionSuccess{}, timeout).Once()
+
+	_, err := submitTestPayment(ts, nil, seed)
+	assert.Equal(t, timeout, err)
+	assert.Equal(t, uint64(101), ts.Accounts[seed].SequenceNumber)
+
+	// the transaction is left as sending
+	mockDatabase.AssertNotCalled(t, "UpdateSentTransaction", mock.Anything)
+	mockHorizon.AssertExpectations(t)
+	mockDatabase.AssertExpectations(t)
+}
+
+func TestSubmitTransactionFailed(t *testing.T) {
+	ts, mockHorizon, mockDatabase, seed := newTestSubmitter(t)
+
+	failed := &horizon.Error{Problem: horizon.Problem{
+		Type:   "https://stellar.org/horizon-errors/transaction_failed",
+		Status: http.StatusBadRequest,
+		Extras: map[string]json.RawMessage{
+			"result_codes": json.RawMessage(`{"transaction":"tx_failed","operations":["op_underfunded"]}`),
+			"result_xdr":   json.RawMessage(`"AAAAAAAAAGT/////AAAAAQAAAAAAAAAB/////gAAAAA="`),
+		},
+	}}
+	mockDatabase.On("InsertSentTransaction", withStatus(db.SentTransactionStatusSending)).Return(nil).Once()
+	mockHorizon.On("SubmitTransaction", mock.AnythingOfType("string")).
+		Return(horizon.TransactionSuccess{}, failed).Once()
+	mockDatabase.On("UpdateSentTransaction", withStatus(db.SentTransactionStatusFailure)).Return(nil).Once()
+
+	_, err := submitTestPayment(ts, nil, seed)
+	assert.Equal(t, failed, err)
+
+	mockHorizon.AssertExpectations(t)
+	mockDatabase.AssertExpectations(t)
+}
+
+func TestResubmitTransactionTimeout(t *testing.T) {
+	ts, mockHorizon, mockDatabase, _ := newTestSubmitter(t)
+
+	sentTransaction := &db.SentTransaction{
+		TransactionID: "sending",
+		Status:        db.SentTransactionStatusSending,
+		EnvelopeXdr:   "envelope",
+	}
+	notFound := &horizon.Error{Problem: horizon.Problem{Status: http.StatusNotFound}}
+	timeout := &horizon.Error{Problem: horizon.Problem{Type: "timeout", Status: http.StatusGatewayTimeout}}
+	mockHorizon.On("LoadTransaction", "sending").Return(horizon.Transaction{}, notFound).Once()
+	mockHorizon.On("SubmitTransaction", "envelope").Return(horizon.TransactionSuccess{}, timeout).Once()
+
+	err := ts.ResubmitTransaction(sentTransaction)
+	assert.Error(t, err)
+	assert.Equal(t, db.SentTransactionStatusSending, sentTransaction.Status)
+
+	mockDatabase.AssertNotCalled(t, "UpdateSentTransaction", mock.Anything)
+	mockHorizon.AssertExpectations(t)
+}
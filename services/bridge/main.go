@@ -169,17 +169,25 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 		}
 	}
 
+	if config.Database != nil {
+		go ts.ResolveSendingTransactions()
+	}
+
 	log.Print("TransactionSubmitter created")
 
 	log.Print("Creating and starting PaymentListener")
 
 	var paymentListener listener.PaymentListener
 
-	if config.Accounts.ReceivingAccountID == "" {
-		log.Warning("No accounts.receiving_account_id param. Skipping...")
-	} else if config.Callbacks.Receive == "" {
+	if len(config.GetReceivingAccounts()) == 0 {
+		log.Warning("No accounts.receiving_account_id or receiving_accounts param. Skipping...")
+	} else if len(config.GetListenedAccounts()) == 0 {
 		log.Warning("No callbacks.receive param. Skipping...")
 	} else {
+		if config.Accounts.ReceivingAccountID != "" && config.Callbacks.Receive == "" {
+			log.Warning("No callbacks.receive param. Not listening for payments to accounts.receiving_account_id")
+		}
+
 		paymentListener, err = listener.NewPaymentListener(&config, &database, &h, time.Now)
 		if err != nil {
 			return
@@ -257,6 +265,9 @@ func (a *App) Serve() {
 	mux.Get("/admin/received-payments", a.requestHandler.AdminReceivedPayments)
 	mux.Get("/admin/received-payments/{id}", a.requestHandler.AdminReceivedPayment)
 	mux.Get("/admin/sent-transactions", a.requestHandler.AdminSentTransactions)
+	mux.Get("/admin/callback-deliveries", a.requestHandler.AdminCallbackDeliveries)
+	mux.Post("/admin/callback-deliveries/{id}/replay", a.requestHandler.AdminReplayCallbackDelivery)
+	mux.Get("/admin/metrics", a.requestHandler.AdminMetrics)
 
 	supportHttp.Run(supportHttp.Config{
 		ListenAddr: fmt.Sprintf(":%d", *a.config.Port),
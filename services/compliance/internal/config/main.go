@@ -18,6 +18,7 @@ type Config struct {
 	Database          Database      `valid:"required"`
 	Keys              Keys          `valid:"required" toml:"keys"`
 	Callbacks         Callbacks     `valid:"optional" toml:"callbacks"`
+	Strategies        Strategies    `valid:"optional" toml:"strategies"`
 	TLS               *config.TLS   `valid:"optional"`
 	TxStatusAuth      *TxStatusAuth `valid:"optional" toml:"tx_status_auth"`
 }
@@ -40,6 +41,39 @@ type Callbacks struct {
 	TxStatus  string `valid:"optional" toml:"tx_status"`
 }
 
+// Strategy names that can be used in `strategies.sanctions`
+const (
+	StrategyCallback      = "callback"
+	StrategyAddressList   = "address_list"
+	StrategySanctionsList = "sanctions_list"
+)
+
+// Strategies contains values of `strategies` config group
+type Strategies struct {
+	// Sanctions is the list of strategies performing the sanctions check, in order. When empty
+	// only the `callbacks.sanctions` callback is used.
+	Sanctions []string `valid:"optional" toml:"sanctions"`
+	// Precedence is one of `deny_overrides` (default), `allow_overrides` or `first_decision`.
+	Precedence string `valid:"optional" toml:"precedence"`
+	// Default is the status used when no strategy makes a decision: `ok` (default), `pending`
+	// or `denied`.
+	Default       string        `valid:"optional" toml:"default"`
+	AddressList   AddressList   `valid:"optional" toml:"address_list"`
+	SanctionsList SanctionsList `valid:"optional" toml:"sanctions_list"`
+}
+
+// AddressList contains values of `strategies.address_list` config group
+type AddressList struct {
+	Allow []string `valid:"optional" toml:"allow"`
+	Deny  []string `valid:"optional" toml:"deny"`
+}
+
+// SanctionsList contains values of `strategies.sanctions_list` config group
+type SanctionsList struct {
+	Path           string  `valid:"optional" toml:"path"`
+	MatchThreshold float64 `valid:"optional" toml:"match_threshold"`
+}
+
 // Database contains values of `database` config group
 type Database struct {
 	Type string `valid:"required"`
@@ -113,6 +147,11 @@ func (c *Config) Validate() (err error) {
 		}
 	}
 
+	err = c.Strategies.validate()
+	if err != nil {
+		return
+	}
+
 	if c.Callbacks.AskUser != "" {
 		_, err = url.Parse(c.Callbacks.AskUser)
 		if err != nil {
@@ -131,3 +170,35 @@ func (c *Config) Validate() (err error) {
 
 	return
 }
+
+func (s *Strategies) validate() error {
+	for _, strategy := range s.Sanctions {
+		switch strategy {
+		case StrategyCallback, StrategyAddressList:
+		case StrategySanctionsList:
+			if s.SanctionsList.Path == "" {
+				return errors.New("strategies.sanctions_list.path param is required when using sanctions_list strategy")
+			}
+		default:
+			return errors.New("Invalid strategy in strategies.sanctions param: " + strategy)
+		}
+	}
+
+	switch s.Precedence {
+	case "", "deny_overrides", "allow_overrides", "first_decision":
+	default:
+		return errors.New("Invalid strategies.precedence param")
+	}
+
+	switch s.Default {
+	case "", "ok", "pending", "denied":
+	default:
+		return errors.New("Invalid strategies.default param")
+	}
+
+	if s.SanctionsList.MatchThreshold < 0 || s.SanctionsList.MatchThreshold > 1 {
+		return errors.New("strategies.sanctions_list.match_threshold param must be between 0 and 1")
+	}
+
+	return nil
+}
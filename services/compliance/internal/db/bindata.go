@@ -4,6 +4,7 @@
 // migrations/01_init.sql
 // migrations/02_auth_data.sql
 // migrations/03_table_names.sql
+// migrations/04_auth_request.sql
 // DO NOT EDIT!
 
 package db
@@ -151,6 +152,26 @@ func migrations03_table_namesSql() (*asset, error) {
 	return a, nil
 }
 
+var _migrations04_auth_requestSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x85\x53\xc1\x6e\x82\x40\x10\xbd\xf3\x15\x73\xd4\x54\x13\x6d\x6a\x2f\x9e\x68\xa1\x89\x29\x05\x43\x30\xa9\xa7\xcd\x0a\x53\xdc\xb4\x2c\x74\x77\xb0\xf6\xef\xbb\x58\x34\x02\x2a\xc7\x9d\xf7\xe6\xcd\xec\xdb\xb7\xe3\x31\xdc\x65\x22\x55\x9c\x10\x56\x85\xf5\x1c\xba\x76\xe4\x42\x64\x3f\x79\x2e\xf0\x92\xb6\x4c\xe1\x77\x89\x9a\x60\x60\x01\x88\x04\x36\x22\xd5\xa8\x04\xff\x1a\x99\xb3\x46\x99\xa0\x82\x1d\x57\xf1\x96\xab\xc1\xfd\x6c\x36\x04\x3f\x88\xc0\x5f\x79\x5e\x85\x2b\x8c\x51\xec\x6e\x31\x32\xcc\xf2\x13\xfa\xf8\xd0\x04\x49\x71\xa9\x79\x4c\x22\x97\xcc\x8c\xbe\x46\x4b\x38\x71\x20\xdc\x53\xa3\xaa\x45\x2a\x39\x95\x0a\x2f\x40\x64\x00\x7d\xd2\x9b\x4e\x5a\x63\xf7\xac\x87\x21\xe4\x47\xde\xc7\x29\x8c\x37\x42\xa6\x86\x4b\x98\x1a\x0b\x8e\x18\x38\xee\x8b\xbd\xf2\x22\x98\x54\x2c\x54\x2a\x57\xf5\x86\xe7\xe0\x51\x25\xde\x62\xfc\xa9\xaf\x8b\x4c\xcf\x6c\x4e\x18\x27\x20\x91\x99\xd7\xe2\x59\xd1\x58\xa6\x2c\x8c\x49\xb7\x08\xd2\x6c\xc0\x0e\xc3\x5a\x9c\xee\x52\x86\xbd\x0c\x17\x6f\x76\xb8\x86\x57\x77\x0d\x03\x91\x0c\xad\xe1\xdc\x3a\x46\x67\xe1\x3b\xee\x7b\x23\x3a\x6c\xf3\xcb\xea\xa4\x04\x7e\x2b\x54\xff\x75\xd3\x7e\xb3\xfb\x90\x92\x4e\x6f\x55\xed\xeb\x6c\x45\xa8\xa3\xd1\xc4\xfb\xd4\xea\x27\xef\xde\xe2\x50\x1f\x35\x5d\xac\x3c\x19\x9f\xfd\x2e\x27\xff\x91\x96\x13\x06\xcb\x0b\xbf\x6b\x6e\xfd\x01\x9a\x57\xad\x5a\x88\x03\x00\x00")
+
+func migrations04_auth_requestSqlBytes() ([]byte, error) {
+	return bindataRead(
+		_migrations04_auth_requestSql,
+		"migrations/04_auth_request.sql",
+	)
+}
+
+func migrations04_auth_requestSql() (*asset, error) {
+	bytes, err := migrations04_auth_requestSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "migrations/04_auth_request.sql", size: 904, mode: os.FileMode(420), modTime: time.Unix(1792281600, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -203,10 +224,11 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
-	"latest.sql":                    latestSql,
-	"migrations/01_init.sql":        migrations01_initSql,
-	"migrations/02_auth_data.sql":   migrations02_auth_dataSql,
-	"migrations/03_table_names.sql": migrations03_table_namesSql,
+	"latest.sql":                     latestSql,
+	"migrations/01_init.sql":         migrations01_initSql,
+	"migrations/02_auth_data.sql":    migrations02_auth_dataSql,
+	"migrations/03_table_names.sql":  migrations03_table_namesSql,
+	"migrations/04_auth_request.sql": migrations04_auth_requestSql,
 }
 
 // AssetDir returns the file names below a certain
@@ -252,9 +274,10 @@ type bintree struct {
 var _bintree = &bintree{nil, map[string]*bintree{
 	"latest.sql": &bintree{latestSql, map[string]*bintree{}},
 	"migrations": &bintree{nil, map[string]*bintree{
-		"01_init.sql":        &bintree{migrations01_initSql, map[string]*bintree{}},
-		"02_auth_data.sql":   &bintree{migrations02_auth_dataSql, map[string]*bintree{}},
-		"03_table_names.sql": &bintree{migrations03_table_namesSql, map[string]*bintree{}},
+		"01_init.sql":         &bintree{migrations01_initSql, map[string]*bintree{}},
+		"02_auth_data.sql":    &bintree{migrations02_auth_dataSql, map[string]*bintree{}},
+		"03_table_names.sql":  &bintree{migrations03_table_namesSql, map[string]*bintree{}},
+		"04_auth_request.sql": &bintree{migrations04_auth_requestSql, map[string]*bintree{}},
 	}},
 }}
 
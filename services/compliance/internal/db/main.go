@@ -30,6 +30,13 @@ type Database interface {
 
 	InsertAuthData(authData *AuthData) error
 	GetAuthData(requestID string) (*AuthData, error)
+
+	InsertAuthRequest(request *AuthRequest) error
+	UpdateAuthRequest(request *AuthRequest) error
+	GetAuthRequestByID(id int64) (*AuthRequest, error)
+	GetAuthRequests(filter AuthRequestFilter, page, limit uint64) ([]*AuthRequest, error)
+	GetDueAuthRequests(now time.Time, limit uint64) ([]*AuthRequest, error)
+	SupersedePendingAuthRequests(transactionID string) error
 }
 
 type PostgresDatabase struct {
@@ -72,3 +79,55 @@ type AuthData struct {
 	Domain    string `db:"domain"`
 	AuthData  string `db:"auth_data"`
 }
+
+// AuthRequestStatus is the outcome of an auth request
+type AuthRequestStatus string
+
+const (
+	// AuthRequestStatusOk means the transaction and access to user data were allowed
+	AuthRequestStatusOk AuthRequestStatus = "ok"
+	// AuthRequestStatusDenied means the transaction or access to user data was denied
+	AuthRequestStatusDenied AuthRequestStatus = "denied"
+	// AuthRequestStatusPending means the decision will be made later
+	AuthRequestStatusPending AuthRequestStatus = "pending"
+	// AuthRequestStatusError means a callback responded with an error or could not be reached
+	AuthRequestStatusError AuthRequestStatus = "error"
+	// AuthRequestStatusInvalid means the request was rejected before making a decision
+	AuthRequestStatusInvalid AuthRequestStatus = "invalid"
+	// AuthRequestStatusSuperseded means the request was pending when the sender sent it again
+	AuthRequestStatusSuperseded AuthRequestStatus = "superseded"
+)
+
+// AuthRequest represents an auth request received by the auth endpoint and its outcome
+type AuthRequest struct {
+	ID            int64             `db:"id" json:"id"`
+	Sender        string            `db:"sender" json:"sender"`
+	Receiver      string            `db:"receiver" json:"receiver"` // route of the attachment
+	Memo          string            `db:"memo" json:"memo"`         // base64 encoded memo hash
+	TransactionID string            `db:"transaction_id" json:"transaction_id"`
+	Data          string            `db:"data" json:"data"`
+	Signature     string            `db:"signature" json:"signature"`
+	Status        AuthRequestStatus `db:"status" json:"status"`
+	TxStatus      string            `db:"tx_status" json:"tx_status"`
+	InfoStatus    string            `db:"info_status" json:"info_status"`
+	Pending       int32             `db:"pending" json:"pending"`
+	Error         *string           `db:"error" json:"error"`
+	// Checks is the number of times a decision was made on the request
+	Checks      int32      `db:"checks" json:"checks"`
+	ReceivedAt  time.Time  `db:"received_at" json:"received_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	NextCheckAt *time.Time `db:"next_check_at" json:"next_check_at"` // when a pending decision is made again
+}
+
+// AuthRequestFilter filters the auth requests returned by GetAuthRequests. Empty fields are
+// ignored.
+type AuthRequestFilter struct {
+	Status        AuthRequestStatus
+	Sender        string
+	Receiver      string
+	Memo          string
+	TransactionID string
+	// ReceivedAfter and ReceivedBefore limit the requests to the ones received in a time range
+	ReceivedAfter  *time.Time
+	ReceivedBefore *time.Time
+}
@@ -0,0 +1,29 @@
+-- +migrate Up
+CREATE TABLE auth_request (
+  id bigserial,
+  sender varchar(255) NOT NULL,
+  receiver varchar(255) NOT NULL,
+  memo varchar(64) NOT NULL,
+  transaction_id varchar(64) NOT NULL,
+  data text NOT NULL,
+  signature text NOT NULL,
+  status varchar(10) NOT NULL,
+  tx_status varchar(10) NOT NULL,
+  info_status varchar(10) NOT NULL,
+  pending integer NOT NULL DEFAULT 0,
+  error text NULL DEFAULT NULL,
+  checks integer NOT NULL DEFAULT 1,
+  received_at timestamp NOT NULL,
+  updated_at timestamp NOT NULL,
+  next_check_at timestamp NULL DEFAULT NULL,
+
+  PRIMARY KEY (id)
+);
+
+CREATE INDEX auth_request_by_sender ON auth_request (sender);
+CREATE INDEX auth_request_by_memo ON auth_request (memo);
+CREATE INDEX auth_request_by_transaction_id ON auth_request (transaction_id);
+CREATE INDEX auth_request_by_status ON auth_request (status, next_check_at);
+
+-- +migrate Down
+DROP TABLE auth_request;
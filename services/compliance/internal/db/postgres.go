@@ -2,7 +2,9 @@ package db
 
 import (
 	"database/sql"
+	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/stellar/go/support/db"
 	"github.com/stellar/go/support/errors"
 )
@@ -12,6 +14,7 @@ const (
 	allowedFITableName             = "allowed_fi"
 	allowedUserTableName           = "allowed_user"
 	authDataTableName              = "auth_data"
+	authRequestTableName           = "auth_request"
 )
 
 func (d *PostgresDatabase) Open(dsn string) error {
@@ -168,3 +171,167 @@ func (d *PostgresDatabase) GetAuthData(requestID string) (*AuthData, error) {
 
 	return &authData, nil
 }
+
+// InsertAuthRequest inserts a new auth request into DB and sets its ID.
+func (d *PostgresDatabase) InsertAuthRequest(request *AuthRequest) error {
+	insert := sq.Insert(authRequestTableName).SetMap(map[string]interface{}{
+		"sender":         request.Sender,
+		"receiver":       request.Receiver,
+		"memo":           request.Memo,
+		"transaction_id": request.TransactionID,
+		"data":           request.Data,
+		"signature":      request.Signature,
+		"status":         request.Status,
+		"tx_status":      request.TxStatus,
+		"info_status":    request.InfoStatus,
+		"pending":        request.Pending,
+		"error":          request.Error,
+		"checks":         request.Checks,
+		"received_at":    request.ReceivedAt,
+		"updated_at":     request.UpdatedAt,
+		"next_check_at":  request.NextCheckAt,
+	}).Suffix("RETURNING id")
+
+	err := d.session.Get(&request.ID, insert)
+	if err != nil {
+		return errors.Wrap(err, "Error inserting auth request")
+	}
+
+	return nil
+}
+
+// UpdateAuthRequest updates an auth request in DB.
+func (d *PostgresDatabase) UpdateAuthRequest(request *AuthRequest) error {
+	if request.ID == 0 {
+		return errors.New("ID equals 0")
+	}
+
+	authRequestTable := d.getTable(authRequestTableName, nil)
+	_, err := authRequestTable.Update(nil, map[string]interface{}{"id": request.ID}).
+		SetStruct(request, []string{"id"}).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "Error updating auth request")
+	}
+
+	return nil
+}
+
+// GetAuthRequestByID returns an auth request by its ID
+func (d *PostgresDatabase) GetAuthRequestByID(id int64) (*AuthRequest, error) {
+	authRequestTable := d.getTable(authRequestTableName, nil)
+	var request AuthRequest
+	err := authRequestTable.Get(&request, map[string]interface{}{"id": id}).Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return nil, nil
+		default:
+			return nil, errors.Wrap(err, "Error getting auth request by ID")
+		}
+	}
+
+	return &request, nil
+}
+
+// GetAuthRequests returns the auth requests matching filter, newest first
+func (d *PostgresDatabase) GetAuthRequests(filter AuthRequestFilter, page, limit uint64) ([]*AuthRequest, error) {
+	authRequestTable := d.getTable(authRequestTableName, nil)
+	requests := []*AuthRequest{}
+
+	if page == 0 {
+		page = 1
+	}
+
+	offset := (page - 1) * limit
+
+	where := authRequestsWhere(filter)
+
+	err := authRequestTable.Select(&requests, where).Limit(limit).Offset(offset).OrderBy("id desc").Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return requests, nil
+		default:
+			return requests, errors.Wrap(err, "Error getting auth requests")
+		}
+	}
+
+	return requests, nil
+}
+
+// authRequestsWhere returns the predicate of the auth requests matching filter, "1=1" when the
+// filter is empty.
+func authRequestsWhere(filter AuthRequestFilter) interface{} {
+	where := sq.And{}
+	eq := sq.Eq{}
+	if filter.Status != "" {
+		eq["status"] = filter.Status
+	}
+	if filter.Sender != "" {
+		eq["sender"] = filter.Sender
+	}
+	if filter.Receiver != "" {
+		eq["receiver"] = filter.Receiver
+	}
+	if filter.Memo != "" {
+		eq["memo"] = filter.Memo
+	}
+	if filter.TransactionID != "" {
+		eq["transaction_id"] = filter.TransactionID
+	}
+	if len(eq) > 0 {
+		where = append(where, eq)
+	}
+	if filter.ReceivedAfter != nil {
+		where = append(where, sq.GtOrEq{"received_at": *filter.ReceivedAfter})
+	}
+	if filter.ReceivedBefore != nil {
+		where = append(where, sq.Lt{"received_at": *filter.ReceivedBefore})
+	}
+
+	if len(where) == 0 {
+		return "1=1"
+	}
+	return where
+}
+
+// GetDueAuthRequests returns pending auth requests whose decision should be made again at now
+func (d *PostgresDatabase) GetDueAuthRequests(now time.Time, limit uint64) ([]*AuthRequest, error) {
+	authRequestTable := d.getTable(authRequestTableName, nil)
+	requests := []*AuthRequest{}
+
+	err := authRequestTable.Select(
+		&requests,
+		"status = ? AND next_check_at <= ?",
+		AuthRequestStatusPending,
+		now,
+	).Limit(limit).OrderBy("next_check_at asc").Exec()
+	if err != nil {
+		switch errors.Cause(err) {
+		case sql.ErrNoRows:
+			return requests, nil
+		default:
+			return requests, errors.Wrap(err, "Error getting due auth requests")
+		}
+	}
+
+	return requests, nil
+}
+
+// SupersedePendingAuthRequests marks the pending auth requests of a transaction as superseded
+func (d *PostgresDatabase) SupersedePendingAuthRequests(transactionID string) error {
+	authRequestTable := d.getTable(authRequestTableName, nil)
+	_, err := authRequestTable.Update(nil, map[string]interface{}{
+		"transaction_id": transactionID,
+		"status":         AuthRequestStatusPending,
+	}).
+		Set("status", AuthRequestStatusSuperseded).
+		Set("next_check_at", nil).
+		Exec()
+	if err != nil {
+		return errors.Wrap(err, "Error superseding pending auth requests")
+	}
+
+	return nil
+}
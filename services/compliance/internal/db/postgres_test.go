@@ -0,0 +1,38 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthRequestsWhere(t *testing.T) {
+	receivedAfter := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name   string
+		filter AuthRequestFilter
+		sql    string
+		args   []interface{}
+	}{
+		{"unfiltered", AuthRequestFilter{}, "SELECT id FROM auth_requests WHERE 1=1 ORDER BY id desc", nil},
+		{"status", AuthRequestFilter{Status: "pending"}, "SELECT id FROM auth_requests WHERE (status = $1) ORDER BY id desc", []interface{}{AuthRequestStatus("pending")}},
+		{"received after", AuthRequestFilter{ReceivedAfter: &receivedAfter}, "SELECT id FROM auth_requests WHERE (received_at >= $1) ORDER BY id desc", []interface{}{receivedAfter}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql, args, err := sq.Select("id").From("auth_requests").
+				Where(authRequestsWhere(tc.filter)).
+				OrderBy("id desc").
+				PlaceholderFormat(sq.Dollar).
+				ToSql()
+			require.NoError(t, err)
+			assert.Equal(t, tc.sql, sql)
+			assert.Equal(t, tc.args, args)
+		})
+	}
+}
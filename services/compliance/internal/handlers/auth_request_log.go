@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stellar/go/protocols/compliance"
+	"github.com/stellar/go/services/compliance/internal/db"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	// defaultPending is the number of seconds after which a pending decision is made again when
+	// the callback does not say
+	defaultPending = 600
+	// authRequestCheckInterval is how often pending auth requests due for a check are loaded
+	authRequestCheckInterval = 10 * time.Second
+	// authRequestCheckBatch is the maximum number of auth requests checked every
+	// authRequestCheckInterval
+	authRequestCheckBatch = 100
+	// authRequestRetryDelay is the delay before checking again a pending auth request whose
+	// check failed
+	authRequestRetryDelay = time.Minute
+)
+
+// responseRecorder keeps the status code and body written by a handler.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
+
+// CheckPendingAuthRequests makes the decision on pending auth requests again when their
+// pending time passes, until the process exits. Requests that become allowed are saved as
+// authorized transactions, so the payment can be received without the sender asking again.
+func (rh *RequestHandler) CheckPendingAuthRequests() {
+	for {
+		requests, err := rh.Database.GetDueAuthRequests(time.Now(), authRequestCheckBatch)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error getting pending auth requests")
+		}
+
+		for _, request := range requests {
+			err = rh.checkAuthRequest(request)
+			if err != nil {
+				log.WithFields(log.Fields{"id": request.ID, "err": err}).Error("Error checking pending auth request")
+			}
+		}
+
+		if len(requests) < authRequestCheckBatch {
+			time.Sleep(authRequestCheckInterval)
+		}
+	}
+}
+
+// checkAuthRequest makes the decision on a pending auth request again and saves it.
+func (rh *RequestHandler) checkAuthRequest(request *db.AuthRequest) error {
+	log.WithFields(log.Fields{"id": request.ID, "sender": request.Sender}).Info("Checking pending auth request")
+
+	now := time.Now()
+	checkErr := rh.recheckAuthRequest(request, now)
+	if checkErr != nil {
+		message := checkErr.Error()
+		next := now.Add(authRequestRetryDelay)
+		request.Error = &message
+		request.UpdatedAt = now
+		request.NextCheckAt = &next
+	}
+
+	err := rh.Database.UpdateAuthRequest(request)
+	if err != nil {
+		return errors.Wrap(err, "Error updating auth request")
+	}
+
+	return checkErr
+}
+
+func (rh *RequestHandler) recheckAuthRequest(request *db.AuthRequest, now time.Time) error {
+	authreq := &compliance.AuthRequest{DataJSON: request.Data, Signature: request.Signature}
+	authData, err := authreq.Data()
+	if err != nil {
+		return err
+	}
+
+	var tx xdr.Transaction
+	err = xdr.SafeUnmarshalBase64(authData.Tx, &tx)
+	if err != nil {
+		return errors.Wrap(err, "Error decoding Transaction XDR")
+	}
+
+	attachment, err := authData.Attachment()
+	if err != nil {
+		return errors.Wrap(err, "Error getting attachment")
+	}
+
+	response, err := rh.authorize(authData, tx, attachment)
+	if err != nil {
+		return errors.Wrap(err, "Error authorizing request")
+	}
+
+	if authRequestStatus(response) == db.AuthRequestStatusOk {
+		err = rh.persistAuthorizedTransaction(request.TransactionID, request.Memo, authData.Tx, request.Data)
+		if err != nil {
+			return errors.Wrap(err, "Error persisting AuthorizedTransaction")
+		}
+	}
+
+	setAuthRequestDecision(request, response, now)
+	return nil
+}
+
+// saveAuthRequest saves an auth request in the audit log. Requests rejected before a decision
+// was made are saved with the response sent to the sender as error.
+func (rh *RequestHandler) saveAuthRequest(request *db.AuthRequest, recorder *responseRecorder) {
+	if request.Status == "" {
+		message := recorder.body.String()
+		request.Error = &message
+		request.UpdatedAt = time.Now()
+		if recorder.status >= http.StatusInternalServerError {
+			request.Status = db.AuthRequestStatusError
+		} else {
+			request.Status = db.AuthRequestStatusInvalid
+		}
+	}
+
+	// Senders repeat pending requests, only the latest one is checked again
+	if request.TransactionID != "" {
+		err := rh.Database.SupersedePendingAuthRequests(request.TransactionID)
+		if err != nil {
+			log.WithFields(log.Fields{"err": err}).Error("Error superseding pending auth requests")
+		}
+	}
+
+	err := rh.Database.InsertAuthRequest(request)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error saving auth request")
+	}
+}
+
+// setAuthRequestDecision sets the decision made on an auth request and, if it is pending,
+// when to make it again.
+func setAuthRequestDecision(request *db.AuthRequest, response *compliance.AuthResponse, now time.Time) {
+	request.Status = authRequestStatus(response)
+	request.TxStatus = string(response.TxStatus)
+	request.InfoStatus = string(response.InfoStatus)
+	request.Pending = int32(response.Pending)
+	request.Error = nil
+	if response.Error != "" {
+		request.Error = &response.Error
+	}
+	request.Checks++
+	request.UpdatedAt = now
+	request.NextCheckAt = nil
+
+	if request.Status == db.AuthRequestStatusPending {
+		pending := response.Pending
+		if pending <= 0 {
+			pending = defaultPending
+		}
+		next := now.Add(time.Duration(pending) * time.Second)
+		request.NextCheckAt = &next
+	}
+}
+
+// authRequestStatus returns the outcome of an auth request: a denied transaction or user data
+// access takes precedence over an error, and an error over a pending decision.
+func authRequestStatus(response *compliance.AuthResponse) db.AuthRequestStatus {
+	switch {
+	case response.TxStatus == compliance.AuthStatusOk && response.InfoStatus == compliance.AuthStatusOk:
+		return db.AuthRequestStatusOk
+	case response.TxStatus == compliance.AuthStatusDenied || response.InfoStatus == compliance.AuthStatusDenied:
+		return db.AuthRequestStatusDenied
+	case response.TxStatus == compliance.AuthStatusError || response.InfoStatus == compliance.AuthStatusError:
+		return db.AuthRequestStatusError
+	case response.TxStatus == compliance.AuthStatusPending || response.InfoStatus == compliance.AuthStatusPending:
+		return db.AuthRequestStatusPending
+	default:
+		return db.AuthRequestStatusError
+	}
+}
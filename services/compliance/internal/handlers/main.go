@@ -6,6 +6,7 @@ import (
 
 	"github.com/stellar/go/clients/federation"
 	"github.com/stellar/go/clients/stellartoml"
+	strategies "github.com/stellar/go/handlers/compliance"
 	"github.com/stellar/go/services/compliance/internal/config"
 	"github.com/stellar/go/services/compliance/internal/crypto"
 	"github.com/stellar/go/services/compliance/internal/db"
@@ -21,6 +22,16 @@ type RequestHandler struct {
 	StellarTomlResolver     stellartoml.ClientInterface    `inject:""`
 	FederationResolver      federation.ClientInterface     `inject:""`
 	NonceGenerator          NonceGeneratorInterface        `inject:""`
+	// SanctionsStrategy performs the sanctions check of auth requests, see
+	// NewSanctionsStrategy. Defaults to the `callbacks.sanctions` callback.
+	SanctionsStrategy strategies.Strategy
+}
+
+func (rh *RequestHandler) sanctionsStrategy() strategies.Strategy {
+	if rh.SanctionsStrategy != nil {
+		return rh.SanctionsStrategy
+	}
+	return &SanctionsCallbackStrategy{URL: rh.Config.Callbacks.Sanctions, Client: rh.Client}
 }
 
 type NonceGeneratorInterface interface {
@@ -20,6 +20,7 @@ import (
 	shared "github.com/stellar/go/services/internal/bridge-compliance-shared"
 	httpHelpers "github.com/stellar/go/services/internal/bridge-compliance-shared/http/helpers"
 	callback "github.com/stellar/go/services/internal/bridge-compliance-shared/protocols/compliance"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/xdr"
 )
 
@@ -32,6 +33,16 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 
 	log.WithFields(log.Fields{"data": authreq.DataJSON, "sig": authreq.Signature}).Info("HandlerAuth")
 
+	// Every request and its outcome is saved in the audit log
+	recorder := &responseRecorder{ResponseWriter: w}
+	w = recorder
+	authRequest := &db.AuthRequest{
+		Data:       authreq.DataJSON,
+		Signature:  authreq.Signature,
+		ReceivedAt: time.Now(),
+	}
+	defer rh.saveAuthRequest(authRequest, recorder)
+
 	err := authreq.Validate()
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Info(err.Error())
@@ -46,6 +57,8 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	authRequest.Sender = authData.Sender
+
 	senderStellarToml, err := rh.StellarTomlResolver.GetStellarTomlByAddress(authData.Sender)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err, "sender": authData.Sender}).Warn("Cannot get stellar.toml of sender")
@@ -106,6 +119,7 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 	// Validate memo preimage hash
 	memoPreimageHashBytes := sha256.Sum256([]byte(authData.AttachmentJSON))
 	memoBytes := [32]byte(*tx.Memo.Hash)
+	authRequest.Memo = base64.StdEncoding.EncodeToString(memoBytes[:])
 
 	if memoPreimageHashBytes != memoBytes {
 		h := xdr.Hash(memoPreimageHashBytes)
@@ -135,87 +149,64 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	authRequest.Receiver = string(attachment.Transaction.Route)
+
 	transactionHash, err := shared.TransactionHash(&tx, rh.Config.NetworkPassphrase)
 	if err != nil {
 		log.WithFields(log.Fields{"err": err}).Warn("Error calculating tx hash")
 		httpHelpers.Write(w, httpHelpers.InternalServerError)
 		return
 	}
+	authRequest.TransactionID = hex.EncodeToString(transactionHash[:])
 
-	response := compliance.AuthResponse{}
-
-	// Sanctions check
-	if rh.Config.Callbacks.Sanctions == "" {
-		response.TxStatus = compliance.AuthStatusOk
-	} else {
-		var senderInfo []byte
-		senderInfo, err = json.Marshal(attachment.Transaction.SenderInfo)
-		if err != nil {
-			log.WithFields(log.Fields{"err": err}).Error(err.Error())
-			w.WriteHeader(http.StatusInternalServerError)
-			w.Write([]byte(err.Error()))
-		}
+	response, err := rh.authorize(authData, tx, attachment)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error authorizing request")
+		httpHelpers.Write(w, httpHelpers.InternalServerError)
+		return
+	}
 
-		var resp *http.Response
-		resp, err = rh.Client.PostForm(
-			rh.Config.Callbacks.Sanctions,
-			url.Values{"sender": {string(senderInfo)}},
-		)
+	if authRequestStatus(response) == db.AuthRequestStatusOk {
+		err = rh.persistAuthorizedTransaction(authRequest.TransactionID, authRequest.Memo, authData.Tx, authreq.DataJSON)
 		if err != nil {
-			log.WithFields(log.Fields{
-				"sanctions": rh.Config.Callbacks.Sanctions,
-				"err":       err,
-			}).Error("Error sending request to sanctions server")
+			log.WithFields(log.Fields{"err": err}).Warn("Error persisting AuthorizedTransaction")
 			httpHelpers.Write(w, httpHelpers.InternalServerError)
 			return
 		}
+	}
 
-		defer resp.Body.Close()
-		var body []byte
-		body, err = ioutil.ReadAll(resp.Body)
-		if err != nil {
-			log.Error("Error reading sanctions server response")
-			httpHelpers.Write(w, httpHelpers.InternalServerError)
-			return
-		}
+	setAuthRequestDecision(authRequest, response, time.Now())
 
-		switch resp.StatusCode {
-		case http.StatusOK: // AuthStatusOk
-			response.TxStatus = compliance.AuthStatusOk
-		case http.StatusAccepted: // AuthStatusPending
-			response.TxStatus = compliance.AuthStatusPending
+	switch authRequest.Status {
+	case db.AuthRequestStatusOk:
+		w.WriteHeader(http.StatusOK)
+	case db.AuthRequestStatusDenied:
+		w.WriteHeader(http.StatusForbidden)
+	case db.AuthRequestStatusError:
+		w.WriteHeader(http.StatusBadRequest)
+	case db.AuthRequestStatusPending:
+		w.WriteHeader(http.StatusAccepted)
+	}
 
-			callbackResponse := callback.CallbackResponse{}
-			err = json.Unmarshal(body, &callbackResponse)
-			if err != nil {
-				// Set default value
-				response.Pending = 600
-			} else {
-				response.Pending = callbackResponse.Pending
-			}
-		case http.StatusBadRequest: // AuthStatusError
-			response.TxStatus = compliance.AuthStatusError
+	responseBody, err := response.Marshal()
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error(err.Error())
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write(responseBody)
+}
 
-			callbackResponse := callback.CallbackResponse{}
-			err = json.Unmarshal(body, &callbackResponse)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"status": resp.StatusCode,
-					"body":   string(body),
-				}).Error("Error response from sanctions server")
-			} else {
-				response.Error = callbackResponse.Error
-			}
-		case http.StatusForbidden: // AuthStatusDenied
-			response.TxStatus = compliance.AuthStatusDenied
-		default:
-			log.WithFields(log.Fields{
-				"status": resp.StatusCode,
-				"body":   string(body),
-			}).Error("Error response from sanctions server")
-			httpHelpers.Write(w, httpHelpers.InternalServerError)
-			return
-		}
+// authorize makes the decision on a valid auth request: it performs the sanctions check and
+// decides whether to share the user data with the sender.
+func (rh *RequestHandler) authorize(authData compliance.AuthData, tx xdr.Transaction, attachment compliance.Attachment) (*compliance.AuthResponse, error) {
+	response := &compliance.AuthResponse{}
+
+	// Sanctions check
+	err := rh.sanctionsStrategy().SanctionsCheck(authData, response)
+	if err != nil {
+		return nil, errors.Wrap(err, "Error performing sanctions check")
 	}
 
 	// User info
@@ -229,24 +220,19 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 				log.WithFields(log.Fields{
 					"sender": authData.Sender,
 				}).Warn("Invalid stellar address")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.New("Invalid stellar address")
 			}
 
 			allowedFi, err2 := rh.Database.GetAllowedFIByDomain(tokens[1])
 			if err2 != nil {
-				log.WithFields(log.Fields{"err": err2}).Error("Error getting AllowedFi from DB")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.Wrap(err2, "Error getting AllowedFi from DB")
 			}
 
 			if allowedFi == nil {
 				// FI not found check AllowedUser
 				allowedUser, err2 := rh.Database.GetAllowedUserByDomainAndUserID(tokens[1], tokens[0])
 				if err2 != nil {
-					log.WithFields(log.Fields{"err": err2}).Error("Error getting AllowedUser from DB")
-					httpHelpers.Write(w, httpHelpers.InternalServerError)
-					return
+					return nil, errors.Wrap(err2, "Error getting AllowedUser from DB")
 				}
 
 				if allowedUser != nil {
@@ -273,9 +259,7 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 			var senderInfo []byte
 			senderInfo, err = json.Marshal(attachment.Transaction.SenderInfo)
 			if err != nil {
-				log.WithFields(log.Fields{"err": err}).Error(err.Error())
-				w.WriteHeader(http.StatusInternalServerError)
-				w.Write([]byte(err.Error()))
+				return nil, errors.Wrap(err, "Error marshaling sender info")
 			}
 
 			var resp *http.Response
@@ -294,17 +278,14 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 					"ask_user": rh.Config.Callbacks.AskUser,
 					"err":      err,
 				}).Error("Error sending request to ask_user server")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.Wrap(err, "Error sending request to ask_user server")
 			}
 
 			defer resp.Body.Close()
 			var body []byte
 			body, err = ioutil.ReadAll(resp.Body)
 			if err != nil {
-				log.Error("Error reading ask_user server response")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.Wrap(err, "Error reading ask_user server response")
 			}
 
 			switch resp.StatusCode {
@@ -341,8 +322,7 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 					"status": resp.StatusCode,
 					"body":   string(body),
 				}).Error("Error response from ask_user server")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.New("Error response from ask_user server")
 			}
 		}
 
@@ -359,8 +339,7 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 					"fetch_info": rh.Config.Callbacks.FetchInfo,
 					"err":        err,
 				}).Error("Error sending request to fetch_info server")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.Wrap(err, "Error sending request to fetch_info server")
 			}
 
 			defer resp.Body.Close()
@@ -371,8 +350,7 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 					"fetch_info": rh.Config.Callbacks.FetchInfo,
 					"err":        err,
 				}).Error("Error reading fetch_info server response")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.Wrap(err, "Error reading fetch_info server response")
 			}
 
 			if resp.StatusCode != http.StatusOK {
@@ -381,8 +359,7 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 					"status":     resp.StatusCode,
 					"body":       string(body),
 				}).Error("Error response from fetch_info server")
-				httpHelpers.Write(w, httpHelpers.InternalServerError)
-				return
+				return nil, errors.New("Error response from fetch_info server")
 			}
 
 			response.DestInfo = string(body)
@@ -391,35 +368,26 @@ func (rh *RequestHandler) HandlerAuth(w http.ResponseWriter, r *http.Request) {
 		response.InfoStatus = compliance.AuthStatusOk
 	}
 
-	if response.TxStatus == compliance.AuthStatusOk && response.InfoStatus == compliance.AuthStatusOk {
-		w.WriteHeader(http.StatusOK)
-		authorizedTransaction := &db.AuthorizedTransaction{
-			TransactionID:  hex.EncodeToString(transactionHash[:]),
-			Memo:           base64.StdEncoding.EncodeToString(memoBytes[:]),
-			TransactionXdr: authData.Tx,
-			AuthorizedAt:   time.Now(),
-			Data:           authreq.DataJSON,
-		}
-		err = rh.Database.InsertAuthorizedTransaction(authorizedTransaction)
-		if err != nil {
-			log.WithFields(log.Fields{"err": err}).Warn("Error persisting AuthorizedTransaction")
-			httpHelpers.Write(w, httpHelpers.InternalServerError)
-			return
-		}
-	} else if response.TxStatus == compliance.AuthStatusDenied || response.InfoStatus == compliance.AuthStatusDenied {
-		w.WriteHeader(http.StatusForbidden)
-	} else if response.TxStatus == compliance.AuthStatusError || response.InfoStatus == compliance.AuthStatusError {
-		w.WriteHeader(http.StatusBadRequest)
-	} else if response.TxStatus == compliance.AuthStatusPending || response.InfoStatus == compliance.AuthStatusPending {
-		w.WriteHeader(http.StatusAccepted)
-	}
+	return response, nil
+}
 
-	responseBody, err := response.Marshal()
+// persistAuthorizedTransaction saves an authorized transaction, so that its memo preimage can
+// be fetched by the receiver, unless it was already authorized.
+func (rh *RequestHandler) persistAuthorizedTransaction(transactionID, memo, transactionXdr, data string) error {
+	existing, err := rh.Database.GetAuthorizedTransactionByMemo(memo)
 	if err != nil {
-		log.WithFields(log.Fields{"err": err}).Error(err.Error())
-		w.WriteHeader(http.StatusInternalServerError)
-		w.Write([]byte(err.Error()))
-		return
+		return err
 	}
-	w.Write(responseBody)
+
+	if existing != nil && existing.TransactionID == transactionID {
+		return nil
+	}
+
+	return rh.Database.InsertAuthorizedTransaction(&db.AuthorizedTransaction{
+		TransactionID:  transactionID,
+		Memo:           memo,
+		TransactionXdr: transactionXdr,
+		AuthorizedAt:   time.Now(),
+		Data:           data,
+	})
 }
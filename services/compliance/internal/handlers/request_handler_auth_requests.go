@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi"
+	log "github.com/sirupsen/logrus"
+
+	"github.com/stellar/go/services/compliance/internal/db"
+	"github.com/stellar/go/services/internal/bridge-compliance-shared/http/helpers"
+)
+
+const (
+	defaultAuthRequestsLimit = 10
+	maxAuthRequestsLimit     = 200
+)
+
+// HandlerAuthRequests implements /auth_requests endpoint
+func (rh *RequestHandler) HandlerAuthRequests(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filter := db.AuthRequestFilter{
+		Status:        db.AuthRequestStatus(query.Get("status")),
+		Sender:        query.Get("sender"),
+		Receiver:      query.Get("receiver"),
+		Memo:          query.Get("memo"),
+		TransactionID: query.Get("transaction_id"),
+	}
+
+	for _, param := range []struct {
+		name string
+		dest **time.Time
+	}{
+		{"received_after", &filter.ReceivedAfter},
+		{"received_before", &filter.ReceivedBefore},
+	} {
+		value := query.Get(param.name)
+		if value == "" {
+			continue
+		}
+
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			helpers.Write(w, helpers.NewInvalidParameterError(param.name, "Time must be in RFC 3339 format"))
+			return
+		}
+		*param.dest = &t
+	}
+
+	rh.writeAuthRequests(w, r, filter)
+}
+
+// HandlerPendingAuthRequests implements /auth_requests/pending endpoint
+func (rh *RequestHandler) HandlerPendingAuthRequests(w http.ResponseWriter, r *http.Request) {
+	rh.writeAuthRequests(w, r, db.AuthRequestFilter{Status: db.AuthRequestStatusPending})
+}
+
+// HandlerAuthRequest implements /auth_requests/{id} endpoint
+func (rh *RequestHandler) HandlerAuthRequest(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		helpers.Write(w, helpers.NewInvalidParameterError("id", "Invalid ID"))
+		return
+	}
+
+	request, err := rh.Database.GetAuthRequestByID(id)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading AuthRequest")
+		helpers.Write(w, helpers.InternalServerError)
+		return
+	}
+
+	if request == nil {
+		helpers.Write(w, &helpers.ErrorResponse{Code: "not_found", Message: "Auth request not found.", Status: http.StatusNotFound})
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(request)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding AuthRequest")
+		helpers.Write(w, helpers.InternalServerError)
+		return
+	}
+}
+
+func (rh *RequestHandler) writeAuthRequests(w http.ResponseWriter, r *http.Request, filter db.AuthRequestFilter) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit <= 0 {
+		limit = defaultAuthRequestsLimit
+	} else if limit > maxAuthRequestsLimit {
+		limit = maxAuthRequestsLimit
+	}
+	if page < 0 {
+		page = 0
+	}
+
+	requests, err := rh.Database.GetAuthRequests(filter, uint64(page), uint64(limit))
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error loading AuthRequests")
+		helpers.Write(w, helpers.InternalServerError)
+		return
+	}
+
+	err = json.NewEncoder(w).Encode(requests)
+	if err != nil {
+		log.WithFields(log.Fields{"err": err}).Error("Error encoding AuthRequests")
+		helpers.Write(w, helpers.InternalServerError)
+		return
+	}
+}
@@ -8,6 +8,7 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/stellar/go/address"
 	b "github.com/stellar/go/build"
+	federationClient "github.com/stellar/go/clients/federation"
 	"github.com/stellar/go/clients/stellartoml"
 	"github.com/stellar/go/protocols/compliance"
 	"github.com/stellar/go/protocols/federation"
@@ -93,7 +94,8 @@ func (rh *RequestHandler) HandlerSend(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 	} else {
-		destinationObject, err = rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Fields)
+		var forwardResponse *federationClient.ForwardResponse
+		forwardResponse, err = rh.FederationResolver.ForwardRequest(request.ForwardDestination.Domain, request.ForwardDestination.Fields)
 		if err != nil {
 			log.WithFields(log.Fields{
 				"destination": request.Destination,
@@ -102,6 +104,7 @@ func (rh *RequestHandler) HandlerSend(w http.ResponseWriter, r *http.Request) {
 			helpers.Write(w, callback.CannotResolveDestination)
 			return
 		}
+		destinationObject = &forwardResponse.NameResponse
 
 		domain = request.ForwardDestination.Domain
 	}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	log "github.com/sirupsen/logrus"
+
+	strategies "github.com/stellar/go/handlers/compliance"
+	"github.com/stellar/go/protocols/compliance"
+	"github.com/stellar/go/services/compliance/internal/config"
+	callback "github.com/stellar/go/services/internal/bridge-compliance-shared/protocols/compliance"
+	"github.com/stellar/go/support/errors"
+	supportHttp "github.com/stellar/go/support/http"
+)
+
+// SanctionsCallbackStrategy performs the sanctions check using the `callbacks.sanctions`
+// callback. Read the Callbacks section of the README for the protocol.
+type SanctionsCallbackStrategy struct {
+	URL    string
+	Client supportHttp.SimpleHTTPClientInterface
+}
+
+var _ strategies.Strategy = &SanctionsCallbackStrategy{}
+
+// NewSanctionsStrategy creates the strategy performing the sanctions check configured in the
+// `strategies` config group. When no strategies are configured, the `callbacks.sanctions`
+// callback is used.
+func NewSanctionsStrategy(cfg *config.Config, client supportHttp.SimpleHTTPClientInterface) (strategies.Strategy, error) {
+	names := cfg.Strategies.Sanctions
+	if len(names) == 0 {
+		names = []string{config.StrategyCallback}
+	}
+
+	composite := &strategies.CompositeStrategy{
+		Precedence: strategies.Precedence(cfg.Strategies.Precedence),
+		Default:    compliance.AuthStatus(cfg.Strategies.Default),
+	}
+	if composite.Default == "" {
+		composite.Default = compliance.AuthStatusOk
+	}
+
+	for _, name := range names {
+		switch name {
+		case config.StrategyCallback:
+			composite.Strategies = append(composite.Strategies, &SanctionsCallbackStrategy{
+				URL:    cfg.Callbacks.Sanctions,
+				Client: client,
+			})
+		case config.StrategyAddressList:
+			composite.Strategies = append(composite.Strategies, &strategies.ListStrategy{
+				Allow: cfg.Strategies.AddressList.Allow,
+				Deny:  cfg.Strategies.AddressList.Deny,
+			})
+		case config.StrategySanctionsList:
+			strategy, err := strategies.NewSanctionsListStrategy(
+				cfg.Strategies.SanctionsList.Path,
+				cfg.Strategies.SanctionsList.MatchThreshold,
+			)
+			if err != nil {
+				return nil, errors.Wrap(err, "Cannot load sanctions list")
+			}
+			composite.Strategies = append(composite.Strategies, strategy)
+		default:
+			return nil, errors.New("Unknown strategy: " + name)
+		}
+	}
+
+	return composite, nil
+}
+
+// SanctionsCheck sends the sender info of the transaction to the sanctions callback. If the
+// callback is not configured every transaction is allowed.
+func (s *SanctionsCallbackStrategy) SanctionsCheck(data compliance.AuthData, response *compliance.AuthResponse) error {
+	if s.URL == "" {
+		response.TxStatus = compliance.AuthStatusOk
+		return nil
+	}
+
+	attachment, err := data.Attachment()
+	if err != nil {
+		return errors.Wrap(err, "Error getting attachment")
+	}
+
+	senderInfo, err := json.Marshal(attachment.Transaction.SenderInfo)
+	if err != nil {
+		return errors.Wrap(err, "Error marshaling sender info")
+	}
+
+	resp, err := s.Client.PostForm(s.URL, url.Values{"sender": {string(senderInfo)}})
+	if err != nil {
+		return errors.Wrap(err, "Error sending request to sanctions server")
+	}
+
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrap(err, "Error reading sanctions server response")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK: // AuthStatusOk
+		response.TxStatus = compliance.AuthStatusOk
+	case http.StatusAccepted: // AuthStatusPending
+		response.TxStatus = compliance.AuthStatusPending
+
+		callbackResponse := callback.CallbackResponse{}
+		err = json.Unmarshal(body, &callbackResponse)
+		if err != nil {
+			// Set default value
+			response.Pending = defaultPending
+		} else {
+			response.Pending = callbackResponse.Pending
+		}
+	case http.StatusBadRequest: // AuthStatusError
+		response.TxStatus = compliance.AuthStatusError
+
+		callbackResponse := callback.CallbackResponse{}
+		err = json.Unmarshal(body, &callbackResponse)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"status": resp.StatusCode,
+				"body":   string(body),
+			}).Error("Error response from sanctions server")
+		} else {
+			response.Error = callbackResponse.Error
+		}
+	case http.StatusForbidden: // AuthStatusDenied
+		response.TxStatus = compliance.AuthStatusDenied
+	default:
+		log.WithFields(log.Fields{
+			"status": resp.StatusCode,
+			"body":   string(body),
+		}).Error("Error response from sanctions server")
+		return errors.New("Error response from sanctions server")
+	}
+
+	return nil
+}
+
+// GetUserData makes no decision: access to user data is decided by the `callbacks.ask_user`
+// callback and the allowed FIs and users.
+func (s *SanctionsCallbackStrategy) GetUserData(data compliance.AuthData, response *compliance.AuthResponse) error {
+	return nil
+}
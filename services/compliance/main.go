@@ -160,6 +160,11 @@ func NewApp(config config.Config, migrateFlag bool, versionFlag bool, version st
 		log.Fatal("Injector: ", err)
 	}
 
+	requestHandler.SanctionsStrategy, err = handlers.NewSanctionsStrategy(&config, &httpClientWithTimeout)
+	if err != nil {
+		return
+	}
+
 	app = &App{
 		config:         config,
 		requestHandler: requestHandler,
@@ -204,6 +209,11 @@ func (a *App) Serve() {
 	internal.Post("/receive", a.requestHandler.HandlerReceive)
 	internal.Post("/allow_access", a.requestHandler.HandlerAllowAccess)
 	internal.Post("/remove_access", a.requestHandler.HandlerRemoveAccess)
+	internal.Get("/auth_requests", a.requestHandler.HandlerAuthRequests)
+	internal.Get("/auth_requests/pending", a.requestHandler.HandlerPendingAuthRequests)
+	internal.Get("/auth_requests/{id}", a.requestHandler.HandlerAuthRequest)
+
+	go a.requestHandler.CheckPendingAuthRequests()
 
 	supportHttp.Run(supportHttp.Config{
 		ListenAddr: fmt.Sprintf(":%d", *a.config.InternalPort),
@@ -2,7 +2,10 @@ package main
 
 import (
 	"fmt"
+	stdhttp "net/http"
 	"os"
+	"regexp"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/spf13/cobra"
@@ -17,18 +20,55 @@ import (
 
 // Config represents the configuration of a federation server
 type Config struct {
-	Port     int `valid:"required"`
-	Database struct {
+	Port     int    `valid:"required"`
+	Driver   string `valid:"matches(^(sql|file|http)$),optional"`
+	Database *struct {
 		Type string `valid:"matches(^mysql|sqlite3|postgres$)"`
 		DSN  string `valid:"required"`
-	} `valid:"required"`
-	Queries struct {
+	} `valid:"optional"`
+	Queries *struct {
 		Federation        string `valid:"required"`
 		ReverseFederation string `toml:"reverse-federation" valid:"optional"`
-	} `valid:"required"`
+		// Forward maps the forward_type of "forward" requests to the query
+		// serving them.
+		Forward map[string]ForwardQuery `valid:"optional"`
+	} `valid:"optional"`
+	File *struct {
+		Path string `valid:"required"`
+		// ReloadInterval is the minimum number of seconds between two checks
+		// for changes to the file.
+		ReloadInterval int `toml:"reload-interval" valid:"optional"`
+	} `valid:"optional"`
+	HTTP *struct {
+		URL string `valid:"required"`
+		// Timeout is the number of seconds to wait for a response of the
+		// upstream server.
+		Timeout int `valid:"optional"`
+	} `valid:"optional"`
 	TLS *config.TLS `valid:"optional"`
 }
 
+// ForwardQuery represents the configuration of a query serving "forward"
+// requests. Params are the request parameters bound to the placeholders of
+// Query and Validate maps parameters to a regular expression their whole value
+// must match.
+type ForwardQuery struct {
+	Query    string            `valid:"required"`
+	Params   []string          `valid:"required"`
+	Validate map[string]string `valid:"optional"`
+}
+
+// Drivers supported in the `driver` config field
+const (
+	DriverSQL  = "sql"
+	DriverFile = "file"
+	DriverHTTP = "http"
+)
+
+// defaultHTTPTimeout is the default timeout of the requests sent by the http
+// driver.
+const defaultHTTPTimeout = 10 * time.Second
+
 func main() {
 	rootCmd := &cobra.Command{
 		Use:   "federation",
@@ -37,7 +77,8 @@ func main() {
 The stellar federation server let's you easily integrate the stellar federation
 protocol with your organization.  This is achieved by connecting the
 application to your customer database and providing the appropriate queries in
-the config file.
+the config file, by serving the records of a TOML or JSON file or by
+forwarding queries to an internal HTTP API.
     `,
 		Run: run,
 	}
@@ -85,6 +126,53 @@ func run(cmd *cobra.Command, args []string) {
 }
 
 func initDriver(cfg Config) (federation.Driver, error) {
+	switch cfg.Driver {
+	case "", DriverSQL:
+		return initSQLDriver(cfg)
+	case DriverFile:
+		return initFileDriver(cfg)
+	case DriverHTTP:
+		return initHTTPDriver(cfg)
+	default:
+		return nil, errors.Errorf("Invalid driver: %s", cfg.Driver)
+	}
+}
+
+func initFileDriver(cfg Config) (federation.Driver, error) {
+	if cfg.File == nil {
+		return nil, errors.New("file config group is required by the file driver")
+	}
+
+	drv, err := federation.NewFileDriver(cfg.File.Path)
+	if err != nil {
+		return nil, errors.Wrap(err, "file driver init failed")
+	}
+	drv.ReloadInterval = time.Duration(cfg.File.ReloadInterval) * time.Second
+
+	return drv, nil
+}
+
+func initHTTPDriver(cfg Config) (federation.Driver, error) {
+	if cfg.HTTP == nil {
+		return nil, errors.New("http config group is required by the http driver")
+	}
+
+	timeout := defaultHTTPTimeout
+	if cfg.HTTP.Timeout > 0 {
+		timeout = time.Duration(cfg.HTTP.Timeout) * time.Second
+	}
+
+	return &federation.HTTPDriver{
+		URL:    cfg.HTTP.URL,
+		Client: &stdhttp.Client{Timeout: timeout},
+	}, nil
+}
+
+func initSQLDriver(cfg Config) (federation.Driver, error) {
+	if cfg.Database == nil || cfg.Queries == nil {
+		return nil, errors.New("database and queries config groups are required by the sql driver")
+	}
+
 	var dialect string
 
 	switch cfg.Database.Type {
@@ -109,6 +197,25 @@ func initDriver(cfg Config) (federation.Driver, error) {
 		LookupRecordQuery: cfg.Queries.Federation,
 	}
 
+	if len(cfg.Queries.Forward) > 0 {
+		forwardQueries, err := initForwardQueries(cfg.Queries.Forward)
+		if err != nil {
+			return nil, err
+		}
+
+		return &federation.ForwardSQLDriver{
+			ReverseSQLDriver: federation.ReverseSQLDriver{
+				SQLDriver: federation.SQLDriver{
+					DB:                repo.DB.DB,
+					Dialect:           dialect,
+					LookupRecordQuery: cfg.Queries.Federation,
+				},
+				LookupReverseRecordQuery: cfg.Queries.ReverseFederation,
+			},
+			ForwardQueries: forwardQueries,
+		}, nil
+	}
+
 	if cfg.Queries.ReverseFederation == "" {
 		return &sqld, nil
 	}
@@ -125,6 +232,49 @@ func initDriver(cfg Config) (federation.Driver, error) {
 	return &rsqld, nil
 }
 
+func initForwardQueries(cfg map[string]ForwardQuery) (map[string]federation.ForwardQuery, error) {
+	queries := map[string]federation.ForwardQuery{}
+
+	for forwardType, fq := range cfg {
+		query := federation.ForwardQuery{Query: fq.Query}
+
+		for _, name := range fq.Params {
+			param := federation.ForwardParam{Name: name}
+
+			if pattern, ok := fq.Validate[name]; ok {
+				// Anchor the pattern so that the whole value must match, even
+				// with alternations like `a|b`
+				re, err := regexp.Compile("^(?:" + pattern + ")$")
+				if err != nil {
+					return nil, errors.Wrapf(err, "invalid validate pattern for %s param of %s forward query", name, forwardType)
+				}
+				param.Pattern = re
+			}
+
+			query.Params = append(query.Params, param)
+		}
+
+		for name := range fq.Validate {
+			if !contains(fq.Params, name) {
+				return nil, errors.Errorf("validate pattern for unknown %s param of %s forward query", name, forwardType)
+			}
+		}
+
+		queries[forwardType] = query
+	}
+
+	return queries, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
 func initMux(driver federation.Driver) *chi.Mux {
 	mux := http.NewAPIMux(false)
 
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitForwardQueriesAnchorsPatterns(t *testing.T) {
+	queries, err := initForwardQueries(map[string]ForwardQuery{
+		"bank_account": {
+			Query:    "SELECT ?",
+			Params:   []string{"type"},
+			Validate: map[string]string{"type": "checking|savings"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, queries["bank_account"].Params, 1)
+
+	pattern := queries["bank_account"].Params[0].Pattern
+	assert.True(t, pattern.MatchString("checking"))
+	assert.True(t, pattern.MatchString("savings"))
+	assert.False(t, pattern.MatchString("checking123"))
+	assert.False(t, pattern.MatchString("xsavings"))
+}
+
+func TestInitForwardQueriesUnknownParam(t *testing.T) {
+	_, err := initForwardQueries(map[string]ForwardQuery{
+		"bank_account": {
+			Query:    "SELECT ?",
+			Params:   []string{"acct"},
+			Validate: map[string]string{"swift": "^[A-Z]+$"},
+		},
+	})
+	assert.EqualError(t, err, "validate pattern for unknown swift param of bank_account forward query")
+}
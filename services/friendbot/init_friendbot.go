@@ -2,35 +2,88 @@ package main
 
 import (
 	"net/http"
+	"regexp"
 
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/services/friendbot/internal"
 	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
 )
 
-func initFriendbot(
-	friendbotSecret string,
-	networkPassphrase string,
-	horizonURL string,
-	startingBalance string,
-) *internal.Bot {
+var assetCodeRegexp = regexp.MustCompile("^[a-zA-Z0-9]{1,12}$")
 
-	if friendbotSecret == "" || networkPassphrase == "" || horizonURL == "" || startingBalance == "" {
-		return nil
+func initFriendbot(cfg Config) (*internal.Bot, error) {
+
+	if cfg.FriendbotSecret == "" || cfg.NetworkPassphrase == "" || cfg.HorizonURL == "" || cfg.StartingBalance == "" {
+		return nil, nil
 	}
 
 	// ensure its a seed if its not blank
-	strkey.MustDecode(strkey.VersionByteSeed, friendbotSecret)
+	strkey.MustDecode(strkey.VersionByteSeed, cfg.FriendbotSecret)
+
+	minions := make([]*internal.Minion, len(cfg.MinionSecrets))
+	for i, secret := range cfg.MinionSecrets {
+		strkey.MustDecode(strkey.VersionByteSeed, secret)
+		minions[i] = &internal.Minion{Secret: secret}
+	}
+
+	err := validatePositiveAmount(cfg.StartingBalance)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid starting_balance")
+	}
+
+	if cfg.MaxStartingBalance != "" {
+		err = validatePositiveAmount(cfg.MaxStartingBalance)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid max_starting_balance")
+		}
+	}
+
+	var asset *internal.Asset
+	if cfg.Asset != nil {
+		if !assetCodeRegexp.MatchString(cfg.Asset.Code) {
+			return nil, errors.New("invalid asset.code: must be 1-12 alphanumeric characters")
+		}
+
+		err = validatePositiveAmount(cfg.Asset.Amount)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid asset.amount")
+		}
+
+		asset = &internal.Asset{
+			Code:   cfg.Asset.Code,
+			Issuer: cfg.Asset.Issuer,
+			Amount: cfg.Asset.Amount,
+		}
+	}
 
 	return &internal.Bot{
-		Secret: friendbotSecret,
+		Secret: cfg.FriendbotSecret,
 		Horizon: &horizon.Client{
-			URL:     horizonURL,
+			URL:     cfg.HorizonURL,
 			HTTP:    http.DefaultClient,
 			AppName: "friendbot",
 		},
-		Network:           networkPassphrase,
-		StartingBalance:   startingBalance,
-		SubmitTransaction: internal.AsyncSubmitTransaction,
+		Network:            cfg.NetworkPassphrase,
+		StartingBalance:    cfg.StartingBalance,
+		MaxStartingBalance: cfg.MaxStartingBalance,
+		Minions:            minions,
+		Asset:              asset,
+		SubmitTransaction:  internal.AsyncSubmitTransaction,
+	}, nil
+}
+
+// validatePositiveAmount returns an error if `value` is not a valid amount larger than 0.
+func validatePositiveAmount(value string) error {
+	parsed, err := amount.Parse(value)
+	if err != nil {
+		return err
+	}
+
+	if parsed <= 0 {
+		return errors.New("amount must be larger than 0")
 	}
+
+	return nil
 }
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitFriendbot(t *testing.T) {
+	cfg := Config{
+		FriendbotSecret:    "SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5",
+		NetworkPassphrase:  "Test SDF Network ; September 2015",
+		HorizonURL:         "https://horizon-testnet.stellar.org",
+		StartingBalance:    "100",
+		MaxStartingBalance: "1000",
+		Asset: &AssetConfig{
+			Code:   "USD",
+			Issuer: "GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z",
+			Amount: "10",
+		},
+	}
+
+	fb, err := initFriendbot(cfg)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", fb.MaxStartingBalance)
+	assert.Equal(t, "USD", fb.Asset.Code)
+
+	invalid := cfg
+	invalid.MaxStartingBalance = "lots"
+	_, err = initFriendbot(invalid)
+	assert.EqualError(t, err, "invalid max_starting_balance: invalid amount format: lots")
+
+	asset := *cfg.Asset
+	asset.Amount = "0"
+	invalid = cfg
+	invalid.Asset = &asset
+	_, err = initFriendbot(invalid)
+	assert.EqualError(t, err, "invalid asset.amount: amount must be larger than 0")
+
+	asset = *cfg.Asset
+	asset.Code = "TOOLONGASSETCODE"
+	invalid.Asset = &asset
+	_, err = initFriendbot(invalid)
+	assert.Error(t, err)
+
+	asset.Code = "U$D"
+	_, err = initFriendbot(invalid)
+	assert.Error(t, err)
+}
@@ -1,13 +1,17 @@
 package internal
 
 import (
+	"net/http"
 	"strconv"
 	"sync"
+	"sync/atomic"
 
+	metrics "github.com/rcrowley/go-metrics"
 	b "github.com/stellar/go/build"
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/render/problem"
 )
 
 // TxResult is the result from the asynchronous submit transaction method over a channel
@@ -18,40 +22,168 @@ type TxResult struct {
 
 // Bot represents the friendbot subsystem.
 type Bot struct {
-	Horizon           *horizon.Client
-	Secret            string
-	Network           string
-	StartingBalance   string
+	Horizon horizon.ClientInterface
+	// Secret is the secret key of the funding account. All funds are sent from this
+	// account.
+	Secret          string
+	Network         string
+	StartingBalance string
+	// MaxStartingBalance is the maximum starting balance that can be requested by
+	// clients. Custom starting balances are disabled when empty.
+	MaxStartingBalance string
+	// Minions are channel accounts used as sources of transactions, in turn, so many
+	// transactions can be submitted at the same time. When empty, the funding account
+	// is the source of transactions.
+	Minions []*Minion
+	// Asset is a credit asset sent to existing accounts that trust it. Optional.
+	Asset             *Asset
 	SubmitTransaction func(bot *Bot, channel chan TxResult, signed string)
 
+	// uninitialized
+	initOnce   sync.Once
+	minions    []*Minion
+	nextMinion uint32
+	registry   metrics.Registry
+	succeeded  metrics.Counter
+	failed     metrics.Counter
+}
+
+// Minion is a channel account used as the source of friendbot transactions. Each
+// minion has its own sequence number.
+type Minion struct {
+	Secret string
+
 	// uninitialized
 	sequence             uint64
 	forceRefreshSequence bool
 	lock                 sync.Mutex
 }
 
+// Asset is a credit asset sent by friendbot.
+type Asset struct {
+	Code   string `json:"code"`
+	Issuer string `json:"issuer"`
+	Amount string `json:"amount"`
+}
+
+// ErrNoTrustline is returned when an existing account does not trust the Asset of
+// friendbot.
+var ErrNoTrustline = &problem.P{
+	Type:   "no_trustline",
+	Title:  "Trustline Missing",
+	Status: http.StatusBadRequest,
+	Detail: "The account already exists and does not trust the asset sent by friendbot. " +
+		"Create a trustline to the asset and try again.",
+}
+
+func (bot *Bot) init() {
+	bot.minions = bot.Minions
+	if len(bot.minions) == 0 {
+		bot.minions = []*Minion{{Secret: bot.Secret}}
+	}
+
+	bot.registry = metrics.NewRegistry()
+	bot.succeeded = metrics.NewRegisteredCounter("friendbot.payments.succeeded", bot.registry)
+	bot.failed = metrics.NewRegisteredCounter("friendbot.payments.failed", bot.registry)
+}
+
+// Metrics returns the metrics registry of the bot.
+func (bot *Bot) Metrics() metrics.Registry {
+	bot.initOnce.Do(bot.init)
+	return bot.registry
+}
+
 // Pay funds the account at `destAddress`
 func (bot *Bot) Pay(destAddress string) (*horizon.TransactionSuccess, error) {
+	return bot.PayAmount(destAddress, bot.StartingBalance)
+}
+
+// PayAmount funds the account at `destAddress`. If the account does not exist it's
+// created with `startingBalance`. If it exists and Asset is set, Asset is sent to the
+// account.
+func (bot *Bot) PayAmount(destAddress, startingBalance string) (*horizon.TransactionSuccess, error) {
+	bot.initOnce.Do(bot.init)
+
+	minion := bot.minions[atomic.AddUint32(&bot.nextMinion, 1)%uint32(len(bot.minions))]
+
+	op, err := bot.makeOperation(minion, destAddress, startingBalance)
+	if err != nil {
+		bot.failed.Inc(1)
+		return nil, err
+	}
+
 	channel := make(chan TxResult)
-	err := bot.lockedPay(channel, destAddress)
+	err = minion.lockedPay(bot, channel, op)
 	if err != nil {
+		bot.failed.Inc(1)
 		return nil, err
 	}
 
 	v := <-channel
+	if v.maybeErr != nil {
+		if e, ok := v.maybeErr.(*horizon.Error); ok {
+			minion.checkHandleBadSequence(e)
+		}
+		bot.failed.Inc(1)
+	} else {
+		bot.succeeded.Inc(1)
+	}
+
 	return v.maybeTransactionSuccess, v.maybeErr
 }
 
-func (bot *Bot) lockedPay(channel chan TxResult, destAddress string) error {
-	bot.lock.Lock()
-	defer bot.lock.Unlock()
+// makeOperation returns a create account operation, or a payment of Asset if Asset
+// is set and the account at `destAddress` exists. Operation's source is the funding
+// account when `minion` is a channel account.
+func (bot *Bot) makeOperation(minion *Minion, destAddress, startingBalance string) (b.TransactionMutator, error) {
+	muts := []interface{}{b.Destination{AddressOrSeed: destAddress}}
+	if minion.address() != bot.address() {
+		muts = append(muts, b.SourceAccount{AddressOrSeed: bot.address()})
+	}
+
+	if bot.Asset == nil {
+		return b.CreateAccount(append(muts, b.NativeAmount{Amount: startingBalance})...), nil
+	}
 
-	err := bot.checkSequenceRefresh()
+	account, err := bot.Horizon.LoadAccount(destAddress)
+	if err != nil {
+		if e, ok := err.(*horizon.Error); ok && e.Response.StatusCode == http.StatusNotFound {
+			return b.CreateAccount(append(muts, b.NativeAmount{Amount: startingBalance})...), nil
+		}
+		return nil, errors.Wrap(err, "Error loading destination account")
+	}
+
+	if !trusts(account, bot.Asset) {
+		return nil, ErrNoTrustline
+	}
+
+	return b.Payment(append(muts, b.CreditAmount{
+		Code:   bot.Asset.Code,
+		Issuer: bot.Asset.Issuer,
+		Amount: bot.Asset.Amount,
+	})...), nil
+}
+
+// trusts returns true if `account` has a trustline to `asset`.
+func trusts(account horizon.Account, asset *Asset) bool {
+	for _, balance := range account.Balances {
+		if balance.Code == asset.Code && balance.Issuer == asset.Issuer {
+			return true
+		}
+	}
+	return false
+}
+
+func (minion *Minion) lockedPay(bot *Bot, channel chan TxResult, op b.TransactionMutator) error {
+	minion.lock.Lock()
+	defer minion.lock.Unlock()
+
+	err := minion.checkSequenceRefresh(bot)
 	if err != nil {
 		return err
 	}
 
-	signed, err := bot.makeTx(destAddress)
+	signed, err := minion.makeTx(bot, op)
 	if err != nil {
 		return err
 	}
@@ -64,11 +196,6 @@ func (bot *Bot) lockedPay(channel chan TxResult, destAddress string) error {
 func AsyncSubmitTransaction(bot *Bot, channel chan TxResult, signed string) {
 	result, err := bot.Horizon.SubmitTransaction(signed)
 	if err != nil {
-		switch e := err.(type) {
-		case *horizon.Error:
-			bot.checkHandleBadSequence(e)
-		}
-
 		channel <- TxResult{
 			maybeTransactionSuccess: nil,
 			maybeErr:                err,
@@ -81,39 +208,44 @@ func AsyncSubmitTransaction(bot *Bot, channel chan TxResult, signed string) {
 	}
 }
 
-func (bot *Bot) checkHandleBadSequence(err *horizon.Error) {
+func (minion *Minion) checkHandleBadSequence(err *horizon.Error) {
 	resCode, e := err.ResultCodes()
 	isTxBadSeqCode := e == nil && resCode.TransactionCode == "tx_bad_seq"
 	if !isTxBadSeqCode {
 		return
 	}
-	bot.forceRefreshSequence = true
+
+	minion.lock.Lock()
+	defer minion.lock.Unlock()
+	minion.forceRefreshSequence = true
 }
 
 // establish initial sequence if needed
-func (bot *Bot) checkSequenceRefresh() error {
-	if bot.sequence != 0 && !bot.forceRefreshSequence {
+func (minion *Minion) checkSequenceRefresh(bot *Bot) error {
+	if minion.sequence != 0 && !minion.forceRefreshSequence {
 		return nil
 	}
-	return bot.refreshSequence()
+	return minion.refreshSequence(bot)
 }
 
-func (bot *Bot) makeTx(destAddress string) (string, error) {
+func (minion *Minion) makeTx(bot *Bot, op b.TransactionMutator) (string, error) {
 	txn, err := b.Transaction(
-		b.SourceAccount{AddressOrSeed: bot.Secret},
-		b.Sequence{Sequence: bot.sequence + 1},
+		b.SourceAccount{AddressOrSeed: minion.Secret},
+		b.Sequence{Sequence: minion.sequence + 1},
 		b.Network{Passphrase: bot.Network},
-		b.CreateAccount(
-			b.Destination{AddressOrSeed: destAddress},
-			b.NativeAmount{Amount: bot.StartingBalance},
-		),
+		op,
 	)
 
 	if err != nil {
 		return "", errors.Wrap(err, "Error building a transaction")
 	}
 
-	txs, err := txn.Sign(bot.Secret)
+	signers := []string{minion.Secret}
+	if minion.address() != bot.address() {
+		signers = append(signers, bot.Secret)
+	}
+
+	txs, err := txn.Sign(signers...)
 	if err != nil {
 		return "", errors.Wrap(err, "Error signing a transaction")
 	}
@@ -122,30 +254,35 @@ func (bot *Bot) makeTx(destAddress string) (string, error) {
 
 	// only increment the in-memory sequence number if we are going to submit the transaction, while we hold the lock
 	if err == nil {
-		bot.sequence++
+		minion.sequence++
 	}
 	return base64, err
 }
 
-// refreshes the sequence from the bot account
-func (bot *Bot) refreshSequence() error {
-	botAccount, err := bot.Horizon.LoadAccount(bot.address())
+// refreshes the sequence from the minion account
+func (minion *Minion) refreshSequence(bot *Bot) error {
+	minionAccount, err := bot.Horizon.LoadAccount(minion.address())
 	if err != nil {
-		bot.sequence = 0
+		minion.sequence = 0
 		return err
 	}
 
-	seq, err := strconv.ParseInt(botAccount.Sequence, 10, 64)
+	seq, err := strconv.ParseInt(minionAccount.Sequence, 10, 64)
 	if err != nil {
-		bot.sequence = 0
+		minion.sequence = 0
 		return err
 	}
 
-	bot.sequence = uint64(seq)
-	bot.forceRefreshSequence = false
+	minion.sequence = uint64(seq)
+	minion.forceRefreshSequence = false
 	return nil
 }
 
+func (minion *Minion) address() string {
+	kp := keypair.MustParse(minion.Secret)
+	return kp.Address()
+}
+
 func (bot *Bot) address() string {
 	kp := keypair.MustParse(bot.Secret)
 	return kp.Address()
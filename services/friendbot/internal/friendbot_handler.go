@@ -1,11 +1,15 @@
 package internal
 
 import (
+	"net"
 	"net/http"
 	"net/url"
 
+	metrics "github.com/rcrowley/go-metrics"
+	"github.com/stellar/go/amount"
 	"github.com/stellar/go/clients/horizon"
 	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/support/errors"
 	"github.com/stellar/go/support/render/hal"
 	"github.com/stellar/go/support/render/problem"
 )
@@ -13,6 +17,19 @@ import (
 // FriendbotHandler causes an account at `Address` to be created.
 type FriendbotHandler struct {
 	Friendbot *Bot
+	// IPRateLimiter limits requests per client IP address. Optional.
+	IPRateLimiter *RateLimiter
+	// DestinationRateLimiter limits requests per funded account. Optional.
+	DestinationRateLimiter *RateLimiter
+}
+
+// RateLimitExceeded is returned when a client sent too many requests.
+var RateLimitExceeded = &problem.P{
+	Type:   "rate_limit_exceeded",
+	Title:  "Rate Limit Exceeded",
+	Status: http.StatusTooManyRequests,
+	Detail: "Too many requests have been sent from your IP address or for this account. " +
+		"Try again later.",
 }
 
 // Handle is a method that implements http.HandlerFunc
@@ -38,12 +55,48 @@ func (handler *FriendbotHandler) doHandle(r *http.Request) (*horizon.Transaction
 		return nil, err
 	}
 
+	if !handler.allow(handler.IPRateLimiter, remoteIP(r)) {
+		return nil, RateLimitExceeded
+	}
+
 	address, err := handler.loadAddress(r)
 	if err != nil {
 		return nil, problem.MakeInvalidFieldProblem("addr", err)
 	}
 
-	return handler.loadResult(address)
+	startingBalance, err := handler.loadStartingBalance(r)
+	if err != nil {
+		return nil, problem.MakeInvalidFieldProblem("amount", err)
+	}
+
+	if !handler.allow(handler.DestinationRateLimiter, address) {
+		return nil, RateLimitExceeded
+	}
+
+	result, err := handler.loadResult(address, startingBalance)
+	if err != nil && handler.DestinationRateLimiter != nil {
+		// the account was not funded so the client can try again
+		handler.DestinationRateLimiter.Cancel(address)
+	}
+	return result, err
+}
+
+// allow returns true if the request identified by `key` is allowed by `limiter`.
+func (handler *FriendbotHandler) allow(limiter *RateLimiter, key string) bool {
+	if limiter == nil || limiter.Allow(key) {
+		return true
+	}
+
+	metrics.GetOrRegisterCounter("friendbot.requests.rate_limited", handler.Friendbot.Metrics()).Inc(1)
+	return false
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
 
 func (handler *FriendbotHandler) checkEnabled() error {
@@ -70,8 +123,37 @@ func (handler *FriendbotHandler) loadAddress(r *http.Request) (string, error) {
 	return unescaped, err
 }
 
-func (handler *FriendbotHandler) loadResult(address string) (*horizon.TransactionSuccess, error) {
-	result, err := handler.Friendbot.Pay(address)
+// loadStartingBalance returns the starting balance requested in `amount` param or the
+// default starting balance if it's empty.
+func (handler *FriendbotHandler) loadStartingBalance(r *http.Request) (string, error) {
+	value := r.Form.Get("amount")
+	if value == "" {
+		return handler.Friendbot.StartingBalance, nil
+	}
+
+	if handler.Friendbot.MaxStartingBalance == "" {
+		return "", errors.New("custom amounts are disabled")
+	}
+
+	requested, err := amount.Parse(value)
+	if err != nil {
+		return "", err
+	}
+
+	max, err := amount.Parse(handler.Friendbot.MaxStartingBalance)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid maximum amount")
+	}
+
+	if requested <= 0 || requested > max {
+		return "", errors.Errorf("amount must be larger than 0 and not larger than %s", handler.Friendbot.MaxStartingBalance)
+	}
+
+	return amount.String(requested), nil
+}
+
+func (handler *FriendbotHandler) loadResult(address, startingBalance string) (*horizon.TransactionSuccess, error) {
+	result, err := handler.Friendbot.PayAmount(address, startingBalance)
 	switch e := err.(type) {
 	case horizon.Error:
 		return result, e.Problem.ToProblem()
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHandler() *FriendbotHandler {
+	return &FriendbotHandler{
+		Friendbot: &Bot{
+			Secret:             "SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5",
+			Network:            "Test SDF Network ; September 2015",
+			StartingBalance:    "100.00",
+			MaxStartingBalance: "1000",
+			SubmitTransaction:  mockSubmitEnvelope,
+			Minions: []*Minion{
+				{Secret: "SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5", sequence: 2},
+			},
+		},
+	}
+}
+
+func friendbotRequest(handler *FriendbotHandler, remoteAddr string, form url.Values) *httptest.ResponseRecorder {
+	r := httptest.NewRequest("POST", "/", strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	handler.Handle(w, r)
+	return w
+}
+
+func TestFriendbotHandler_Amount(t *testing.T) {
+	handler := newTestHandler()
+	destination := randomKeypair(t).Address()
+
+	_, err := handler.doHandle(httptest.NewRequest("GET", "/?addr="+destination+"&amount=1001", nil))
+	assert.Error(t, err)
+
+	_, err = handler.doHandle(httptest.NewRequest("GET", "/?addr="+destination+"&amount=0", nil))
+	assert.Error(t, err)
+
+	result, err := handler.doHandle(httptest.NewRequest("GET", "/?addr="+destination+"&amount=250.5", nil))
+	require.NoError(t, err)
+	op := decodeEnvelope(t, result).Tx.Operations[0]
+	assert.Equal(t, xdr.Int64(2505000000), op.Body.CreateAccountOp.StartingBalance)
+
+	handler.Friendbot.MaxStartingBalance = ""
+	_, err = handler.doHandle(httptest.NewRequest("GET", "/?addr="+destination+"&amount=10", nil))
+	assert.Error(t, err)
+}
+
+func TestFriendbotHandler_RateLimit(t *testing.T) {
+	handler := newTestHandler()
+	handler.IPRateLimiter = &RateLimiter{Limit: 2, Period: time.Hour}
+	handler.DestinationRateLimiter = &RateLimiter{Limit: 1, Period: time.Hour}
+
+	first := randomKeypair(t).Address()
+	second := randomKeypair(t).Address()
+	third := randomKeypair(t).Address()
+
+	w := friendbotRequest(handler, "192.0.2.1:1234", url.Values{"addr": {first}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// destination limit
+	w = friendbotRequest(handler, "192.0.2.2:1234", url.Values{"addr": {first}})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	w = friendbotRequest(handler, "192.0.2.1:5678", url.Values{"addr": {second}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// IP limit
+	w = friendbotRequest(handler, "192.0.2.1:1234", url.Values{"addr": {third}})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "rate_limit_exceeded")
+}
+
+func TestFriendbotHandler_RateLimitFailedPayment(t *testing.T) {
+	handler := newTestHandler()
+	handler.DestinationRateLimiter = &RateLimiter{Limit: 1, Period: time.Hour}
+	destination := randomKeypair(t).Address()
+
+	handler.Friendbot.SubmitTransaction = func(bot *Bot, channel chan TxResult, signed string) {
+		channel <- TxResult{maybeErr: errors.New("submission failed")}
+	}
+	w := friendbotRequest(handler, "192.0.2.1:1234", url.Values{"addr": {destination}})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	// the failed payment does not count towards the destination limit
+	handler.Friendbot.SubmitTransaction = mockSubmitEnvelope
+	w = friendbotRequest(handler, "192.0.2.1:1234", url.Values{"addr": {destination}})
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = friendbotRequest(handler, "192.0.2.1:1234", url.Values{"addr": {destination}})
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+}
@@ -1,10 +1,17 @@
 package internal
 
 import (
+	"errors"
+	"net/http"
 	"testing"
 
+	metrics "github.com/rcrowley/go-metrics"
 	"github.com/stellar/go/clients/horizon"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/protocols/horizon/base"
+	"github.com/stellar/go/xdr"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"sync"
 )
@@ -24,7 +31,9 @@ func TestFriendbot_Pay(t *testing.T) {
 		Network:           "Test SDF Network ; September 2015",
 		StartingBalance:   "100.00",
 		SubmitTransaction: mockSubmitTransaction,
-		sequence:          2,
+		Minions: []*Minion{
+			{Secret: "SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5", sequence: 2},
+		},
 	}
 
 	txSuccess, err := fb.Pay("GDJIN6W6PLTPKLLM57UW65ZH4BITUXUMYQHIMAZFYXF45PZVAWDBI77Z")
@@ -52,3 +61,106 @@ func TestFriendbot_Pay(t *testing.T) {
 	}()
 	wg.Wait()
 }
+
+func mockSubmitEnvelope(bot *Bot, channel chan TxResult, signed string) {
+	channel <- TxResult{maybeTransactionSuccess: &horizon.TransactionSuccess{Env: signed}}
+}
+
+func randomKeypair(t *testing.T) *keypair.Full {
+	kp, err := keypair.Random()
+	require.NoError(t, err)
+	return kp
+}
+
+func decodeEnvelope(t *testing.T, txSuccess *horizon.TransactionSuccess) xdr.TransactionEnvelope {
+	var envelope xdr.TransactionEnvelope
+	require.NoError(t, xdr.SafeUnmarshalBase64(txSuccess.Env, &envelope))
+	return envelope
+}
+
+func TestFriendbot_PayMinions(t *testing.T) {
+	funder := randomKeypair(t)
+	minions := []*keypair.Full{randomKeypair(t), randomKeypair(t)}
+	destination := randomKeypair(t).Address()
+
+	fb := &Bot{
+		Secret:            funder.Seed(),
+		Network:           "Test SDF Network ; September 2015",
+		StartingBalance:   "100.00",
+		SubmitTransaction: mockSubmitEnvelope,
+		Minions: []*Minion{
+			{Secret: minions[0].Seed(), sequence: 10},
+			{Secret: minions[1].Seed(), sequence: 20},
+		},
+	}
+
+	sources := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		txSuccess, err := fb.Pay(destination)
+		require.NoError(t, err)
+
+		envelope := decodeEnvelope(t, txSuccess)
+		sources[envelope.Tx.SourceAccount.Address()] = true
+		// funds are sent from the funding account, signed by both the minion and the funder
+		require.Len(t, envelope.Tx.Operations, 1)
+		assert.Equal(t, funder.Address(), envelope.Tx.Operations[0].SourceAccount.Address())
+		assert.Equal(t, xdr.OperationTypeCreateAccount, envelope.Tx.Operations[0].Body.Type)
+		assert.Len(t, envelope.Signatures, 2)
+	}
+
+	assert.Equal(t, map[string]bool{minions[0].Address(): true, minions[1].Address(): true}, sources)
+	assert.Equal(t, int64(2), fb.Metrics().Get("friendbot.payments.succeeded").(metrics.Counter).Count())
+}
+
+func TestFriendbot_PayAsset(t *testing.T) {
+	issuer := randomKeypair(t).Address()
+	destination := randomKeypair(t).Address()
+	mockHorizon := &horizon.MockClient{}
+
+	fb := &Bot{
+		Horizon:           mockHorizon,
+		Secret:            "SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5",
+		Network:           "Test SDF Network ; September 2015",
+		StartingBalance:   "100.00",
+		SubmitTransaction: mockSubmitEnvelope,
+		Minions: []*Minion{
+			{Secret: "SAQWC7EPIYF3XGILYVJM4LVAVSLZKT27CTEI3AFBHU2VRCMQ3P3INPG5", sequence: 2},
+		},
+		Asset: &Asset{Code: "USD", Issuer: issuer, Amount: "50"},
+	}
+
+	// account does not trust the asset
+	mockHorizon.On("LoadAccount", destination).Return(horizon.Account{}, nil).Once()
+	_, err := fb.Pay(destination)
+	assert.Equal(t, ErrNoTrustline, err)
+
+	// account trusts the asset
+	account := horizon.Account{Balances: []horizon.Balance{
+		{Asset: base.Asset{Type: "credit_alphanum4", Code: "USD", Issuer: issuer}},
+	}}
+	mockHorizon.On("LoadAccount", destination).Return(account, nil).Once()
+	txSuccess, err := fb.Pay(destination)
+	require.NoError(t, err)
+
+	op := decodeEnvelope(t, txSuccess).Tx.Operations[0]
+	require.Equal(t, xdr.OperationTypePayment, op.Body.Type)
+	assert.Equal(t, xdr.Int64(500000000), op.Body.PaymentOp.Amount)
+
+	// account does not exist
+	notFound := &horizon.Error{Response: &http.Response{StatusCode: http.StatusNotFound}}
+	mockHorizon.On("LoadAccount", destination).Return(horizon.Account{}, notFound).Once()
+	txSuccess, err = fb.Pay(destination)
+	require.NoError(t, err)
+	assert.Equal(t, xdr.OperationTypeCreateAccount, decodeEnvelope(t, txSuccess).Tx.Operations[0].Body.Type)
+
+	// error loading the account
+	mockHorizon.On("LoadAccount", destination).Return(horizon.Account{}, errors.New("horizon down")).Once()
+	_, err = fb.Pay(destination)
+	assert.Error(t, err)
+
+	// payments that were not sent because of the destination account are failed too
+	assert.Equal(t, int64(2), fb.Metrics().Get("friendbot.payments.failed").(metrics.Counter).Count())
+	assert.Equal(t, int64(2), fb.Metrics().Get("friendbot.payments.succeeded").(metrics.Counter).Count())
+
+	mockHorizon.AssertExpectations(t)
+}
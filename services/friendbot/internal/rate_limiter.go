@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimiter limits the number of requests per key (ex. IP address) to Limit in
+// every Period. It keeps the state in memory so it limits requests sent to a single
+// friendbot instance only.
+type RateLimiter struct {
+	Limit  int
+	Period time.Duration
+
+	// uninitialized
+	lock        sync.Mutex
+	windows     map[string]*rateLimiterWindow
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+type rateLimiterWindow struct {
+	start time.Time
+	count int
+}
+
+// Allow records a request for `key` and returns false if the limit of requests for
+// `key` in the current period has been reached.
+func (rl *RateLimiter) Allow(key string) bool {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	if rl.windows == nil {
+		rl.windows = make(map[string]*rateLimiterWindow)
+	}
+
+	now := rl.currentTime()
+	rl.cleanup(now)
+
+	window, ok := rl.windows[key]
+	if !ok || now.Sub(window.start) >= rl.Period {
+		window = &rateLimiterWindow{start: now}
+		rl.windows[key] = window
+	}
+
+	if window.count >= rl.Limit {
+		return false
+	}
+
+	window.count++
+	return true
+}
+
+// Cancel removes a request for `key` recorded by Allow, ex. when it failed and should
+// not count towards the limit.
+func (rl *RateLimiter) Cancel(key string) {
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	window, ok := rl.windows[key]
+	if ok && window.count > 0 {
+		window.count--
+	}
+}
+
+// cleanup removes expired windows, at most once per Period, so memory usage does
+// not grow with the number of keys seen since start.
+func (rl *RateLimiter) cleanup(now time.Time) {
+	if now.Sub(rl.lastCleanup) < rl.Period {
+		return
+	}
+
+	for key, window := range rl.windows {
+		if now.Sub(window.start) >= rl.Period {
+			delete(rl.windows, key)
+		}
+	}
+	rl.lastCleanup = now
+}
+
+func (rl *RateLimiter) currentTime() time.Time {
+	if rl.now != nil {
+		return rl.now()
+	}
+	return time.Now()
+}
@@ -0,0 +1,60 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Allow(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	limiter := &RateLimiter{
+		Limit:  2,
+		Period: time.Minute,
+		now:    func() time.Time { return now },
+	}
+
+	assert.True(t, limiter.Allow("a"))
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+	// other keys have separate limits
+	assert.True(t, limiter.Allow("b"))
+
+	now = now.Add(59 * time.Second)
+	assert.False(t, limiter.Allow("a"))
+
+	now = now.Add(time.Second)
+	assert.True(t, limiter.Allow("a"))
+}
+
+func TestRateLimiter_Cancel(t *testing.T) {
+	limiter := &RateLimiter{Limit: 1, Period: time.Minute}
+
+	// canceling unknown keys is a no-op
+	limiter.Cancel("a")
+
+	assert.True(t, limiter.Allow("a"))
+	limiter.Cancel("a")
+	assert.True(t, limiter.Allow("a"))
+	assert.False(t, limiter.Allow("a"))
+}
+
+func TestRateLimiter_Cleanup(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+	limiter := &RateLimiter{
+		Limit:  1,
+		Period: time.Minute,
+		now:    func() time.Time { return now },
+	}
+
+	limiter.Allow("a")
+	now = now.Add(30 * time.Second)
+	limiter.Allow("b")
+	assert.Len(t, limiter.windows, 2)
+
+	now = now.Add(30 * time.Second)
+	limiter.Allow("c")
+	assert.Len(t, limiter.windows, 2)
+	assert.NotContains(t, limiter.windows, "a")
+}
@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/stellar/go/support/log"
+	"github.com/stellar/go/support/render/hal"
+	"github.com/stellar/go/support/render/problem"
+)
+
+// Status describes the configuration of friendbot.
+type Status struct {
+	FundingAccount     string   `json:"funding_account"`
+	Minions            []string `json:"minions"`
+	StartingBalance    string   `json:"starting_balance"`
+	MaxStartingBalance string   `json:"max_starting_balance,omitempty"`
+	Asset              *Asset   `json:"asset,omitempty"`
+}
+
+// StatusHandler renders friendbot's Status.
+type StatusHandler struct {
+	Friendbot *Bot
+}
+
+// Handle is a method that implements http.HandlerFunc
+func (handler *StatusHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if handler.Friendbot == nil {
+		problem.Render(r.Context(), w, problem.NotFound)
+		return
+	}
+
+	bot := handler.Friendbot
+	bot.initOnce.Do(bot.init)
+
+	status := Status{
+		FundingAccount:     bot.address(),
+		Minions:            make([]string, len(bot.minions)),
+		StartingBalance:    bot.StartingBalance,
+		MaxStartingBalance: bot.MaxStartingBalance,
+		Asset:              bot.Asset,
+	}
+
+	for i, minion := range bot.minions {
+		status.Minions[i] = minion.address()
+	}
+
+	hal.Render(w, status)
+}
+
+// MetricsHandler renders metrics of friendbot as JSON.
+type MetricsHandler struct {
+	Friendbot *Bot
+}
+
+// Handle is a method that implements http.HandlerFunc
+func (handler *MetricsHandler) Handle(w http.ResponseWriter, r *http.Request) {
+	if handler.Friendbot == nil {
+		problem.Render(r.Context(), w, problem.NotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(handler.Friendbot.Metrics())
+	if err != nil {
+		log.WithField("err", err).Error("Error encoding metrics")
+	}
+}
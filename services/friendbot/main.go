@@ -5,6 +5,7 @@ import (
 	"fmt"
 	stdhttp "net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/spf13/cobra"
@@ -19,14 +20,39 @@ import (
 
 // Config represents the configuration of a friendbot server
 type Config struct {
-	Port              int         `toml:"port" valid:"required"`
-	FriendbotSecret   string      `toml:"friendbot_secret" valid:"required"`
-	NetworkPassphrase string      `toml:"network_passphrase" valid:"required"`
-	HorizonURL        string      `toml:"horizon_url" valid:"required"`
-	StartingBalance   string      `toml:"starting_balance" valid:"required"`
-	TLS               *config.TLS `valid:"optional"`
+	Port               int              `toml:"port" valid:"required"`
+	FriendbotSecret    string           `toml:"friendbot_secret" valid:"required"`
+	NetworkPassphrase  string           `toml:"network_passphrase" valid:"required"`
+	HorizonURL         string           `toml:"horizon_url" valid:"required"`
+	StartingBalance    string           `toml:"starting_balance" valid:"required"`
+	MaxStartingBalance string           `toml:"max_starting_balance" valid:"optional"`
+	MinionSecrets      []string         `toml:"minion_secrets" valid:"optional"`
+	UsingProxy         bool             `toml:"using_proxy" valid:"optional"`
+	Asset              *AssetConfig     `toml:"asset" valid:"optional"`
+	RateLimit          *RateLimitConfig `toml:"rate_limit" valid:"optional"`
+	TLS                *config.TLS      `valid:"optional"`
 }
 
+// AssetConfig represents a credit asset sent to accounts that already exist
+type AssetConfig struct {
+	Code   string `toml:"code" valid:"required"`
+	Issuer string `toml:"issuer" valid:"required,stellar_accountid"`
+	Amount string `toml:"amount" valid:"required"`
+}
+
+// RateLimitConfig represents the limits of requests per client IP address and per
+// funded account. Periods are in seconds, requests are not limited when the number
+// of requests is empty.
+type RateLimitConfig struct {
+	IPRequests          int `toml:"ip_requests" valid:"optional"`
+	IPPeriod            int `toml:"ip_period" valid:"optional"`
+	DestinationRequests int `toml:"destination_requests" valid:"optional"`
+	DestinationPeriod   int `toml:"destination_period" valid:"optional"`
+}
+
+// defaultRateLimitPeriod is the period of rate limits when not set in config.
+const defaultRateLimitPeriod = time.Hour
+
 func main() {
 
 	rootCmd := &cobra.Command{
@@ -58,8 +84,13 @@ func run(cmd *cobra.Command, args []string) {
 		os.Exit(1)
 	}
 
-	fb := initFriendbot(cfg.FriendbotSecret, cfg.NetworkPassphrase, cfg.HorizonURL, cfg.StartingBalance)
-	router := initRouter(fb)
+	fb, err := initFriendbot(cfg)
+	if err != nil {
+		log.Error("config file: ", err)
+		os.Exit(1)
+	}
+
+	router := initRouter(cfg, fb)
 	registerProblems()
 
 	addr := fmt.Sprintf("0.0.0.0:%d", cfg.Port)
@@ -75,12 +106,19 @@ func run(cmd *cobra.Command, args []string) {
 	})
 }
 
-func initRouter(fb *internal.Bot) *chi.Mux {
-	mux := http.NewAPIMux(false)
+func initRouter(cfg Config, fb *internal.Bot) *chi.Mux {
+	mux := http.NewAPIMux(cfg.UsingProxy)
 
 	handler := &internal.FriendbotHandler{Friendbot: fb}
+	if cfg.RateLimit != nil {
+		handler.IPRateLimiter = newRateLimiter(cfg.RateLimit.IPRequests, cfg.RateLimit.IPPeriod)
+		handler.DestinationRateLimiter = newRateLimiter(cfg.RateLimit.DestinationRequests, cfg.RateLimit.DestinationPeriod)
+	}
+
 	mux.Get("/", handler.Handle)
 	mux.Post("/", handler.Handle)
+	mux.Get("/status", (&internal.StatusHandler{Friendbot: fb}).Handle)
+	mux.Get("/metrics", (&internal.MetricsHandler{Friendbot: fb}).Handle)
 	mux.NotFound(stdhttp.HandlerFunc(func(w stdhttp.ResponseWriter, r *stdhttp.Request) {
 		problem.Render(r.Context(), w, problem.NotFound)
 	}))
@@ -88,6 +126,24 @@ func initRouter(fb *internal.Bot) *chi.Mux {
 	return mux
 }
 
+// newRateLimiter returns a rate limiter allowing `requests` in `period` seconds or nil
+// if `requests` is not set.
+func newRateLimiter(requests, period int) *internal.RateLimiter {
+	if requests <= 0 {
+		return nil
+	}
+
+	limiter := &internal.RateLimiter{
+		Limit:  requests,
+		Period: time.Duration(period) * time.Second,
+	}
+	if limiter.Period <= 0 {
+		limiter.Period = defaultRateLimitPeriod
+	}
+
+	return limiter
+}
+
 func registerProblems() {
 	problem.RegisterError(sql.ErrNoRows, problem.NotFound)
 }
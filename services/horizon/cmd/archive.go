@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/stellar/go/services/horizon/internal/db2/history"
+	apkg "github.com/stellar/go/support/app"
+	"github.com/stellar/go/support/db"
+	"github.com/stellar/go/support/errors"
+	"github.com/stellar/go/support/historyarchive"
+	"github.com/stellar/go/xdr"
+)
+
+var archiveOpts struct {
+	low      uint32
+	high     uint32
+	s3region string
+	dryRun   bool
+	force    bool
+}
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive [command]",
+	Short: "commands to manage history archives",
+}
+
+var archivePublishCmd = &cobra.Command{
+	Use:   "publish [ARCHIVE-URL]",
+	Short: "publishes ingested ledgers to a history archive",
+	Long: "publish writes the ledger headers, transaction sets and results of every " +
+		"fully ingested checkpoint between --low and --high to the history archive at " +
+		"ARCHIVE-URL, and points its root HAS at the latest one. Buckets are not " +
+		"published. A checkpoint is only complete if failed transactions were ingested " +
+		"as well (see --ingest-failed-transactions), others are skipped.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			cmd.Usage()
+			log.Fatal("Missing ARCHIVE-URL")
+		}
+		initConfig()
+
+		hdb, err := db.Open("postgres", config.DatabaseURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		arch, err := historyarchive.Connect(args[0], historyarchive.ConnectOptions{
+			S3Region: archiveOpts.s3region,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		q := &history.Q{Session: hdb}
+		low, high := archiveOpts.low, archiveOpts.high
+		// Default to everything horizon has ingested.
+		if low == 0 {
+			var elder int32
+			if err = q.ElderLedger(&elder); err != nil {
+				log.Fatal(err)
+			}
+			low = uint32(elder)
+		}
+		if high == 0 {
+			var latest int32
+			if err = q.LatestLedger(&latest); err != nil {
+				log.Fatal(err)
+			}
+			high = uint32(latest)
+		}
+
+		opts := &historyarchive.CommandOptions{
+			Range:  historyarchive.MakeRange(low, high),
+			DryRun: archiveOpts.dryRun,
+			Force:  archiveOpts.force,
+		}
+		src := &historyLedgerSource{q: q}
+		err = historyarchive.Publish(src, arch, "horizon "+apkg.Version(), opts)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// historyLedgerSource reads ledgers back from the history tables. Ledgers
+// whose transaction set does not hash to the value in their header (for
+// instance because failed transactions were not ingested) are left out.
+type historyLedgerSource struct {
+	q *history.Q
+}
+
+func (s *historyLedgerSource) GetLedgers(first, last uint32) ([]historyarchive.LedgerData, error) {
+	var seqs []int32
+	for seq := first; seq <= last; seq++ {
+		seqs = append(seqs, int32(seq))
+	}
+	var ledgers []history.Ledger
+	if err := s.q.LedgersBySequence(&ledgers, seqs...); err != nil {
+		return nil, errors.Wrap(err, "loading ledgers failed")
+	}
+
+	var txs []history.Transaction
+	err := s.q.Transactions().
+		IncludeFailed().
+		ForLedgerRange(int32(first), int32(last)).
+		Select(&txs)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading transactions failed")
+	}
+
+	bySeq := make(map[int32]*historyarchive.LedgerData, len(ledgers))
+	for _, l := range ledgers {
+		if !l.LedgerHeaderXDR.Valid {
+			continue
+		}
+		data := &historyarchive.LedgerData{}
+		if err = xdr.SafeUnmarshalBase64(l.LedgerHeaderXDR.String, &data.Header.Header); err != nil {
+			return nil, errors.Wrapf(err, "decoding header of ledger %d failed", l.Sequence)
+		}
+		if err = decodeHash(l.LedgerHash, &data.Header.Hash); err != nil {
+			return nil, errors.Wrapf(err, "decoding hash of ledger %d failed", l.Sequence)
+		}
+		bySeq[l.Sequence] = data
+	}
+
+	for _, tx := range txs {
+		data, ok := bySeq[tx.LedgerSequence]
+		if !ok {
+			continue
+		}
+		var env xdr.TransactionEnvelope
+		if err = xdr.SafeUnmarshalBase64(tx.TxEnvelope, &env); err != nil {
+			return nil, errors.Wrapf(err, "decoding envelope of %s failed", tx.TransactionHash)
+		}
+		pair := xdr.TransactionResultPair{}
+		if err = xdr.SafeUnmarshalBase64(tx.TxResult, &pair.Result); err != nil {
+			return nil, errors.Wrapf(err, "decoding result of %s failed", tx.TransactionHash)
+		}
+		if err = decodeHash(tx.TransactionHash, &pair.TransactionHash); err != nil {
+			return nil, errors.Wrapf(err, "decoding hash of %s failed", tx.TransactionHash)
+		}
+		data.Txs = append(data.Txs, env)
+		data.Results = append(data.Results, pair)
+	}
+
+	var out []historyarchive.LedgerData
+	for seq := first; seq <= last; seq++ {
+		data, ok := bySeq[int32(seq)]
+		if !ok {
+			continue
+		}
+		txSetHash, err := historyarchive.HashTxSet(&xdr.TransactionSet{
+			PreviousLedgerHash: data.Header.Header.PreviousLedgerHash,
+			Txs:                append([]xdr.TransactionEnvelope(nil), data.Txs...),
+		})
+		if err != nil {
+			return nil, err
+		}
+		if xdr.Hash(txSetHash) != data.Header.Header.ScpValue.TxSetHash {
+			log.Printf("Transaction set of ledger %d is incomplete", seq)
+			continue
+		}
+		out = append(out, *data)
+	}
+	return out, nil
+}
+
+func decodeHash(s string, dest *xdr.Hash) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dest) {
+		return errors.Errorf("hash '%s' has the wrong length", s)
+	}
+	copy(dest[:], b)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(archiveCmd)
+	archiveCmd.AddCommand(archivePublishCmd)
+
+	archivePublishCmd.Flags().Uint32Var(&archiveOpts.low, "low", 0, "first ledger to publish (default: oldest ingested ledger)")
+	archivePublishCmd.Flags().Uint32Var(&archiveOpts.high, "high", 0, "last ledger to publish (default: latest ingested ledger)")
+	archivePublishCmd.Flags().StringVar(&archiveOpts.s3region, "s3region", "us-east-1", "S3 region of s3:// archives")
+	archivePublishCmd.Flags().BoolVar(&archiveOpts.dryRun, "dryrun", false, "don't write anything")
+	archivePublishCmd.Flags().BoolVar(&archiveOpts.force, "force", false, "overwrite existing files")
+}
@@ -72,6 +72,20 @@ func (q *TransactionsQ) ForLedger(seq int32) *TransactionsQ {
 	return q
 }
 
+// ForLedgerRange filters the query to only transactions in the ledgers from
+// `start` to `end` inclusive, in the order they were applied.
+func (q *TransactionsQ) ForLedgerRange(start, end int32) *TransactionsQ {
+	from := toid.ID{LedgerSequence: start}
+	to := toid.ID{LedgerSequence: end + 1}
+	q.sql = q.sql.Where(
+		"ht.id >= ? AND ht.id < ?",
+		from.ToInt64(),
+		to.ToInt64(),
+	).OrderBy("ht.id asc")
+
+	return q
+}
+
 // IncludeFailed changes the query to include failed transactions.
 func (q *TransactionsQ) IncludeFailed() *TransactionsQ {
 	q.includeFailed = true
@@ -127,3 +127,32 @@ func TestExtraChecksTransactionSuccessfulFalseResultTrue(t *testing.T) {
 	tt.Assert.Error(err)
 	tt.Assert.Contains(err.Error(), "Corrupted data! `successful=false` but returned transaction is success")
 }
+
+// TestTransactionsForLedgerRange tests `ForLedgerRange` method.
+func TestTransactionsForLedgerRange(t *testing.T) {
+	tt := test.Start(t).Scenario("failed_transactions")
+	defer tt.Finish()
+	q := &Q{tt.HorizonSession()}
+
+	var all []Transaction
+	err := q.Transactions().IncludeFailed().Select(&all)
+	tt.Assert.NoError(err)
+	tt.Assert.NotEmpty(all)
+
+	var transactions []Transaction
+	err = q.Transactions().IncludeFailed().ForLedgerRange(1, 1000).Select(&transactions)
+	tt.Assert.NoError(err)
+	tt.Assert.Equal(len(all), len(transactions))
+	for i := 1; i < len(transactions); i++ {
+		tt.Assert.True(transactions[i-1].ID < transactions[i].ID)
+	}
+
+	last := transactions[len(transactions)-1].LedgerSequence
+	transactions = nil
+	err = q.Transactions().IncludeFailed().ForLedgerRange(last, last).Select(&transactions)
+	tt.Assert.NoError(err)
+	tt.Assert.NotEmpty(transactions)
+	for _, tx := range transactions {
+		tt.Assert.Equal(last, tx.LedgerSequence)
+	}
+}
@@ -9,7 +9,6 @@ import (
 	"encoding/json"
 	"errors"
 	"io"
-	"io/ioutil"
 	"log"
 	"net/url"
 	"path"
@@ -33,12 +32,21 @@ type CommandOptions struct {
 type ConnectOptions struct {
 	S3Region   string
 	S3Endpoint string
+
+	// HttpListIndex lets http(s) archives list files by reading the
+	// directory index pages served by the web server.
+	HttpListIndex bool
+
+	// Commands maps archive names, as used in cmd://<name> URLs, to the
+	// stellar-core style commands used to access them.
+	Commands map[string]CommandTemplates
 }
 
 type ArchiveBackend interface {
 	Exists(path string) bool
 	GetFile(path string) (io.ReadCloser, error)
 	PutFile(path string, in io.ReadCloser) error
+	RemoveFile(path string) error
 	ListFiles(path string) (chan string, chan error)
 	CanListFiles() bool
 }
@@ -87,8 +95,7 @@ func (a *Archive) PutPathHAS(path string, has HistoryArchiveState, opts *Command
 	if err != nil {
 		return err
 	}
-	return a.backend.PutFile(path,
-		ioutil.NopCloser(bytes.NewReader(buf)))
+	return a.backend.PutFile(path, bytesReadCloser{bytes.NewReader(buf)})
 }
 
 func (a *Archive) BucketExists(bucket Hash) bool {
@@ -198,8 +205,10 @@ func Connect(u string, opts ConnectOptions) (*Archive, error) {
 	} else if parsed.Scheme == "file" {
 		pth = path.Join(parsed.Host, pth)
 		arch.backend = makeFsBackend(pth, opts)
-	} else if parsed.Scheme == "http" {
+	} else if parsed.Scheme == "http" || parsed.Scheme == "https" {
 		arch.backend = makeHttpBackend(parsed, opts)
+	} else if parsed.Scheme == "cmd" {
+		arch.backend, err = makeCommandBackend(parsed.Host, opts)
 	} else if parsed.Scheme == "mock" {
 		arch.backend = makeMockBackend(opts)
 	} else {
@@ -209,6 +209,141 @@ func TestDryRunNoRepair(t *testing.T) {
 	assert.NotEqual(t, 0, countMissing(dst, opts))
 }
 
+func countBuckets(arch *Archive) int {
+	n := 0
+	ch, errs := arch.ListAllBucketHashes()
+	for range ch {
+		n++
+	}
+	drainErrors(errs)
+	return n
+}
+
+func TestExtract(t *testing.T) {
+	defer cleanup()
+	src := GetRandomPopulatedArchive()
+	dst := GetTestArchive()
+	opts := testOptions()
+	opts.Range = Range{Low: 0x13f, High: 0x23f}
+	assert.NoError(t, Extract(src, dst, opts))
+
+	has, e := dst.GetRootHAS()
+	assert.NoError(t, e)
+	assert.Equal(t, uint32(0x1ff), has.CurrentLedger)
+	for chk := range opts.Range.Checkpoints() {
+		for _, cat := range Categories() {
+			assert.True(t, dst.CategoryCheckpointExists(cat, chk))
+		}
+	}
+	assert.Equal(t, opts.Range.Size()*NumLevels*3, countBuckets(dst))
+	dst.Scan(opts)
+	assert.Empty(t, dst.CheckBucketsMissing())
+}
+
+func TestPrune(t *testing.T) {
+	defer cleanup()
+	arch := GetRandomPopulatedArchive()
+	opts := testOptions()
+	referenced := countBuckets(arch)
+	for i := 0; i < 10; i++ {
+		arch.AddRandomBucket()
+	}
+	assert.Equal(t, referenced+10, countBuckets(arch))
+
+	// Only reports unreferenced buckets by default.
+	assert.NoError(t, Prune(arch, opts))
+	assert.Equal(t, referenced+10, countBuckets(arch))
+
+	opts.Force = true
+	opts.DryRun = true
+	assert.NoError(t, Prune(arch, opts))
+	assert.Equal(t, referenced+10, countBuckets(arch))
+
+	opts.DryRun = false
+	assert.NoError(t, Prune(arch, opts))
+	assert.Equal(t, referenced, countBuckets(arch))
+	assert.Equal(t, 0, countMissing(arch, testOptions()))
+}
+
+// testLedgerSource holds a hash-chained run of ledgers starting at genesis,
+// with one payment in every 10th ledger.
+type testLedgerSource []LedgerData
+
+func makeTestLedgerSource(t *testing.T, n uint32) testLedgerSource {
+	var src testLedgerSource
+	var prev xdr.Hash
+	for seq := uint32(1); seq <= n; seq++ {
+		var l LedgerData
+		if seq%10 == 0 {
+			l.Txs = []xdr.TransactionEnvelope{testPaymentTx(t, testSource, xdr.Int64(seq))}
+			l.Results = []xdr.TransactionResultPair{{
+				Result: xdr.TransactionResult{
+					FeeCharged: 100,
+					Result: xdr.TransactionResultResult{
+						Code:    xdr.TransactionResultCodeTxSuccess,
+						Results: &[]xdr.OperationResult{},
+					},
+				},
+			}}
+		}
+		txSetHash, err := HashTxSet(&xdr.TransactionSet{PreviousLedgerHash: prev, Txs: l.Txs})
+		assert.NoError(t, err)
+		resultHash, err := HashXdr(&xdr.TransactionResultSet{Results: l.Results})
+		assert.NoError(t, err)
+		l.Header.Header = xdr.LedgerHeader{
+			LedgerSeq:          xdr.Uint32(seq),
+			PreviousLedgerHash: prev,
+			ScpValue:           xdr.StellarValue{TxSetHash: xdr.Hash(txSetHash)},
+			TxSetResultHash:    xdr.Hash(resultHash),
+		}
+		h, err := HashXdr(&l.Header.Header)
+		assert.NoError(t, err)
+		l.Header.Hash = xdr.Hash(h)
+		prev = l.Header.Hash
+		src = append(src, l)
+	}
+	return src
+}
+
+func (s testLedgerSource) GetLedgers(first uint32, last uint32) ([]LedgerData, error) {
+	var out []LedgerData
+	for _, l := range s {
+		seq := uint32(l.Header.Header.LedgerSeq)
+		if seq >= first && seq <= last {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func TestPublish(t *testing.T) {
+	defer cleanup()
+	arch := GetTestArchive()
+	// The third checkpoint is incomplete and must be skipped.
+	src := makeTestLedgerSource(t, 0xbf-10)
+	opts := testOptions()
+	opts.Range = Range{Low: 0x3f, High: 0xbf}
+	assert.NoError(t, Publish(src, arch, "test", opts))
+
+	has, err := arch.GetRootHAS()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x7f), has.CurrentLedger)
+	assert.Equal(t, "test", has.Server)
+	assert.False(t, arch.CategoryCheckpointExists("history", 0xbf))
+
+	for _, chk := range []uint32{0x3f, 0x7f} {
+		for _, cat := range []string{"ledger", "transactions", "results"} {
+			assert.NoError(t, arch.VerifyCategoryCheckpoint(cat, chk))
+		}
+	}
+	for seq := uint32(10); seq <= 0x7f; seq += 10 {
+		assert.Equal(t, arch.expectTxSetHashes[seq], arch.actualTxSetHashes[seq], "ledger %d", seq)
+	}
+	assert.Equal(t, 0x7f/10, len(arch.actualTxSetHashes))
+	assert.Equal(t, arch.expectLedgerHashes[0x7e], arch.actualLedgerHashes[0x7e])
+	assert.Equal(t, arch.expectTxResultSetHashes[0x46], arch.actualTxResultSetHashes[0x46])
+}
+
 func TestXdrDecode(t *testing.T) {
 
 	xdrbytes := []byte{
@@ -0,0 +1,166 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"sync"
+)
+
+// CommandTemplates holds the shell command templates of a stellar-core
+// style [HISTORY.<name>] archive. As in stellar-core, "{0}" and "{1}" are
+// replaced by the remote and local paths in `get`, by the local and remote
+// paths in `put`, and by the remote directory in `mkdir`.
+type CommandTemplates struct {
+	Get   string `toml:"get"`
+	Put   string `toml:"put"`
+	Mkdir string `toml:"mkdir"`
+}
+
+type CommandArchiveBackend struct {
+	templates CommandTemplates
+
+	mutex sync.Mutex
+	dirs  map[string]bool
+}
+
+func expandCommand(tmpl string, args ...string) string {
+	for i, arg := range args {
+		tmpl = strings.Replace(tmpl, fmt.Sprintf("{%d}", i), arg, -1)
+	}
+	return tmpl
+}
+
+func runCommand(cmdline string) error {
+	out, err := exec.Command("sh", "-c", cmdline).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("command '%s' failed: %s: %s",
+			cmdline, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// tempFileReader removes its backing temporary file once closed.
+type tempFileReader struct {
+	*os.File
+}
+
+func (t tempFileReader) Close() error {
+	err := t.File.Close()
+	os.Remove(t.File.Name())
+	return err
+}
+
+func (b *CommandArchiveBackend) fetch(pth string) (*os.File, error) {
+	if b.templates.Get == "" {
+		return nil, errors.New("no get command configured")
+	}
+	tmp, err := ioutil.TempFile("", "archivist-get")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	err = runCommand(expandCommand(b.templates.Get, pth, tmp.Name()))
+	if err != nil {
+		os.Remove(tmp.Name())
+		return nil, err
+	}
+	return os.Open(tmp.Name())
+}
+
+func (b *CommandArchiveBackend) GetFile(pth string) (io.ReadCloser, error) {
+	f, err := b.fetch(pth)
+	if err != nil {
+		return nil, err
+	}
+	return tempFileReader{f}, nil
+}
+
+// Exists has no cheaper command to rely on than `get`, so it downloads the
+// whole file to a temporary file and discards it. Checking every file of an
+// archive (as scan, or mirror without --force, do) costs as much as
+// downloading the archive.
+func (b *CommandArchiveBackend) Exists(pth string) bool {
+	f, err := b.fetch(pth)
+	if err != nil {
+		return false
+	}
+	tempFileReader{f}.Close()
+	return true
+}
+
+func (b *CommandArchiveBackend) makeDir(dir string) error {
+	if b.templates.Mkdir == "" {
+		return nil
+	}
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.dirs[dir] {
+		return nil
+	}
+	if err := runCommand(expandCommand(b.templates.Mkdir, dir)); err != nil {
+		return err
+	}
+	b.dirs[dir] = true
+	return nil
+}
+
+func (b *CommandArchiveBackend) PutFile(pth string, in io.ReadCloser) error {
+	defer in.Close()
+	if b.templates.Put == "" {
+		return errors.New("no put command configured")
+	}
+	if err := b.makeDir(path.Dir(pth)); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile("", "archivist-put")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	_, err = io.Copy(tmp, in)
+	tmp.Close()
+	if err != nil {
+		return err
+	}
+	return runCommand(expandCommand(b.templates.Put, tmp.Name(), pth))
+}
+
+func (b *CommandArchiveBackend) RemoveFile(pth string) error {
+	return errors.New("RemoveFile not available for command archives")
+}
+
+func (b *CommandArchiveBackend) ListFiles(pth string) (chan string, chan error) {
+	ch := make(chan string)
+	er := make(chan error)
+	close(ch)
+	go func() {
+		er <- errors.New("ListFiles not available for command archives")
+		close(er)
+	}()
+	return ch, er
+}
+
+func (b *CommandArchiveBackend) CanListFiles() bool {
+	return false
+}
+
+func makeCommandBackend(name string, opts ConnectOptions) (ArchiveBackend, error) {
+	templates, ok := opts.Commands[name]
+	if !ok {
+		return nil, errors.New("no commands configured for archive '" + name + "'")
+	}
+	return &CommandArchiveBackend{
+		templates: templates,
+		dirs:      make(map[string]bool),
+	}, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommandArchive(t *testing.T) {
+	d, e := ioutil.TempDir("", "archivist-cmd")
+	if !assert.NoError(t, e) {
+		return
+	}
+	defer os.RemoveAll(d)
+
+	opts := ConnectOptions{
+		Commands: map[string]CommandTemplates{
+			"local": {
+				Get:   "cp " + d + "/{0} {1}",
+				Put:   "cp {0} " + d + "/{1}",
+				Mkdir: "mkdir -p " + d + "/{0}",
+			},
+		},
+	}
+	_, e = Connect("cmd://missing", opts)
+	assert.Error(t, e)
+
+	arch := MustConnect("cmd://local", opts)
+	assert.False(t, arch.backend.CanListFiles())
+
+	bucket, e := arch.AddRandomBucket()
+	assert.NoError(t, e)
+	assert.True(t, arch.BucketExists(bucket))
+	_, e = os.Stat(path.Join(d, BucketPath(bucket)))
+	assert.NoError(t, e)
+
+	rdr, e := arch.backend.GetFile(BucketPath(bucket))
+	assert.NoError(t, e)
+	buf, e := ioutil.ReadAll(rdr)
+	assert.NoError(t, e)
+	assert.NoError(t, rdr.Close())
+	assert.Len(t, buf, 1024)
+
+	assert.False(t, arch.CategoryCheckpointExists("ledger", 0x3f))
+	_, e = arch.backend.GetFile(CategoryCheckpointPath("ledger", 0x3f))
+	assert.Error(t, e)
+}
@@ -0,0 +1,47 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// Extract builds a self-contained archive in dst holding only the
+// checkpoints of opts.Range: their checkpoint files, the buckets referenced
+// by their HAS files and, as root HAS, the HAS of the last checkpoint
+// copied. Unlike Mirror, nothing in dst refers to ledgers outside the range.
+func Extract(src *Archive, dst *Archive, opts *CommandOptions) error {
+	rootHAS, e := src.GetRootHAS()
+	if e != nil {
+		return e
+	}
+
+	opts.Range = opts.Range.clamp(rootHAS.Range())
+	if opts.Range.Size() == 0 {
+		return errors.New("no checkpoints to extract in range " + opts.Range.String())
+	}
+
+	var last uint32
+	for chk := range opts.Range.Checkpoints() {
+		last = chk
+	}
+	lastHAS, e := src.GetCheckpointHAS(last)
+	if e != nil {
+		return e
+	}
+
+	errs := copyCheckpoints(src, dst, opts)
+	if errs != 0 {
+		return fmt.Errorf("%d errors while extracting", errs)
+	}
+
+	log.Printf("Setting root HAS to checkpoint 0x%8.8x", last)
+	if opts.DryRun {
+		return nil
+	}
+	return dst.PutRootHAS(lastHAS, opts)
+}
@@ -5,31 +5,50 @@
 package historyarchive
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
+	"regexp"
+	"strings"
+	"sync"
 )
 
 type HttpArchiveBackend struct {
-	client http.Client
-	base   url.URL
+	client    http.Client
+	base      url.URL
+	listIndex bool
+
+	mutex       sync.Mutex
+	collections map[string]bool
 }
 
+// Matches the link targets of an HTML directory index page, as produced by
+// nginx's autoindex, Apache's mod_autoindex and most WebDAV servers.
+var hrefRx = regexp.MustCompile(`(?i)href\s*=\s*"([^"]*)"`)
+
 func checkResp(r *http.Response) error {
 	if r.StatusCode >= 200 && r.StatusCode < 400 {
 		return nil
 	} else {
-		return fmt.Errorf("Bad HTTP response '%s' for GET '%s'",
-			r.Status, r.Request.URL.String())
+		return fmt.Errorf("Bad HTTP response '%s' for %s '%s'",
+			r.Status, r.Request.Method, r.Request.URL.String())
 	}
 }
 
-func (b *HttpArchiveBackend) GetFile(pth string) (io.ReadCloser, error) {
+func (b *HttpArchiveBackend) url(pth string) url.URL {
 	var derived url.URL = b.base
 	derived.Path = path.Join(derived.Path, pth)
+	return derived
+}
+
+func (b *HttpArchiveBackend) GetFile(pth string) (io.ReadCloser, error) {
+	derived := b.url(pth)
 	resp, err := b.client.Get(derived.String())
 	if err != nil {
 		if resp != nil && resp.Body != nil {
@@ -48,8 +67,7 @@ func (b *HttpArchiveBackend) GetFile(pth string) (io.ReadCloser, error) {
 }
 
 func (b *HttpArchiveBackend) Exists(pth string) bool {
-	var derived url.URL = b.base
-	derived.Path = path.Join(derived.Path, pth)
+	derived := b.url(pth)
 	resp, err := b.client.Head(derived.String())
 	if resp != nil && resp.Body != nil {
 		resp.Body.Close()
@@ -57,26 +75,203 @@ func (b *HttpArchiveBackend) Exists(pth string) bool {
 	return err == nil && resp != nil && checkResp(resp) == nil
 }
 
+func (b *HttpArchiveBackend) do(method string, u url.URL, body []byte) (*http.Response, error) {
+	var rdr io.Reader
+	if body != nil {
+		rdr = bytes.NewReader(body)
+	}
+	req, err := http.NewRequest(method, u.String(), rdr)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := b.client.Do(req)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+// makeCollections creates the WebDAV collections leading up to (and
+// including) dir, skipping any that this backend already created.
+func (b *HttpArchiveBackend) makeCollections(dir string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.collections == nil {
+		b.collections = make(map[string]bool)
+	}
+	pth := ""
+	for _, part := range strings.Split(path.Clean(dir), "/") {
+		if part == "" || part == "." {
+			continue
+		}
+		pth = path.Join(pth, part)
+		if b.collections[pth] {
+			continue
+		}
+		u := b.url(pth)
+		u.Path += "/"
+		resp, err := b.do("MKCOL", u, nil)
+		if err != nil {
+			return err
+		}
+		// 405 Method Not Allowed means the collection already exists.
+		if resp.StatusCode != http.StatusMethodNotAllowed {
+			if err = checkResp(resp); err != nil {
+				return err
+			}
+		}
+		b.collections[pth] = true
+	}
+	return nil
+}
+
+// markCollections records that dir and its parents exist, so they are not
+// created again before later PUTs.
+func (b *HttpArchiveBackend) markCollections(dir string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.collections == nil {
+		b.collections = make(map[string]bool)
+	}
+	for pth := path.Clean(dir); pth != "." && pth != "/" && pth != ""; pth = path.Dir(pth) {
+		b.collections[pth] = true
+	}
+}
+
+// contentLength returns the number of bytes left in `in`, or -1 if it isn't
+// known without reading it.
+func contentLength(in io.Reader) int64 {
+	switch r := in.(type) {
+	case interface {
+		Len() int
+	}:
+		return int64(r.Len())
+	case interface {
+		Stat() (os.FileInfo, error)
+	}:
+		if fi, err := r.Stat(); err == nil && fi.Mode().IsRegular() {
+			return fi.Size()
+		}
+	}
+	return -1
+}
+
 func (b *HttpArchiveBackend) PutFile(pth string, in io.ReadCloser) error {
-	in.Close()
-	return errors.New("PutFile not available over HTTP")
+	defer in.Close()
+	// The body is streamed, so it can't be sent again after a 409 Conflict:
+	// missing parent collections are created before the PUT. Servers that
+	// create directories on PUT may reject MKCOL, so that error is only
+	// reported if the PUT fails too.
+	mkErr := b.makeCollections(path.Dir(pth))
+	derived := b.url(pth)
+	req, err := http.NewRequest("PUT", derived.String(), in)
+	if err != nil {
+		return err
+	}
+	if n := contentLength(in); n > 0 {
+		req.ContentLength = n
+	} else if n == 0 {
+		req.Body = http.NoBody
+	}
+	resp, err := b.client.Do(req)
+	if resp != nil && resp.Body != nil {
+		resp.Body.Close()
+	}
+	if err == nil {
+		err = checkResp(resp)
+	}
+	if err != nil {
+		if mkErr != nil {
+			return fmt.Errorf("%s (creating collections: %s)", err, mkErr)
+		}
+		return err
+	}
+	b.markCollections(path.Dir(pth))
+	return nil
+}
+
+func (b *HttpArchiveBackend) RemoveFile(pth string) error {
+	resp, err := b.do("DELETE", b.url(pth), nil)
+	if err != nil {
+		return err
+	}
+	return checkResp(resp)
+}
+
+// listDir reads the directory index page at pth and sends every file found
+// below it on ch, descending into subdirectories.
+func (b *HttpArchiveBackend) listDir(pth string, ch chan string, errs chan error) {
+	dir := b.url(pth)
+	if !strings.HasSuffix(dir.Path, "/") {
+		dir.Path += "/"
+	}
+	resp, err := b.client.Get(dir.String())
+	if err != nil {
+		errs <- err
+		return
+	}
+	defer resp.Body.Close()
+	if err = checkResp(resp); err != nil {
+		errs <- err
+		return
+	}
+	page, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		errs <- err
+		return
+	}
+	seen := make(map[string]bool)
+	for _, m := range hrefRx.FindAllSubmatch(page, -1) {
+		ref, err := url.Parse(string(m[1]))
+		if err != nil {
+			continue
+		}
+		u := dir.ResolveReference(ref)
+		if u.Host != dir.Host || !strings.HasPrefix(u.Path, dir.Path) {
+			continue
+		}
+		name := strings.TrimPrefix(u.Path, dir.Path)
+		isDir := strings.HasSuffix(name, "/")
+		name = strings.TrimSuffix(name, "/")
+		// Only direct children; skip sort links, parent links and the like.
+		if name == "" || strings.Contains(name, "/") || seen[name] {
+			continue
+		}
+		seen[name] = true
+		if isDir {
+			b.listDir(path.Join(pth, name), ch, errs)
+		} else {
+			ch <- path.Join(pth, name)
+		}
+	}
 }
 
 func (b *HttpArchiveBackend) ListFiles(pth string) (chan string, chan error) {
 	ch := make(chan string)
 	er := make(chan error)
-	close(ch)
-	er <- errors.New("ListFiles not available over HTTP")
-	close(er)
+	if !b.listIndex {
+		close(ch)
+		go func() {
+			er <- errors.New("ListFiles not available over HTTP")
+			close(er)
+		}()
+		return ch, er
+	}
+	go func() {
+		b.listDir(pth, ch, er)
+		close(ch)
+		close(er)
+	}()
 	return ch, er
 }
 
 func (b *HttpArchiveBackend) CanListFiles() bool {
-	return false
+	return b.listIndex
 }
 
 func makeHttpBackend(base *url.URL, opts ConnectOptions) ArchiveBackend {
 	return &HttpArchiveBackend{
-		base: *base,
+		base:      *base,
+		listIndex: opts.HttpListIndex,
 	}
 }
@@ -0,0 +1,162 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// davServer is a minimal WebDAV server that requires collections to exist
+// before files are PUT into them, and renders directory index pages.
+type davServer struct {
+	mutex sync.Mutex
+	files map[string][]byte
+	dirs  map[string]bool
+}
+
+func (s *davServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pth := path.Clean(r.URL.Path)
+	switch r.Method {
+	case "MKCOL":
+		if s.dirs[pth] {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		s.dirs[pth] = true
+		w.WriteHeader(http.StatusCreated)
+	case "PUT":
+		if !s.dirs[path.Dir(pth)] {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		buf, _ := ioutil.ReadAll(r.Body)
+		s.files[pth] = buf
+		w.WriteHeader(http.StatusCreated)
+	case "GET", "HEAD":
+		if buf, ok := s.files[pth]; ok {
+			w.Write(buf)
+			return
+		}
+		if !s.dirs[pth] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprint(w, `<html><body><a href="../">../</a>`)
+		fmt.Fprint(w, `<a href="?C=N;O=D">Name</a>`)
+		for d := range s.dirs {
+			if path.Dir(d) == pth && d != pth {
+				fmt.Fprintf(w, `<a href="%s/">%s/</a>`, path.Base(d), path.Base(d))
+			}
+		}
+		for f := range s.files {
+			if path.Dir(f) == pth {
+				fmt.Fprintf(w, `<a href="%s">%s</a>`, f, path.Base(f))
+			}
+		}
+		fmt.Fprint(w, `</body></html>`)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHttpPutAndList(t *testing.T) {
+	srv := httptest.NewServer(&davServer{
+		files: make(map[string][]byte),
+		dirs:  map[string]bool{"/": true, "/archive": true},
+	})
+	defer srv.Close()
+
+	arch := MustConnect(srv.URL+"/archive", ConnectOptions{HttpListIndex: true})
+	assert.True(t, arch.backend.CanListFiles())
+
+	bucket, e := arch.AddRandomBucket()
+	assert.NoError(t, e)
+	assert.NoError(t, arch.AddRandomCheckpointFile("ledger", 0x3f))
+	assert.True(t, arch.BucketExists(bucket))
+	assert.True(t, arch.CategoryCheckpointExists("ledger", 0x3f))
+
+	rdr, e := arch.backend.GetFile(BucketPath(bucket))
+	assert.NoError(t, e)
+	buf, e := ioutil.ReadAll(rdr)
+	rdr.Close()
+	assert.NoError(t, e)
+	assert.Len(t, buf, 1024)
+
+	ch, errs := arch.backend.ListFiles("")
+	var files []string
+	for f := range ch {
+		files = append(files, f)
+	}
+	assert.Equal(t, uint32(0), drainErrors(errs))
+	sort.Strings(files)
+	assert.Equal(t, []string{
+		BucketPath(bucket),
+		CategoryCheckpointPath("ledger", 0x3f),
+	}, files)
+
+	hashes, errs := arch.ListAllBucketHashes()
+	var found []Hash
+	for h := range hashes {
+		found = append(found, h)
+	}
+	assert.Equal(t, uint32(0), drainErrors(errs))
+	assert.Equal(t, []Hash{bucket}, found)
+}
+
+func TestHttpListWithoutIndex(t *testing.T) {
+	arch := MustConnect("https://example.com/archive", ConnectOptions{})
+	assert.False(t, arch.backend.CanListFiles())
+	ch, errs := arch.backend.ListFiles("bucket")
+	for range ch {
+		t.Fatal("unexpected file")
+	}
+	e := <-errs
+	assert.Error(t, e)
+	assert.True(t, strings.Contains(e.Error(), "not available"))
+}
+
+func TestHttpPutFileStreams(t *testing.T) {
+	var lengths []int64
+	var methods []string
+	files := make(map[string][]byte)
+	// A server that creates directories on PUT and doesn't implement MKCOL.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		switch r.Method {
+		case "PUT":
+			lengths = append(lengths, r.ContentLength)
+			files[r.URL.Path], _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotImplemented)
+		}
+	}))
+	defer srv.Close()
+
+	backend := MustConnect(srv.URL+"/archive", ConnectOptions{}).backend
+	body := []byte("checkpoint")
+
+	assert.NoError(t, backend.PutFile("a/b/file1", bytesReadCloser{bytes.NewReader(body)}))
+	// Once a PUT succeeded its directories aren't created again.
+	assert.NoError(t, backend.PutFile("a/b/file2", ioutil.NopCloser(bytes.NewReader(body))))
+
+	assert.Equal(t, []string{"MKCOL", "PUT", "PUT"}, methods)
+	assert.Equal(t, []int64{int64(len(body)), -1}, lengths)
+	assert.Equal(t, body, files["/archive/a/b/file1"])
+	assert.Equal(t, body, files["/archive/a/b/file2"])
+}
@@ -5,24 +5,359 @@
 package historyarchive
 
 import (
+	"bytes"
 	"compress/gzip"
+	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"os"
 	"path"
+	"reflect"
 	"strings"
 
+	"github.com/stellar/go/amount"
+	"github.com/stellar/go/strkey"
 	"github.com/stellar/go/xdr"
 )
 
-func DumpXdrAsJson(args []string) error {
-	var lhe xdr.LedgerHeaderHistoryEntry
-	var the xdr.TransactionHistoryEntry
-	var thre xdr.TransactionHistoryResultEntry
-	var bke xdr.BucketEntry
-	var scp xdr.ScpHistoryEntry
-	var tmp interface{}
+// XdrFilter selects the records printed by DumpXdrAsJson. Zero values
+// match everything. In transaction and result files, the filter is applied
+// to each transaction of a ledger, and ledgers left empty are skipped.
+// Accounts and OperationTypes only apply to the files whose records carry
+// them (see FilterApplies); other files are not filtered by them.
+type XdrFilter struct {
+	// Accounts keeps transactions and bucket entries mentioning any of these
+	// accounts.
+	Accounts []xdr.AccountId
+	// OperationTypes keeps transactions and results with an operation of
+	// any of these types.
+	OperationTypes []xdr.OperationType
+	// LowLedger and HighLedger bound the ledger of the records kept;
+	// HighLedger is ignored when 0.
+	LowLedger  uint32
+	HighLedger uint32
+}
+
+// ParseOperationType returns the operation type named by s, either as in
+// horizon ("path_payment") or as the XDR enum name ("OperationTypePathPayment").
+func ParseOperationType(s string) (xdr.OperationType, error) {
+	norm := strings.ToLower(strings.Replace(s, "_", "", -1))
+	for i := int32(0); xdr.OperationType(i).ValidEnum(i); i++ {
+		name := xdr.OperationType(i).String()
+		short := strings.TrimPrefix(name, "OperationType")
+		if norm == strings.ToLower(name) || norm == strings.ToLower(short) {
+			return xdr.OperationType(i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown operation type '%s'", s)
+}
+
+// AddAccount adds the account with the given strkey address to the filter.
+func (f *XdrFilter) AddAccount(address string) error {
+	var aid xdr.AccountId
+	if err := aid.SetAddress(address); err != nil {
+		return fmt.Errorf("invalid account '%s': %s", address, err)
+	}
+	f.Accounts = append(f.Accounts, aid)
+	return nil
+}
+
+func (f *XdrFilter) matchLedger(seq uint32) bool {
+	if seq < f.LowLedger {
+		return false
+	}
+	return f.HighLedger == 0 || seq <= f.HighLedger
+}
+
+// hasAccounts and hasOperationTypes report whether records of the type of
+// rec carry accounts and operation types. Results only carry the hash of
+// their transaction, ledger headers and SCP messages carry neither.
+func hasAccounts(rec interface{}) bool {
+	switch rec.(type) {
+	case *xdr.TransactionHistoryEntry, *xdr.BucketEntry:
+		return true
+	}
+	return false
+}
+
+func hasOperationTypes(rec interface{}) bool {
+	switch rec.(type) {
+	case *xdr.TransactionHistoryEntry, *xdr.TransactionHistoryResultEntry:
+		return true
+	}
+	return false
+}
+
+// FilterApplies reports whether the account and operation type criteria of
+// the filter apply to records of the type of rec.
+func (f *XdrFilter) FilterApplies(rec interface{}) (accounts bool, operationTypes bool) {
+	return len(f.Accounts) == 0 || hasAccounts(rec),
+		len(f.OperationTypes) == 0 || hasOperationTypes(rec)
+}
+
+// match reports whether v, or anything it contains, satisfies the account
+// and operation type criteria of the filter that apply to records of the
+// type of rec.
+func (f *XdrFilter) match(rec interface{}, v interface{}) bool {
+	if len(f.Accounts) > 0 && hasAccounts(rec) && !walkXdr(reflect.ValueOf(v), f.mentionsAccount) {
+		return false
+	}
+	if len(f.OperationTypes) > 0 && hasOperationTypes(rec) && !walkXdr(reflect.ValueOf(v), f.mentionsOperationType) {
+		return false
+	}
+	return true
+}
+
+func (f *XdrFilter) mentionsAccount(v reflect.Value) bool {
+	aid, ok := v.Interface().(xdr.AccountId)
+	if !ok {
+		return false
+	}
+	for _, a := range f.Accounts {
+		if aid.Equals(a) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *XdrFilter) mentionsOperationType(v reflect.Value) bool {
+	ty, ok := v.Interface().(xdr.OperationType)
+	if !ok {
+		return false
+	}
+	for _, t := range f.OperationTypes {
+		if ty == t {
+			return true
+		}
+	}
+	return false
+}
+
+// walkXdr reports whether pred holds for v or any value nested in it.
+func walkXdr(v reflect.Value, pred func(reflect.Value) bool) bool {
+	if !v.IsValid() {
+		return false
+	}
+	if v.CanInterface() && pred(v) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		return !v.IsNil() && walkXdr(v.Elem(), pred)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if walkXdr(v.Field(i), pred) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return false
+		}
+		for i := 0; i < v.Len(); i++ {
+			if walkXdr(v.Index(i), pred) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// apply returns the part of an archive record selected by the filter, or
+// nil if nothing is selected.
+func (f *XdrFilter) apply(rec interface{}) interface{} {
+	switch r := rec.(type) {
+	case *xdr.LedgerHeaderHistoryEntry:
+		if !f.matchLedger(uint32(r.Header.LedgerSeq)) {
+			return nil
+		}
+	case *xdr.TransactionHistoryEntry:
+		if !f.matchLedger(uint32(r.LedgerSeq)) {
+			return nil
+		}
+		out := *r
+		out.TxSet.Txs = nil
+		for _, tx := range r.TxSet.Txs {
+			if f.match(r, tx) {
+				out.TxSet.Txs = append(out.TxSet.Txs, tx)
+			}
+		}
+		if len(out.TxSet.Txs) == 0 {
+			return nil
+		}
+		return &out
+	case *xdr.TransactionHistoryResultEntry:
+		if !f.matchLedger(uint32(r.LedgerSeq)) {
+			return nil
+		}
+		out := *r
+		out.TxResultSet.Results = nil
+		for _, res := range r.TxResultSet.Results {
+			if f.match(r, res) {
+				out.TxResultSet.Results = append(out.TxResultSet.Results, res)
+			}
+		}
+		if len(out.TxResultSet.Results) == 0 {
+			return nil
+		}
+		return &out
+	case *xdr.ScpHistoryEntry:
+		if r.V0 == nil || !f.matchLedger(uint32(r.V0.LedgerMessages.LedgerSeq)) {
+			return nil
+		}
+	case *xdr.BucketEntry:
+		if r.LiveEntry != nil && !f.matchLedger(uint32(r.LiveEntry.LastModifiedLedgerSeq)) {
+			return nil
+		}
+		if !f.match(r, r) {
+			return nil
+		}
+	}
+	return rec
+}
+
+// jsonObject is a JSON object that keeps the field order of the XDR
+// struct it was made from.
+type jsonObject struct {
+	keys   []string
+	values []interface{}
+}
+
+func (o *jsonObject) set(k string, v interface{}) {
+	o.keys = append(o.keys, k)
+	o.values = append(o.values, v)
+}
+
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		kb, err := json.Marshal(k)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(kb)
+		buf.WriteByte(':')
+		vb, err := json.Marshal(o.values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(vb)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+type xdrEnum interface {
+	ValidEnum(int32) bool
+	String() string
+}
+
+func publicKeyAddress(pk xdr.PublicKey) string {
+	if pk.Ed25519 == nil {
+		return ""
+	}
+	return strkey.MustEncode(strkey.VersionByteAccountID, pk.Ed25519[:])
+}
+
+func assetCode(code []byte) string {
+	return strings.TrimRight(string(code), "\x00")
+}
+
+// XdrToJson converts an XDR value into a form that marshals to readable
+// JSON: keys are rendered as strkey addresses, assets as "native" or
+// "CODE:ISSUER", amounts as decimals, enums by name, hashes as hex and
+// other opaque data as base64. Unset union arms and optional fields are
+// left out.
+func XdrToJson(x interface{}) interface{} {
+	return xdrValueToJson(reflect.ValueOf(x))
+}
+
+func xdrValueToJson(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		return xdrValueToJson(v.Elem())
+	}
+
+	switch x := v.Interface().(type) {
+	case xdr.AccountId:
+		return publicKeyAddress(xdr.PublicKey(x))
+	case xdr.NodeId:
+		return publicKeyAddress(xdr.PublicKey(x))
+	case xdr.PublicKey:
+		return publicKeyAddress(x)
+	case xdr.SignerKey:
+		return x.Address()
+	case xdr.Asset:
+		var typ, code, issuer string
+		if err := x.Extract(&typ, &code, &issuer); err != nil {
+			return nil
+		}
+		if x.Type == xdr.AssetTypeAssetTypeNative {
+			return typ
+		}
+		return code + ":" + issuer
+	case xdr.AllowTrustOpAsset:
+		if x.AssetCode4 != nil {
+			return assetCode(x.AssetCode4[:])
+		}
+		if x.AssetCode12 != nil {
+			return assetCode(x.AssetCode12[:])
+		}
+		return nil
+	case xdr.Int64:
+		return amount.String(x)
+	case xdr.Price:
+		return x.String()
+	case xdrEnum:
+		if v.Kind() == reflect.Int32 {
+			return x.String()
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		obj := &jsonObject{}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if (f.Kind() == reflect.Ptr || f.Kind() == reflect.Slice) && f.IsNil() {
+				continue
+			}
+			obj.set(v.Type().Field(i).Name, xdrValueToJson(f))
+		}
+		return obj
+	case reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			buf := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(buf), v)
+			return hex.EncodeToString(buf)
+		}
+		fallthrough
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return base64.StdEncoding.EncodeToString(v.Bytes())
+		}
+		arr := make([]interface{}, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			arr[i] = xdrValueToJson(v.Index(i))
+		}
+		return arr
+	}
+	return v.Interface()
+}
+
+func DumpXdrAsJson(args []string, filter XdrFilter) error {
 	var rdr io.ReadCloser
 	var err error
 
@@ -40,23 +375,32 @@ func DumpXdrAsJson(args []string) error {
 		}
 
 		base := path.Base(arg)
+		var newRecord func() interface{}
 		if strings.HasPrefix(base, "bucket") {
-			tmp = &bke
+			newRecord = func() interface{} { return &xdr.BucketEntry{} }
 		} else if strings.HasPrefix(base, "ledger") {
-			tmp = &lhe
+			newRecord = func() interface{} { return &xdr.LedgerHeaderHistoryEntry{} }
 		} else if strings.HasPrefix(base, "transactions") {
-			tmp = &the
+			newRecord = func() interface{} { return &xdr.TransactionHistoryEntry{} }
 		} else if strings.HasPrefix(base, "results") {
-			tmp = &thre
+			newRecord = func() interface{} { return &xdr.TransactionHistoryResultEntry{} }
 		} else if strings.HasPrefix(base, "scp") {
-			tmp = &scp
+			newRecord = func() interface{} { return &xdr.ScpHistoryEntry{} }
 		} else {
 			return fmt.Errorf("Error: unrecognized XDR file type %s", base)
 		}
+		accounts, operationTypes := filter.FilterApplies(newRecord())
+		if !accounts {
+			fmt.Fprintf(os.Stderr, "%s: records carry no accounts, not filtering by account\n", arg)
+		}
+		if !operationTypes {
+			fmt.Fprintf(os.Stderr, "%s: records carry no operations, not filtering by operation type\n", arg)
+		}
 		xr := NewXdrStream(rdr)
 		n := 0
 		for {
-			if err = xr.ReadOne(&tmp); err != nil {
+			rec := newRecord()
+			if err = xr.ReadOne(rec); err != nil {
 				if err == io.EOF {
 					break
 				} else {
@@ -65,11 +409,16 @@ func DumpXdrAsJson(args []string) error {
 				}
 			}
 			n++
-			buf, err := json.MarshalIndent(tmp, "", "    ")
+			sel := filter.apply(rec)
+			if sel == nil {
+				continue
+			}
+			buf, err := json.MarshalIndent(XdrToJson(sel), "", "    ")
 			if err != nil {
 				return err
 			}
 			os.Stdout.Write(buf)
+			os.Stdout.Write([]byte("\n"))
 		}
 		xr.Close()
 	}
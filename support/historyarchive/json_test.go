@@ -0,0 +1,121 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stellar/go/xdr"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testSource      = "GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H"
+	testDestination = "GAHK7EEG2WWHVKDNT4CEQFZGKF2LGDSW2IVM4S5DP42RBW3K6BTODB4A"
+)
+
+func testPaymentTx(t *testing.T, source string, amount xdr.Int64) xdr.TransactionEnvelope {
+	var src, dst xdr.AccountId
+	assert.NoError(t, src.SetAddress(source))
+	assert.NoError(t, dst.SetAddress(testDestination))
+	body, err := xdr.NewOperationBody(xdr.OperationTypePayment, xdr.PaymentOp{
+		Destination: dst,
+		Asset:       xdr.MustNewCreditAsset("USD", source),
+		Amount:      amount,
+	})
+	assert.NoError(t, err)
+	return xdr.TransactionEnvelope{
+		Tx: xdr.Transaction{
+			SourceAccount: src,
+			Fee:           100,
+			SeqNum:        1,
+			Operations:    []xdr.Operation{{Body: body}},
+		},
+	}
+}
+
+func TestXdrToJson(t *testing.T) {
+	tx := testPaymentTx(t, testSource, 123456789)
+	buf, err := json.Marshal(XdrToJson(tx))
+	assert.NoError(t, err)
+
+	var out map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf, &out))
+	txj := out["Tx"].(map[string]interface{})
+	assert.Equal(t, testSource, txj["SourceAccount"])
+	op := txj["Operations"].([]interface{})[0].(map[string]interface{})
+	assert.NotContains(t, op, "SourceAccount")
+	opBody := op["Body"].(map[string]interface{})
+	assert.Equal(t, "OperationTypePayment", opBody["Type"])
+	assert.NotContains(t, opBody, "CreateAccountOp")
+	payment := opBody["PaymentOp"].(map[string]interface{})
+	assert.Equal(t, testDestination, payment["Destination"])
+	assert.Equal(t, "USD:"+testSource, payment["Asset"])
+	assert.Equal(t, "12.3456789", payment["Amount"])
+}
+
+func TestXdrFilter(t *testing.T) {
+	entry := &xdr.TransactionHistoryEntry{
+		LedgerSeq: 100,
+		TxSet: xdr.TransactionSet{
+			Txs: []xdr.TransactionEnvelope{
+				testPaymentTx(t, testSource, 10),
+				testPaymentTx(t, testDestination, 20),
+			},
+		},
+	}
+
+	var f XdrFilter
+	assert.Equal(t, entry, f.apply(entry))
+
+	assert.NoError(t, f.AddAccount(testSource))
+	sel := f.apply(entry).(*xdr.TransactionHistoryEntry)
+	assert.Len(t, sel.TxSet.Txs, 1)
+	assert.Equal(t, xdr.Int64(10), sel.TxSet.Txs[0].Tx.Operations[0].Body.PaymentOp.Amount)
+	assert.Len(t, entry.TxSet.Txs, 2)
+
+	f = XdrFilter{LowLedger: 101}
+	assert.Nil(t, f.apply(entry))
+	f = XdrFilter{LowLedger: 50, HighLedger: 100}
+	assert.NotNil(t, f.apply(entry))
+
+	ty, err := ParseOperationType("create_account")
+	assert.NoError(t, err)
+	assert.Equal(t, xdr.OperationTypeCreateAccount, ty)
+	f = XdrFilter{OperationTypes: []xdr.OperationType{ty}}
+	assert.Nil(t, f.apply(entry))
+
+	ty, err = ParseOperationType("OperationTypePayment")
+	assert.NoError(t, err)
+	f = XdrFilter{OperationTypes: []xdr.OperationType{ty}}
+	assert.Equal(t, entry, f.apply(entry))
+
+	_, err = ParseOperationType("teleport")
+	assert.Error(t, err)
+}
+
+func TestXdrFilterRecordTypes(t *testing.T) {
+	results := &xdr.TransactionHistoryResultEntry{
+		LedgerSeq: 100,
+		TxResultSet: xdr.TransactionResultSet{
+			Results: []xdr.TransactionResultPair{{}},
+		},
+	}
+	header := &xdr.LedgerHeaderHistoryEntry{Header: xdr.LedgerHeader{LedgerSeq: 100}}
+
+	var f XdrFilter
+	assert.NoError(t, f.AddAccount(testSource))
+	accounts, operationTypes := f.FilterApplies(results)
+	assert.False(t, accounts)
+	assert.True(t, operationTypes)
+	// results and ledger headers carry no accounts, so they aren't dropped
+	assert.Equal(t, results, f.apply(results))
+	assert.Equal(t, header, f.apply(header))
+
+	f = XdrFilter{OperationTypes: []xdr.OperationType{xdr.OperationTypePayment}}
+	assert.Nil(t, f.apply(results))
+	assert.Equal(t, header, f.apply(header))
+}
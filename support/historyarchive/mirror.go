@@ -19,6 +19,19 @@ func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 
 	opts.Range = opts.Range.clamp(rootHAS.Range())
 
+	errs := copyCheckpoints(src, dst, opts)
+	e = dst.PutRootHAS(rootHAS, opts)
+	errs += noteError(e)
+	if errs != 0 {
+		return fmt.Errorf("%d errors while mirroring", errs)
+	}
+	return nil
+}
+
+// copyCheckpoints copies the checkpoint files of every checkpoint in
+// opts.Range from src to dst, along with the buckets their HAS files
+// reference, and returns the number of errors encountered.
+func copyCheckpoints(src *Archive, dst *Archive, opts *CommandOptions) uint32 {
 	log.Printf("copying range %s\n", opts.Range)
 
 	// Make a bucket-fetch map that shows which buckets are
@@ -62,14 +75,14 @@ func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 					bucketFetchMutex.Unlock()
 					if !alreadyFetching {
 						pth := BucketPath(bucket)
-						e = copyPath(src, dst, pth, opts)
+						e := copyPath(src, dst, pth, opts)
 						atomic.AddUint32(&errs, noteError(e))
 					}
 				}
 
 				for _, cat := range Categories() {
 					pth := CategoryCheckpointPath(cat, ix)
-					e = copyPath(src, dst, pth, opts)
+					e := copyPath(src, dst, pth, opts)
 					if e != nil && !categoryRequired(cat) {
 						continue
 					}
@@ -85,10 +98,5 @@ func Mirror(src *Archive, dst *Archive, opts *CommandOptions) error {
 	log.Printf("Copied %d checkpoints, %d buckets",
 		opts.Range.Size(), len(bucketFetch))
 	close(tick)
-	e = dst.PutRootHAS(rootHAS, opts)
-	errs += noteError(e)
-	if errs != 0 {
-		return fmt.Errorf("%d errors while mirroring", errs)
-	}
-	return nil
+	return errs
 }
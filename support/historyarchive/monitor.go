@@ -0,0 +1,358 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ArchiveHealth is the state of a monitored archive as of its last update.
+type ArchiveHealth struct {
+	Name string
+	// Up is false if the root HAS could not be fetched on the last update.
+	Up bool
+	// CurrentLedger is the ledger of the root HAS.
+	CurrentLedger uint32
+	// ScannedLedger is the newest checkpoint scanned so far.
+	ScannedLedger uint32
+	// LagLedgers is how far CurrentLedger is behind the newest archive.
+	LagLedgers uint32
+
+	MissingCheckpointFiles int
+	MissingBuckets         int
+	InvalidLedgers         int
+	InvalidTxSets          int
+	InvalidTxResultSets    int
+	InvalidBuckets         int
+
+	Errors     int
+	LastUpdate time.Time
+}
+
+type monitoredArchive struct {
+	arch   *Archive
+	health ArchiveHealth
+	// first is the first checkpoint scanned, 0 until the first scan.
+	first uint32
+}
+
+// Monitor keeps scanning a set of archives as they grow, and serves their
+// health as Prometheus metrics. Each update only scans the checkpoints
+// published since the previous one; the scan results of an archive are
+// kept in its cached info, which must not be cleared while monitoring.
+type Monitor struct {
+	mutex    sync.Mutex
+	archives []*monitoredArchive
+	opts     CommandOptions
+}
+
+// NewMonitor creates a monitor that scans with the given options, starting
+// at the checkpoint of opts.Range.Low. When opts.Verify is set, checkpoint
+// files and buckets are verified as they are scanned.
+func NewMonitor(opts *CommandOptions) *Monitor {
+	return &Monitor{opts: *opts}
+}
+
+// AddArchive adds an archive to the monitor, under the given name.
+func (m *Monitor) AddArchive(name string, arch *Archive) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.archives = append(m.archives, &monitoredArchive{
+		arch:   arch,
+		health: ArchiveHealth{Name: name},
+	})
+}
+
+// Health returns the state of every archive, in the order they were added.
+func (m *Monitor) Health() []ArchiveHealth {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	res := make([]ArchiveHealth, len(m.archives))
+	for i, a := range m.archives {
+		res[i] = a.health
+	}
+	return res
+}
+
+// Update fetches the root HAS of every archive and scans their new
+// checkpoints.
+func (m *Monitor) Update() {
+	m.mutex.Lock()
+	archives := append([]*monitoredArchive(nil), m.archives...)
+	m.mutex.Unlock()
+
+	updated := make([]ArchiveHealth, len(archives))
+	for i, a := range archives {
+		m.mutex.Lock()
+		updated[i] = a.health
+		m.mutex.Unlock()
+		a.update(&updated[i], m.opts)
+	}
+
+	var newest uint32
+	for _, h := range updated {
+		if h.Up && h.CurrentLedger > newest {
+			newest = h.CurrentLedger
+		}
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	for i, a := range archives {
+		h := updated[i]
+		h.LagLedgers = 0
+		if h.CurrentLedger < newest {
+			h.LagLedgers = newest - h.CurrentLedger
+		}
+		a.health = h
+	}
+}
+
+// Run updates the monitor every interval until stop is closed.
+func (m *Monitor) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		m.Update()
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *monitoredArchive) update(h *ArchiveHealth, opts CommandOptions) {
+	h.LastUpdate = time.Now()
+	has, e := a.arch.GetRootHAS()
+	if e != nil {
+		log.Printf("Error fetching root HAS of %s: %s", h.Name, e)
+		h.Up = false
+		h.Errors++
+		return
+	}
+	h.Up = true
+	h.CurrentLedger = has.CurrentLedger
+
+	h.Errors += int(a.recheckMissing(h.ScannedLedger, opts))
+
+	low := NextCheckpoint(opts.Range.Low)
+	if h.ScannedLedger != 0 {
+		low = h.ScannedLedger + CheckpointFreq
+	}
+	if low <= has.CurrentLedger {
+		log.Printf("Scanning %s in range %s", h.Name, Range{Low: low, High: has.CurrentLedger})
+		errs := a.scan(low, has.CurrentLedger, opts)
+		if errs != 0 {
+			// Leave ScannedLedger alone so the range is scanned again.
+			h.Errors += int(errs)
+		} else {
+			if a.first == 0 {
+				a.first = low
+			}
+			h.ScannedLedger = has.CurrentLedger
+		}
+	}
+	a.count(h, opts)
+}
+
+// scan notes (and if asked, verifies) the checkpoint files and referenced
+// buckets of the checkpoints from low to high, returning the number of
+// errors.
+func (a *monitoredArchive) scan(low, high uint32, opts CommandOptions) uint32 {
+	var errs uint32
+	if a.arch.backend.CanListFiles() {
+		opts.Range = Range{Low: low, High: high}
+		errs += noteError(a.arch.ScanCheckpointsFast(&opts))
+	} else {
+		// Range.Checkpoints() stops before High, so end the range one
+		// checkpoint past the last one to scan.
+		opts.Range = Range{Low: low, High: high + CheckpointFreq}
+		errs += noteError(a.arch.ScanCheckpointsSlow(&opts))
+	}
+
+	var seqs []uint32
+	a.arch.mutex.Lock()
+	for chk := low; chk >= low && chk <= high; chk += CheckpointFreq {
+		if a.arch.checkpointFiles["history"][chk] {
+			seqs = append(seqs, chk)
+		}
+	}
+	a.arch.mutex.Unlock()
+	return errs + a.arch.scanReferencedBuckets(seqs, false, &opts)
+}
+
+type checkpointFile struct {
+	cat string
+	chk uint32
+}
+
+// missingFiles returns the required checkpoint files not found between the
+// first scanned checkpoint and last.
+func (a *monitoredArchive) missingFiles(last uint32) []checkpointFile {
+	var missing []checkpointFile
+	if a.first == 0 {
+		return missing
+	}
+	rng := Range{Low: a.first, High: last + CheckpointFreq}
+	a.arch.mutex.Lock()
+	defer a.arch.mutex.Unlock()
+	for chk := range rng.Checkpoints() {
+		for _, cat := range Categories() {
+			if categoryRequired(cat) && !a.arch.checkpointFiles[cat][chk] {
+				missing = append(missing, checkpointFile{cat, chk})
+			}
+		}
+	}
+	return missing
+}
+
+// recheckMissing looks again for the files found missing up to the
+// checkpoint last, as they may have been uploaded late or repaired since.
+// It returns the number of errors.
+func (a *monitoredArchive) recheckMissing(last uint32, opts CommandOptions) uint32 {
+	var errs uint32
+	var hists []uint32
+	for _, f := range a.missingFiles(last) {
+		if !a.arch.CategoryCheckpointExists(f.cat, f.chk) {
+			continue
+		}
+		log.Printf("Found %s", CategoryCheckpointPath(f.cat, f.chk))
+		a.arch.NoteCheckpointFile(f.cat, f.chk, true)
+		if opts.Verify {
+			errs += noteError(a.arch.VerifyCategoryCheckpoint(f.cat, f.chk))
+		}
+		if f.cat == "history" {
+			hists = append(hists, f.chk)
+		}
+	}
+	if len(hists) != 0 {
+		errs += a.arch.scanReferencedBuckets(hists, false, &opts)
+	}
+	for bucket := range a.arch.CheckBucketsMissing() {
+		if a.arch.BucketExists(bucket) {
+			log.Printf("Found bucket %s", bucket)
+			a.arch.NoteExistingBucket(bucket)
+			if opts.Verify {
+				if n := noteError(a.arch.VerifyBucketHash(bucket)); n != 0 {
+					errs += n
+					a.arch.mutex.Lock()
+					a.arch.invalidBuckets++
+					a.arch.mutex.Unlock()
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func (a *monitoredArchive) count(h *ArchiveHealth, opts CommandOptions) {
+	if opts.Verify {
+		// The error only repeats what the counts below say.
+		a.arch.ReportInvalid(&opts)
+	}
+
+	missing := len(a.missingFiles(h.ScannedLedger))
+	missingBuckets := len(a.arch.CheckBucketsMissing())
+
+	a.arch.mutex.Lock()
+	defer a.arch.mutex.Unlock()
+	h.MissingCheckpointFiles = missing
+	h.MissingBuckets = missingBuckets
+	h.InvalidLedgers = a.arch.invalidLedgers
+	h.InvalidTxSets = a.arch.invalidTxSets
+	h.InvalidTxResultSets = a.arch.invalidTxResultSets
+	h.InvalidBuckets = a.arch.invalidBuckets
+}
+
+type metric struct {
+	name  string
+	typ   string
+	help  string
+	value func(h *ArchiveHealth) float64
+}
+
+var monitorMetrics = []metric{
+	{"history_archive_up", "gauge",
+		"Whether the root HAS could be fetched on the last update.",
+		func(h *ArchiveHealth) float64 {
+			if h.Up {
+				return 1
+			}
+			return 0
+		}},
+	{"history_archive_current_ledger", "gauge",
+		"Ledger of the root HAS.",
+		func(h *ArchiveHealth) float64 { return float64(h.CurrentLedger) }},
+	{"history_archive_scanned_ledger", "gauge",
+		"Newest checkpoint scanned.",
+		func(h *ArchiveHealth) float64 { return float64(h.ScannedLedger) }},
+	{"history_archive_lag_ledgers", "gauge",
+		"Number of ledgers the archive is behind the newest monitored archive.",
+		func(h *ArchiveHealth) float64 { return float64(h.LagLedgers) }},
+	{"history_archive_missing_checkpoint_files", "gauge",
+		"Required checkpoint files missing from the scanned range.",
+		func(h *ArchiveHealth) float64 { return float64(h.MissingCheckpointFiles) }},
+	{"history_archive_missing_buckets", "gauge",
+		"Buckets referenced by scanned checkpoints but missing.",
+		func(h *ArchiveHealth) float64 { return float64(h.MissingBuckets) }},
+	{"history_archive_invalid_ledgers", "gauge",
+		"Ledger headers with unexpected hashes.",
+		func(h *ArchiveHealth) float64 { return float64(h.InvalidLedgers) }},
+	{"history_archive_invalid_tx_sets", "gauge",
+		"Transaction sets with unexpected hashes.",
+		func(h *ArchiveHealth) float64 { return float64(h.InvalidTxSets) }},
+	{"history_archive_invalid_tx_result_sets", "gauge",
+		"Transaction result sets with unexpected hashes.",
+		func(h *ArchiveHealth) float64 { return float64(h.InvalidTxResultSets) }},
+	{"history_archive_invalid_buckets", "gauge",
+		"Buckets with unexpected hashes.",
+		func(h *ArchiveHealth) float64 { return float64(h.InvalidBuckets) }},
+	{"history_archive_errors_total", "counter",
+		"Errors encountered while updating.",
+		func(h *ArchiveHealth) float64 { return float64(h.Errors) }},
+	{"history_archive_last_update_timestamp_seconds", "gauge",
+		"Unix time of the last update.",
+		func(h *ArchiveHealth) float64 {
+			if h.LastUpdate.IsZero() {
+				return 0
+			}
+			return float64(h.LastUpdate.Unix())
+		}},
+}
+
+// WriteMetrics writes the health of every archive in the Prometheus text
+// exposition format, with the archive name as the "archive" label.
+func (m *Monitor) WriteMetrics(w io.Writer) error {
+	health := m.Health()
+	sort.Slice(health, func(i, j int) bool { return health[i].Name < health[j].Name })
+	for _, mt := range monitorMetrics {
+		if _, e := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n",
+			mt.name, mt.help, mt.name, mt.typ); e != nil {
+			return e
+		}
+		for i := range health {
+			v := strconv.FormatFloat(mt.value(&health[i]), 'f', -1, 64)
+			if _, e := fmt.Fprintf(w, "%s{archive=%q} %s\n",
+				mt.name, health[i].Name, v); e != nil {
+				return e
+			}
+		}
+	}
+	return nil
+}
+
+// ServeHTTP serves the metrics written by WriteMetrics.
+func (m *Monitor) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	m.WriteMetrics(w)
+}
@@ -0,0 +1,58 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitor(t *testing.T) {
+	defer cleanup()
+	ahead := GetTestArchive()
+	behind := GetTestArchive()
+	ahead.PopulateRandomRange(Range{Low: 0x3f, High: 0x13f})
+	behind.PopulateRandomRange(Range{Low: 0x3f, High: 0xff})
+
+	m := NewMonitor(&CommandOptions{Concurrency: 4})
+	m.AddArchive("ahead", ahead)
+	m.AddArchive("behind", behind)
+	m.Update()
+
+	health := m.Health()
+	assert.Equal(t, "ahead", health[0].Name)
+	assert.True(t, health[0].Up)
+	assert.Equal(t, uint32(0xff), health[0].CurrentLedger)
+	assert.Equal(t, uint32(0xff), health[0].ScannedLedger)
+	assert.Equal(t, uint32(0), health[0].LagLedgers)
+	assert.Equal(t, 0, health[0].MissingCheckpointFiles)
+	assert.Equal(t, 0, health[0].MissingBuckets)
+	assert.Equal(t, uint32(0xbf), health[1].CurrentLedger)
+	assert.Equal(t, uint32(0x40), health[1].LagLedgers)
+
+	// New checkpoints are picked up, missing files are noticed and
+	// found again once they appear.
+	ahead.AddRandomCheckpoint(0x13f)
+	ahead.backend.RemoveFile(CategoryCheckpointPath("ledger", 0x13f))
+	m.Update()
+	health = m.Health()
+	assert.Equal(t, uint32(0x13f), health[0].ScannedLedger)
+	assert.Equal(t, 1, health[0].MissingCheckpointFiles)
+	assert.Equal(t, uint32(0x80), health[1].LagLedgers)
+
+	ahead.AddRandomCheckpointFile("ledger", 0x13f)
+	m.Update()
+	assert.Equal(t, 0, m.Health()[0].MissingCheckpointFiles)
+
+	var buf bytes.Buffer
+	assert.NoError(t, m.WriteMetrics(&buf))
+	out := buf.String()
+	assert.Contains(t, out, "# TYPE history_archive_current_ledger gauge\n")
+	assert.Contains(t, out, "history_archive_current_ledger{archive=\"ahead\"} 319\n")
+	assert.Contains(t, out, "history_archive_lag_ledgers{archive=\"behind\"} 128\n")
+	assert.Contains(t, out, "history_archive_up{archive=\"behind\"} 1\n")
+}
@@ -0,0 +1,74 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"errors"
+	"fmt"
+	"log"
+)
+
+// CheckBucketsUnreferenced returns the buckets that exist in the archive
+// but are not referenced by any of the scanned HAS files.
+func (arch *Archive) CheckBucketsUnreferenced() map[Hash]bool {
+	arch.mutex.Lock()
+	defer arch.mutex.Unlock()
+	unreferenced := make(map[Hash]bool)
+	for k, _ := range arch.allBuckets {
+		_, ok := arch.referencedBuckets[k]
+		if !ok {
+			unreferenced[k] = true
+		}
+	}
+	return unreferenced
+}
+
+// Prune reports the buckets of an archive that are referenced neither by
+// its root HAS nor by any of its checkpoint HAS files, and deletes them only
+// if opts.Force is set. The whole archive is always scanned, whatever
+// opts.Range says, and nothing is deleted if the scan hit any error or
+// opts.DryRun is set.
+func Prune(arch *Archive, opts *CommandOptions) error {
+	if !arch.backend.CanListFiles() {
+		return errors.New("prune requires an archive that can list its files")
+	}
+
+	rootHAS, e := arch.GetRootHAS()
+	if e != nil {
+		return e
+	}
+	opts.Range = rootHAS.Range()
+
+	log.Printf("Scanning archive for referenced buckets")
+	arch.ClearCachedInfo()
+	if e = arch.Scan(opts); e != nil {
+		return fmt.Errorf("not pruning, scan failed: %s", e)
+	}
+	for _, bucket := range rootHAS.Buckets() {
+		arch.NoteReferencedBucket(bucket)
+	}
+
+	unreferenced := arch.CheckBucketsUnreferenced()
+	log.Printf("Found %d unreferenced buckets", len(unreferenced))
+
+	var errs uint32
+	for bucket, _ := range unreferenced {
+		pth := BucketPath(bucket)
+		if opts.DryRun || !opts.Force {
+			log.Printf("Unreferenced %s", pth)
+			continue
+		}
+		log.Printf("Deleting %s", pth)
+		errs += noteError(arch.backend.RemoveFile(pth))
+	}
+
+	if len(unreferenced) != 0 && (opts.DryRun || !opts.Force) {
+		log.Printf("Not deleting unreferenced buckets, pass --force to delete them")
+	}
+	if errs != 0 {
+		return fmt.Errorf("%d errors while pruning", errs)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Copyright 2016 Stellar Development Foundation and contributors. Licensed
+// under the Apache License, Version 2.0. See the COPYING file at the root
+// of this distribution or at http://www.apache.org/licenses/LICENSE-2.0
+
+package historyarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"log"
+
+	"github.com/stellar/go/xdr"
+)
+
+// LedgerData is what a checkpoint records about a single ledger. Txs and
+// Results must be in apply order and of the same length.
+type LedgerData struct {
+	Header  xdr.LedgerHeaderHistoryEntry
+	Txs     []xdr.TransactionEnvelope
+	Results []xdr.TransactionResultPair
+}
+
+// LedgerSource supplies ledgers to Publish. GetLedgers returns the ledgers
+// in [first, last] in order; ledgers it does not know about are left out.
+type LedgerSource interface {
+	GetLedgers(first uint32, last uint32) ([]LedgerData, error)
+}
+
+// CheckpointFirstLedger returns the first ledger of the checkpoint chk.
+func CheckpointFirstLedger(chk uint32) uint32 {
+	if chk < CheckpointFreq {
+		// Ledger 0 doesn't exist, the first checkpoint starts at genesis.
+		return 1
+	}
+	return chk - CheckpointFreq + 1
+}
+
+func (a *Archive) putXdrGzFile(pth string, entries []interface{}, opts *CommandOptions) error {
+	if opts.DryRun {
+		log.Printf("dryrun skipping " + pth)
+		return nil
+	}
+	if a.backend.Exists(pth) && !opts.Force {
+		log.Printf("skipping existing " + pth)
+		return nil
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for _, e := range entries {
+		if err := WriteFramedXdr(gz, e); err != nil {
+			return err
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+	return a.backend.PutFile(pth, bytesReadCloser{bytes.NewReader(buf.Bytes())})
+}
+
+// PutCheckpoint writes the ledger, transactions and results files and the
+// HAS of checkpoint chk, given all of its ledgers. As there are no buckets
+// to go with them, the HAS has no buckets either.
+func (a *Archive) PutCheckpoint(chk uint32, ledgers []LedgerData, server string, opts *CommandOptions) (HistoryArchiveState, error) {
+	var has HistoryArchiveState
+	first := CheckpointFirstLedger(chk)
+	if len(ledgers) != int(chk-first+1) {
+		return has, fmt.Errorf("checkpoint 0x%8.8x needs %d ledgers, got %d",
+			chk, chk-first+1, len(ledgers))
+	}
+
+	var headers, txs, results []interface{}
+	for i, l := range ledgers {
+		seq := l.Header.Header.LedgerSeq
+		if uint32(seq) != first+uint32(i) {
+			return has, fmt.Errorf("unexpected ledger %d in checkpoint 0x%8.8x", seq, chk)
+		}
+		if len(l.Txs) != len(l.Results) {
+			return has, fmt.Errorf("ledger %d has %d transactions but %d results",
+				seq, len(l.Txs), len(l.Results))
+		}
+		headers = append(headers, &ledgers[i].Header)
+		// Like stellar-core, only ledgers with transactions get entries.
+		if len(l.Txs) == 0 {
+			continue
+		}
+		txs = append(txs, &xdr.TransactionHistoryEntry{
+			LedgerSeq: seq,
+			TxSet: xdr.TransactionSet{
+				PreviousLedgerHash: l.Header.Header.PreviousLedgerHash,
+				Txs:                l.Txs,
+			},
+		})
+		results = append(results, &xdr.TransactionHistoryResultEntry{
+			LedgerSeq: seq,
+			TxResultSet: xdr.TransactionResultSet{
+				Results: l.Results,
+			},
+		})
+	}
+
+	files := map[string][]interface{}{
+		"ledger":       headers,
+		"transactions": txs,
+		"results":      results,
+	}
+	for cat, entries := range files {
+		if err := a.putXdrGzFile(CategoryCheckpointPath(cat, chk), entries, opts); err != nil {
+			return has, err
+		}
+	}
+
+	has.Version = 1
+	has.Server = server
+	has.CurrentLedger = chk
+	if opts.DryRun {
+		return has, nil
+	}
+	return has, a.PutCheckpointHAS(chk, has, opts)
+}
+
+// Publish writes every checkpoint of opts.Range for which src has all the
+// ledgers into dst, and points the root HAS of dst at the newest checkpoint
+// written if it is newer than the current one. Checkpoints with missing
+// ledgers are skipped.
+func Publish(src LedgerSource, dst *Archive, server string, opts *CommandOptions) error {
+	log.Printf("publishing range %s", opts.Range)
+
+	var errs uint32
+	var latest *HistoryArchiveState
+	written, skipped := 0, 0
+	// The chk >= low test stops the loop if chk wraps around.
+	low := NextCheckpoint(opts.Range.Low)
+	for chk := low; chk >= low && chk <= opts.Range.High; chk += CheckpointFreq {
+		first := CheckpointFirstLedger(chk)
+		ledgers, err := src.GetLedgers(first, chk)
+		if err != nil {
+			errs += noteError(err)
+			continue
+		}
+		if len(ledgers) != int(chk-first+1) {
+			log.Printf("Skipping checkpoint 0x%8.8x, %d of %d ledgers available",
+				chk, len(ledgers), chk-first+1)
+			skipped++
+			continue
+		}
+		has, err := dst.PutCheckpoint(chk, ledgers, server, opts)
+		if err != nil {
+			errs += noteError(err)
+			continue
+		}
+		latest = &has
+		written++
+	}
+	log.Printf("Published %d checkpoints, skipped %d", written, skipped)
+
+	if latest != nil && !opts.DryRun {
+		root, err := dst.GetRootHAS()
+		if err != nil || root.CurrentLedger < latest.CurrentLedger {
+			errs += noteError(dst.PutRootHAS(*latest, opts))
+		}
+	}
+
+	if errs != 0 {
+		return fmt.Errorf("%d errors while publishing", errs)
+	}
+	return nil
+}
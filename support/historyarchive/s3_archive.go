@@ -59,6 +59,15 @@ func (b *S3ArchiveBackend) PutFile(pth string, in io.ReadCloser) error {
 	return err
 }
 
+func (b *S3ArchiveBackend) RemoveFile(pth string) error {
+	params := &s3.DeleteObjectInput{
+		Bucket: aws.String(b.bucket),
+		Key:    aws.String(path.Join(b.prefix, pth)),
+	}
+	_, err := b.svc.DeleteObject(params)
+	return err
+}
+
 func (b *S3ArchiveBackend) ListFiles(pth string) (chan string, chan error) {
 	prefix := path.Join(b.prefix, pth)
 	ch := make(chan string)
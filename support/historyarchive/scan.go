@@ -212,6 +212,19 @@ func (arch *Archive) ScanBuckets(opts *CommandOptions) error {
 	}
 	arch.mutex.Unlock()
 
+	errs += arch.scanReferencedBuckets(seqs, doList, opts)
+	if errs != 0 {
+		return fmt.Errorf("%d errors while scanning buckets", errs)
+	}
+	return nil
+}
+
+// scanReferencedBuckets notes the buckets referenced by the HAS files of
+// the checkpoints in seqs. Unless doList says that all existing buckets
+// were already noted, each newly referenced bucket is checked for
+// existence. It returns the number of errors encountered.
+func (arch *Archive) scanReferencedBuckets(seqs []uint32, doList bool, opts *CommandOptions) uint32 {
+	var errs uint32
 	var wg sync.WaitGroup
 	wg.Add(opts.Concurrency)
 
@@ -274,10 +287,7 @@ func (arch *Archive) ScanBuckets(opts *CommandOptions) error {
 	wg.Wait()
 	arch.ReportBucketStats()
 	close(tick)
-	if errs != 0 {
-		return fmt.Errorf("%d errors while scanning buckets", errs)
-	}
-	return nil
+	return errs
 }
 
 func (arch *Archive) ClearCachedInfo() {
@@ -6,6 +6,7 @@ package historyarchive
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"log"
@@ -26,6 +27,16 @@ func makeTicker(onTick func(uint)) chan bool {
 	return tick
 }
 
+// bytesReadCloser is a ReadCloser over an in-memory buffer that keeps its
+// length visible to backends, unlike ioutil.NopCloser.
+type bytesReadCloser struct {
+	*bytes.Reader
+}
+
+func (bytesReadCloser) Close() error {
+	return nil
+}
+
 func bufReadCloser(in io.ReadCloser) io.ReadCloser {
 	return struct {
 		io.Reader
@@ -10,9 +10,12 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"os"
+	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/spf13/cobra"
 	"github.com/stellar/go/support/historyarchive"
+	supportHttp "github.com/stellar/go/support/http"
 )
 
 func status(a string, opts *Options) {
@@ -37,6 +40,7 @@ type Options struct {
 	High        uint32
 	Last        int
 	Profile     bool
+	CoreConfig  string
 	CommandOpts historyarchive.CommandOptions
 	ConnectOpts historyarchive.ConnectOptions
 }
@@ -57,6 +61,22 @@ func (opts *Options) SetRange(arch *historyarchive.Archive) {
 
 }
 
+// LoadCoreConfig reads the [HISTORY.<name>] sections of a stellar-core
+// config file, so that their get/put/mkdir commands can be used through
+// cmd://<name> archive URLs.
+func (opts *Options) LoadCoreConfig() {
+	if opts.CoreConfig == "" {
+		return
+	}
+	var cfg struct {
+		History map[string]historyarchive.CommandTemplates `toml:"HISTORY"`
+	}
+	if _, e := toml.DecodeFile(opts.CoreConfig, &cfg); e != nil {
+		log.Fatal(e)
+	}
+	opts.ConnectOpts.Commands = cfg.History
+}
+
 func (opts *Options) MaybeProfile() {
 	if opts.Profile {
 		go func() {
@@ -104,6 +124,75 @@ func repair(src string, dst string, opts *Options) {
 	}
 }
 
+func extract(src string, dst string, opts *Options) {
+	srcArch := historyarchive.MustConnect(src, opts.ConnectOpts)
+	dstArch := historyarchive.MustConnect(dst, opts.ConnectOpts)
+	opts.SetRange(srcArch)
+	log.Printf("extracting %v -> %v\n", src, dst)
+	e := historyarchive.Extract(srcArch, dstArch, &opts.CommandOpts)
+	if e != nil {
+		log.Fatal(e)
+	}
+}
+
+func prune(a string, opts *Options) {
+	arch := historyarchive.MustConnect(a, opts.ConnectOpts)
+	log.Printf("pruning %v\n", a)
+	e := historyarchive.Prune(arch, &opts.CommandOpts)
+	if e != nil {
+		log.Fatal(e)
+	}
+}
+
+func monitor(archives []string, interval time.Duration, listen string, opts *Options) {
+	if len(archives) == 0 {
+		log.Fatal("require at least 1 argument")
+	}
+	var archs []*historyarchive.Archive
+	for _, a := range archives {
+		archs = append(archs, historyarchive.MustConnect(a, opts.ConnectOpts))
+	}
+	opts.SetRange(archs[0])
+	m := historyarchive.NewMonitor(&opts.CommandOpts)
+	for i, a := range archives {
+		m.AddArchive(a, archs[i])
+	}
+	go m.Run(interval, nil)
+
+	mux := supportHttp.NewMux(false)
+	mux.Get("/metrics", m.ServeHTTP)
+	log.Printf("serving metrics on http://%s/metrics\n", listen)
+	supportHttp.Run(supportHttp.Config{
+		ListenAddr: listen,
+		Handler:    mux,
+	})
+}
+
+func dumpxdr(files []string, accounts []string, ops []string, opts *Options) {
+	filter := historyarchive.XdrFilter{
+		LowLedger: uint32(opts.Low),
+	}
+	if opts.High != 0xffffffff {
+		filter.HighLedger = opts.High
+	}
+	for _, a := range accounts {
+		if e := filter.AddAccount(a); e != nil {
+			log.Fatal(e)
+		}
+	}
+	for _, op := range ops {
+		ty, e := historyarchive.ParseOperationType(op)
+		if e != nil {
+			log.Fatal(e)
+		}
+		filter.OperationTypes = append(filter.OperationTypes, ty)
+	}
+	e := historyarchive.DumpXdrAsJson(files, filter)
+	if e != nil {
+		log.Fatal(e)
+	}
+}
+
 func main() {
 
 	var opts Options
@@ -111,6 +200,9 @@ func main() {
 	rootCmd := &cobra.Command{
 		Use:   "stellar-archivist",
 		Short: "inspect stellar history archive",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			opts.LoadCoreConfig()
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			cmd.Help()
 			os.Exit(0)
@@ -160,6 +252,20 @@ func main() {
 		"S3 endpoint to use",
 	)
 
+	rootCmd.PersistentFlags().BoolVar(
+		&opts.ConnectOpts.HttpListIndex,
+		"http-index",
+		false,
+		"list files of http archives from their directory index pages",
+	)
+
+	rootCmd.PersistentFlags().StringVar(
+		&opts.CoreConfig,
+		"core-config",
+		"",
+		"stellar-core config file with [HISTORY] sections for cmd:// archives",
+	)
+
 	rootCmd.PersistentFlags().BoolVarP(
 		&opts.CommandOpts.DryRun,
 		"dryrun",
@@ -173,7 +279,7 @@ func main() {
 		"force",
 		"f",
 		false,
-		"overwrite existing files",
+		"overwrite existing files, and delete buckets when pruning",
 	)
 
 	rootCmd.PersistentFlags().BoolVar(
@@ -231,15 +337,70 @@ func main() {
 	})
 
 	rootCmd.AddCommand(&cobra.Command{
-		Use: "dumpxdr",
+		Use:   "extract",
+		Short: "copy a ledger range and the buckets it references into a new archive",
 		Run: func(cmd *cobra.Command, args []string) {
-			err := historyarchive.DumpXdrAsJson(args)
-			if err != nil {
-				log.Fatal(err)
-			}
+			opts.MaybeProfile()
+			src, dst := srcDst(args)
+			extract(src, dst, &opts)
 		},
 	})
 
+	rootCmd.AddCommand(&cobra.Command{
+		Use:   "prune",
+		Short: "report buckets not referenced by any checkpoint of an archive, and delete them with --force",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.MaybeProfile()
+			prune(firstArg(args), &opts)
+		},
+	})
+
+	var monitorInterval time.Duration
+	var monitorListen string
+	monitorCmd := &cobra.Command{
+		Use:   "monitor",
+		Short: "keep scanning archives and serve their health as Prometheus metrics",
+		Run: func(cmd *cobra.Command, args []string) {
+			opts.MaybeProfile()
+			monitor(args, monitorInterval, monitorListen, &opts)
+		},
+	}
+	monitorCmd.Flags().DurationVar(
+		&monitorInterval,
+		"interval",
+		5*time.Minute,
+		"time between updates",
+	)
+	monitorCmd.Flags().StringVar(
+		&monitorListen,
+		"listen",
+		"localhost:9090",
+		"address to serve metrics on",
+	)
+	rootCmd.AddCommand(monitorCmd)
+
+	var dumpAccounts, dumpOps []string
+	dumpCmd := &cobra.Command{
+		Use:   "dumpxdr",
+		Short: "print XDR files of an archive as JSON",
+		Run: func(cmd *cobra.Command, args []string) {
+			dumpxdr(args, dumpAccounts, dumpOps, &opts)
+		},
+	}
+	dumpCmd.Flags().StringSliceVar(
+		&dumpAccounts,
+		"account",
+		nil,
+		"only print records mentioning this account (repeatable)",
+	)
+	dumpCmd.Flags().StringSliceVar(
+		&dumpOps,
+		"op",
+		nil,
+		"only print records with an operation of this type, e.g. payment (repeatable)",
+	)
+	rootCmd.AddCommand(dumpCmd)
+
 	rootCmd.Execute()
 }
 